@@ -278,12 +278,13 @@ const EditProductModal = ({ open, onClose, product, onProductUpdated }) => {
         '团购团批': 'GroupBuying',
       };
 
-      // Map mini-app type to store type for backward compatibility
+      // Location-based products take the type of their store; the others use their mini-app's
+      const selectedStore = stores.find(store => store.id === parseInt(formData.store_id));
       const storeTypeMap = {
-        '零售门店': '展销商店',
-        '无人商店': '无人门店',
-        '展销展消': '展销商店',
-        '团购团批': '展销商店',
+        '零售门店': 'RetailStore',
+        '无人商店': selectedStore ? selectedStore.type : 'UnmannedStore',
+        '展销展消': selectedStore ? selectedStore.type : 'ExhibitionStore',
+        '团购团批': 'GroupBuying',
       };
 
       // Prepare data for API
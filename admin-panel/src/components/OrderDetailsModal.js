@@ -84,6 +84,11 @@ const OrderDetailsModal = ({ open, onClose, order, onStatusUpdate }) => {
     return `¥${amount.toFixed(2)}`;
   };
 
+  const fulfillmentLabels = {
+    delivery: 'Delivery',
+    pickup: 'Pickup at store',
+  };
+
   if (!order) return null;
 
   const { order: orderInfo, items } = order;
@@ -226,6 +231,75 @@ const OrderDetailsModal = ({ open, onClose, order, onStatusUpdate }) => {
                 </Box>
               </Box>
             </Paper>
+
+            {/* Delivery Information */}
+            <Paper sx={{ p: 2, mt: 2 }}>
+              <Typography variant="h6" gutterBottom fontWeight={600}>
+                Delivery Information
+              </Typography>
+              <Box display="flex" flexDirection="column" gap={1}>
+                <Box display="flex" justifyContent="space-between">
+                  <Typography variant="body2" color="text.secondary">Fulfillment:</Typography>
+                  <Typography variant="body2" fontWeight={500}>
+                    {fulfillmentLabels[orderInfo.fulfillment_method] || 'Not recorded'}
+                  </Typography>
+                </Box>
+                {orderInfo.fulfillment_method === 'pickup' && orderInfo.store_name && (
+                  <Box display="flex" justifyContent="space-between">
+                    <Typography variant="body2" color="text.secondary">Pickup Store:</Typography>
+                    <Typography variant="body2" fontWeight={500}>{orderInfo.store_name}</Typography>
+                  </Box>
+                )}
+                {orderInfo.delivery_address && (
+                  <>
+                    <Box display="flex" justifyContent="space-between">
+                      <Typography variant="body2" color="text.secondary">Recipient:</Typography>
+                      <Typography variant="body2" fontWeight={500}>
+                        {orderInfo.delivery_address.recipient_name || 'N/A'}
+                      </Typography>
+                    </Box>
+                    {orderInfo.delivery_address.phone && (
+                      <Box display="flex" justifyContent="space-between">
+                        <Typography variant="body2" color="text.secondary">Phone:</Typography>
+                        <Typography variant="body2">{orderInfo.delivery_address.phone}</Typography>
+                      </Box>
+                    )}
+                    <Box display="flex" justifyContent="space-between" gap={2}>
+                      <Typography variant="body2" color="text.secondary">Address:</Typography>
+                      <Typography variant="body2" textAlign="right">
+                        {[
+                          orderInfo.delivery_address.line1,
+                          orderInfo.delivery_address.line2,
+                          [orderInfo.delivery_address.postal_code, orderInfo.delivery_address.city]
+                            .filter(Boolean)
+                            .join(' '),
+                          orderInfo.delivery_address.region,
+                          orderInfo.delivery_address.country_code,
+                        ]
+                          .filter(Boolean)
+                          .join(', ')}
+                      </Typography>
+                    </Box>
+                    {orderInfo.delivery_address.latitude != null && (
+                      <Box display="flex" justifyContent="space-between">
+                        <Typography variant="body2" color="text.secondary">Coordinates:</Typography>
+                        <Typography variant="body2">
+                          {orderInfo.delivery_address.latitude}, {orderInfo.delivery_address.longitude}
+                        </Typography>
+                      </Box>
+                    )}
+                    {orderInfo.delivery_address.delivery_instructions && (
+                      <Box display="flex" justifyContent="space-between" gap={2}>
+                        <Typography variant="body2" color="text.secondary">Instructions:</Typography>
+                        <Typography variant="body2" textAlign="right">
+                          {orderInfo.delivery_address.delivery_instructions}
+                        </Typography>
+                      </Box>
+                    )}
+                  </>
+                )}
+              </Box>
+            </Paper>
           </Grid>
 
           {/* Order Items */}
@@ -20,6 +20,7 @@ import {
   AttachMoney as PriceIcon,
   Category as CategoryIcon,
 } from '@mui/icons-material';
+import { storeTypeLabel, UNMANNED_STORE_TYPES } from '../constants/storeTypes';
 
 const ProductDetailsModal = ({ open, onClose, product }) => {
   if (!product) return null;
@@ -41,10 +42,10 @@ const ProductDetailsModal = ({ open, onClose, product }) => {
     } else if (product.mini_app_type === 'UnmannedStore' || product.mini_app_type === 'ExhibitionSales') {
       // For location-dependent mini-apps, use store_type from the associated store
       // The backend should populate this correctly via JOIN with stores table
-      return product.store_type;
+      return storeTypeLabel(product.store_type);
     }
     // Fallback to store_type
-    return product.store_type;
+    return storeTypeLabel(product.store_type);
   };
 
   const getStoreTypeChip = (product) => {
@@ -253,7 +254,7 @@ const ProductDetailsModal = ({ open, onClose, product }) => {
               </Box>
 
               {/* Featured Status - Only for Unmanned Stores */}
-              {product.store_type?.toLowerCase() === 'unmanned' && (
+              {UNMANNED_STORE_TYPES.includes(product.store_type) && (
                 <Box>
                   <Box sx={{ display: 'flex', alignItems: 'center', gap: 1, mb: 1 }}>
                     <Typography variant="h6" sx={{ fontWeight: 600 }}>
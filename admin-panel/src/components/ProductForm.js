@@ -482,15 +482,15 @@ const ProductForm = ({ open, onClose, onProductCreated, product = null, onProduc
       if (formData.mini_app_type === '无人商店') {
         // For unmanned stores, store_type should be derived from selected store
         const selectedStore = stores.find(store => store.id === parseInt(formData.store_id));
-        storeType = selectedStore ? selectedStore.type : '无人门店'; // fallback
+        storeType = selectedStore ? selectedStore.type : 'UnmannedStore'; // fallback
       } else if (formData.mini_app_type === '展销展消') {
         // For exhibition sales, store_type should be derived from selected store
         const selectedStore = stores.find(store => store.id === parseInt(formData.store_id));
-        storeType = selectedStore ? selectedStore.type : '展销商店'; // fallback
+        storeType = selectedStore ? selectedStore.type : 'ExhibitionStore'; // fallback
       } else {
-        // For retail store and group buying, store_type is not the primary identifier
-        // Set a default value but the mini_app_type will be the primary identifier
-        storeType = '展销商店'; // default fallback for database compatibility
+        // Retail store and group buying products are not tied to a store and use the
+        // store type of their mini-app
+        storeType = miniAppTypeMap[formData.mini_app_type];
       }
 
       // Prepare data for API
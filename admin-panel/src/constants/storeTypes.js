@@ -0,0 +1,14 @@
+// Store type codes returned and accepted by the catalog API (GET /store-types lists them
+// with localized names); the labels below are the zh-CN display names
+export const STORE_TYPE_LABELS = {
+  RetailStore: '零售商店',
+  UnmannedStore: '无人门店',
+  UnmannedWarehouse: '无人仓店',
+  ExhibitionStore: '展销商店',
+  ExhibitionMall: '展销商城',
+  GroupBuying: '团购团批',
+};
+
+export const UNMANNED_STORE_TYPES = ['UnmannedStore', 'UnmannedWarehouse'];
+
+export const storeTypeLabel = (code) => STORE_TYPE_LABELS[code] || code;
@@ -46,6 +46,7 @@ import {
   PhotoCamera as PhotoIcon,
 } from '@mui/icons-material';
 import { useToast } from '../contexts/ToastContext';
+import { storeTypeLabel } from '../constants/storeTypes';
 
 const CategoryListPage = () => {
   const [categories, setCategories] = useState([]);
@@ -78,10 +79,10 @@ const CategoryListPage = () => {
 
   // Store type color mapping for consistent color coding across admin panel
   const storeTypeOptions = [
-    { value: '无人门店', label: '无人门店', color: '#2196f3', miniApp: '无人商店' },
-    { value: '无人仓店', label: '无人仓店', color: '#4caf50', miniApp: '无人商店' },
-    { value: '展销商店', label: '展销商店', color: '#ffd556', miniApp: '展销展消' },
-    { value: '展销商城', label: '展销商城', color: '#f38900', miniApp: '展销展消' },
+    { value: 'UnmannedStore', label: '无人门店', color: '#2196f3', miniApp: '无人商店' },
+    { value: 'UnmannedWarehouse', label: '无人仓店', color: '#4caf50', miniApp: '无人商店' },
+    { value: 'ExhibitionStore', label: '展销商店', color: '#ffd556', miniApp: '展销展消' },
+    { value: 'ExhibitionMall', label: '展销商城', color: '#f38900', miniApp: '展销展消' },
   ];
 
   const getStoreTypeInfo = (type) => {
@@ -584,7 +585,7 @@ const CategoryListPage = () => {
                               {store.name}
                             </Typography>
                             <Typography variant="caption" color="text.secondary">
-                              {store.city} • {store.type}
+                              {store.city} • {storeTypeLabel(store.type)}
                             </Typography>
                           </Box>
                         </Box>
@@ -394,7 +394,19 @@ const OrderListPage = () => {
                       />
                     </TableCell>
                     <TableCell>
-                      {order.store_name || 'N/A'}
+                      <Box>
+                        <Typography variant="body2">{order.store_name || 'N/A'}</Typography>
+                        {order.fulfillment_method === 'delivery' && (
+                          <Typography variant="caption" color="text.secondary">
+                            Delivery{order.delivery_address?.city ? ` to ${order.delivery_address.city}` : ''}
+                          </Typography>
+                        )}
+                        {order.fulfillment_method === 'pickup' && (
+                          <Typography variant="caption" color="text.secondary">
+                            Pickup at store
+                          </Typography>
+                        )}
+                      </Box>
                     </TableCell>
                     <TableCell>
                       <Typography variant="body2" fontWeight={600}>
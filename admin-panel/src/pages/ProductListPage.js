@@ -32,6 +32,7 @@ import ProductForm from '../components/ProductForm';
 import ProductDetailsModal from '../components/ProductDetailsModal';
 import DeleteProductDialog from '../components/DeleteProductDialog';
 import ProductStatusToggle from '../components/ProductStatusToggle';
+import { storeTypeLabel, UNMANNED_STORE_TYPES } from '../constants/storeTypes';
 
 // Resolve image URLs via Worker
 const API_BASE = process.env.REACT_APP_API_BASE_URL || 'https://device-api.expomadeinworld.com';
@@ -149,10 +150,10 @@ const ProductListPage = () => {
     } else if (product.mini_app_type === 'UnmannedStore' || product.mini_app_type === 'ExhibitionSales') {
       // For location-dependent mini-apps, use store_type from the associated store
       // The backend should populate this correctly via JOIN with stores table
-      return product.store_type;
+      return storeTypeLabel(product.store_type);
     }
     // Fallback to store_type
-    return product.store_type;
+    return storeTypeLabel(product.store_type);
   };
 
   const getStoreTypeChip = (product) => {
@@ -409,7 +410,7 @@ const ProductListPage = () => {
                       </TableCell>
                       
                       <TableCell>
-                        {(product.store_type === '无人门店' || product.store_type === '无人仓店' || product.store_type?.toLowerCase() === 'unmanned') ? (
+                        {UNMANNED_STORE_TYPES.includes(product.store_type) ? (
                           <Typography variant="body2">
                             {product.stock_left || 0} units
                           </Typography>
@@ -37,6 +37,7 @@ import {
   Navigation as NavigationIcon,
 } from '@mui/icons-material';
 import { useToast } from '../contexts/ToastContext';
+import { storeTypeLabel } from '../constants/storeTypes';
 
 // API base for production routing via Cloudflare Worker
 const API_BASE = process.env.REACT_APP_API_BASE_URL || 'https://device-api.expomadeinworld.com';
@@ -66,10 +67,10 @@ const StoreListPage = () => {
   });
 
   const storeTypeOptions = [
-    { value: '无人门店', label: '无人门店', color: '#2196f3', miniApp: '无人商店' },
-    { value: '无人仓店', label: '无人仓店', color: '#4caf50', miniApp: '无人商店' },
-    { value: '展销商店', label: '展销商店', color: '#ffd556', miniApp: '展销展消' },
-    { value: '展销商城', label: '展销商城', color: '#f38900', miniApp: '展销展消' },
+    { value: 'UnmannedStore', label: '无人门店', color: '#2196f3', miniApp: '无人商店' },
+    { value: 'UnmannedWarehouse', label: '无人仓店', color: '#4caf50', miniApp: '无人商店' },
+    { value: 'ExhibitionStore', label: '展销商店', color: '#ffd556', miniApp: '展销展消' },
+    { value: 'ExhibitionMall', label: '展销商城', color: '#f38900', miniApp: '展销展消' },
   ];
 
   useEffect(() => {
@@ -287,7 +288,7 @@ const StoreListPage = () => {
                           {store.name}
                         </Typography>
                         <Chip
-                          label={store.type}
+                          label={storeTypeLabel(store.type)}
                           size="small"
                           sx={{
                             bgcolor: typeInfo.color,
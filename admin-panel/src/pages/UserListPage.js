@@ -40,6 +40,7 @@ import {
   CalendarToday as CalendarIcon,
   TrendingUp as TrendingUpIcon,
   Add as AddIcon,
+  Download as DownloadIcon,
 } from '@mui/icons-material';
 import { userService } from '../services/api';
 import { useToast } from '../contexts/ToastContext';
@@ -113,6 +114,29 @@ const UserListPage = () => {
     }
   };
 
+  // Download the users matching the current filters as CSV
+  const handleExportUsers = async () => {
+    try {
+      const params = { sort: orderBy, order: order };
+      if (searchTerm) params.search = searchTerm;
+      if (roleFilter) params.role = roleFilter;
+      if (statusFilter) params.status = statusFilter;
+
+      const blob = await userService.exportUsers(params);
+      const url = window.URL.createObjectURL(blob);
+      const link = document.createElement('a');
+      link.href = url;
+      link.download = `users-${new Date().toISOString().slice(0, 10)}.csv`;
+      document.body.appendChild(link);
+      link.click();
+      link.remove();
+      window.URL.revokeObjectURL(url);
+    } catch (err) {
+      showToast('Failed to export users', 'error');
+      console.error('Error exporting users:', err);
+    }
+  };
+
   // Fetch analytics data
   const fetchAnalytics = async () => {
     try {
@@ -451,13 +475,22 @@ const UserListPage = () => {
       <Paper sx={{ p: 2, mb: 3 }}>
         <Box sx={{ display: 'flex', justifyContent: 'space-between', alignItems: 'center', mb: 2 }}>
           <Typography variant="h6">Users</Typography>
-          <Button
-            variant="contained"
-            startIcon={<AddIcon />}
-            onClick={handleCreateUser}
-          >
-            Create User
-          </Button>
+          <Box sx={{ display: 'flex', gap: 1 }}>
+            <Button
+              variant="outlined"
+              startIcon={<DownloadIcon />}
+              onClick={handleExportUsers}
+            >
+              Export CSV
+            </Button>
+            <Button
+              variant="contained"
+              startIcon={<AddIcon />}
+              onClick={handleCreateUser}
+            >
+              Create User
+            </Button>
+          </Box>
         </Box>
         <Grid container spacing={2} alignItems="center">
           <Grid item xs={12} md={4}>
@@ -179,6 +179,16 @@ export const userService = {
     return response.data;
   },
 
+  // Export the filtered user list as a CSV file (Blob)
+  exportUsers: async (params = {}) => {
+    const response = await axios.get(`${ADMIN_BASE}/users/export`, {
+      params,
+      headers: getAuthHeaders(),
+      responseType: 'blob'
+    });
+    return response.data;
+  },
+
   // Bulk update users
   bulkUpdateUsers: async (bulkData) => {
     const response = await axios.post(`${ADMIN_BASE}/users/bulk-update`, bulkData, {
@@ -118,6 +118,13 @@ func setupRouter(handler *api.Handler) *gin.Engine {
 		auth.POST("/admin/verify-code", handler.AdminVerifyCode)
 	}
 
+	// Service-to-service routes authenticated with the shared internal API key
+	internal := router.Group("/api/internal")
+	internal.Use(api.InternalAPIKeyMiddleware())
+	{
+		internal.POST("/emails/cart-recovery", handler.SendCartRecoveryEmail)
+	}
+
 	// Protected routes for testing JWT validation
 	protected := router.Group("/api/protected")
 	protected.Use(api.AuthMiddleware())
@@ -125,6 +132,17 @@ func setupRouter(handler *api.Handler) *gin.Engine {
 		protected.GET("/profile", handler.GetProfile)
 	}
 
+	// Self-service profile of the signed-in user
+	me := router.Group("/api/me")
+	me.Use(api.AuthMiddleware())
+	{
+		me.GET("", handler.GetMe)
+		me.PATCH("", handler.UpdateMe)
+		me.DELETE("", handler.DeleteMe)
+		me.POST("/email", handler.RequestEmailChange)
+		me.POST("/email/verify", handler.ConfirmEmailChange)
+	}
+
 	// Root endpoint for basic info
 	router.GET("/", func(c *gin.Context) {
 		c.JSON(200, gin.H{
@@ -141,7 +159,7 @@ func setupRouter(handler *api.Handler) *gin.Engine {
 func corsMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
-		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
 		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization")
 
 		if c.Request.Method == "OPTIONS" {
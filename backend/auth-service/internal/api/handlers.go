@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
@@ -135,7 +136,7 @@ func (h *Handler) Login(c *gin.Context) {
 	// Get user by email
 	user, err := h.DB.GetUserByEmail(ctx, req.Email)
 	if err != nil {
-		if err == pgx.ErrNoRows {
+		if errors.Is(err, pgx.ErrNoRows) {
 			c.JSON(http.StatusUnauthorized, models.ErrorResponse{
 				Error:   "Invalid credentials",
 				Message: "Email or password is incorrect",
@@ -159,6 +160,12 @@ func (h *Handler) Login(c *gin.Context) {
 		return
 	}
 
+	// Only active accounts can sign in
+	if user.Status != models.AccountStatusActive {
+		respondAccountNotActive(c, user.Status, user.SuspendedUntil)
+		return
+	}
+
 	// Update last login timestamp
 	if err := h.DB.UpdateLastLogin(ctx, user.ID); err != nil {
 		// Log the error but don't fail the login
@@ -273,6 +280,23 @@ func (h *Handler) Refresh(c *gin.Context) {
 	userID, _ := claims["user_id"].(string)
 	email, _ := claims["email"].(string)
 
+	// Tokens of accounts that are no longer active are not renewed. Admin tokens do not
+	// belong to a users row and are renewed as before.
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	status, suspendedUntil, err := h.DB.GetUserAccountStatus(ctx, userID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to check account status",
+			Message: err.Error(),
+		})
+		return
+	}
+	if err == nil && status != models.AccountStatusActive {
+		respondAccountNotActive(c, status, suspendedUntil)
+		return
+	}
+
 	// Generate new token
 	newToken, err := h.generateJWTToken(userID, email)
 	if err != nil {
@@ -299,6 +323,27 @@ func (h *Handler) Refresh(c *gin.Context) {
 	})
 }
 
+// respondAccountNotActive refuses to authenticate an account that is not active
+func respondAccountNotActive(c *gin.Context, status string, suspendedUntil *time.Time) {
+	message := "This account is not active"
+	switch status {
+	case models.AccountStatusSuspended:
+		message = "This account is suspended"
+		if suspendedUntil != nil {
+			message += " until " + suspendedUntil.UTC().Format(time.RFC3339)
+		}
+	case models.AccountStatusDeactivated:
+		message = "This account has been deactivated"
+	case models.AccountStatusPendingVerification:
+		message = "This account is waiting for email verification"
+	}
+
+	c.JSON(http.StatusForbidden, models.ErrorResponse{
+		Error:   "Account " + status,
+		Message: message,
+	})
+}
+
 // isDuplicateEmailError checks if the error is due to duplicate email constraint
 func isDuplicateEmailError(err error) bool {
 	return strings.Contains(err.Error(), "duplicate key value violates unique constraint") &&
@@ -429,6 +474,128 @@ func (h *Handler) UserSendVerification(c *gin.Context) {
 		}
 	*/
 
+	verificationCode, ok := h.sendUserVerificationCode(ctx, c, req.Email, clientIP)
+	if !ok {
+		return
+	}
+
+	// Security logging - success
+	fmt.Printf("[USER_AUTH] Verification code sent successfully to %s from IP: %s\n",
+		req.Email, clientIP)
+
+	// Return success response
+	c.JSON(http.StatusOK, models.SendUserVerificationResponse{
+		Message:   "Verification code sent successfully",
+		ExpiresAt: verificationCode.ExpiresAt,
+	})
+}
+
+// UserVerifyCode handles verification code validation and JWT generation for users
+func (h *Handler) UserVerifyCode(c *gin.Context) {
+	var req models.VerifyUserCodeRequest
+
+	// Bind and validate request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request data",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	// Get client IP for security logging
+	clientIP := getClientIP(c)
+	userAgent := c.GetHeader("User-Agent")
+
+	// Security logging
+	fmt.Printf("[USER_AUTH] Code verification attempt from IP: %s, Email: %s, UserAgent: %s\n",
+		clientIP, req.Email, userAgent)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if !h.consumeUserVerificationCode(ctx, c, req.Email, req.Code, clientIP) {
+		return
+	}
+
+	// Check if user exists, if not auto-register
+	user, err := h.DB.GetUserByEmail(ctx, req.Email)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			// Auto-register new user
+			user, err = h.DB.CreateUserFromEmail(ctx, req.Email)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+					Error:   "Failed to create user account",
+					Message: err.Error(),
+				})
+				return
+			}
+			fmt.Printf("[USER_AUTH] Auto-registered new user: %s\n", req.Email)
+		} else {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Error:   "Failed to retrieve user",
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	// Verifying the code proves the user owns the address, which completes a pending verification
+	if user.Status == models.AccountStatusPendingVerification {
+		if err := h.DB.ActivatePendingUser(ctx, user.ID); err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Error:   "Failed to activate user account",
+				Message: err.Error(),
+			})
+			return
+		}
+		user.Status = models.AccountStatusActive
+		fmt.Printf("[USER_AUTH] Activated pending user after email verification: %s\n", req.Email)
+	}
+
+	// Only active accounts can sign in
+	if user.Status != models.AccountStatusActive {
+		fmt.Printf("[USER_AUTH] REFUSED sign-in for %s account %s from IP: %s\n", user.Status, req.Email, clientIP)
+		respondAccountNotActive(c, user.Status, user.SuspendedUntil)
+		return
+	}
+
+	// Update last login timestamp
+	if err := h.DB.UpdateLastLogin(ctx, user.ID); err != nil {
+		// Log the error but don't fail the login
+		fmt.Printf("Failed to update last login for user %s: %v\n", user.ID, err)
+	}
+
+	// Generate JWT token
+	token, err := h.generateJWTToken(user.ID, user.Email)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to generate token",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	// Calculate token expiration
+	expirationHours := getEnvInt("JWT_EXPIRATION_HOURS", 24)
+	tokenExpiresAt := time.Now().Add(time.Duration(expirationHours) * time.Hour)
+
+	// Security logging - successful authentication
+	fmt.Printf("[USER_AUTH] SUCCESSFUL authentication for %s from IP: %s, Token expires: %s\n",
+		req.Email, clientIP, tokenExpiresAt.Format("2006-01-02 15:04:05"))
+
+	// Return success response
+	c.JSON(http.StatusOK, models.VerifyUserCodeResponse{
+		Token:     token,
+		ExpiresAt: tokenExpiresAt,
+		User:      *user,
+	})
+}
+
+// sendUserVerificationCode generates a sign-in code for email, stores its hash and emails it.
+// It responds with an error and returns false when any step fails.
+func (h *Handler) sendUserVerificationCode(ctx context.Context, c *gin.Context, email, clientIP string) (*models.UserVerificationCode, bool) {
 	// Generate 6-digit verification code
 	code, err := generateVerificationCode()
 	if err != nil {
@@ -436,7 +603,7 @@ func (h *Handler) UserSendVerification(c *gin.Context) {
 			Error:   "Failed to generate verification code",
 			Message: err.Error(),
 		})
-		return
+		return nil, false
 	}
 
 	// Hash the code
@@ -446,7 +613,7 @@ func (h *Handler) UserSendVerification(c *gin.Context) {
 			Error:   "Failed to process verification code",
 			Message: err.Error(),
 		})
-		return
+		return nil, false
 	}
 
 	// Calculate expiration time
@@ -454,13 +621,13 @@ func (h *Handler) UserSendVerification(c *gin.Context) {
 	expiresAt := time.Now().Add(time.Duration(expirationMinutes) * time.Minute)
 
 	// Store verification code in database
-	verificationCode, err := h.DB.CreateUserVerificationCode(ctx, req.Email, string(codeHash), clientIP, expiresAt)
+	verificationCode, err := h.DB.CreateUserVerificationCode(ctx, email, string(codeHash), clientIP, expiresAt)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Error:   "Failed to store verification code",
 			Message: err.Error(),
 		})
-		return
+		return nil, false
 	}
 
 	// Increment rate limit - TEMPORARILY DISABLED FOR TESTING
@@ -476,7 +643,7 @@ func (h *Handler) UserSendVerification(c *gin.Context) {
 	emailService := services.NewEmailService()
 	emailData := models.EmailVerificationData{
 		Code:         code,
-		Email:        req.Email,
+		Email:        email,
 		ExpiresAt:    expiresAt,
 		IPAddress:    clientIP,
 		UserAgent:    c.GetHeader("User-Agent"),
@@ -484,65 +651,36 @@ func (h *Handler) UserSendVerification(c *gin.Context) {
 		ExpiresInMin: expirationMinutes,
 	}
 
-	if err := emailService.SendUserVerificationCode(req.Email, emailData); err != nil {
+	if err := emailService.SendUserVerificationCode(email, emailData); err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Error:   "Failed to send verification email",
 			Message: err.Error(),
 		})
-		return
+		return nil, false
 	}
 
-	// Security logging - success
-	fmt.Printf("[USER_AUTH] Verification code sent successfully to %s from IP: %s\n",
-		req.Email, clientIP)
-
-	// Return success response
-	c.JSON(http.StatusOK, models.SendUserVerificationResponse{
-		Message:   "Verification code sent successfully",
-		ExpiresAt: verificationCode.ExpiresAt,
-	})
+	return verificationCode, true
 }
 
-// UserVerifyCode handles verification code validation and JWT generation for users
-func (h *Handler) UserVerifyCode(c *gin.Context) {
-	var req models.VerifyUserCodeRequest
-
-	// Bind and validate request
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "Invalid request data",
-			Message: err.Error(),
-		})
-		return
-	}
-
-	// Get client IP for security logging
-	clientIP := getClientIP(c)
-	userAgent := c.GetHeader("User-Agent")
-
-	// Security logging
-	fmt.Printf("[USER_AUTH] Code verification attempt from IP: %s, Email: %s, UserAgent: %s\n",
-		clientIP, req.Email, userAgent)
-
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
+// consumeUserVerificationCode checks code against the latest code sent to email and marks it
+// used. It responds with an error and returns false when the code is missing, wrong or spent.
+func (h *Handler) consumeUserVerificationCode(ctx context.Context, c *gin.Context, email, code, clientIP string) bool {
 	// Get verification code from database
-	verificationCode, err := h.DB.GetUserVerificationCode(ctx, req.Email)
+	verificationCode, err := h.DB.GetUserVerificationCode(ctx, email)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			c.JSON(http.StatusUnauthorized, models.ErrorResponse{
 				Error:   "Invalid or expired code",
 				Message: "No valid verification code found",
 			})
-			return
+			return false
 		}
 
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Error:   "Failed to retrieve verification code",
 			Message: err.Error(),
 		})
-		return
+		return false
 	}
 
 	// Check if code has exceeded maximum attempts
@@ -552,11 +690,11 @@ func (h *Handler) UserVerifyCode(c *gin.Context) {
 			Error:   "Maximum attempts exceeded",
 			Message: fmt.Sprintf("Code has exceeded maximum %d attempts", maxAttempts),
 		})
-		return
+		return false
 	}
 
 	// Verify the code
-	if err := bcrypt.CompareHashAndPassword([]byte(verificationCode.CodeHash), []byte(req.Code)); err != nil {
+	if err := bcrypt.CompareHashAndPassword([]byte(verificationCode.CodeHash), []byte(code)); err != nil {
 		// Increment attempt count
 		if updateErr := h.DB.UpdateUserVerificationCodeAttempts(ctx, verificationCode.ID); updateErr != nil {
 			fmt.Printf("Failed to update user attempt count: %v\n", updateErr)
@@ -564,13 +702,13 @@ func (h *Handler) UserVerifyCode(c *gin.Context) {
 
 		// Security logging - failed attempt
 		fmt.Printf("[USER_AUTH] FAILED verification attempt from IP: %s, Email: %s, Attempts: %d\n",
-			clientIP, req.Email, verificationCode.Attempts+1)
+			clientIP, email, verificationCode.Attempts+1)
 
 		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
 			Error:   "Invalid verification code",
 			Message: "The provided code is incorrect",
 		})
-		return
+		return false
 	}
 
 	// Mark code as used
@@ -579,60 +717,8 @@ func (h *Handler) UserVerifyCode(c *gin.Context) {
 			Error:   "Failed to mark code as used",
 			Message: err.Error(),
 		})
-		return
-	}
-
-	// Check if user exists, if not auto-register
-	user, err := h.DB.GetUserByEmail(ctx, req.Email)
-	if err != nil {
-		if err == pgx.ErrNoRows {
-			// Auto-register new user
-			user, err = h.DB.CreateUserFromEmail(ctx, req.Email)
-			if err != nil {
-				c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-					Error:   "Failed to create user account",
-					Message: err.Error(),
-				})
-				return
-			}
-			fmt.Printf("[USER_AUTH] Auto-registered new user: %s\n", req.Email)
-		} else {
-			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-				Error:   "Failed to retrieve user",
-				Message: err.Error(),
-			})
-			return
-		}
+		return false
 	}
 
-	// Update last login timestamp
-	if err := h.DB.UpdateLastLogin(ctx, user.ID); err != nil {
-		// Log the error but don't fail the login
-		fmt.Printf("Failed to update last login for user %s: %v\n", user.ID, err)
-	}
-
-	// Generate JWT token
-	token, err := h.generateJWTToken(user.ID, user.Email)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "Failed to generate token",
-			Message: err.Error(),
-		})
-		return
-	}
-
-	// Calculate token expiration
-	expirationHours := getEnvInt("JWT_EXPIRATION_HOURS", 24)
-	tokenExpiresAt := time.Now().Add(time.Duration(expirationHours) * time.Hour)
-
-	// Security logging - successful authentication
-	fmt.Printf("[USER_AUTH] SUCCESSFUL authentication for %s from IP: %s, Token expires: %s\n",
-		req.Email, clientIP, tokenExpiresAt.Format("2006-01-02 15:04:05"))
-
-	// Return success response
-	c.JSON(http.StatusOK, models.VerifyUserCodeResponse{
-		Token:     token,
-		ExpiresAt: tokenExpiresAt,
-		User:      *user,
-	})
+	return true
 }
@@ -0,0 +1,66 @@
+package api
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/expomadeinworld/madeinworld/auth-service/internal/models"
+	"github.com/expomadeinworld/madeinworld/auth-service/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// InternalAPIKeyMiddleware restricts service-to-service endpoints to callers that present
+// the shared INTERNAL_API_KEY in the X-Internal-API-Key header
+func InternalAPIKeyMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		apiKey := os.Getenv("INTERNAL_API_KEY")
+		if apiKey == "" {
+			c.JSON(http.StatusServiceUnavailable, models.ErrorResponse{
+				Error:   "Internal API disabled",
+				Message: "INTERNAL_API_KEY is not configured",
+			})
+			c.Abort()
+			return
+		}
+
+		if subtle.ConstantTimeCompare([]byte(c.GetHeader("X-Internal-API-Key")), []byte(apiKey)) != 1 {
+			c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+				Error:   "Invalid internal API key",
+				Message: "A valid X-Internal-API-Key header is required",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// SendCartRecoveryEmail sends a cart recovery email on behalf of the order service
+func (h *Handler) SendCartRecoveryEmail(c *gin.Context) {
+	var req models.CartRecoveryEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request data",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	emailService := services.NewEmailService()
+	if err := emailService.SendCartRecoveryEmail(req); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to send cart recovery email",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	fmt.Printf("[INTERNAL] Cart recovery email sent to %s\n", req.Email)
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: "Cart recovery email sent successfully",
+	})
+}
@@ -0,0 +1,315 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/expomadeinworld/madeinworld/auth-service/internal/db"
+	"github.com/expomadeinworld/madeinworld/auth-service/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+// localePattern accepts language tags such as "en", "it" or "zh-CN"
+var localePattern = regexp.MustCompile(`^[a-z]{2,3}(-[A-Za-z0-9]{2,8})*$`)
+
+// GetMe handles GET /api/me
+func (h *Handler) GetMe(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	profile, ok := h.loadOwnProfile(ctx, c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+// UpdateMe handles PATCH /api/me
+func (h *Handler) UpdateMe(c *gin.Context) {
+	var req models.UpdateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request data",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if req.Locale != nil && *req.Locale != "" && !localePattern.MatchString(*req.Locale) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid locale",
+			Message: "locale must be a language tag such as en or zh-CN",
+		})
+		return
+	}
+	if req.AvatarURL != nil && *req.AvatarURL != "" {
+		if u, err := url.ParseRequestURI(*req.AvatarURL); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "Invalid avatar URL",
+				Message: "avatar_url must be an http or https URL",
+			})
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	profile, ok := h.loadOwnProfile(ctx, c)
+	if !ok {
+		return
+	}
+	if profile.Status != models.AccountStatusActive {
+		respondAccountNotActive(c, profile.Status, nil)
+		return
+	}
+
+	if err := h.DB.UpdateUserProfile(ctx, profile.ID, req); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to update profile",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	h.respondOwnProfile(ctx, c, profile.ID)
+}
+
+// RequestEmailChange handles POST /api/me/email. The code is sent to the new address, which
+// replaces the current one once the user confirms it with POST /api/me/email/verify.
+func (h *Handler) RequestEmailChange(c *gin.Context) {
+	var req models.ChangeEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request data",
+			Message: err.Error(),
+		})
+		return
+	}
+	newEmail := strings.TrimSpace(req.Email)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	profile, ok := h.loadOwnProfile(ctx, c)
+	if !ok {
+		return
+	}
+	if profile.Status != models.AccountStatusActive {
+		respondAccountNotActive(c, profile.Status, nil)
+		return
+	}
+
+	if strings.EqualFold(newEmail, profile.Email) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Email unchanged",
+			Message: "The new email is the same as the current one",
+		})
+		return
+	}
+
+	taken, err := h.DB.IsEmailTaken(ctx, newEmail, profile.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to check email",
+			Message: err.Error(),
+		})
+		return
+	}
+	if taken {
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error:   "Email already exists",
+			Message: "A user with this email address already exists",
+		})
+		return
+	}
+
+	if err := h.DB.SetPendingEmail(ctx, profile.ID, newEmail); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to start email change",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	clientIP := getClientIP(c)
+	verificationCode, ok := h.sendUserVerificationCode(ctx, c, newEmail, clientIP)
+	if !ok {
+		return
+	}
+
+	fmt.Printf("[USER_AUTH] Email change code sent for user %s to %s from IP: %s\n", profile.ID, newEmail, clientIP)
+
+	c.JSON(http.StatusAccepted, models.SendUserVerificationResponse{
+		Message:   "Verification code sent to the new email",
+		ExpiresAt: verificationCode.ExpiresAt,
+	})
+}
+
+// ConfirmEmailChange handles POST /api/me/email/verify. The token carries the email, so a new
+// token is returned along with the updated profile.
+func (h *Handler) ConfirmEmailChange(c *gin.Context) {
+	var req models.ConfirmEmailChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request data",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	profile, ok := h.loadOwnProfile(ctx, c)
+	if !ok {
+		return
+	}
+	if profile.Status != models.AccountStatusActive {
+		respondAccountNotActive(c, profile.Status, nil)
+		return
+	}
+	if profile.PendingEmail == nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "No email change pending",
+			Message: "Request a code for the new email with POST /api/me/email first",
+		})
+		return
+	}
+	newEmail := *profile.PendingEmail
+
+	clientIP := getClientIP(c)
+	if !h.consumeUserVerificationCode(ctx, c, newEmail, req.Code, clientIP) {
+		return
+	}
+
+	if err := h.DB.CompleteEmailChange(ctx, profile.ID, newEmail); err != nil {
+		if isDuplicateEmailError(err) {
+			c.JSON(http.StatusConflict, models.ErrorResponse{
+				Error:   "Email already exists",
+				Message: "A user with this email address already exists",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to change email",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	fmt.Printf("[USER_AUTH] Email changed for user %s from %s to %s from IP: %s\n", profile.ID, profile.Email, newEmail, clientIP)
+
+	token, err := h.generateJWTToken(profile.ID, newEmail)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to generate token",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	updated, err := h.DB.GetUserProfile(ctx, profile.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to retrieve profile",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	expirationHours := getEnvInt("JWT_EXPIRATION_HOURS", 24)
+	c.JSON(http.StatusOK, gin.H{
+		"token":      token,
+		"expires_at": time.Now().Add(time.Duration(expirationHours) * time.Hour),
+		"user":       updated,
+	})
+}
+
+// DeleteMe handles DELETE /api/me. The account is deactivated right away and user-service
+// erases the personal data in the background; orders are kept, anonymized.
+func (h *Handler) DeleteMe(c *gin.Context) {
+	var req struct {
+		Reason string `json:"reason,omitempty"`
+	}
+	// The body is optional
+	_ = c.ShouldBindJSON(&req)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	profile, ok := h.loadOwnProfile(ctx, c)
+	if !ok {
+		return
+	}
+
+	requestID, err := h.DB.RequestAccountDeletion(ctx, profile.ID, strings.TrimSpace(req.Reason))
+	if err != nil {
+		if errors.Is(err, db.ErrErasureAlreadyRequested) {
+			c.JSON(http.StatusConflict, models.ErrorResponse{
+				Error:   "Account deletion already requested",
+				Message: "This account is already being deleted",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to delete account",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	fmt.Printf("[USER_AUTH] Account deletion requested by user %s (erasure request %s)\n", profile.ID, requestID)
+
+	c.JSON(http.StatusAccepted, models.SuccessResponse{
+		Message: "Account deleted; personal data will be erased shortly",
+		Data:    gin.H{"privacy_request_id": requestID},
+	})
+}
+
+// loadOwnProfile loads the profile of the token's user, responding 404 when the token does
+// not belong to a user account (e.g. admin tokens)
+func (h *Handler) loadOwnProfile(ctx context.Context, c *gin.Context) (*models.UserProfile, bool) {
+	userID, _ := c.Get("user_id")
+	userIDStr, _ := userID.(string)
+
+	profile, err := h.DB.GetUserProfile(ctx, userIDStr)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Error:   "User not found",
+				Message: "The token does not belong to a user account",
+			})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to retrieve profile",
+			Message: err.Error(),
+		})
+		return nil, false
+	}
+
+	return profile, true
+}
+
+// respondOwnProfile responds with the current profile of a user
+func (h *Handler) respondOwnProfile(ctx context.Context, c *gin.Context, userID string) {
+	profile, err := h.DB.GetUserProfile(ctx, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to retrieve profile",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
@@ -239,7 +239,7 @@ func (db *Database) CreateUser(ctx context.Context, req models.SignupRequest) (*
 	query := `
 		INSERT INTO users (username, email, password_hash, phone, first_name, last_name)
 		VALUES ($1, $2, $3, $4, $5, $6)
-		RETURNING id, username, email, password_hash, phone, first_name, last_name, created_at, updated_at
+		RETURNING id, username, email, password_hash, phone, first_name, last_name, created_at, updated_at, status
 	`
 
 	err = db.Pool.QueryRow(ctx, query, req.Username, req.Email, string(hashedPassword), req.Phone, req.FirstName, req.LastName).Scan(
@@ -252,6 +252,7 @@ func (db *Database) CreateUser(ctx context.Context, req models.SignupRequest) (*
 		&user.LastName,
 		&user.CreatedAt,
 		&user.UpdatedAt,
+		&user.Status,
 	)
 
 	if err != nil {
@@ -265,7 +266,8 @@ func (db *Database) CreateUser(ctx context.Context, req models.SignupRequest) (*
 func (db *Database) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
 	var user models.User
 	query := `
-		SELECT id, username, email, password_hash, first_name, last_name, created_at, updated_at
+		SELECT id, username, email, COALESCE(password_hash, ''), first_name, last_name, created_at, updated_at,
+		       user_account_status(status, suspended_until), suspended_until
 		FROM users
 		WHERE email = $1
 	`
@@ -279,6 +281,8 @@ func (db *Database) GetUserByEmail(ctx context.Context, email string) (*models.U
 		&user.LastName,
 		&user.CreatedAt,
 		&user.UpdatedAt,
+		&user.Status,
+		&user.SuspendedUntil,
 	)
 
 	if err != nil {
@@ -304,6 +308,58 @@ func (db *Database) UpdateLastLogin(ctx context.Context, userID string) error {
 	return nil
 }
 
+// GetUserAccountStatus returns the current account status of a user and the end of its
+// suspension, if any
+func (db *Database) GetUserAccountStatus(ctx context.Context, userID string) (string, *time.Time, error) {
+	var status string
+	var suspendedUntil *time.Time
+	err := db.Pool.QueryRow(ctx, `
+		SELECT user_account_status(status, suspended_until), suspended_until
+		FROM users
+		WHERE id::text = $1
+	`, userID).Scan(&status, &suspendedUntil)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get user account status: %w", err)
+	}
+	return status, suspendedUntil, nil
+}
+
+// ActivatePendingUser activates an account waiting for email verification once the user
+// proved they own the address, recording the change in the status history
+func (db *Database) ActivatePendingUser(ctx context.Context, userID string) error {
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE users
+		SET status = 'active', status_reason = 'Email verified', status_changed_at = now(),
+		    status_changed_by = 'auth-service', updated_at = now()
+		WHERE id = $1 AND status = 'pending_verification'
+	`, userID)
+	if err != nil {
+		return fmt.Errorf("failed to activate user: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return nil
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO user_status_history (user_id, from_status, to_status, reason, changed_by)
+		VALUES ($1, 'pending_verification', 'active', 'Email verified', 'auth-service')
+	`, userID)
+	if err != nil {
+		return fmt.Errorf("failed to record status change: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
 // ValidatePassword checks if the provided password matches the stored hash
 func (db *Database) ValidatePassword(hashedPassword, password string) error {
 	return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
@@ -784,7 +840,7 @@ func (db *Database) CreateUserFromEmail(ctx context.Context, email string) (*mod
 	query := `
 		INSERT INTO users (username, email, first_name, last_name, created_at, updated_at)
 		VALUES ($1, $2, $3, $4, now(), now())
-		RETURNING id, username, email, first_name, last_name, created_at, updated_at
+		RETURNING id, username, email, first_name, last_name, created_at, updated_at, status
 	`
 
 	err := db.Pool.QueryRow(ctx, query, user.Username, user.Email, user.FirstName, user.LastName).Scan(
@@ -795,6 +851,7 @@ func (db *Database) CreateUserFromEmail(ctx context.Context, email string) (*mod
 		&user.LastName,
 		&user.CreatedAt,
 		&user.UpdatedAt,
+		&user.Status,
 	)
 
 	if err != nil {
@@ -0,0 +1,215 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/expomadeinworld/madeinworld/auth-service/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrErasureAlreadyRequested is returned when a user asks to delete an account whose erasure
+// is already queued or done
+var ErrErasureAlreadyRequested = errors.New("account deletion already requested")
+
+// GetUserProfile retrieves the self-service profile of a user
+func (db *Database) GetUserProfile(ctx context.Context, userID string) (*models.UserProfile, error) {
+	var profile models.UserProfile
+	query := `
+		SELECT id, username, email, pending_email, phone, first_name, last_name, locale, avatar_url,
+		       marketing_consent, marketing_consent_updated_at, user_account_status(status, suspended_until),
+		       last_login, created_at, updated_at
+		FROM users
+		WHERE id::text = $1
+	`
+
+	err := db.Pool.QueryRow(ctx, query, userID).Scan(
+		&profile.ID,
+		&profile.Username,
+		&profile.Email,
+		&profile.PendingEmail,
+		&profile.Phone,
+		&profile.FirstName,
+		&profile.LastName,
+		&profile.Locale,
+		&profile.AvatarURL,
+		&profile.MarketingConsent,
+		&profile.MarketingConsentUpdatedAt,
+		&profile.Status,
+		&profile.LastLogin,
+		&profile.CreatedAt,
+		&profile.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user profile: %w", err)
+	}
+
+	return &profile, nil
+}
+
+// UpdateUserProfile applies the fields set in req to a user's profile. Empty strings clear
+// optional fields.
+func (db *Database) UpdateUserProfile(ctx context.Context, userID string, req models.UpdateProfileRequest) error {
+	var setParts []string
+	var args []interface{}
+	argIndex := 1
+
+	optionalFields := []struct {
+		column string
+		value  *string
+	}{
+		{"first_name", req.FirstName},
+		{"last_name", req.LastName},
+		{"phone", req.Phone},
+		{"locale", req.Locale},
+		{"avatar_url", req.AvatarURL},
+	}
+	for _, field := range optionalFields {
+		if field.value == nil {
+			continue
+		}
+		setParts = append(setParts, fmt.Sprintf("%s = NULLIF($%d, '')", field.column, argIndex))
+		args = append(args, strings.TrimSpace(*field.value))
+		argIndex++
+	}
+
+	if req.MarketingConsent != nil {
+		// Only a change of mind moves the consent timestamp
+		setParts = append(setParts, fmt.Sprintf(
+			"marketing_consent_updated_at = CASE WHEN marketing_consent IS DISTINCT FROM $%d THEN now() ELSE marketing_consent_updated_at END",
+			argIndex))
+		setParts = append(setParts, fmt.Sprintf("marketing_consent = $%d", argIndex))
+		args = append(args, *req.MarketingConsent)
+		argIndex++
+	}
+
+	if len(setParts) == 0 {
+		return nil
+	}
+
+	setParts = append(setParts, "updated_at = now()")
+	query := fmt.Sprintf("UPDATE users SET %s WHERE id = $%d", strings.Join(setParts, ", "), argIndex)
+	args = append(args, userID)
+
+	tag, err := db.Pool.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to update user profile: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("failed to update user profile: %w", pgx.ErrNoRows)
+	}
+
+	return nil
+}
+
+// IsEmailTaken reports whether another user already signs in with email
+func (db *Database) IsEmailTaken(ctx context.Context, email, exceptUserID string) (bool, error) {
+	var taken bool
+	err := db.Pool.QueryRow(ctx,
+		"SELECT EXISTS (SELECT 1 FROM users WHERE lower(email) = lower($1) AND id::text <> $2)",
+		email, exceptUserID).Scan(&taken)
+	if err != nil {
+		return false, fmt.Errorf("failed to check email: %w", err)
+	}
+	return taken, nil
+}
+
+// SetPendingEmail records the new email a user wants to move to until they confirm it
+func (db *Database) SetPendingEmail(ctx context.Context, userID, email string) error {
+	_, err := db.Pool.Exec(ctx, `
+		UPDATE users
+		SET pending_email = $2, pending_email_requested_at = now(), updated_at = now()
+		WHERE id = $1
+	`, userID, email)
+	if err != nil {
+		return fmt.Errorf("failed to set pending email: %w", err)
+	}
+	return nil
+}
+
+// CompleteEmailChange replaces the email of a user with their confirmed pending email
+func (db *Database) CompleteEmailChange(ctx context.Context, userID, email string) error {
+	tag, err := db.Pool.Exec(ctx, `
+		UPDATE users
+		SET email = pending_email, pending_email = NULL, pending_email_requested_at = NULL, updated_at = now()
+		WHERE id = $1 AND pending_email = $2
+	`, userID, email)
+	if err != nil {
+		return fmt.Errorf("failed to change email: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("failed to change email: %w", pgx.ErrNoRows)
+	}
+	return nil
+}
+
+// RequestAccountDeletion deactivates a user's account right away and queues the erasure of
+// their personal data, which user-service carries out (orders are kept, anonymized).
+// The user is recorded as 'self' rather than by email, which the erasure removes.
+// It returns the ID of the erasure request.
+func (db *Database) RequestAccountDeletion(ctx context.Context, userID, reason string) (string, error) {
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var fromStatus string
+	var erased bool
+	err = tx.QueryRow(ctx, `
+		SELECT user_account_status(status, suspended_until), erased_at IS NOT NULL
+		FROM users WHERE id = $1 FOR UPDATE
+	`, userID).Scan(&fromStatus, &erased)
+	if err != nil {
+		return "", fmt.Errorf("failed to get user: %w", err)
+	}
+	if erased {
+		return "", ErrErasureAlreadyRequested
+	}
+
+	var reasonValue *string
+	if reason != "" {
+		reasonValue = &reason
+	}
+
+	var requestID string
+	err = tx.QueryRow(ctx, `
+		INSERT INTO privacy_requests (user_id, request_type, source, requested_by, reason)
+		VALUES ($1, 'erasure', 'self', 'self', $2)
+		ON CONFLICT (user_id, request_type) WHERE status IN ('pending', 'processing') DO NOTHING
+		RETURNING id
+	`, userID, reasonValue).Scan(&requestID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", ErrErasureAlreadyRequested
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to queue erasure: %w", err)
+	}
+
+	if fromStatus != models.AccountStatusDeactivated {
+		_, err = tx.Exec(ctx, `
+			UPDATE users
+			SET status = 'deactivated', status_reason = 'Account deleted by user', suspended_until = NULL,
+			    status_changed_at = now(), status_changed_by = 'self', updated_at = now()
+			WHERE id = $1
+		`, userID)
+		if err != nil {
+			return "", fmt.Errorf("failed to deactivate user: %w", err)
+		}
+
+		_, err = tx.Exec(ctx, `
+			INSERT INTO user_status_history (user_id, from_status, to_status, reason, changed_by)
+			VALUES ($1, $2, 'deactivated', 'Account deleted by user', 'self')
+		`, userID, fromStatus)
+		if err != nil {
+			return "", fmt.Errorf("failed to record status change: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return "", fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return requestID, nil
+}
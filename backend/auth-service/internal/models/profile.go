@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+)
+
+// UserProfile is the profile a user reads and edits through /api/me
+type UserProfile struct {
+	ID                        string     `json:"id"`
+	Username                  string     `json:"username"`
+	Email                     string     `json:"email"`
+	PendingEmail              *string    `json:"pending_email,omitempty"`
+	Phone                     *string    `json:"phone,omitempty"`
+	FirstName                 *string    `json:"first_name,omitempty"`
+	LastName                  *string    `json:"last_name,omitempty"`
+	Locale                    *string    `json:"locale,omitempty"`
+	AvatarURL                 *string    `json:"avatar_url,omitempty"`
+	MarketingConsent          bool       `json:"marketing_consent"`
+	MarketingConsentUpdatedAt *time.Time `json:"marketing_consent_updated_at,omitempty"`
+	Status                    string     `json:"status"`
+	LastLogin                 *time.Time `json:"last_login,omitempty"`
+	CreatedAt                 time.Time  `json:"created_at"`
+	UpdatedAt                 time.Time  `json:"updated_at"`
+}
+
+// UpdateProfileRequest represents the request payload for PATCH /api/me. Omitted fields are
+// left unchanged; an empty string clears an optional field.
+type UpdateProfileRequest struct {
+	FirstName        *string `json:"first_name,omitempty" binding:"omitempty,max=100"`
+	LastName         *string `json:"last_name,omitempty" binding:"omitempty,max=100"`
+	Phone            *string `json:"phone,omitempty" binding:"omitempty,max=20"`
+	Locale           *string `json:"locale,omitempty" binding:"omitempty,max=10"`
+	AvatarURL        *string `json:"avatar_url,omitempty" binding:"omitempty,max=500"`
+	MarketingConsent *bool   `json:"marketing_consent,omitempty"`
+}
+
+// ChangeEmailRequest represents the request to move an account to a new email
+type ChangeEmailRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// ConfirmEmailChangeRequest represents the code sent to the new email
+type ConfirmEmailChangeRequest struct {
+	Code string `json:"code" binding:"required,len=6"`
+}
@@ -15,8 +15,20 @@ type User struct {
 	LastName     *string   `json:"last_name,omitempty" db:"last_name"`
 	CreatedAt    time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+
+	// Account status; an expired suspension reads as active
+	Status         string     `json:"status" db:"status"`
+	SuspendedUntil *time.Time `json:"suspended_until,omitempty" db:"suspended_until"`
 }
 
+// Account statuses (users.status); only active accounts can sign in
+const (
+	AccountStatusActive              = "active"
+	AccountStatusSuspended           = "suspended"
+	AccountStatusDeactivated         = "deactivated"
+	AccountStatusPendingVerification = "pending_verification"
+)
+
 // SignupRequest represents the request payload for user registration
 type SignupRequest struct {
 	Username  string  `json:"username" binding:"required,min=3"`
@@ -94,3 +106,12 @@ type VerifyUserCodeResponse struct {
 	ExpiresAt time.Time `json:"expires_at"`
 	User      User      `json:"user"`
 }
+
+// CartRecoveryEmailRequest represents a request from another service to send a cart recovery email
+type CartRecoveryEmailRequest struct {
+	Email       string  `json:"email" binding:"required,email"`
+	FullName    string  `json:"full_name"`
+	ItemCount   int     `json:"item_count" binding:"min=1"`
+	CartValue   float64 `json:"cart_value"`
+	RecoveryURL string  `json:"recovery_url" binding:"required,url"`
+}
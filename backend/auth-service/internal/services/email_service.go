@@ -4,6 +4,7 @@ import (
 	"crypto/rand"
 	"crypto/tls"
 	"fmt"
+	"html"
 	"math/big"
 	"net/smtp"
 	"os"
@@ -48,6 +49,14 @@ func (e *EmailService) SendUserVerificationCode(email string, data models.EmailV
 	return e.sendEmail(email, subject, body)
 }
 
+// SendCartRecoveryEmail reminds a user about the items left in their cart
+func (e *EmailService) SendCartRecoveryEmail(data models.CartRecoveryEmailRequest) error {
+	subject := "Made in World - You left something in your cart"
+	body := e.generateCartRecoveryEmailHTML(data)
+
+	return e.sendEmail(data.Email, subject, body)
+}
+
 // generateRandomID generates a random string for Message-ID
 func generateRandomID() string {
 	const charset = "abcdefghijklmnopqrstuvwxyz0123456789"
@@ -411,6 +420,135 @@ func (e *EmailService) generateUserEmailHTML(data models.EmailVerificationData)
 	)
 }
 
+// generateCartRecoveryEmailHTML creates the HTML template for cart recovery emails
+func (e *EmailService) generateCartRecoveryEmailHTML(data models.CartRecoveryEmailRequest) string {
+	greeting := "Hello!"
+	if data.FullName != "" {
+		greeting = fmt.Sprintf("Hello %s,", html.EscapeString(data.FullName))
+	}
+
+	itemLabel := "items"
+	if data.ItemCount == 1 {
+		itemLabel = "item"
+	}
+
+	return fmt.Sprintf(`
+<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>Made in World - Your Cart</title>
+    <style>
+        body {
+            font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, Oxygen, Ubuntu, Cantarell, sans-serif;
+            line-height: 1.6;
+            color: #333;
+            max-width: 600px;
+            margin: 0 auto;
+            padding: 20px;
+            background-color: #f8f9fa;
+        }
+        .container {
+            background: white;
+            border-radius: 12px;
+            padding: 40px;
+            box-shadow: 0 4px 6px rgba(0, 0, 0, 0.1);
+            border: 1px solid #e9ecef;
+        }
+        .header {
+            text-align: center;
+            margin-bottom: 30px;
+            padding-bottom: 20px;
+            border-bottom: 2px solid #f1f3f4;
+        }
+        .logo {
+            font-size: 28px;
+            font-weight: bold;
+            color: #dc3545;
+            margin-bottom: 10px;
+        }
+        .subtitle {
+            color: #6c757d;
+            font-size: 16px;
+        }
+        .summary {
+            background: #f8f9fa;
+            border-radius: 8px;
+            padding: 20px;
+            margin: 25px 0;
+            text-align: center;
+            font-size: 18px;
+        }
+        .button {
+            display: inline-block;
+            background: #dc3545;
+            color: white;
+            padding: 12px 30px;
+            text-decoration: none;
+            border-radius: 6px;
+            font-weight: bold;
+            margin: 20px 0;
+        }
+        .footer {
+            margin-top: 40px;
+            padding-top: 20px;
+            border-top: 1px solid #e9ecef;
+            text-align: center;
+            color: #6c757d;
+            font-size: 14px;
+        }
+        .footer a {
+            color: #dc3545;
+            text-decoration: none;
+        }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <div class="logo">🌍 Made in World</div>
+            <div class="subtitle">Your cart is waiting for you</div>
+        </div>
+
+        <p>%s</p>
+
+        <p>You still have items in your Made in World cart. They are saved for you, but stock is limited.</p>
+
+        <div class="summary">
+            <strong>%d %s</strong> · €%.2f
+        </div>
+
+        <p style="text-align: center;">
+            <a class="button" href="%s">Return to your cart</a>
+        </p>
+
+        <div class="footer">
+            <p><strong>Made in World Mobile App</strong><br>
+            Please do not reply to this email.</p>
+
+            <p>Need help? Contact our support team at <a href="mailto:support@expomadeinworld.com">support@expomadeinworld.com</a></p>
+
+            <p style="color: #999; font-size: 12px;">
+                <strong>Made in World</strong><br>
+                Business Address: Frankfurt, Germany<br>
+                This email was sent to: %s<br>
+                <a href="mailto:unsubscribe@expomadeinworld.com" style="color: #999;">Unsubscribe</a> |
+                <a href="mailto:support@expomadeinworld.com" style="color: #999;">Support</a>
+            </p>
+        </div>
+    </div>
+</body>
+</html>`,
+		greeting,
+		data.ItemCount,
+		itemLabel,
+		data.CartValue,
+		html.EscapeString(data.RecoveryURL),
+		html.EscapeString(data.Email),
+	)
+}
+
 // TestConnection tests the SMTP connection
 func (e *EmailService) TestConnection() error {
 	// Test TLS connection
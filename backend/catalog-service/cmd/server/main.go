@@ -8,6 +8,7 @@ import (
 
 	"github.com/expomadeinworld/madeinworld/catalog-service/internal/api"
 	"github.com/expomadeinworld/madeinworld/catalog-service/internal/db"
+	"github.com/expomadeinworld/madeinworld/catalog-service/internal/services"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 )
@@ -32,6 +33,15 @@ func main() {
 		defer database.Close()
 	}
 
+	// Start the automatic low-stock trigger for stock requests
+	if database != nil {
+		lowStockService := services.NewLowStockService(database, services.LowStockIntervalFromEnv())
+		lowStockService.Start()
+		defer lowStockService.Stop()
+	} else {
+		log.Println("[WARN] Skipping low stock service start; database unavailable at startup")
+	}
+
 	// Initialize handlers
 	handler := api.NewHandler(database)
 
@@ -111,12 +121,98 @@ func setupRouter(handler *api.Handler) *gin.Engine {
 		v1.DELETE("/subcategories/:id", handler.DeleteSubcategory)
 		v1.POST("/subcategories/:id/image", handler.UploadSubcategoryImage)
 
+		// Manufacturer endpoints
+		v1.GET("/manufacturers", handler.GetManufacturers)
+		v1.GET("/manufacturers/:id", handler.GetManufacturer)
+		v1.POST("/manufacturers", handler.CreateManufacturer)
+		v1.PUT("/manufacturers/:id", handler.UpdateManufacturer)
+		v1.DELETE("/manufacturers/:id", handler.DeleteManufacturer)
+		v1.PUT("/manufacturers/:id/user", api.AuthMiddleware(), handler.AccountStatusMiddleware(), api.AdminMiddleware(), handler.LinkManufacturerUser)
+
+		// Manufacturer price change approvals (admin)
+		v1.GET("/price-change-requests", api.AuthMiddleware(), handler.AccountStatusMiddleware(), api.AdminMiddleware(), handler.GetPriceChangeRequests)
+		v1.POST("/price-change-requests/:id/approve", api.AuthMiddleware(), handler.AccountStatusMiddleware(), api.AdminMiddleware(), handler.ApprovePriceChangeRequest)
+		v1.POST("/price-change-requests/:id/reject", api.AuthMiddleware(), handler.AccountStatusMiddleware(), api.AdminMiddleware(), handler.RejectPriceChangeRequest)
+
+		// Stock request endpoints (admin)
+		v1.GET("/stock-requests", api.AuthMiddleware(), handler.AccountStatusMiddleware(), api.AdminMiddleware(), handler.GetStockRequests)
+		v1.POST("/stock-requests", api.AuthMiddleware(), handler.AccountStatusMiddleware(), api.AdminMiddleware(), handler.CreateStockRequest)
+		v1.POST("/stock-requests/low-stock-check", api.AuthMiddleware(), handler.AccountStatusMiddleware(), api.AdminMiddleware(), handler.RunLowStockCheck)
+		v1.GET("/stock-requests/:id", api.AuthMiddleware(), handler.AccountStatusMiddleware(), api.AdminMiddleware(), handler.GetStockRequest)
+		v1.PUT("/stock-requests/:id/status", api.AuthMiddleware(), handler.AccountStatusMiddleware(), api.AdminMiddleware(), handler.UpdateStockRequestStatus)
+		v1.POST("/stock-requests/:id/verify", api.AuthMiddleware(), handler.AccountStatusMiddleware(), api.AdminMiddleware(), handler.VerifyStockRequest)
+
+		// Stock thresholds and low stock alerts (admin)
+		v1.GET("/stock-thresholds", api.AuthMiddleware(), handler.AccountStatusMiddleware(), api.AdminMiddleware(), handler.GetStockThresholds)
+		v1.PUT("/stock-thresholds", api.AuthMiddleware(), handler.AccountStatusMiddleware(), api.AdminMiddleware(), handler.UpsertStockThreshold)
+		v1.DELETE("/stock-thresholds/:id", api.AuthMiddleware(), handler.AccountStatusMiddleware(), api.AdminMiddleware(), handler.DeleteStockThreshold)
+		v1.GET("/low-stock-alerts", api.AuthMiddleware(), handler.AccountStatusMiddleware(), api.AdminMiddleware(), handler.GetLowStockAlerts)
+		v1.POST("/low-stock-alerts/:id/acknowledge", api.AuthMiddleware(), handler.AccountStatusMiddleware(), api.AdminMiddleware(), handler.AcknowledgeLowStockAlert)
+
+		// Inventory endpoints (admin); every change is recorded as a stock movement
+		v1.GET("/inventory", api.AuthMiddleware(), handler.AccountStatusMiddleware(), api.AdminMiddleware(), handler.GetInventory)
+		v1.POST("/inventory/adjustments", api.AuthMiddleware(), handler.AccountStatusMiddleware(), api.AdminMiddleware(), handler.AdjustStock)
+		v1.POST("/inventory/transfers", api.AuthMiddleware(), handler.AccountStatusMiddleware(), api.AdminMiddleware(), handler.TransferStock)
+		v1.POST("/inventory/counts", api.AuthMiddleware(), handler.AccountStatusMiddleware(), api.AdminMiddleware(), handler.CountStock)
+		v1.GET("/inventory/movements", api.AuthMiddleware(), handler.AccountStatusMiddleware(), api.AdminMiddleware(), handler.GetStockMovements)
+		v1.GET("/inventory/reconciliation", api.AuthMiddleware(), handler.AccountStatusMiddleware(), api.AdminMiddleware(), handler.ReconcileInventory)
+
+		// Mini-app registry endpoints
+		v1.GET("/mini-apps", handler.GetMiniApps)
+		v1.GET("/mini-apps/:code", handler.GetMiniApp)
+		v1.POST("/mini-apps", api.AuthMiddleware(), handler.AccountStatusMiddleware(), api.AdminMiddleware(), handler.CreateMiniApp)
+		v1.PUT("/mini-apps/:code", api.AuthMiddleware(), handler.AccountStatusMiddleware(), api.AdminMiddleware(), handler.UpdateMiniApp)
+
 		// Store endpoints
+		v1.GET("/store-types", handler.GetStoreTypes)
 		v1.GET("/stores", handler.GetStores)
+		v1.GET("/stores/nearby", handler.GetNearbyStores)
+		v1.GET("/stores/in-bounds", handler.GetStoresInBounds)
 		v1.POST("/stores", handler.CreateStore)
 		v1.PUT("/stores/:id", handler.UpdateStore)
 		v1.DELETE("/stores/:id", handler.DeleteStore)
 		v1.POST("/stores/:id/image", handler.UploadStoreImage)
+		v1.GET("/stores/:id/hours", handler.GetStoreHours)
+		v1.PUT("/stores/:id/hours", api.AuthMiddleware(), handler.AccountStatusMiddleware(), api.AdminMiddleware(), handler.UpdateStoreHours)
+		v1.POST("/stores/:id/hours/exceptions", api.AuthMiddleware(), handler.AccountStatusMiddleware(), api.AdminMiddleware(), handler.CreateStoreHoursException)
+		v1.DELETE("/stores/:id/hours/exceptions/:exception_id", api.AuthMiddleware(), handler.AccountStatusMiddleware(), api.AdminMiddleware(), handler.DeleteStoreHoursException)
+		v1.GET("/stores/:id/product-overrides", api.AuthMiddleware(), handler.AccountStatusMiddleware(), api.AdminMiddleware(), handler.GetStoreProductOverrides)
+		v1.PUT("/stores/:id/product-overrides/:product_id", api.AuthMiddleware(), handler.AccountStatusMiddleware(), api.AdminMiddleware(), handler.UpsertStoreProductOverride)
+		v1.DELETE("/stores/:id/product-overrides/:product_id", api.AuthMiddleware(), handler.AccountStatusMiddleware(), api.AdminMiddleware(), handler.DeleteStoreProductOverride)
+	}
+
+	// Manufacturer self-service portal, scoped to the manufacturer linked to the caller
+	manufacturerGroup := router.Group("/api/v1/manufacturer")
+	manufacturerGroup.Use(api.AuthMiddleware())
+	manufacturerGroup.Use(handler.AccountStatusMiddleware())
+	manufacturerGroup.Use(handler.ManufacturerMiddleware())
+	{
+		manufacturerGroup.GET("/me", handler.GetMyManufacturer)
+		manufacturerGroup.GET("/products", handler.GetMyProducts)
+		manufacturerGroup.GET("/products/:id", handler.GetMyProduct)
+		manufacturerGroup.PUT("/products/:id", handler.UpdateMyProduct)
+		manufacturerGroup.POST("/products/:id/images", handler.UploadMyProductImages)
+		manufacturerGroup.POST("/products/:id/price-requests", handler.CreateMyPriceChangeRequest)
+		manufacturerGroup.GET("/price-requests", handler.GetMyPriceChangeRequests)
+		manufacturerGroup.POST("/price-change-requests/:id/withdraw", handler.WithdrawMyPriceChangeRequest)
+		manufacturerGroup.GET("/stock-requests", handler.GetMyStockRequests)
+		manufacturerGroup.POST("/stock-requests/:id/confirm", handler.ConfirmMyStockRequest)
+		manufacturerGroup.POST("/stock-requests/:id/ready", handler.MarkMyStockRequestReady)
+		manufacturerGroup.GET("/low-stock-alerts", handler.GetMyLowStockAlerts)
+	}
+
+	// Store operator routes (Partner and Admin accounts) for replenishment
+	operatorGroup := router.Group("/api/v1/operator")
+	operatorGroup.Use(api.AuthMiddleware())
+	operatorGroup.Use(handler.AccountStatusMiddleware())
+	operatorGroup.Use(handler.RoleMiddleware("Partner", "Admin"))
+	operatorGroup.Use(handler.PartnerScopeMiddleware())
+	{
+		operatorGroup.GET("/stock-requests", handler.GetStockRequests)
+		operatorGroup.POST("/stock-requests", handler.CreateStockRequest)
+		operatorGroup.GET("/stock-requests/:id", handler.GetStockRequest)
+		operatorGroup.POST("/stock-requests/:id/cancel", handler.CancelStockRequest)
+		operatorGroup.POST("/stock-requests/:id/verify", handler.VerifyStockRequest)
 	}
 
 	// Root endpoint for basic info
@@ -0,0 +1,254 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/jackc/pgx/v5"
+)
+
+// AuthMiddleware validates JWT tokens issued by auth-service
+func AuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authHeader := c.GetHeader("Authorization")
+		if authHeader == "" {
+			abortWithAuthError(c, http.StatusUnauthorized, "Authorization header required",
+				"Please provide a valid authorization token", "AUTH_REQUIRED")
+			return
+		}
+
+		// Extract token from "Bearer <token>"
+		tokenParts := strings.Split(authHeader, " ")
+		if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
+			abortWithAuthError(c, http.StatusUnauthorized, "Invalid authorization format",
+				"Authorization header must be in format 'Bearer <token>'", "INVALID_AUTH_FORMAT")
+			return
+		}
+
+		secret := os.Getenv("JWT_SECRET")
+		if secret == "" {
+			secret = "your-secret-key-change-this-in-production"
+		}
+
+		token, err := jwt.Parse(tokenParts[1], func(token *jwt.Token) (interface{}, error) {
+			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+				return nil, jwt.ErrSignatureInvalid
+			}
+			return []byte(secret), nil
+		})
+
+		if err != nil || !token.Valid {
+			abortWithAuthError(c, http.StatusUnauthorized, "Invalid token",
+				"The provided token is invalid or expired", "INVALID_TOKEN")
+			return
+		}
+
+		// Extract claims
+		if claims, ok := token.Claims.(jwt.MapClaims); ok {
+			c.Set("user_id", claims["user_id"])
+			c.Set("email", claims["email"])
+		}
+
+		c.Next()
+	}
+}
+
+// GetUserID extracts user ID from the JWT token claims
+func GetUserID(c *gin.Context) (string, bool) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		return "", false
+	}
+
+	userIDStr, ok := userID.(string)
+	return userIDStr, ok
+}
+
+// AdminMiddleware ensures the request comes from the admin panel
+func AdminMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("X-Admin-Request") != "true" {
+			abortWithAuthError(c, http.StatusForbidden, "Admin access required",
+				"This endpoint requires admin privileges", "ADMIN_REQUIRED")
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// AccountStatusMiddleware rejects tokens of users whose account is no longer active
+// (suspended, deactivated or pending verification). Tokens that do not belong to a users
+// row, such as admin panel tokens, pass.
+func (h *Handler) AccountStatusMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := GetUserID(c)
+		if !ok {
+			abortWithAuthError(c, http.StatusUnauthorized, "Invalid user",
+				"Could not identify user from token", "INVALID_USER")
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		status, err := h.db.GetUserAccountStatus(ctx, userID)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			log.Printf("Account status lookup failed for user %s: %v", userID, err)
+			abortWithAuthError(c, http.StatusInternalServerError, "Failed to check account status",
+				"The account status could not be verified", "ACCOUNT_STATUS_UNAVAILABLE")
+			return
+		}
+		if err == nil && status != "active" {
+			abortWithAuthError(c, http.StatusForbidden, "Account "+status,
+				"This account is "+status, "ACCOUNT_NOT_ACTIVE")
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// ManufacturerMiddleware resolves the manufacturer linked to the authenticated user.
+// Only users with the Manufacturer role that are linked to a manufacturers row pass.
+func (h *Handler) ManufacturerMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := GetUserID(c)
+		if !ok {
+			abortWithAuthError(c, http.StatusUnauthorized, "Invalid user",
+				"Could not identify user from token", "INVALID_USER")
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		manufacturer, err := h.db.GetManufacturerByUserID(ctx, userID)
+		if err != nil {
+			log.Printf("Manufacturer lookup failed for user %s: %v", userID, err)
+			abortWithAuthError(c, http.StatusForbidden, "Manufacturer access required",
+				"This endpoint requires a Manufacturer account linked to a manufacturer", "MANUFACTURER_ACCESS_REQUIRED")
+			return
+		}
+
+		c.Set("manufacturer_id", manufacturer.ID)
+		c.Set("manufacturer", manufacturer)
+		c.Next()
+	}
+}
+
+// RoleMiddleware allows only authenticated users whose account holds one of the given roles
+func (h *Handler) RoleMiddleware(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := GetUserID(c)
+		if !ok {
+			abortWithAuthError(c, http.StatusUnauthorized, "Invalid user",
+				"Could not identify user from token", "INVALID_USER")
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		role, err := h.db.GetUserRole(ctx, userID)
+		if err != nil {
+			log.Printf("Role lookup failed for user %s: %v", userID, err)
+			abortWithAuthError(c, http.StatusForbidden, "Access denied",
+				"The role of this account could not be verified", "ROLE_REQUIRED")
+			return
+		}
+
+		for _, allowed := range roles {
+			if role == allowed {
+				c.Set("user_role", role)
+				c.Next()
+				return
+			}
+		}
+
+		abortWithAuthError(c, http.StatusForbidden, "Role required",
+			"This endpoint requires one of the roles: "+strings.Join(roles, ", "), "ROLE_REQUIRED")
+	}
+}
+
+// PartnerScopeMiddleware limits Partner-role users to the stores of their partner assignment:
+// the stores of their region and those assigned to them individually. It follows
+// RoleMiddleware; other roles pass unscoped. Handlers read the scope with partnerStoreIDs.
+func (h *Handler) PartnerScopeMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("user_role") != "Partner" {
+			c.Next()
+			return
+		}
+
+		userID, _ := GetUserID(c)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		storeIDs, err := h.db.GetPartnerStoreIDs(ctx, userID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				abortWithAuthError(c, http.StatusForbidden, "Partner access required",
+					"This account has the Partner role but no partner assignment", "PARTNER_ASSIGNMENT_REQUIRED")
+			} else {
+				log.Printf("Partner store lookup failed for user %s: %v", userID, err)
+				abortWithAuthError(c, http.StatusInternalServerError, "Failed to load partner stores",
+					"The stores of this partner could not be loaded", "PARTNER_STORES_UNAVAILABLE")
+			}
+			return
+		}
+
+		// A partner without stores is limited to none, not unscoped
+		if storeIDs == nil {
+			storeIDs = []int{}
+		}
+		c.Set("partner_store_ids", storeIDs)
+		c.Next()
+	}
+}
+
+// partnerStoreIDs returns the stores a Partner caller is limited to, or nil when unscoped
+func partnerStoreIDs(c *gin.Context) []int {
+	storeIDs, exists := c.Get("partner_store_ids")
+	if !exists {
+		return nil
+	}
+	ids, _ := storeIDs.([]int)
+	return ids
+}
+
+// inStoreScope reports whether a store is visible to a caller limited to storeIDs
+func inStoreScope(storeIDs []int, storeID int) bool {
+	if storeIDs == nil {
+		return true
+	}
+	for _, id := range storeIDs {
+		if id == storeID {
+			return true
+		}
+	}
+	return false
+}
+
+// abortWithAuthError stops the request with the error, message and error_code body shared
+// with the other services
+func abortWithAuthError(c *gin.Context, status int, errMsg, message, errorCode string) {
+	c.AbortWithStatusJSON(status, gin.H{
+		"error":      errMsg,
+		"message":    message,
+		"error_code": errorCode,
+	})
+}
+
+// getManufacturerID returns the manufacturer resolved by ManufacturerMiddleware
+func getManufacturerID(c *gin.Context) int {
+	return c.GetInt("manufacturer_id")
+}
@@ -20,40 +20,6 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-// convertStoreTypeToDBValue converts English API enum values to Chinese database values
-func convertStoreTypeToDBValue(apiValue string) string {
-	switch apiValue {
-	case "RetailStore":
-		return "零售商店"
-	case "UnmannedStore":
-		return "无人门店"
-	case "UnmannedWarehouse":
-		return "无人仓店"
-	case "ExhibitionStore":
-		return "展销商店"
-	case "ExhibitionMall":
-		return "展销商城"
-	case "GroupBuying":
-		return "团购团批"
-	default:
-		// Fallback: try to use the value as-is (for backward compatibility)
-		return apiValue
-	}
-}
-
-// convertStoreTypeToAssociation converts English API enum values to store type association values
-func convertStoreTypeToAssociation(apiValue string) string {
-	switch apiValue {
-	case "UnmannedStore", "UnmannedWarehouse":
-		return "Unmanned"
-	case "ExhibitionStore", "ExhibitionMall":
-		return "Retail"
-	default:
-		// Fallback: try to use the value as-is (for backward compatibility)
-		return apiValue
-	}
-}
-
 // Handler holds the database connection and provides HTTP handlers
 type Handler struct {
 	db *db.Database
@@ -79,6 +45,15 @@ func (h *Handler) CreateProduct(c *gin.Context) {
 		return
 	}
 
+	// Every product must belong to an existing manufacturer
+	if !h.validateProductManufacturer(ctx, c, newProduct.ManufacturerID) {
+		return
+	}
+
+	if !h.validateProductMiniApp(ctx, c, &newProduct) {
+		return
+	}
+
 	// Call the database function to insert the product
 	productID, err := h.db.CreateProduct(ctx, newProduct)
 	if err != nil {
@@ -211,11 +186,21 @@ func (h *Handler) UpdateProduct(c *gin.Context) {
 		return
 	}
 
+	if !h.validateProductManufacturer(ctx, c, updatedProduct.ManufacturerID) {
+		return
+	}
+
+	if !h.validateProductMiniApp(ctx, c, &updatedProduct) {
+		return
+	}
+
 	// Update the product in the database
 	if err := h.db.UpdateProduct(ctx, productID, updatedProduct); err != nil {
 		log.Printf("Failed to update product %d: %v", productID, err)
 		if err.Error() == fmt.Sprintf("product with ID %d not found", productID) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+		} else if strings.HasPrefix(err.Error(), "insufficient stock") || strings.HasPrefix(err.Error(), "store with ID") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		} else {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update product"})
 		}
@@ -290,6 +275,7 @@ func (h *Handler) GetProducts(c *gin.Context) {
 	storeType := c.Query("store_type")
 	featured := c.Query("featured")
 	storeID := c.Query("store_id")
+	manufacturerID := c.Query("manufacturer_id")
 
 	// Check if this is an admin request (for internal admin panel use)
 	isAdminRequest := c.GetHeader("X-Admin-Request") == "true"
@@ -298,7 +284,7 @@ func (h *Handler) GetProducts(c *gin.Context) {
 	log.Printf("🔍 DEBUG: GetProducts called with params - storeType: %s, featured: %s, storeID: %s, isAdmin: %t", storeType, featured, storeID, isAdminRequest)
 
 	// Build the query - include cost_price only for admin requests
-	// For location-dependent mini-apps (UnmannedStore, ExhibitionSales), we need to JOIN with stores table
+	// For mini-apps that require a store (mini_apps.requires_store), we need to JOIN with stores table
 	// to get the actual store type from the associated store
 	var query string
 	if isAdminRequest {
@@ -308,14 +294,14 @@ func (h *Handler) GetProducts(c *gin.Context) {
                 p.product_id, p.product_uuid, p.sku, p.title, p.description_short, p.description_long,
                 p.manufacturer_id,
                 CASE
-                    WHEN p.mini_app_type IN ('UnmannedStore', 'ExhibitionSales') AND s.type IS NOT NULL
+                    WHEN p.mini_app_type IN (SELECT code FROM mini_apps WHERE requires_store) AND s.type IS NOT NULL
                     THEN s.type
                     ELSE p.store_type
                 END as store_type,
                 p.mini_app_type, p.store_id, p.main_price, p.strikethrough_price,
                 p.cost_price, p.stock_left, p.minimum_order_quantity, p.is_active, p.is_featured, p.is_mini_app_recommendation, p.created_at, p.updated_at
             FROM products p
-            LEFT JOIN stores s ON p.store_id = s.store_id AND p.mini_app_type IN ('UnmannedStore', 'ExhibitionSales')
+            LEFT JOIN stores s ON p.store_id = s.store_id AND p.mini_app_type IN (SELECT code FROM mini_apps WHERE requires_store)
             WHERE 1=1
         `
 	} else {
@@ -325,14 +311,14 @@ func (h *Handler) GetProducts(c *gin.Context) {
                 p.product_id, p.product_uuid, p.sku, p.title, p.description_short, p.description_long,
                 p.manufacturer_id,
                 CASE
-                    WHEN p.mini_app_type IN ('UnmannedStore', 'ExhibitionSales') AND s.type IS NOT NULL
+                    WHEN p.mini_app_type IN (SELECT code FROM mini_apps WHERE requires_store) AND s.type IS NOT NULL
                     THEN s.type
                     ELSE p.store_type
                 END as store_type,
                 p.mini_app_type, p.store_id, p.main_price, p.strikethrough_price,
                 p.stock_left, p.minimum_order_quantity, p.is_active, p.is_featured, p.is_mini_app_recommendation, p.created_at, p.updated_at
             FROM products p
-            LEFT JOIN stores s ON p.store_id = s.store_id AND p.mini_app_type IN ('UnmannedStore', 'ExhibitionSales')
+            LEFT JOIN stores s ON p.store_id = s.store_id AND p.mini_app_type IN (SELECT code FROM mini_apps WHERE requires_store)
             WHERE p.is_active = true
         `
 	}
@@ -343,17 +329,38 @@ func (h *Handler) GetProducts(c *gin.Context) {
 	// Add store type filter
 	if storeType != "" {
 		// Use the same logic as the SELECT statement for store type filtering
-		query += fmt.Sprintf(" AND (CASE WHEN p.mini_app_type IN ('UnmannedStore', 'ExhibitionSales') AND s.type IS NOT NULL THEN s.type ELSE p.store_type END) = $%d", argIndex)
-		// Convert English enum values to Chinese database values
-		dbStoreType := convertStoreTypeToDBValue(storeType)
-		args = append(args, dbStoreType)
+		parsedStoreType, _, ok := h.parseStoreType(ctx, c, storeType)
+		if !ok {
+			return
+		}
+		query += fmt.Sprintf(" AND (CASE WHEN p.mini_app_type IN (SELECT code FROM mini_apps WHERE requires_store) AND s.type IS NOT NULL THEN s.type ELSE p.store_type END) = $%d", argIndex)
+		args = append(args, parsedStoreType)
 		argIndex++
 	}
 
-	// Add store ID filter
+	// Add store ID filter: the store's own products plus products it carries through an override
+	storeIDInt := 0
 	if storeID != "" {
-		query += fmt.Sprintf(" AND p.store_id = $%d", argIndex)
-		args = append(args, storeID)
+		id, err := strconv.Atoi(storeID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid store_id format"})
+			return
+		}
+		storeIDInt = id
+		query += fmt.Sprintf(" AND (p.store_id = $%d OR EXISTS (SELECT 1 FROM store_product_overrides spo WHERE spo.product_id = p.product_id AND spo.store_id = $%d))", argIndex, argIndex)
+		args = append(args, storeIDInt)
+		argIndex++
+	}
+
+	// Add manufacturer filter
+	if manufacturerID != "" {
+		id, err := strconv.Atoi(manufacturerID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid manufacturer_id format"})
+			return
+		}
+		query += fmt.Sprintf(" AND p.manufacturer_id = $%d", argIndex)
+		args = append(args, id)
 		argIndex++
 	}
 
@@ -489,6 +496,42 @@ func (h *Handler) GetProducts(c *gin.Context) {
 		return
 	}
 
+	// Replace price, stock and availability with the store's own values
+	if storeID != "" && len(products) > 0 {
+		productIDs := make([]int, len(products))
+		for i, product := range products {
+			productIDs[i] = product.ID
+		}
+		storeValues, err := h.db.GetStoreProductValues(ctx, storeIDInt, productIDs)
+		if err != nil {
+			log.Printf("Error getting store %d product values: %v", storeIDInt, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch store prices"})
+			return
+		}
+
+		storeProducts := products[:0]
+		for _, product := range products {
+			if values, ok := storeValues[product.ID]; ok {
+				product.ApplyStoreValues(values)
+			}
+			// Products deactivated at this store are hidden from the public catalog
+			if !product.IsActive && !isAdminRequest {
+				continue
+			}
+			storeProducts = append(storeProducts, product)
+		}
+		products = storeProducts
+	}
+
+	// Hold back each product's safety stock at the requested store, else at its own store
+	if len(products) > 0 {
+		var safetyStore *int
+		if storeID != "" {
+			safetyStore = &storeIDInt
+		}
+		h.applySafetyStock(ctx, products, safetyStore)
+	}
+
 	// Debug logging for results
 	log.Printf("🔍 DEBUG: Found %d products", len(products))
 	if len(products) > 0 {
@@ -544,14 +587,14 @@ func (h *Handler) GetProduct(c *gin.Context) {
 	                p.product_id, p.product_uuid, p.sku, p.title, p.description_short, p.description_long,
 	                p.manufacturer_id,
 	                CASE
-	                    WHEN p.mini_app_type IN ('UnmannedStore', 'ExhibitionSales') AND s.type IS NOT NULL
+	                    WHEN p.mini_app_type IN (SELECT code FROM mini_apps WHERE requires_store) AND s.type IS NOT NULL
 	                    THEN s.type
 	                    ELSE p.store_type
 	                END as store_type,
 	                p.mini_app_type, p.store_id, p.main_price, p.strikethrough_price,
 	                p.cost_price, p.stock_left, p.minimum_order_quantity, p.is_active, p.is_featured, p.is_mini_app_recommendation, p.created_at, p.updated_at
 	            FROM products p
-	            LEFT JOIN stores s ON p.store_id = s.store_id AND p.mini_app_type IN ('UnmannedStore', 'ExhibitionSales')
+	            LEFT JOIN stores s ON p.store_id = s.store_id AND p.mini_app_type IN (SELECT code FROM mini_apps WHERE requires_store)
 	            WHERE p.product_id = $1 AND p.is_active = true
 	        `
 		} else {
@@ -560,14 +603,14 @@ func (h *Handler) GetProduct(c *gin.Context) {
 	                p.product_id, p.product_uuid, p.sku, p.title, p.description_short, p.description_long,
 	                p.manufacturer_id,
 	                CASE
-	                    WHEN p.mini_app_type IN ('UnmannedStore', 'ExhibitionSales') AND s.type IS NOT NULL
+	                    WHEN p.mini_app_type IN (SELECT code FROM mini_apps WHERE requires_store) AND s.type IS NOT NULL
 	                    THEN s.type
 	                    ELSE p.store_type
 	                END as store_type,
 	                p.mini_app_type, p.store_id, p.main_price, p.strikethrough_price,
 	                p.stock_left, p.minimum_order_quantity, p.is_active, p.is_featured, p.is_mini_app_recommendation, p.created_at, p.updated_at
 	            FROM products p
-	            LEFT JOIN stores s ON p.store_id = s.store_id AND p.mini_app_type IN ('UnmannedStore', 'ExhibitionSales')
+	            LEFT JOIN stores s ON p.store_id = s.store_id AND p.mini_app_type IN (SELECT code FROM mini_apps WHERE requires_store)
 	            WHERE p.product_id = $1 AND p.is_active = true
 	        `
 		}
@@ -580,14 +623,14 @@ func (h *Handler) GetProduct(c *gin.Context) {
 	                p.product_id, p.product_uuid, p.sku, p.title, p.description_short, p.description_long,
 	                p.manufacturer_id,
 	                CASE
-	                    WHEN p.mini_app_type IN ('UnmannedStore', 'ExhibitionSales') AND s.type IS NOT NULL
+	                    WHEN p.mini_app_type IN (SELECT code FROM mini_apps WHERE requires_store) AND s.type IS NOT NULL
 	                    THEN s.type
 	                    ELSE p.store_type
 	                END as store_type,
 	                p.mini_app_type, p.store_id, p.main_price, p.strikethrough_price,
 	                p.cost_price, p.stock_left, p.minimum_order_quantity, p.is_active, p.is_featured, p.is_mini_app_recommendation, p.created_at, p.updated_at
 	            FROM products p
-	            LEFT JOIN stores s ON p.store_id = s.store_id AND p.mini_app_type IN ('UnmannedStore', 'ExhibitionSales')
+	            LEFT JOIN stores s ON p.store_id = s.store_id AND p.mini_app_type IN (SELECT code FROM mini_apps WHERE requires_store)
 	            WHERE p.product_uuid = $1 AND p.is_active = true
 	        `
 		} else {
@@ -596,14 +639,14 @@ func (h *Handler) GetProduct(c *gin.Context) {
 	                p.product_id, p.product_uuid, p.sku, p.title, p.description_short, p.description_long,
 	                p.manufacturer_id,
 	                CASE
-	                    WHEN p.mini_app_type IN ('UnmannedStore', 'ExhibitionSales') AND s.type IS NOT NULL
+	                    WHEN p.mini_app_type IN (SELECT code FROM mini_apps WHERE requires_store) AND s.type IS NOT NULL
 	                    THEN s.type
 	                    ELSE p.store_type
 	                END as store_type,
 	                p.mini_app_type, p.store_id, p.main_price, p.strikethrough_price,
 	                p.stock_left, p.minimum_order_quantity, p.is_active, p.is_featured, p.is_mini_app_recommendation, p.created_at, p.updated_at
 	            FROM products p
-	            LEFT JOIN stores s ON p.store_id = s.store_id AND p.mini_app_type IN ('UnmannedStore', 'ExhibitionSales')
+	            LEFT JOIN stores s ON p.store_id = s.store_id AND p.mini_app_type IN (SELECT code FROM mini_apps WHERE requires_store)
 	            WHERE p.product_uuid = $1 AND p.is_active = true
 	        `
 		}
@@ -692,9 +735,50 @@ func (h *Handler) GetProduct(c *gin.Context) {
 		product.SubcategoryIds = subcategories
 	}
 
+	// Embed manufacturer details
+	if product.ManufacturerID > 0 {
+		manufacturer, err := h.db.GetManufacturer(ctx, product.ManufacturerID)
+		if err != nil {
+			log.Printf("Error getting manufacturer %d for product %d: %v", product.ManufacturerID, product.ID, err)
+		} else {
+			product.Manufacturer = manufacturer
+		}
+	}
+
+	// Replace price, stock and availability with the store's own values
+	storeID := c.Query("store_id")
+	if storeID != "" {
+		storeIDInt, err := strconv.Atoi(storeID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid store_id format"})
+			return
+		}
+		storeValues, err := h.db.GetStoreProductValues(ctx, storeIDInt, []int{product.ID})
+		if err != nil {
+			log.Printf("Error getting store %d values for product %d: %v", storeIDInt, product.ID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch store price"})
+			return
+		}
+		product.ApplyStoreValues(storeValues[product.ID])
+		if !product.IsActive && !isAdminRequest {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Product not available at this store"})
+			return
+		}
+	}
+
+	// Hold back the safety stock at the requested store, else at the product's own store
+	var safetyStore *int
+	if storeID != "" {
+		if id, err := strconv.Atoi(storeID); err == nil {
+			safetyStore = &id
+		}
+	}
+	single := []models.Product{product}
+	h.applySafetyStock(ctx, single, safetyStore)
+	product.SafetyStock = single[0].SafetyStock
+
 	// Get stock quantity for unmanned stores and warehouses
 	if product.StoreType == models.StoreTypeUnmannedStore || product.StoreType == models.StoreTypeUnmannedWarehouse {
-		storeID := c.Query("store_id")
 		stockQuantity, err := h.getProductStock(ctx, product.ID, storeID)
 		if err != nil {
 			log.Printf("Error getting stock for product %d: %v", product.ID, err)
@@ -753,11 +837,17 @@ func (h *Handler) GetCategories(c *gin.Context) {
 	}
 
 	if storeType != "" {
-		conditions = append(conditions, fmt.Sprintf("(store_type_association = $%d OR store_type_association = 'All')", argIndex))
-		// Convert English enum values to appropriate store type association values
-		dbStoreTypeAssociation := convertStoreTypeToAssociation(storeType)
-		args = append(args, dbStoreTypeAssociation)
-		argIndex++
+		_, association, ok := h.parseStoreType(ctx, c, storeType)
+		if !ok {
+			return
+		}
+		if association != "" {
+			conditions = append(conditions, fmt.Sprintf("(store_type_association = $%d OR store_type_association = 'All')", argIndex))
+			args = append(args, association)
+			argIndex++
+		} else {
+			conditions = append(conditions, "store_type_association = 'All'")
+		}
 	}
 
 	if miniAppType != "" {
@@ -917,20 +1007,23 @@ func (h *Handler) GetStores(c *gin.Context) {
 	userLat := c.Query("user_lat")
 	userLng := c.Query("user_lng")
 	orderByDistance := c.Query("order_by_distance") == "true"
+	openNow := c.Query("open_now") == "true"
 
 	// Base query with distance calculation if user location provided
 	var query string
 	if userLat != "" && userLng != "" && orderByDistance {
 		query = `
             SELECT
-                store_id, name, city, address, latitude, longitude, type, image_url, is_active, created_at, updated_at,
+                store_id, name, city, address, latitude, longitude, type, image_url, is_active, timezone, created_at, updated_at,
+                store_is_open(store_id, CURRENT_TIMESTAMP), store_next_open_at(store_id, CURRENT_TIMESTAMP),
                 (6371 * acos(cos(radians($1)) * cos(radians(latitude)) * cos(radians(longitude) - radians($2)) + sin(radians($1)) * sin(radians(latitude)))) AS distance_km
             FROM stores
             WHERE is_active = true
         `
 	} else {
 		query = `
-            SELECT store_id, name, city, address, latitude, longitude, type, image_url, is_active, created_at, updated_at
+            SELECT store_id, name, city, address, latitude, longitude, type, image_url, is_active, timezone, created_at, updated_at,
+                store_is_open(store_id, CURRENT_TIMESTAMP), store_next_open_at(store_id, CURRENT_TIMESTAMP)
             FROM stores
             WHERE is_active = true
         `
@@ -945,27 +1038,25 @@ func (h *Handler) GetStores(c *gin.Context) {
 		argIndex = 3
 	}
 
-	// Filter by store type
-	if storeType != "" {
-		query += fmt.Sprintf(" AND type = $%d", argIndex)
-		// Convert English enum values to Chinese database values
-		dbStoreType := convertStoreTypeToDBValue(storeType)
-		args = append(args, dbStoreType)
+	// Filter by store type and by the store types the mini-app serves
+	types, ok, err := h.storeTypeFilter(ctx, storeType, miniAppType)
+	if err != nil {
+		respondStoreTypeFilterError(c, err)
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusOK, []models.Store{})
+		return
+	}
+	if types != nil {
+		query += fmt.Sprintf(" AND type = ANY($%d)", argIndex)
+		args = append(args, types)
 		argIndex++
 	}
 
-	// Filter by mini-app type (map store types to mini-app types)
-	if miniAppType != "" {
-		switch miniAppType {
-		case "UnmannedStore":
-			query += fmt.Sprintf(" AND type IN ($%d, $%d)", argIndex, argIndex+1)
-			args = append(args, "无人门店", "无人仓店")
-			argIndex += 2
-		case "ExhibitionSales":
-			query += fmt.Sprintf(" AND type IN ($%d, $%d)", argIndex, argIndex+1)
-			args = append(args, "展销商店", "展销商城")
-			argIndex += 2
-		}
+	// Only stores that are open right now
+	if openNow {
+		query += " AND store_is_open(store_id, CURRENT_TIMESTAMP)"
 	}
 
 	// Order by distance if requested, otherwise by store_id
@@ -999,8 +1090,11 @@ func (h *Handler) GetStores(c *gin.Context) {
 				&store.Type,
 				&store.ImageURL,
 				&store.IsActive,
+				&store.Timezone,
 				&store.CreatedAt,
 				&store.UpdatedAt,
+				&store.IsOpen,
+				&store.NextOpenAt,
 				&distanceKm,
 			)
 			if err != nil {
@@ -1008,6 +1102,7 @@ func (h *Handler) GetStores(c *gin.Context) {
 				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to scan store"})
 				return
 			}
+			store.DistanceKm = distanceKm
 		} else {
 			err := rows.Scan(
 				&store.ID,
@@ -1019,8 +1114,11 @@ func (h *Handler) GetStores(c *gin.Context) {
 				&store.Type,
 				&store.ImageURL,
 				&store.IsActive,
+				&store.Timezone,
 				&store.CreatedAt,
 				&store.UpdatedAt,
+				&store.IsOpen,
+				&store.NextOpenAt,
 			)
 			if err != nil {
 				log.Printf("Error scanning store: %v", err)
@@ -1140,10 +1238,31 @@ func (h *Handler) getProductSubcategories(ctx context.Context, productID int) ([
 	return subcategories, rows.Err()
 }
 
+// applySafetyStock sets the configured safety stock of each product, falling back to the
+// default buffer when the lookup fails
+func (h *Handler) applySafetyStock(ctx context.Context, products []models.Product, storeID *int) {
+	productIDs := make([]int, len(products))
+	for i, product := range products {
+		productIDs[i] = product.ID
+	}
+
+	safetyStocks, err := h.db.GetSafetyStocks(ctx, productIDs, storeID)
+	if err != nil {
+		log.Printf("Error getting safety stock: %v", err)
+	}
+	for i := range products {
+		if safetyStock, ok := safetyStocks[products[i].ID]; ok {
+			products[i].SafetyStock = safetyStock
+		} else {
+			products[i].SafetyStock = models.DefaultSafetyStock
+		}
+	}
+}
+
 func (h *Handler) getProductStock(ctx context.Context, productID int, storeID string) (*int, error) {
-	// If no store ID specified, get stock from first available store
+	// Without a store ID, report the stock across all stores
 	query := `
-        SELECT quantity
+        SELECT SUM(quantity)::int
         FROM inventory
         WHERE product_id = $1
     `
@@ -1158,19 +1277,13 @@ func (h *Handler) getProductStock(ctx context.Context, productID int, storeID st
 		}
 	}
 
-	query += " LIMIT 1"
-
-	var quantity int
-	err := h.db.Pool.QueryRow(ctx, query, args...).Scan(&quantity)
-	if err != nil {
-		// Return nil instead of an error if no rows are found
-		if err.Error() == "no rows in result set" {
-			return nil, nil
-		}
+	// SUM is NULL (nil) when the product has no inventory rows
+	var quantity *int
+	if err := h.db.Pool.QueryRow(ctx, query, args...).Scan(&quantity); err != nil {
 		return nil, err
 	}
 
-	return &quantity, nil
+	return quantity, nil
 }
 
 // uploadToS3 uploads file to AWS S3 bucket
@@ -1721,11 +1834,22 @@ func (h *Handler) CreateStore(c *gin.Context) {
 	}
 
 	query := `
-        INSERT INTO stores (name, city, address, latitude, longitude, type, image_url, is_active)
-        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+        INSERT INTO stores (name, city, address, latitude, longitude, type, image_url, is_active, timezone)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
         RETURNING store_id, created_at, updated_at
     `
 
+	if _, _, ok := h.parseStoreType(ctx, c, string(newStore.Type)); !ok {
+		return
+	}
+	if newStore.Timezone == "" {
+		newStore.Timezone = "UTC"
+	}
+	if !h.validTimezone(ctx, newStore.Timezone) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid timezone: " + newStore.Timezone})
+		return
+	}
+
 	var storeID int
 	var createdAt, updatedAt time.Time
 	err := h.db.Pool.QueryRow(ctx, query,
@@ -1737,6 +1861,7 @@ func (h *Handler) CreateStore(c *gin.Context) {
 		newStore.Type,
 		newStore.ImageURL,
 		newStore.IsActive,
+		newStore.Timezone,
 	).Scan(&storeID, &createdAt, &updatedAt)
 
 	if err != nil {
@@ -1767,11 +1892,20 @@ func (h *Handler) UpdateStore(c *gin.Context) {
 
 	query := `
         UPDATE stores
-        SET name = $2, city = $3, address = $4, latitude = $5, longitude = $6, type = $7, image_url = $8, is_active = $9, updated_at = CURRENT_TIMESTAMP
+        SET name = $2, city = $3, address = $4, latitude = $5, longitude = $6, type = $7, image_url = $8, is_active = $9,
+            timezone = COALESCE(NULLIF($10, ''), timezone), updated_at = CURRENT_TIMESTAMP
         WHERE store_id = $1
-        RETURNING updated_at
+        RETURNING timezone, updated_at
     `
 
+	if _, _, ok := h.parseStoreType(ctx, c, string(updatedStore.Type)); !ok {
+		return
+	}
+	if updatedStore.Timezone != "" && !h.validTimezone(ctx, updatedStore.Timezone) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid timezone: " + updatedStore.Timezone})
+		return
+	}
+
 	var updatedAt time.Time
 	err := h.db.Pool.QueryRow(ctx, query,
 		storeID,
@@ -1783,7 +1917,8 @@ func (h *Handler) UpdateStore(c *gin.Context) {
 		updatedStore.Type,
 		updatedStore.ImageURL,
 		updatedStore.IsActive,
-	).Scan(&updatedAt)
+		updatedStore.Timezone,
+	).Scan(&updatedStore.Timezone, &updatedAt)
 
 	if err != nil {
 		log.Printf("Failed to update store in DB: %v", err)
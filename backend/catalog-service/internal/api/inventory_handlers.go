@@ -0,0 +1,182 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/expomadeinworld/madeinworld/catalog-service/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// =================================================================================
+// INVENTORY HANDLERS
+// =================================================================================
+
+// GetInventory handles GET /inventory
+func (h *Handler) GetInventory(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	var filter models.InventoryFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query parameters: " + err.Error()})
+		return
+	}
+
+	levels, err := h.db.GetInventoryLevels(ctx, filter)
+	if err != nil {
+		log.Printf("Error fetching inventory: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch inventory"})
+		return
+	}
+
+	c.JSON(http.StatusOK, levels)
+}
+
+// AdjustStock handles POST /inventory/adjustments
+func (h *Handler) AdjustStock(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	var req models.StockAdjustmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	movement, err := h.db.AdjustStock(ctx, req, optionalUserID(c))
+	if err != nil {
+		respondInventoryError(c, err, "Failed to adjust stock")
+		return
+	}
+
+	c.JSON(http.StatusCreated, movement)
+}
+
+// TransferStock handles POST /inventory/transfers
+func (h *Handler) TransferStock(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	var req models.StockTransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	transfer, err := h.db.TransferStock(ctx, req, optionalUserID(c))
+	if err != nil {
+		respondInventoryError(c, err, "Failed to transfer stock")
+		return
+	}
+
+	c.JSON(http.StatusCreated, transfer)
+}
+
+// CountStock handles POST /inventory/counts
+func (h *Handler) CountStock(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	var req models.StockCountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	count, err := h.db.CountStock(ctx, req, optionalUserID(c))
+	if err != nil {
+		respondInventoryError(c, err, "Failed to record stock count")
+		return
+	}
+
+	c.JSON(http.StatusCreated, count)
+}
+
+// GetStockMovements handles GET /inventory/movements
+func (h *Handler) GetStockMovements(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	var filter models.StockMovementFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query parameters: " + err.Error()})
+		return
+	}
+	if filter.Type != "" && !filter.Type.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid movement_type '%s'", filter.Type)})
+		return
+	}
+	for _, date := range []string{filter.DateFrom, filter.DateTo} {
+		if date == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Dates must be in YYYY-MM-DD format"})
+			return
+		}
+	}
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.Limit < 1 || filter.Limit > 200 {
+		filter.Limit = 50
+	}
+
+	movements, err := h.db.GetStockMovements(ctx, filter)
+	if err != nil {
+		log.Printf("Error fetching stock movements: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch stock movements"})
+		return
+	}
+
+	c.JSON(http.StatusOK, movements)
+}
+
+// ReconcileInventory handles GET /inventory/reconciliation
+func (h *Handler) ReconcileInventory(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	var storeID *int
+	if value := c.Query("store_id"); value != "" {
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid store_id format"})
+			return
+		}
+		storeID = &id
+	}
+
+	result, err := h.db.ReconcileInventory(ctx, storeID)
+	if err != nil {
+		log.Printf("Error reconciling inventory: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reconcile inventory"})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// respondInventoryError maps inventory errors to HTTP responses
+func respondInventoryError(c *gin.Context, err error, fallback string) {
+	switch {
+	case strings.HasSuffix(err.Error(), "not found"):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case strings.HasPrefix(err.Error(), "insufficient stock"):
+		c.JSON(http.StatusConflict, gin.H{
+			"error":      err.Error(),
+			"error_code": "INSUFFICIENT_STOCK",
+		})
+	case strings.HasPrefix(err.Error(), "cannot transfer"), strings.HasSuffix(err.Error(), "counted more than once"):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		log.Printf("%s: %v", fallback, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
@@ -0,0 +1,192 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/expomadeinworld/madeinworld/catalog-service/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// GetManufacturers handles GET /manufacturers
+func (h *Handler) GetManufacturers(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	manufacturers, err := h.db.GetManufacturers(ctx, strings.TrimSpace(c.Query("search")))
+	if err != nil {
+		log.Printf("Error fetching manufacturers: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch manufacturers"})
+		return
+	}
+
+	c.JSON(http.StatusOK, manufacturers)
+}
+
+// GetManufacturer handles GET /manufacturers/:id
+func (h *Handler) GetManufacturer(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	manufacturerID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid manufacturer ID format"})
+		return
+	}
+
+	manufacturer, err := h.db.GetManufacturer(ctx, manufacturerID)
+	if err != nil {
+		if err.Error() == fmt.Sprintf("manufacturer with ID %d not found", manufacturerID) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Manufacturer not found"})
+			return
+		}
+		log.Printf("Error fetching manufacturer %d: %v", manufacturerID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch manufacturer"})
+		return
+	}
+
+	c.JSON(http.StatusOK, manufacturer)
+}
+
+// CreateManufacturer handles POST /manufacturers
+func (h *Handler) CreateManufacturer(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	var manufacturer models.Manufacturer
+	if err := c.ShouldBindJSON(&manufacturer); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	manufacturer.CompanyName = strings.TrimSpace(manufacturer.CompanyName)
+	if manufacturer.CompanyName == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "company_name is required"})
+		return
+	}
+
+	manufacturerID, err := h.db.CreateManufacturer(ctx, manufacturer)
+	if err != nil {
+		log.Printf("Failed to create manufacturer: %v", err)
+		if strings.Contains(err.Error(), "manufacturers_company_name_key") {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":      fmt.Sprintf("Manufacturer '%s' already exists", manufacturer.CompanyName),
+				"error_code": "DUPLICATE_MANUFACTURER",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create manufacturer"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"manufacturer_id": manufacturerID})
+}
+
+// UpdateManufacturer handles PUT /manufacturers/:id
+func (h *Handler) UpdateManufacturer(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	manufacturerID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid manufacturer ID format"})
+		return
+	}
+
+	var manufacturer models.Manufacturer
+	if err := c.ShouldBindJSON(&manufacturer); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	manufacturer.CompanyName = strings.TrimSpace(manufacturer.CompanyName)
+	if manufacturer.CompanyName == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "company_name is required"})
+		return
+	}
+
+	if err := h.db.UpdateManufacturer(ctx, manufacturerID, manufacturer); err != nil {
+		log.Printf("Failed to update manufacturer %d: %v", manufacturerID, err)
+		if err.Error() == fmt.Sprintf("manufacturer with ID %d not found", manufacturerID) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Manufacturer not found"})
+		} else if strings.Contains(err.Error(), "manufacturers_company_name_key") {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":      fmt.Sprintf("Manufacturer '%s' already exists", manufacturer.CompanyName),
+				"error_code": "DUPLICATE_MANUFACTURER",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update manufacturer"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":         "Manufacturer updated successfully",
+		"manufacturer_id": manufacturerID,
+	})
+}
+
+// DeleteManufacturer handles DELETE /manufacturers/:id
+func (h *Handler) DeleteManufacturer(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	manufacturerID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid manufacturer ID format"})
+		return
+	}
+
+	if err := h.db.DeleteManufacturer(ctx, manufacturerID); err != nil {
+		log.Printf("Failed to delete manufacturer %d: %v", manufacturerID, err)
+		if err.Error() == fmt.Sprintf("manufacturer with ID %d not found", manufacturerID) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Manufacturer not found"})
+		} else if strings.Contains(err.Error(), "still referenced") {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":      "Manufacturer still has products. Reassign or delete them first.",
+				"error_code": "MANUFACTURER_IN_USE",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete manufacturer"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":         "Manufacturer deleted successfully",
+		"manufacturer_id": manufacturerID,
+	})
+}
+
+// validateProductManufacturer ensures the product references an existing manufacturer.
+// It writes the error response itself and returns false when the request must stop.
+func (h *Handler) validateProductManufacturer(ctx context.Context, c *gin.Context, manufacturerID int) bool {
+	if manufacturerID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "manufacturer_id is required",
+			"error_code": "MANUFACTURER_REQUIRED",
+		})
+		return false
+	}
+
+	exists, err := h.db.ManufacturerExists(ctx, manufacturerID)
+	if err != nil {
+		log.Printf("Failed to validate manufacturer %d: %v", manufacturerID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to validate manufacturer"})
+		return false
+	}
+	if !exists {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      fmt.Sprintf("Manufacturer with ID %d does not exist", manufacturerID),
+			"error_code": "MANUFACTURER_NOT_FOUND",
+		})
+		return false
+	}
+
+	return true
+}
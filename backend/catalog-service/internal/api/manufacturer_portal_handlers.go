@@ -0,0 +1,373 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/expomadeinworld/madeinworld/catalog-service/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// =================================================================================
+// MANUFACTURER PORTAL HANDLERS (Manufacturer role, scoped to the linked manufacturer)
+// =================================================================================
+
+// GetMyManufacturer handles GET /manufacturer/me
+func (h *Handler) GetMyManufacturer(c *gin.Context) {
+	manufacturer, _ := c.Get("manufacturer")
+	c.JSON(http.StatusOK, manufacturer)
+}
+
+// GetMyProducts handles GET /manufacturer/products
+func (h *Handler) GetMyProducts(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	manufacturerID := getManufacturerID(c)
+	products, err := h.db.GetManufacturerProducts(ctx, manufacturerID)
+	if err != nil {
+		log.Printf("Error fetching products for manufacturer %d: %v", manufacturerID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch products"})
+		return
+	}
+
+	for i := range products {
+		images, err := h.getProductImages(ctx, products[i].ID)
+		if err != nil {
+			log.Printf("Error getting product images for product %d: %v", products[i].ID, err)
+			images = []string{}
+		}
+		products[i].ImageUrls = images
+	}
+
+	c.JSON(http.StatusOK, products)
+}
+
+// GetMyProduct handles GET /manufacturer/products/:id
+func (h *Handler) GetMyProduct(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID format"})
+		return
+	}
+
+	product, err := h.db.GetManufacturerProduct(ctx, getManufacturerID(c), productID)
+	if err != nil {
+		if err.Error() == fmt.Sprintf("product with ID %d not found", productID) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+			return
+		}
+		log.Printf("Error fetching product %d: %v", productID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch product"})
+		return
+	}
+
+	images, err := h.getProductImagesDetailed(ctx, productID)
+	if err != nil {
+		log.Printf("Error getting product images for product %d: %v", productID, err)
+	}
+	if images == nil {
+		images = []models.ProductImage{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"product": product,
+		"images":  images,
+	})
+}
+
+// UpdateMyProduct handles PUT /manufacturer/products/:id
+func (h *Handler) UpdateMyProduct(c *gin.Context) {
+	productID, ok := h.requireOwnedProduct(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	var update models.ManufacturerProductUpdate
+	if err := c.ShouldBindJSON(&update); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	if update.Title != nil && strings.TrimSpace(*update.Title) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "title cannot be empty"})
+		return
+	}
+	if update.MinimumOrderQuantity != nil && *update.MinimumOrderQuantity < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "minimum_order_quantity must be at least 1"})
+		return
+	}
+
+	if err := h.db.UpdateManufacturerProduct(ctx, productID, getManufacturerID(c), update); err != nil {
+		log.Printf("Failed to update product %d for manufacturer %d: %v", productID, getManufacturerID(c), err)
+		if err.Error() == fmt.Sprintf("product with ID %d not found", productID) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update product"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Product updated successfully",
+		"product_id": productID,
+	})
+}
+
+// UploadMyProductImages handles POST /manufacturer/products/:id/images
+func (h *Handler) UploadMyProductImages(c *gin.Context) {
+	if _, ok := h.requireOwnedProduct(c); !ok {
+		return
+	}
+
+	// Ownership is verified; the upload itself is identical to the admin flow
+	h.UploadProductImages(c)
+}
+
+// CreateMyPriceChangeRequest handles POST /manufacturer/products/:id/price-requests
+func (h *Handler) CreateMyPriceChangeRequest(c *gin.Context) {
+	productID, ok := h.requireOwnedProduct(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	var req models.CreatePriceChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	if req.ProposedStrikethroughPrice != nil && *req.ProposedStrikethroughPrice <= req.ProposedPrice {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "proposed_strikethrough_price must be greater than proposed_price"})
+		return
+	}
+
+	userID, _ := GetUserID(c)
+	requestID, err := h.db.CreatePriceChangeRequest(ctx, productID, getManufacturerID(c), userID, req)
+	if err != nil {
+		log.Printf("Failed to create price change request for product %d: %v", productID, err)
+		if strings.Contains(err.Error(), "idx_price_change_requests_one_pending") {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":      "A price change for this product is already awaiting review",
+				"error_code": "PRICE_CHANGE_PENDING",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create price change request"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":    "Price change submitted for admin approval",
+		"request_id": requestID,
+		"status":     models.PriceChangeStatusPending,
+	})
+}
+
+// GetMyPriceChangeRequests handles GET /manufacturer/price-requests
+func (h *Handler) GetMyPriceChangeRequests(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	manufacturerID := getManufacturerID(c)
+	requests, err := h.db.GetPriceChangeRequests(ctx, &manufacturerID, c.Query("status"))
+	if err != nil {
+		log.Printf("Error fetching price change requests for manufacturer %d: %v", manufacturerID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch price change requests"})
+		return
+	}
+
+	c.JSON(http.StatusOK, requests)
+}
+
+// WithdrawMyPriceChangeRequest handles POST /manufacturer/price-change-requests/:id/withdraw
+func (h *Handler) WithdrawMyPriceChangeRequest(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	requestID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request ID format"})
+		return
+	}
+
+	if err := h.db.WithdrawPriceChangeRequest(ctx, requestID, getManufacturerID(c)); err != nil {
+		log.Printf("Failed to withdraw price change request %d: %v", requestID, err)
+		switch {
+		case err.Error() == fmt.Sprintf("price change request with ID %d not found", requestID):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Price change request not found"})
+		case strings.Contains(err.Error(), "is already"):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to withdraw price change request"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Price change request withdrawn",
+		"request_id": requestID,
+		"status":     models.PriceChangeStatusWithdrawn,
+	})
+}
+
+// requireOwnedProduct parses :id and verifies it belongs to the caller's manufacturer.
+// A product owned by someone else is reported as not found.
+func (h *Handler) requireOwnedProduct(c *gin.Context) (int, bool) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID format"})
+		return 0, false
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	owned, err := h.db.ProductBelongsToManufacturer(ctx, productID, getManufacturerID(c))
+	if err != nil {
+		log.Printf("Failed to check ownership of product %d: %v", productID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify product ownership"})
+		return 0, false
+	}
+	if !owned {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+		return 0, false
+	}
+
+	return productID, true
+}
+
+// =================================================================================
+// ADMIN HANDLERS FOR MANUFACTURER ACCOUNTS AND PRICE APPROVALS
+// =================================================================================
+
+// LinkManufacturerUser handles PUT /manufacturers/:id/user
+func (h *Handler) LinkManufacturerUser(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	manufacturerID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid manufacturer ID format"})
+		return
+	}
+
+	var req models.LinkManufacturerUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	if err := h.db.LinkManufacturerUser(ctx, manufacturerID, req.UserID); err != nil {
+		log.Printf("Failed to link user to manufacturer %d: %v", manufacturerID, err)
+		switch {
+		case err.Error() == fmt.Sprintf("manufacturer with ID %d not found", manufacturerID):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Manufacturer not found"})
+		case strings.Contains(err.Error(), "does not have the Manufacturer role"), strings.HasSuffix(err.Error(), "not found"):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case strings.Contains(err.Error(), "manufacturers_user_id_key"):
+			c.JSON(http.StatusConflict, gin.H{"error": "User is already linked to another manufacturer"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to link manufacturer user"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":         "Manufacturer user updated successfully",
+		"manufacturer_id": manufacturerID,
+		"user_id":         req.UserID,
+	})
+}
+
+// GetPriceChangeRequests handles GET /price-change-requests
+func (h *Handler) GetPriceChangeRequests(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	var manufacturerID *int
+	if idStr := c.Query("manufacturer_id"); idStr != "" {
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid manufacturer_id format"})
+			return
+		}
+		manufacturerID = &id
+	}
+
+	requests, err := h.db.GetPriceChangeRequests(ctx, manufacturerID, c.Query("status"))
+	if err != nil {
+		log.Printf("Error fetching price change requests: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch price change requests"})
+		return
+	}
+
+	c.JSON(http.StatusOK, requests)
+}
+
+// ApprovePriceChangeRequest handles POST /price-change-requests/:id/approve
+func (h *Handler) ApprovePriceChangeRequest(c *gin.Context) {
+	h.reviewPriceChangeRequest(c, true)
+}
+
+// RejectPriceChangeRequest handles POST /price-change-requests/:id/reject
+func (h *Handler) RejectPriceChangeRequest(c *gin.Context) {
+	h.reviewPriceChangeRequest(c, false)
+}
+
+func (h *Handler) reviewPriceChangeRequest(c *gin.Context, approve bool) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	requestID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request ID format"})
+		return
+	}
+
+	var review models.ReviewPriceChangeRequest
+	// The body is optional; an empty body is a plain approve/reject
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&review); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+			return
+		}
+	}
+
+	reviewerID, _ := GetUserID(c)
+	if err := h.db.ReviewPriceChangeRequest(ctx, requestID, approve, reviewerID, review); err != nil {
+		log.Printf("Failed to review price change request %d: %v", requestID, err)
+		switch {
+		case err.Error() == fmt.Sprintf("price change request with ID %d not found", requestID):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Price change request not found"})
+		case strings.Contains(err.Error(), "is already"):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to review price change request"})
+		}
+		return
+	}
+
+	status := models.PriceChangeStatusRejected
+	if approve {
+		status = models.PriceChangeStatusApproved
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Price change request reviewed",
+		"request_id": requestID,
+		"status":     status,
+	})
+}
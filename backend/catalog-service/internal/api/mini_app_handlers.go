@@ -0,0 +1,220 @@
+package api
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/expomadeinworld/madeinworld/catalog-service/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// =================================================================================
+// MINI-APP REGISTRY HANDLERS
+// =================================================================================
+
+// GetMiniApps handles GET /mini-apps
+func (h *Handler) GetMiniApps(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	// Admin requests also list deactivated mini-apps
+	isAdminRequest := c.GetHeader("X-Admin-Request") == "true"
+
+	miniApps, err := h.db.GetMiniApps(ctx, isAdminRequest)
+	if err != nil {
+		log.Printf("Error fetching mini-apps: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch mini-apps"})
+		return
+	}
+
+	c.JSON(http.StatusOK, miniApps)
+}
+
+// GetMiniApp handles GET /mini-apps/:code
+func (h *Handler) GetMiniApp(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	miniApp, err := h.db.GetMiniApp(ctx, models.MiniAppType(c.Param("code")))
+	if err != nil {
+		respondStoreError(c, err, "Failed to fetch mini-app")
+		return
+	}
+
+	c.JSON(http.StatusOK, miniApp)
+}
+
+// CreateMiniApp handles POST /mini-apps
+func (h *Handler) CreateMiniApp(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	var req models.CreateMiniAppRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	for _, t := range req.StoreTypes {
+		if _, _, ok := h.parseStoreType(ctx, c, string(t)); !ok {
+			return
+		}
+	}
+
+	if err := h.db.CreateMiniApp(ctx, req); err != nil {
+		if strings.HasSuffix(err.Error(), "already exists") {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("Failed to create mini-app: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create mini-app"})
+		return
+	}
+
+	miniApp, err := h.db.GetMiniApp(ctx, req.Code)
+	if err != nil {
+		respondStoreError(c, err, "Failed to fetch mini-app")
+		return
+	}
+
+	c.JSON(http.StatusCreated, miniApp)
+}
+
+// UpdateMiniApp handles PUT /mini-apps/:code
+func (h *Handler) UpdateMiniApp(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	code := models.MiniAppType(c.Param("code"))
+
+	var req models.UpdateMiniAppRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	for _, t := range req.StoreTypes {
+		if _, _, ok := h.parseStoreType(ctx, c, string(t)); !ok {
+			return
+		}
+	}
+
+	if err := h.db.UpdateMiniApp(ctx, code, req); err != nil {
+		respondStoreError(c, err, "Failed to update mini-app")
+		return
+	}
+
+	miniApp, err := h.db.GetMiniApp(ctx, code)
+	if err != nil {
+		respondStoreError(c, err, "Failed to fetch mini-app")
+		return
+	}
+
+	c.JSON(http.StatusOK, miniApp)
+}
+
+// validateProductMiniApp checks the product's mini-app and store type against the registry,
+// defaulting the store type when the mini-app serves a single one. It writes the error
+// response and returns false when the product is rejected.
+func (h *Handler) validateProductMiniApp(ctx context.Context, c *gin.Context, product *models.Product) bool {
+	miniApp, err := h.db.GetMiniApp(ctx, product.MiniAppType)
+	if err != nil {
+		if strings.HasSuffix(err.Error(), "not found") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return false
+		}
+		log.Printf("Failed to load mini-app %s: %v", product.MiniAppType, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to validate mini-app"})
+		return false
+	}
+
+	if product.StoreType == "" && len(miniApp.StoreTypes) == 1 {
+		product.StoreType = miniApp.StoreTypes[0]
+	}
+	if _, _, ok := h.parseStoreType(ctx, c, string(product.StoreType)); !ok {
+		return false
+	}
+	if !miniApp.AllowsStoreType(product.StoreType) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Store type " + string(product.StoreType) + " is not served by mini-app " + string(miniApp.Code)})
+		return false
+	}
+	if miniApp.RequiresStore && product.StoreID == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Mini-app " + string(miniApp.Code) + " requires a store_id"})
+		return false
+	}
+	return true
+}
+
+// storeTypeFilter returns the store type codes matching the type and mini-app filters (nil
+// when neither is set). Mini-apps that do not require a store do not restrict the types.
+// ok is false when the filters cannot match any store.
+func (h *Handler) storeTypeFilter(ctx context.Context, storeType, miniAppType string) (types []string, ok bool, err error) {
+	if storeType != "" {
+		parsed, _, err := h.db.ParseStoreType(ctx, storeType)
+		if err != nil {
+			return nil, false, err
+		}
+		types = []string{string(parsed)}
+	}
+	if miniAppType == "" {
+		return types, true, nil
+	}
+
+	miniApp, err := h.db.GetMiniApp(ctx, models.MiniAppType(miniAppType))
+	if err != nil {
+		return nil, false, err
+	}
+	if !miniApp.RequiresStore {
+		return types, true, nil
+	}
+
+	miniAppTypes := make([]string, len(miniApp.StoreTypes))
+	for i, t := range miniApp.StoreTypes {
+		miniAppTypes[i] = string(t)
+	}
+	if types == nil {
+		return miniAppTypes, len(miniAppTypes) > 0, nil
+	}
+	for _, t := range miniAppTypes {
+		if t == types[0] {
+			return types, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+// respondStoreTypeFilterError maps storeTypeFilter errors to HTTP responses
+// parseStoreType validates a store type code received from a client against the store_types
+// registry and returns it with its category association. It writes the error response and
+// returns false when the code is rejected.
+func (h *Handler) parseStoreType(ctx context.Context, c *gin.Context, value string) (models.StoreType, string, bool) {
+	storeType, association, err := h.db.ParseStoreType(ctx, value)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid store type") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return "", "", false
+		}
+		log.Printf("Failed to validate store type %s: %v", value, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to validate store type"})
+		return "", "", false
+	}
+	return storeType, association, true
+}
+
+func respondStoreTypeFilterError(c *gin.Context, err error) {
+	if strings.HasPrefix(err.Error(), "invalid store type") || strings.HasSuffix(err.Error(), "not found") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	log.Printf("Error resolving store type filter: %v", err)
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch stores"})
+}
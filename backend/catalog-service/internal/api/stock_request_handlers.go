@@ -0,0 +1,376 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/expomadeinworld/madeinworld/catalog-service/internal/models"
+	"github.com/expomadeinworld/madeinworld/catalog-service/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// =================================================================================
+// STOCK REQUEST HANDLERS
+// =================================================================================
+
+// CreateStockRequest handles POST /stock-requests (admin) and POST /operator/stock-requests
+func (h *Handler) CreateStockRequest(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	var req models.CreateStockRequestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	if !inStoreScope(partnerStoreIDs(c), req.StoreID) {
+		respondStoreOutOfScope(c)
+		return
+	}
+
+	requestID, err := h.db.CreateStockRequest(ctx, req, optionalUserID(c))
+	if err != nil {
+		log.Printf("Failed to create stock request: %v", err)
+		switch {
+		case strings.HasSuffix(err.Error(), "not found"), strings.Contains(err.Error(), "has no manufacturer"):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case strings.Contains(err.Error(), "idx_stock_requests_one_open"):
+			c.JSON(http.StatusConflict, gin.H{
+				"error":      "An open stock request already exists for this product and store",
+				"error_code": "STOCK_REQUEST_OPEN",
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create stock request"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"request_id": requestID,
+		"status":     models.StockRequestStatusPending,
+	})
+}
+
+// GetStockRequests handles GET /stock-requests (admin) and GET /operator/stock-requests
+func (h *Handler) GetStockRequests(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	var filter models.StockRequestFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query parameters: " + err.Error()})
+		return
+	}
+
+	filter.StoreIDs = partnerStoreIDs(c)
+
+	requests, err := h.db.GetStockRequests(ctx, filter)
+	if err != nil {
+		log.Printf("Error fetching stock requests: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch stock requests"})
+		return
+	}
+
+	c.JSON(http.StatusOK, requests)
+}
+
+// GetStockRequest handles GET /stock-requests/:id
+func (h *Handler) GetStockRequest(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	requestID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid stock request ID format"})
+		return
+	}
+
+	request, err := h.db.GetStockRequest(ctx, requestID)
+	if err != nil {
+		respondStockRequestError(c, requestID, err, "Failed to fetch stock request")
+		return
+	}
+	if !inStoreScope(partnerStoreIDs(c), request.StoreID) {
+		respondStoreOutOfScope(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, request)
+}
+
+// UpdateStockRequestStatus handles PUT /stock-requests/:id/status (admin)
+func (h *Handler) UpdateStockRequestStatus(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	requestID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid stock request ID format"})
+		return
+	}
+
+	var req models.UpdateStockRequestStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	if !req.Status.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid status '%s'", req.Status)})
+		return
+	}
+
+	if err := h.db.UpdateStockRequestStatus(ctx, requestID, req.Status, optionalUserID(c), req.Note, nil, req.QuantityConfirmed); err != nil {
+		respondStockRequestError(c, requestID, err, "Failed to update stock request")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Stock request updated successfully",
+		"request_id": requestID,
+		"status":     req.Status,
+	})
+}
+
+// CancelStockRequest handles POST /operator/stock-requests/:id/cancel
+func (h *Handler) CancelStockRequest(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	requestID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid stock request ID format"})
+		return
+	}
+
+	var body struct {
+		Note string `json:"note"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+			return
+		}
+	}
+
+	if !h.requireStockRequestInScope(ctx, c, requestID) {
+		return
+	}
+
+	if err := h.db.UpdateStockRequestStatus(ctx, requestID, models.StockRequestStatusCancelled, optionalUserID(c), body.Note, nil, nil); err != nil {
+		respondStockRequestError(c, requestID, err, "Failed to cancel stock request")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Stock request cancelled",
+		"request_id": requestID,
+		"status":     models.StockRequestStatusCancelled,
+	})
+}
+
+// VerifyStockRequest handles POST /stock-requests/:id/verify and POST /operator/stock-requests/:id/verify
+func (h *Handler) VerifyStockRequest(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	requestID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid stock request ID format"})
+		return
+	}
+
+	var req models.VerifyStockRequestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	if !h.requireStockRequestInScope(ctx, c, requestID) {
+		return
+	}
+
+	if err := h.db.VerifyStockRequest(ctx, requestID, optionalUserID(c), req); err != nil {
+		respondStockRequestError(c, requestID, err, "Failed to verify stock request")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":           "Stock request verified and inventory updated",
+		"request_id":        requestID,
+		"status":            models.StockRequestStatusVerified,
+		"quantity_verified": req.QuantityVerified,
+	})
+}
+
+// RunLowStockCheck handles POST /stock-requests/low-stock-check (admin).
+// It runs the automatic trigger immediately instead of waiting for the background interval.
+func (h *Handler) RunLowStockCheck(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	defaults := services.LowStockDefaultsFromEnv()
+	result, err := h.db.RunLowStockCheck(ctx, defaults)
+	if err != nil {
+		log.Printf("Low stock check failed: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to run low stock check"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":                  "Low stock check completed",
+		"alerts_raised":            result.AlertsRaised,
+		"alerts_resolved":          result.AlertsResolved,
+		"requests_created":         result.RequestsCreated,
+		"default_reorder_point":    defaults.ReorderPoint,
+		"default_reorder_quantity": defaults.ReorderQuantity,
+	})
+}
+
+// =================================================================================
+// MANUFACTURER STOCK REQUEST HANDLERS
+// =================================================================================
+
+// GetMyStockRequests handles GET /manufacturer/stock-requests
+func (h *Handler) GetMyStockRequests(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	var filter models.StockRequestFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query parameters: " + err.Error()})
+		return
+	}
+	manufacturerID := getManufacturerID(c)
+	filter.ManufacturerID = &manufacturerID
+
+	requests, err := h.db.GetStockRequests(ctx, filter)
+	if err != nil {
+		log.Printf("Error fetching stock requests for manufacturer %d: %v", manufacturerID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch stock requests"})
+		return
+	}
+
+	c.JSON(http.StatusOK, requests)
+}
+
+// ConfirmMyStockRequest handles POST /manufacturer/stock-requests/:id/confirm
+func (h *Handler) ConfirmMyStockRequest(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	requestID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid stock request ID format"})
+		return
+	}
+
+	var req models.ConfirmStockRequestRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+			return
+		}
+	}
+	if req.QuantityConfirmed != nil && *req.QuantityConfirmed < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "quantity_confirmed must be at least 1"})
+		return
+	}
+
+	manufacturerID := getManufacturerID(c)
+	err = h.db.UpdateStockRequestStatus(ctx, requestID, models.StockRequestStatusConfirmedByManufacturer,
+		optionalUserID(c), req.Note, &manufacturerID, req.QuantityConfirmed)
+	if err != nil {
+		respondStockRequestError(c, requestID, err, "Failed to confirm stock request")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Stock request confirmed",
+		"request_id": requestID,
+		"status":     models.StockRequestStatusConfirmedByManufacturer,
+	})
+}
+
+// MarkMyStockRequestReady handles POST /manufacturer/stock-requests/:id/ready
+func (h *Handler) MarkMyStockRequestReady(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	requestID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid stock request ID format"})
+		return
+	}
+
+	manufacturerID := getManufacturerID(c)
+	err = h.db.UpdateStockRequestStatus(ctx, requestID, models.StockRequestStatusReadyForPickup,
+		optionalUserID(c), "", &manufacturerID, nil)
+	if err != nil {
+		respondStockRequestError(c, requestID, err, "Failed to update stock request")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Stock request is ready for pickup",
+		"request_id": requestID,
+		"status":     models.StockRequestStatusReadyForPickup,
+	})
+}
+
+// respondStockRequestError maps stock request errors to HTTP responses
+func respondStockRequestError(c *gin.Context, requestID int, err error, fallback string) {
+	log.Printf("Stock request %d: %v", requestID, err)
+	switch {
+	case err.Error() == fmt.Sprintf("stock request with ID %d not found", requestID):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Stock request not found"})
+	case strings.HasPrefix(err.Error(), "cannot move stock request"), strings.Contains(err.Error(), "verified through verification"):
+		c.JSON(http.StatusConflict, gin.H{
+			"error":      err.Error(),
+			"error_code": "INVALID_STATUS_TRANSITION",
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
+
+// requireStockRequestInScope checks that a Partner caller may act on the stock request's
+// destination store and writes the error response when not
+func (h *Handler) requireStockRequestInScope(ctx context.Context, c *gin.Context, requestID int) bool {
+	storeIDs := partnerStoreIDs(c)
+	if storeIDs == nil {
+		return true
+	}
+
+	storeID, err := h.db.GetStockRequestStoreID(ctx, requestID)
+	if err != nil {
+		respondStockRequestError(c, requestID, err, "Failed to fetch stock request")
+		return false
+	}
+	if !inStoreScope(storeIDs, storeID) {
+		respondStoreOutOfScope(c)
+		return false
+	}
+	return true
+}
+
+// respondStoreOutOfScope rejects a Partner request for a store outside their assignment
+func respondStoreOutOfScope(c *gin.Context) {
+	c.JSON(http.StatusForbidden, gin.H{
+		"error":      "This store is not assigned to your partner account",
+		"error_code": "STORE_OUT_OF_SCOPE",
+	})
+}
+
+// optionalUserID returns the authenticated user ID, or nil for header-only admin requests
+func optionalUserID(c *gin.Context) *string {
+	if userID, ok := GetUserID(c); ok && userID != "" {
+		return &userID
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/expomadeinworld/madeinworld/catalog-service/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// =================================================================================
+// STOCK THRESHOLD AND LOW STOCK ALERT HANDLERS
+// =================================================================================
+
+// GetStockThresholds handles GET /stock-thresholds
+func (h *Handler) GetStockThresholds(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	var filter models.StockThresholdFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query parameters: " + err.Error()})
+		return
+	}
+
+	thresholds, err := h.db.GetStockThresholds(ctx, filter)
+	if err != nil {
+		log.Printf("Error fetching stock thresholds: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch stock thresholds"})
+		return
+	}
+
+	c.JSON(http.StatusOK, thresholds)
+}
+
+// UpsertStockThreshold handles PUT /stock-thresholds
+func (h *Handler) UpsertStockThreshold(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	var req models.UpsertStockThresholdRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	if req.ReorderPoint < req.SafetyStock {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "reorder_point must not be below safety_stock"})
+		return
+	}
+
+	thresholdID, err := h.db.UpsertStockThreshold(ctx, req)
+	if err != nil {
+		if strings.HasSuffix(err.Error(), "not found") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("Failed to save stock threshold: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save stock threshold"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":      "Stock threshold saved",
+		"threshold_id": thresholdID,
+	})
+}
+
+// DeleteStockThreshold handles DELETE /stock-thresholds/:id
+func (h *Handler) DeleteStockThreshold(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	thresholdID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid threshold ID"})
+		return
+	}
+
+	if err := h.db.DeleteStockThreshold(ctx, thresholdID); err != nil {
+		respondStoreError(c, err, "Failed to delete stock threshold")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Stock threshold deleted"})
+}
+
+// GetLowStockAlerts handles GET /low-stock-alerts
+func (h *Handler) GetLowStockAlerts(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	var filter models.LowStockAlertFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query parameters: " + err.Error()})
+		return
+	}
+	if filter.Status != "" && !filter.Status.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid status '%s'", filter.Status)})
+		return
+	}
+
+	alerts, err := h.db.GetLowStockAlerts(ctx, filter)
+	if err != nil {
+		log.Printf("Error fetching low stock alerts: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch low stock alerts"})
+		return
+	}
+
+	c.JSON(http.StatusOK, alerts)
+}
+
+// AcknowledgeLowStockAlert handles POST /low-stock-alerts/:id/acknowledge
+func (h *Handler) AcknowledgeLowStockAlert(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	alertID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid alert ID"})
+		return
+	}
+
+	if err := h.db.AcknowledgeLowStockAlert(ctx, alertID, optionalUserID(c)); err != nil {
+		if strings.HasPrefix(err.Error(), "cannot acknowledge") {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		respondStoreError(c, err, "Failed to acknowledge low stock alert")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"alert_id": alertID,
+		"status":   models.LowStockAlertAcknowledged,
+	})
+}
+
+// GetMyLowStockAlerts handles GET /manufacturer/low-stock-alerts
+func (h *Handler) GetMyLowStockAlerts(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	var filter models.LowStockAlertFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query parameters: " + err.Error()})
+		return
+	}
+	if filter.Status != "" && !filter.Status.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid status '%s'", filter.Status)})
+		return
+	}
+	manufacturerID := getManufacturerID(c)
+	filter.ManufacturerID = &manufacturerID
+
+	alerts, err := h.db.GetLowStockAlerts(ctx, filter)
+	if err != nil {
+		log.Printf("Error fetching low stock alerts for manufacturer %d: %v", manufacturerID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch low stock alerts"})
+		return
+	}
+
+	c.JSON(http.StatusOK, alerts)
+}
@@ -0,0 +1,210 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/expomadeinworld/madeinworld/catalog-service/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// =================================================================================
+// STORE OPENING HOURS HANDLERS
+// =================================================================================
+
+// GetStoreHours handles GET /stores/:id/hours
+func (h *Handler) GetStoreHours(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	storeID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid store ID"})
+		return
+	}
+
+	hours, err := h.db.GetStoreHours(ctx, storeID)
+	if err != nil {
+		respondStoreError(c, err, "Failed to fetch store hours")
+		return
+	}
+
+	c.JSON(http.StatusOK, hours)
+}
+
+// UpdateStoreHours handles PUT /stores/:id/hours
+func (h *Handler) UpdateStoreHours(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	storeID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid store ID"})
+		return
+	}
+
+	var req models.UpdateStoreHoursRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	if !h.validTimezone(ctx, req.Timezone) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid timezone: " + req.Timezone})
+		return
+	}
+	if err := validateWeeklyHours(req.Weekly); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.db.ReplaceStoreHours(ctx, storeID, req); err != nil {
+		respondStoreError(c, err, "Failed to update store hours")
+		return
+	}
+
+	hours, err := h.db.GetStoreHours(ctx, storeID)
+	if err != nil {
+		respondStoreError(c, err, "Failed to fetch store hours")
+		return
+	}
+
+	c.JSON(http.StatusOK, hours)
+}
+
+// CreateStoreHoursException handles POST /stores/:id/hours/exceptions
+func (h *Handler) CreateStoreHoursException(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	storeID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid store ID"})
+		return
+	}
+
+	var req models.CreateStoreHoursExceptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	if _, err := time.Parse("2006-01-02", req.Date); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "date must be in YYYY-MM-DD format"})
+		return
+	}
+	if req.IsClosed {
+		req.OpensAt, req.ClosesAt = nil, nil
+	} else {
+		if req.OpensAt == nil || req.ClosesAt == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "opens_at and closes_at are required unless the store is closed"})
+			return
+		}
+		if err := validateInterval(*req.OpensAt, *req.ClosesAt); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	exceptionID, err := h.db.UpsertStoreHoursException(ctx, storeID, req)
+	if err != nil {
+		respondStoreError(c, err, "Failed to save hours exception")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":      "Hours exception saved",
+		"exception_id": exceptionID,
+	})
+}
+
+// DeleteStoreHoursException handles DELETE /stores/:id/hours/exceptions/:exception_id
+func (h *Handler) DeleteStoreHoursException(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	storeID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid store ID"})
+		return
+	}
+	exceptionID, err := strconv.Atoi(c.Param("exception_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid exception ID"})
+		return
+	}
+
+	if err := h.db.DeleteStoreHoursException(ctx, storeID, exceptionID); err != nil {
+		respondStoreError(c, err, "Failed to delete hours exception")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Hours exception deleted"})
+}
+
+// validTimezone reports whether the IANA timezone name is known to the database
+func (h *Handler) validTimezone(ctx context.Context, name string) bool {
+	valid, err := h.db.IsValidTimezone(ctx, name)
+	if err != nil {
+		log.Printf("Failed to validate timezone %q: %v", name, err)
+		return false
+	}
+	return valid
+}
+
+// respondStoreError maps store hours and store product errors to HTTP responses
+func respondStoreError(c *gin.Context, err error, fallback string) {
+	if strings.HasSuffix(err.Error(), "not found") {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	log.Printf("%s: %v", fallback, err)
+	c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+}
+
+// validateWeeklyHours checks every interval and rejects overlapping intervals on the same day
+func validateWeeklyHours(weekly []models.OpeningHours) error {
+	byDay := make(map[int][]models.OpeningHours)
+	for _, h := range weekly {
+		if err := validateInterval(h.OpensAt, h.ClosesAt); err != nil {
+			return err
+		}
+		byDay[h.DayOfWeek] = append(byDay[h.DayOfWeek], h)
+	}
+
+	for day, intervals := range byDay {
+		sort.Slice(intervals, func(i, j int) bool { return intervals[i].OpensAt < intervals[j].OpensAt })
+		for i := 1; i < len(intervals); i++ {
+			if intervals[i].OpensAt < intervals[i-1].ClosesAt {
+				return fmt.Errorf("opening hours overlap on day %d", day)
+			}
+		}
+	}
+	return nil
+}
+
+// validateInterval checks that both times are HH:MM and the interval ends after it starts.
+// 24:00 is accepted as a closing time.
+func validateInterval(opensAt, closesAt string) error {
+	if !isClock(opensAt, false) || !isClock(closesAt, true) {
+		return fmt.Errorf("times must be in HH:MM format")
+	}
+	if closesAt <= opensAt {
+		return fmt.Errorf("closing time %s must be after opening time %s", closesAt, opensAt)
+	}
+	return nil
+}
+
+func isClock(value string, allowMidnightEnd bool) bool {
+	if allowMidnightEnd && value == "24:00" {
+		return true
+	}
+	_, err := time.Parse("15:04", value)
+	return err == nil && len(value) == 5
+}
@@ -0,0 +1,116 @@
+package api
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/expomadeinworld/madeinworld/catalog-service/internal/db"
+	"github.com/expomadeinworld/madeinworld/catalog-service/internal/geo"
+	"github.com/expomadeinworld/madeinworld/catalog-service/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// =================================================================================
+// STORE LOCATION HANDLERS
+// =================================================================================
+
+// GetNearbyStores handles GET /stores/nearby
+func (h *Handler) GetNearbyStores(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	var req models.NearbyStoresRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query parameters: " + err.Error()})
+		return
+	}
+
+	if req.RadiusKm == 0 {
+		req.RadiusKm = 10
+	}
+	if req.Limit == 0 {
+		req.Limit = 20
+	}
+
+	types, ok, err := h.storeTypeFilter(ctx, req.Type, req.MiniAppType)
+	if err != nil {
+		respondStoreTypeFilterError(c, err)
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusOK, []models.Store{})
+		return
+	}
+
+	center := geo.Point{Lat: *req.Lat, Lng: *req.Lng}
+	stores, err := h.db.SearchStores(ctx, db.StoreSearch{
+		Box:      geo.BoxAround(center.Lat, center.Lng, req.RadiusKm),
+		Types:    types,
+		Center:   &center,
+		RadiusKm: req.RadiusKm,
+		OpenNow:  req.OpenNow,
+		Limit:    req.Limit,
+	})
+	if err != nil {
+		log.Printf("Error searching nearby stores: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch stores"})
+		return
+	}
+
+	c.JSON(http.StatusOK, stores)
+}
+
+// GetStoresInBounds handles GET /stores/in-bounds
+func (h *Handler) GetStoresInBounds(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	var req models.StoresInBoundsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query parameters: " + err.Error()})
+		return
+	}
+
+	if *req.MinLat > *req.MaxLat {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "min_lat must not be greater than max_lat"})
+		return
+	}
+	if (req.UserLat == nil) != (req.UserLng == nil) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "user_lat and user_lng must be provided together"})
+		return
+	}
+	if req.Limit == 0 {
+		req.Limit = 200
+	}
+
+	types, ok, err := h.storeTypeFilter(ctx, req.Type, req.MiniAppType)
+	if err != nil {
+		respondStoreTypeFilterError(c, err)
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusOK, []models.Store{})
+		return
+	}
+
+	search := db.StoreSearch{
+		Box:     geo.Box{MinLat: *req.MinLat, MinLng: *req.MinLng, MaxLat: *req.MaxLat, MaxLng: *req.MaxLng},
+		Types:   types,
+		OpenNow: req.OpenNow,
+		Limit:   req.Limit,
+	}
+	if req.UserLat != nil {
+		search.Center = &geo.Point{Lat: *req.UserLat, Lng: *req.UserLng}
+	}
+
+	stores, err := h.db.SearchStores(ctx, search)
+	if err != nil {
+		log.Printf("Error searching stores in bounds: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch stores"})
+		return
+	}
+
+	c.JSON(http.StatusOK, stores)
+}
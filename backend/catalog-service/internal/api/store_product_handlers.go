@@ -0,0 +1,90 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/expomadeinworld/madeinworld/catalog-service/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// =================================================================================
+// STORE PRODUCT OVERRIDE HANDLERS
+// =================================================================================
+
+// GetStoreProductOverrides handles GET /stores/:id/product-overrides
+func (h *Handler) GetStoreProductOverrides(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	storeID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid store ID"})
+		return
+	}
+
+	overrides, err := h.db.GetStoreProductOverrides(ctx, storeID)
+	if err != nil {
+		respondStoreError(c, err, "Failed to fetch store product overrides")
+		return
+	}
+
+	c.JSON(http.StatusOK, overrides)
+}
+
+// UpsertStoreProductOverride handles PUT /stores/:id/product-overrides/:product_id
+func (h *Handler) UpsertStoreProductOverride(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	storeID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid store ID"})
+		return
+	}
+	productID, err := strconv.Atoi(c.Param("product_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	var req models.UpsertStoreProductOverrideRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	override, err := h.db.UpsertStoreProductOverride(ctx, storeID, productID, req)
+	if err != nil {
+		respondStoreError(c, err, "Failed to save store product override")
+		return
+	}
+
+	c.JSON(http.StatusOK, override)
+}
+
+// DeleteStoreProductOverride handles DELETE /stores/:id/product-overrides/:product_id
+func (h *Handler) DeleteStoreProductOverride(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	storeID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid store ID"})
+		return
+	}
+	productID, err := strconv.Atoi(c.Param("product_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	if err := h.db.DeleteStoreProductOverride(ctx, storeID, productID); err != nil {
+		respondStoreError(c, err, "Failed to delete store product override")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Store product override deleted"})
+}
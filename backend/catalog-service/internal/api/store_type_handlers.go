@@ -0,0 +1,32 @@
+package api
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/expomadeinworld/madeinworld/catalog-service/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// =================================================================================
+// STORE TYPE HANDLERS
+// =================================================================================
+
+// GetStoreTypes handles GET /store-types
+func (h *Handler) GetStoreTypes(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	locale := c.DefaultQuery("locale", models.DefaultLocale)
+
+	storeTypes, err := h.db.GetStoreTypes(ctx, locale)
+	if err != nil {
+		log.Printf("Error fetching store types: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch store types"})
+		return
+	}
+
+	c.JSON(http.StatusOK, storeTypes)
+}
@@ -2,6 +2,7 @@ package db
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net"
@@ -213,6 +214,21 @@ func (db *Database) CreateProduct(ctx context.Context, product models.Product) (
 		return 0, fmt.Errorf("failed to insert product: %w", err)
 	}
 
+	// The stock of a store product is its inventory at that store
+	if product.StoreID != nil && product.StockLeft > 0 {
+		stock := product.StockLeft
+		_, err = applyStockChange(ctx, tx, stockChange{
+			ProductID: productID,
+			StoreID:   *product.StoreID,
+			Type:      models.StockMovementInitial,
+			SetTo:     &stock,
+			Reason:    "Product created",
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+
 	// Insert category mappings if provided
 	if len(product.CategoryIds) > 0 {
 		for _, categoryIDStr := range product.CategoryIds {
@@ -320,6 +336,16 @@ func (db *Database) UpdateProduct(ctx context.Context, productID int, product mo
 	}
 	defer tx.Rollback(ctx)
 
+	// Remember the stock so an edited stock level can be booked in the inventory ledger
+	var previousStock int
+	err = tx.QueryRow(ctx, "SELECT stock_left FROM products WHERE product_id = $1 FOR UPDATE", productID).Scan(&previousStock)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("product with ID %d not found", productID)
+		}
+		return fmt.Errorf("failed to lock product: %w", err)
+	}
+
 	// Update the basic product fields
 	query := `
         UPDATE products
@@ -372,6 +398,21 @@ func (db *Database) UpdateProduct(ctx context.Context, productID int, product mo
 		return fmt.Errorf("product with ID %d not found", productID)
 	}
 
+	// The stock of a store product is its inventory at that store
+	if product.StoreID != nil && product.StockLeft != previousStock {
+		stock := product.StockLeft
+		_, err = applyStockChange(ctx, tx, stockChange{
+			ProductID: productID,
+			StoreID:   *product.StoreID,
+			Type:      models.StockMovementAdjustment,
+			SetTo:     &stock,
+			Reason:    "Product edit",
+		})
+		if err != nil {
+			return err
+		}
+	}
+
 	// Update category mappings
 	// Delete existing category mappings
 	_, err = tx.Exec(ctx, "DELETE FROM product_category_mapping WHERE product_id = $1", productID)
@@ -0,0 +1,426 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/expomadeinworld/madeinworld/catalog-service/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// stockChange is one inventory change to book in the ledger
+type stockChange struct {
+	ProductID      int
+	StoreID        int
+	Type           models.StockMovementType
+	Change         int  // relative change, ignored when SetTo is set
+	SetTo          *int // absolute quantity for counts and product edits
+	Reason         string
+	ActorID        *string
+	OrderID        *string
+	StockRequestID *int
+	TransferID     *string
+}
+
+// applyStockChange locks (or creates) the product's inventory row at the store, applies the
+// change, mirrors products.stock_left when the store is the product's own store and writes
+// the movement. It returns nil when the change leaves the quantity as it is.
+func applyStockChange(ctx context.Context, tx pgx.Tx, sc stockChange) (*models.StockMovement, error) {
+	var productTitle, storeName *string
+	err := tx.QueryRow(ctx, `
+        SELECT (SELECT title FROM products WHERE product_id = $1),
+               (SELECT name FROM stores WHERE store_id = $2)
+    `, sc.ProductID, sc.StoreID).Scan(&productTitle, &storeName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query product and store: %w", err)
+	}
+	if productTitle == nil {
+		return nil, fmt.Errorf("product with ID %d not found", sc.ProductID)
+	}
+	if storeName == nil {
+		return nil, fmt.Errorf("store with ID %d not found", sc.StoreID)
+	}
+
+	_, err = tx.Exec(ctx, `
+        INSERT INTO inventory (product_id, store_id, quantity, last_updated)
+        VALUES ($1, $2, 0, CURRENT_TIMESTAMP)
+        ON CONFLICT (product_id, store_id) DO NOTHING
+    `, sc.ProductID, sc.StoreID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create inventory row: %w", err)
+	}
+
+	var before int
+	err = tx.QueryRow(ctx, `
+        SELECT quantity FROM inventory
+        WHERE product_id = $1 AND store_id = $2
+        FOR UPDATE
+    `, sc.ProductID, sc.StoreID).Scan(&before)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock inventory: %w", err)
+	}
+
+	change := sc.Change
+	if sc.SetTo != nil {
+		change = *sc.SetTo - before
+	}
+	if change == 0 {
+		return nil, nil
+	}
+	after := before + change
+	if after < 0 {
+		return nil, fmt.Errorf("insufficient stock for product %d at store %d: %d in stock", sc.ProductID, sc.StoreID, before)
+	}
+
+	_, err = tx.Exec(ctx, `
+        UPDATE inventory
+        SET quantity = $3, last_updated = CURRENT_TIMESTAMP
+        WHERE product_id = $1 AND store_id = $2
+    `, sc.ProductID, sc.StoreID, after)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update inventory: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+        UPDATE products
+        SET stock_left = $3, updated_at = CURRENT_TIMESTAMP
+        WHERE product_id = $1 AND store_id = $2
+    `, sc.ProductID, sc.StoreID, after)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update product stock: %w", err)
+	}
+
+	m := &models.StockMovement{
+		ProductID:      sc.ProductID,
+		ProductTitle:   *productTitle,
+		StoreID:        sc.StoreID,
+		StoreName:      *storeName,
+		Type:           sc.Type,
+		QuantityChange: change,
+		QuantityBefore: before,
+		QuantityAfter:  after,
+		ActorID:        sc.ActorID,
+		OrderID:        sc.OrderID,
+		StockRequestID: sc.StockRequestID,
+		TransferID:     sc.TransferID,
+	}
+	if sc.Reason != "" {
+		m.Reason = &sc.Reason
+	}
+
+	err = tx.QueryRow(ctx, `
+        INSERT INTO stock_movements
+            (product_id, store_id, movement_type, quantity_change, quantity_before, quantity_after,
+             reason, actor_id, order_id, stock_request_id, transfer_id)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+        RETURNING movement_id, created_at
+    `, m.ProductID, m.StoreID, m.Type, m.QuantityChange, m.QuantityBefore, m.QuantityAfter,
+		m.Reason, m.ActorID, m.OrderID, m.StockRequestID, m.TransferID).Scan(&m.ID, &m.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record stock movement: %w", err)
+	}
+
+	return m, nil
+}
+
+// AdjustStock adds or removes stock of a product at a store
+func (db *Database) AdjustStock(ctx context.Context, req models.StockAdjustmentRequest, actorID *string) (*models.StockMovement, error) {
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	movement, err := applyStockChange(ctx, tx, stockChange{
+		ProductID: req.ProductID,
+		StoreID:   req.StoreID,
+		Type:      models.StockMovementAdjustment,
+		Change:    req.QuantityChange,
+		Reason:    req.Reason,
+		ActorID:   actorID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return movement, nil
+}
+
+// TransferStock moves stock of a product between two stores as a pair of movements
+func (db *Database) TransferStock(ctx context.Context, req models.StockTransferRequest, actorID *string) (*models.StockTransferResponse, error) {
+	if req.FromStoreID == req.ToStoreID {
+		return nil, fmt.Errorf("cannot transfer stock to the same store")
+	}
+
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var transferID string
+	if err := tx.QueryRow(ctx, "SELECT gen_random_uuid()::text").Scan(&transferID); err != nil {
+		return nil, fmt.Errorf("failed to create transfer ID: %w", err)
+	}
+
+	out := stockChange{
+		ProductID:  req.ProductID,
+		StoreID:    req.FromStoreID,
+		Type:       models.StockMovementTransferOut,
+		Change:     -req.Quantity,
+		Reason:     req.Reason,
+		ActorID:    actorID,
+		TransferID: &transferID,
+	}
+	in := out
+	in.StoreID = req.ToStoreID
+	in.Type = models.StockMovementTransferIn
+	in.Change = req.Quantity
+
+	// Lock the two inventory rows in store order so concurrent transfers cannot deadlock
+	changes := []stockChange{out, in}
+	if req.ToStoreID < req.FromStoreID {
+		changes = []stockChange{in, out}
+	}
+
+	resp := &models.StockTransferResponse{TransferID: transferID}
+	for _, sc := range changes {
+		movement, err := applyStockChange(ctx, tx, sc)
+		if err != nil {
+			return nil, err
+		}
+		if sc.Type == models.StockMovementTransferOut {
+			resp.Out = *movement
+		} else {
+			resp.In = *movement
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return resp, nil
+}
+
+// CountStock sets every counted product at the store to its counted quantity
+func (db *Database) CountStock(ctx context.Context, req models.StockCountRequest, actorID *string) (*models.StockCountResponse, error) {
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	reason := req.Reason
+	if reason == "" {
+		reason = "Stock count"
+	}
+
+	resp := &models.StockCountResponse{StoreID: req.StoreID, Movements: []models.StockMovement{}}
+	counted := make(map[int]bool, len(req.Items))
+	for _, item := range req.Items {
+		if counted[item.ProductID] {
+			return nil, fmt.Errorf("product %d is counted more than once", item.ProductID)
+		}
+		counted[item.ProductID] = true
+
+		quantity := item.CountedQuantity
+		movement, err := applyStockChange(ctx, tx, stockChange{
+			ProductID: item.ProductID,
+			StoreID:   req.StoreID,
+			Type:      models.StockMovementCount,
+			SetTo:     &quantity,
+			Reason:    reason,
+			ActorID:   actorID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if movement != nil {
+			resp.Movements = append(resp.Movements, *movement)
+		}
+	}
+	resp.Counted = len(req.Items)
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return resp, nil
+}
+
+// GetInventoryLevels lists current inventory, optionally for one product or store
+func (db *Database) GetInventoryLevels(ctx context.Context, filter models.InventoryFilter) ([]models.InventoryLevel, error) {
+	query := `
+        SELECT i.product_id, p.title, p.sku, i.store_id, s.name, i.quantity, i.reserved_quantity,
+               i.quantity - i.reserved_quantity, i.last_updated
+        FROM inventory i
+        JOIN products p ON p.product_id = i.product_id
+        JOIN stores s ON s.store_id = i.store_id
+        WHERE 1=1
+    `
+	args := []interface{}{}
+	argIndex := 1
+
+	if filter.ProductID != nil {
+		query += fmt.Sprintf(" AND i.product_id = $%d", argIndex)
+		args = append(args, *filter.ProductID)
+		argIndex++
+	}
+	if filter.StoreID != nil {
+		query += fmt.Sprintf(" AND i.store_id = $%d", argIndex)
+		args = append(args, *filter.StoreID)
+		argIndex++
+	}
+	query += " ORDER BY s.name, p.title"
+
+	rows, err := db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query inventory: %w", err)
+	}
+	defer rows.Close()
+
+	levels := []models.InventoryLevel{}
+	for rows.Next() {
+		var l models.InventoryLevel
+		if err := rows.Scan(&l.ProductID, &l.ProductTitle, &l.ProductSKU, &l.StoreID, &l.StoreName,
+			&l.Quantity, &l.ReservedQuantity, &l.Available, &l.LastUpdated); err != nil {
+			return nil, fmt.Errorf("failed to scan inventory: %w", err)
+		}
+		levels = append(levels, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating inventory: %w", err)
+	}
+
+	return levels, nil
+}
+
+// GetStockMovements lists ledger entries, newest first
+func (db *Database) GetStockMovements(ctx context.Context, filter models.StockMovementFilter) (*models.StockMovementListResponse, error) {
+	where := " WHERE 1=1"
+	args := []interface{}{}
+	argIndex := 1
+
+	if filter.ProductID != nil {
+		where += fmt.Sprintf(" AND sm.product_id = $%d", argIndex)
+		args = append(args, *filter.ProductID)
+		argIndex++
+	}
+	if filter.StoreID != nil {
+		where += fmt.Sprintf(" AND sm.store_id = $%d", argIndex)
+		args = append(args, *filter.StoreID)
+		argIndex++
+	}
+	if filter.Type != "" {
+		where += fmt.Sprintf(" AND sm.movement_type = $%d", argIndex)
+		args = append(args, filter.Type)
+		argIndex++
+	}
+	if filter.OrderID != "" {
+		where += fmt.Sprintf(" AND sm.order_id::text = $%d", argIndex)
+		args = append(args, filter.OrderID)
+		argIndex++
+	}
+	if filter.DateFrom != "" {
+		where += fmt.Sprintf(" AND sm.created_at >= $%d::date", argIndex)
+		args = append(args, filter.DateFrom)
+		argIndex++
+	}
+	if filter.DateTo != "" {
+		where += fmt.Sprintf(" AND sm.created_at < $%d::date + 1", argIndex)
+		args = append(args, filter.DateTo)
+		argIndex++
+	}
+
+	resp := &models.StockMovementListResponse{
+		Movements: []models.StockMovement{},
+		Page:      filter.Page,
+		Limit:     filter.Limit,
+	}
+
+	if err := db.Pool.QueryRow(ctx, "SELECT COUNT(*) FROM stock_movements sm"+where, args...).Scan(&resp.Total); err != nil {
+		return nil, fmt.Errorf("failed to count stock movements: %w", err)
+	}
+
+	query := `
+        SELECT sm.movement_id, sm.product_id, p.title, sm.store_id, s.name, sm.movement_type,
+               sm.quantity_change, sm.quantity_before, sm.quantity_after, sm.reason, sm.actor_id::text,
+               sm.order_id::text, sm.stock_request_id, sm.transfer_id::text, sm.created_at
+        FROM stock_movements sm
+        JOIN products p ON p.product_id = sm.product_id
+        JOIN stores s ON s.store_id = sm.store_id
+    ` + where + fmt.Sprintf(" ORDER BY sm.created_at DESC, sm.movement_id DESC LIMIT $%d OFFSET $%d", argIndex, argIndex+1)
+	args = append(args, filter.Limit, (filter.Page-1)*filter.Limit)
+
+	rows, err := db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query stock movements: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var m models.StockMovement
+		if err := rows.Scan(&m.ID, &m.ProductID, &m.ProductTitle, &m.StoreID, &m.StoreName, &m.Type,
+			&m.QuantityChange, &m.QuantityBefore, &m.QuantityAfter, &m.Reason, &m.ActorID,
+			&m.OrderID, &m.StockRequestID, &m.TransferID, &m.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan stock movement: %w", err)
+		}
+		resp.Movements = append(resp.Movements, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating stock movements: %w", err)
+	}
+
+	return resp, nil
+}
+
+// ReconcileInventory compares every inventory quantity with the sum of its movements
+func (db *Database) ReconcileInventory(ctx context.Context, storeID *int) (*models.InventoryReconciliation, error) {
+	query := `
+        SELECT i.product_id, p.title, i.store_id, s.name, i.quantity,
+               COALESCE(l.ledger_quantity, 0)::int
+        FROM inventory i
+        JOIN products p ON p.product_id = i.product_id
+        JOIN stores s ON s.store_id = i.store_id
+        LEFT JOIN (
+            SELECT product_id, store_id, SUM(quantity_change) AS ledger_quantity
+            FROM stock_movements
+            GROUP BY product_id, store_id
+        ) l ON l.product_id = i.product_id AND l.store_id = i.store_id
+        WHERE ($1::int IS NULL OR i.store_id = $1)
+        ORDER BY s.name, p.title
+    `
+
+	rows, err := db.Pool.Query(ctx, query, storeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reconcile inventory: %w", err)
+	}
+	defer rows.Close()
+
+	result := &models.InventoryReconciliation{
+		CheckedAt:     time.Now().UTC(),
+		Discrepancies: []models.InventoryDiscrepancy{},
+	}
+	for rows.Next() {
+		var d models.InventoryDiscrepancy
+		if err := rows.Scan(&d.ProductID, &d.ProductTitle, &d.StoreID, &d.StoreName, &d.Quantity, &d.LedgerQuantity); err != nil {
+			return nil, fmt.Errorf("failed to scan inventory: %w", err)
+		}
+		result.Checked++
+		if d.Quantity != d.LedgerQuantity {
+			d.Difference = d.Quantity - d.LedgerQuantity
+			result.Discrepancies = append(result.Discrepancies, d)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating inventory: %w", err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,407 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/expomadeinworld/madeinworld/catalog-service/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// GetManufacturerByUserID returns the manufacturer linked to a user with the Manufacturer role
+func (db *Database) GetManufacturerByUserID(ctx context.Context, userID string) (*models.Manufacturer, error) {
+	query := "SELECT" + manufacturerColumns + `
+        FROM manufacturers m
+        JOIN users u ON u.id = m.user_id
+        WHERE m.user_id = $1 AND u.role = 'Manufacturer'
+    `
+
+	var m models.Manufacturer
+	if err := scanManufacturer(db.Pool.QueryRow(ctx, query, userID), &m); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("no manufacturer linked to user %s", userID)
+		}
+		return nil, fmt.Errorf("failed to query manufacturer for user: %w", err)
+	}
+
+	return &m, nil
+}
+
+// LinkManufacturerUser links (or, with a nil userID, unlinks) a user account to a manufacturer.
+// The user must hold the Manufacturer role.
+func (db *Database) LinkManufacturerUser(ctx context.Context, manufacturerID int, userID *string) error {
+	if userID != nil {
+		var role string
+		err := db.Pool.QueryRow(ctx, "SELECT role FROM users WHERE id = $1", *userID).Scan(&role)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return fmt.Errorf("user %s not found", *userID)
+			}
+			return fmt.Errorf("failed to query user: %w", err)
+		}
+		if role != "Manufacturer" {
+			return fmt.Errorf("user %s does not have the Manufacturer role", *userID)
+		}
+	}
+
+	result, err := db.Pool.Exec(ctx,
+		"UPDATE manufacturers SET user_id = $2, updated_at = CURRENT_TIMESTAMP WHERE manufacturer_id = $1",
+		manufacturerID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to link manufacturer user: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("manufacturer with ID %d not found", manufacturerID)
+	}
+
+	return nil
+}
+
+// ProductBelongsToManufacturer reports whether the product is owned by the manufacturer
+func (db *Database) ProductBelongsToManufacturer(ctx context.Context, productID, manufacturerID int) (bool, error) {
+	var owned bool
+	err := db.Pool.QueryRow(ctx,
+		"SELECT EXISTS(SELECT 1 FROM products WHERE product_id = $1 AND manufacturer_id = $2)",
+		productID, manufacturerID).Scan(&owned)
+	if err != nil {
+		return false, fmt.Errorf("failed to check product ownership: %w", err)
+	}
+	return owned, nil
+}
+
+// GetManufacturerProducts returns every product (active or not) owned by a manufacturer
+func (db *Database) GetManufacturerProducts(ctx context.Context, manufacturerID int) ([]models.Product, error) {
+	return db.queryManufacturerProducts(ctx, manufacturerID, nil)
+}
+
+// GetManufacturerProduct returns a single product owned by a manufacturer
+func (db *Database) GetManufacturerProduct(ctx context.Context, manufacturerID, productID int) (*models.Product, error) {
+	products, err := db.queryManufacturerProducts(ctx, manufacturerID, &productID)
+	if err != nil {
+		return nil, err
+	}
+	if len(products) == 0 {
+		return nil, fmt.Errorf("product with ID %d not found", productID)
+	}
+	return &products[0], nil
+}
+
+func (db *Database) queryManufacturerProducts(ctx context.Context, manufacturerID int, productID *int) ([]models.Product, error) {
+	query := `
+        SELECT
+            product_id, product_uuid, sku, title, description_short, description_long,
+            manufacturer_id, store_type, mini_app_type, store_id, main_price, strikethrough_price,
+            cost_price, stock_left, minimum_order_quantity, is_active, is_featured,
+            is_mini_app_recommendation, created_at, updated_at
+        FROM products
+        WHERE manufacturer_id = $1 AND ($2::int IS NULL OR product_id = $2)
+        ORDER BY product_id
+    `
+
+	rows, err := db.Pool.Query(ctx, query, manufacturerID, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query manufacturer products: %w", err)
+	}
+	defer rows.Close()
+
+	products := []models.Product{}
+	for rows.Next() {
+		var p models.Product
+		err := rows.Scan(
+			&p.ID,
+			&p.UUID,
+			&p.SKU,
+			&p.Title,
+			&p.DescriptionShort,
+			&p.DescriptionLong,
+			&p.ManufacturerID,
+			&p.StoreType,
+			&p.MiniAppType,
+			&p.StoreID,
+			&p.MainPrice,
+			&p.StrikethroughPrice,
+			&p.CostPrice,
+			&p.StockLeft,
+			&p.MinimumOrderQuantity,
+			&p.IsActive,
+			&p.IsFeatured,
+			&p.IsMiniAppRecommendation,
+			&p.CreatedAt,
+			&p.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan manufacturer product: %w", err)
+		}
+		products = append(products, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating manufacturer products: %w", err)
+	}
+
+	return products, nil
+}
+
+// UpdateManufacturerProduct applies the manufacturer-editable fields to one of their products
+func (db *Database) UpdateManufacturerProduct(ctx context.Context, productID, manufacturerID int, update models.ManufacturerProductUpdate) error {
+	query := `
+        UPDATE products
+        SET
+            title = COALESCE($3, title),
+            description_short = COALESCE($4, description_short),
+            description_long = COALESCE($5, description_long),
+            minimum_order_quantity = COALESCE($6, minimum_order_quantity),
+            updated_at = CURRENT_TIMESTAMP
+        WHERE product_id = $1 AND manufacturer_id = $2
+    `
+	result, err := db.Pool.Exec(ctx, query,
+		productID,
+		manufacturerID,
+		update.Title,
+		update.DescriptionShort,
+		update.DescriptionLong,
+		update.MinimumOrderQuantity,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update product: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("product with ID %d not found", productID)
+	}
+
+	return nil
+}
+
+// priceChangeColumns is the column list shared by price change request reads
+const priceChangeColumns = `
+        r.request_id, r.product_id, p.title, p.sku, r.manufacturer_id, r.requested_by::text,
+        r.current_price, r.proposed_price, r.proposed_strikethrough_price, r.reason, r.status,
+        r.reviewed_by, r.review_note, r.reviewed_at, r.created_at
+`
+
+func scanPriceChangeRequest(row pgx.Row, r *models.PriceChangeRequest) error {
+	return row.Scan(
+		&r.ID,
+		&r.ProductID,
+		&r.ProductTitle,
+		&r.ProductSKU,
+		&r.ManufacturerID,
+		&r.RequestedBy,
+		&r.CurrentPrice,
+		&r.ProposedPrice,
+		&r.ProposedStrikethroughPrice,
+		&r.Reason,
+		&r.Status,
+		&r.ReviewedBy,
+		&r.ReviewNote,
+		&r.ReviewedAt,
+		&r.CreatedAt,
+	)
+}
+
+// CreatePriceChangeRequest records a pending price proposal for a manufacturer's product
+func (db *Database) CreatePriceChangeRequest(ctx context.Context, productID, manufacturerID int, userID string, req models.CreatePriceChangeRequest) (int, error) {
+	var requestID int
+	query := `
+        INSERT INTO product_price_change_requests
+            (product_id, manufacturer_id, requested_by, current_price, proposed_price, proposed_strikethrough_price, reason)
+        SELECT product_id, manufacturer_id, $3, main_price, $4, $5, NULLIF($6, '')
+        FROM products
+        WHERE product_id = $1 AND manufacturer_id = $2
+        RETURNING request_id
+    `
+	err := db.Pool.QueryRow(ctx, query,
+		productID,
+		manufacturerID,
+		userID,
+		req.ProposedPrice,
+		req.ProposedStrikethroughPrice,
+		req.Reason,
+	).Scan(&requestID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, fmt.Errorf("product with ID %d not found", productID)
+		}
+		return 0, fmt.Errorf("failed to insert price change request: %w", err)
+	}
+
+	return requestID, nil
+}
+
+// GetPriceChangeRequests lists price proposals, optionally scoped to a manufacturer and status
+func (db *Database) GetPriceChangeRequests(ctx context.Context, manufacturerID *int, status string) ([]models.PriceChangeRequest, error) {
+	query := "SELECT" + priceChangeColumns + `
+        FROM product_price_change_requests r
+        JOIN products p ON p.product_id = r.product_id
+        WHERE 1=1
+    `
+	args := []interface{}{}
+	argIndex := 1
+
+	if manufacturerID != nil {
+		query += fmt.Sprintf(" AND r.manufacturer_id = $%d", argIndex)
+		args = append(args, *manufacturerID)
+		argIndex++
+	}
+	if status != "" {
+		query += fmt.Sprintf(" AND r.status = $%d", argIndex)
+		args = append(args, status)
+		argIndex++
+	}
+	query += " ORDER BY r.created_at DESC"
+
+	rows, err := db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query price change requests: %w", err)
+	}
+	defer rows.Close()
+
+	requests := []models.PriceChangeRequest{}
+	for rows.Next() {
+		var r models.PriceChangeRequest
+		if err := scanPriceChangeRequest(rows, &r); err != nil {
+			return nil, fmt.Errorf("failed to scan price change request: %w", err)
+		}
+		requests = append(requests, r)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating price change requests: %w", err)
+	}
+
+	return requests, nil
+}
+
+// ReviewPriceChangeRequest approves or rejects a pending proposal.
+// Approval applies the proposed prices to the product in the same transaction.
+func (db *Database) ReviewPriceChangeRequest(ctx context.Context, requestID int, approve bool, reviewerID string, review models.ReviewPriceChangeRequest) error {
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var productID int
+	var status models.PriceChangeStatus
+	var proposedPrice float64
+	var proposedStrikethrough *float64
+	err = tx.QueryRow(ctx, `
+        SELECT product_id, status, proposed_price, proposed_strikethrough_price
+        FROM product_price_change_requests
+        WHERE request_id = $1
+        FOR UPDATE
+    `, requestID).Scan(&productID, &status, &proposedPrice, &proposedStrikethrough)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("price change request with ID %d not found", requestID)
+		}
+		return fmt.Errorf("failed to query price change request: %w", err)
+	}
+	if status != models.PriceChangeStatusPending {
+		return fmt.Errorf("price change request with ID %d is already %s", requestID, status)
+	}
+
+	newStatus := models.PriceChangeStatusRejected
+	if approve {
+		newStatus = models.PriceChangeStatusApproved
+		_, err = tx.Exec(ctx, `
+            UPDATE products
+            SET main_price = $2, strikethrough_price = $3, updated_at = CURRENT_TIMESTAMP
+            WHERE product_id = $1
+        `, productID, proposedPrice, proposedStrikethrough)
+		if err != nil {
+			return fmt.Errorf("failed to apply price change: %w", err)
+		}
+	}
+
+	_, err = tx.Exec(ctx, `
+        UPDATE product_price_change_requests
+        SET status = $2, reviewed_by = NULLIF($3, ''), review_note = NULLIF($4, ''), reviewed_at = CURRENT_TIMESTAMP
+        WHERE request_id = $1
+    `, requestID, newStatus, reviewerID, review.Note)
+	if err != nil {
+		return fmt.Errorf("failed to update price change request: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// WithdrawPriceChangeRequest lets a manufacturer take back one of its own pending proposals.
+// A proposal of another manufacturer is reported as not found.
+func (db *Database) WithdrawPriceChangeRequest(ctx context.Context, requestID, manufacturerID int) error {
+	var withdrawn bool
+	var status models.PriceChangeStatus
+	err := db.Pool.QueryRow(ctx, `
+        WITH target AS (
+            SELECT request_id, status
+            FROM product_price_change_requests
+            WHERE request_id = $1 AND manufacturer_id = $2
+        ), withdrawn AS (
+            UPDATE product_price_change_requests r
+            SET status = $3
+            FROM target
+            WHERE r.request_id = target.request_id AND r.status = $4
+            RETURNING r.request_id
+        )
+        SELECT EXISTS (SELECT 1 FROM withdrawn), target.status FROM target
+    `, requestID, manufacturerID, models.PriceChangeStatusWithdrawn, models.PriceChangeStatusPending).Scan(&withdrawn, &status)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("price change request with ID %d not found", requestID)
+		}
+		return fmt.Errorf("failed to withdraw price change request: %w", err)
+	}
+	if !withdrawn {
+		return fmt.Errorf("price change request with ID %d is already %s", requestID, status)
+	}
+
+	return nil
+}
+
+// GetUserRole returns the role of a user account
+func (db *Database) GetUserRole(ctx context.Context, userID string) (string, error) {
+	var role string
+	err := db.Pool.QueryRow(ctx, "SELECT role FROM users WHERE id = $1", userID).Scan(&role)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", fmt.Errorf("user %s not found", userID)
+		}
+		return "", fmt.Errorf("failed to query user role: %w", err)
+	}
+	return role, nil
+}
+
+// GetUserAccountStatus returns the current account status of a user; an expired suspension
+// reads as active. The error wraps pgx.ErrNoRows when no user has the ID.
+func (db *Database) GetUserAccountStatus(ctx context.Context, userID string) (string, error) {
+	var status string
+	err := db.Pool.QueryRow(ctx,
+		"SELECT user_account_status(status, suspended_until) FROM users WHERE id::text = $1",
+		userID).Scan(&status)
+	if err != nil {
+		return "", fmt.Errorf("failed to query user account status: %w", err)
+	}
+	return status, nil
+}
+
+// GetPartnerStoreIDs returns the stores a Partner-role user may operate, from the
+// partner_store_scope view. The error wraps pgx.ErrNoRows when the user has no partners row.
+func (db *Database) GetPartnerStoreIDs(ctx context.Context, userID string) ([]int, error) {
+	var storeIDs []int
+	err := db.Pool.QueryRow(ctx, `
+        SELECT COALESCE(array_agg(DISTINCT sc.store_id) FILTER (WHERE sc.store_id IS NOT NULL), '{}')
+        FROM partners p
+        LEFT JOIN partner_store_scope sc ON sc.partner_id = p.partner_id
+        WHERE p.user_id::text = $1
+        GROUP BY p.partner_id
+    `, userID).Scan(&storeIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query partner stores: %w", err)
+	}
+	return storeIDs, nil
+}
@@ -0,0 +1,177 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/expomadeinworld/madeinworld/catalog-service/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// manufacturerColumns is the column list shared by all manufacturer reads.
+// Contact fields are nullable in the schema, so they are coalesced to empty strings.
+const manufacturerColumns = `
+        m.manufacturer_id, m.company_name,
+        COALESCE(m.contact_person, ''), COALESCE(m.contact_email, ''), COALESCE(m.address, ''),
+        m.user_id::text, m.created_at, m.updated_at,
+        (SELECT COUNT(*) FROM products p WHERE p.manufacturer_id = m.manufacturer_id)
+`
+
+func scanManufacturer(row pgx.Row, m *models.Manufacturer) error {
+	return row.Scan(
+		&m.ID,
+		&m.CompanyName,
+		&m.ContactPerson,
+		&m.ContactEmail,
+		&m.Address,
+		&m.UserID,
+		&m.CreatedAt,
+		&m.UpdatedAt,
+		&m.ProductCount,
+	)
+}
+
+// GetManufacturers returns all manufacturers ordered by company name
+func (db *Database) GetManufacturers(ctx context.Context, search string) ([]models.Manufacturer, error) {
+	query := "SELECT" + manufacturerColumns + "FROM manufacturers m"
+	args := []interface{}{}
+	if search != "" {
+		query += " WHERE m.company_name ILIKE $1 OR m.contact_email ILIKE $1"
+		args = append(args, "%"+search+"%")
+	}
+	query += " ORDER BY m.company_name"
+
+	rows, err := db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query manufacturers: %w", err)
+	}
+	defer rows.Close()
+
+	manufacturers := []models.Manufacturer{}
+	for rows.Next() {
+		var m models.Manufacturer
+		if err := scanManufacturer(rows, &m); err != nil {
+			return nil, fmt.Errorf("failed to scan manufacturer: %w", err)
+		}
+		manufacturers = append(manufacturers, m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating manufacturers: %w", err)
+	}
+
+	return manufacturers, nil
+}
+
+// GetManufacturer returns a single manufacturer by ID
+func (db *Database) GetManufacturer(ctx context.Context, manufacturerID int) (*models.Manufacturer, error) {
+	query := "SELECT" + manufacturerColumns + "FROM manufacturers m WHERE m.manufacturer_id = $1"
+
+	var m models.Manufacturer
+	if err := scanManufacturer(db.Pool.QueryRow(ctx, query, manufacturerID), &m); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("manufacturer with ID %d not found", manufacturerID)
+		}
+		return nil, fmt.Errorf("failed to query manufacturer: %w", err)
+	}
+
+	return &m, nil
+}
+
+// ManufacturerExists reports whether a manufacturer with the given ID exists
+func (db *Database) ManufacturerExists(ctx context.Context, manufacturerID int) (bool, error) {
+	var exists bool
+	err := db.Pool.QueryRow(ctx,
+		"SELECT EXISTS(SELECT 1 FROM manufacturers WHERE manufacturer_id = $1)",
+		manufacturerID).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check manufacturer: %w", err)
+	}
+	return exists, nil
+}
+
+// CreateManufacturer inserts a new manufacturer and returns its ID
+func (db *Database) CreateManufacturer(ctx context.Context, m models.Manufacturer) (int, error) {
+	var manufacturerID int
+	query := `
+        INSERT INTO manufacturers (company_name, contact_person, contact_email, address)
+        VALUES ($1, NULLIF($2, ''), NULLIF($3, ''), NULLIF($4, ''))
+        RETURNING manufacturer_id
+    `
+	err := db.Pool.QueryRow(ctx, query,
+		m.CompanyName,
+		m.ContactPerson,
+		m.ContactEmail,
+		m.Address,
+	).Scan(&manufacturerID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert manufacturer: %w", err)
+	}
+
+	return manufacturerID, nil
+}
+
+// UpdateManufacturer updates an existing manufacturer
+func (db *Database) UpdateManufacturer(ctx context.Context, manufacturerID int, m models.Manufacturer) error {
+	query := `
+        UPDATE manufacturers
+        SET
+            company_name = $2,
+            contact_person = NULLIF($3, ''),
+            contact_email = NULLIF($4, ''),
+            address = NULLIF($5, ''),
+            updated_at = CURRENT_TIMESTAMP
+        WHERE manufacturer_id = $1
+    `
+	result, err := db.Pool.Exec(ctx, query,
+		manufacturerID,
+		m.CompanyName,
+		m.ContactPerson,
+		m.ContactEmail,
+		m.Address,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update manufacturer: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("manufacturer with ID %d not found", manufacturerID)
+	}
+
+	return nil
+}
+
+// DeleteManufacturer removes a manufacturer that is no longer referenced by any product
+func (db *Database) DeleteManufacturer(ctx context.Context, manufacturerID int) error {
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var productCount int
+	err = tx.QueryRow(ctx,
+		"SELECT COUNT(*) FROM products WHERE manufacturer_id = $1",
+		manufacturerID).Scan(&productCount)
+	if err != nil {
+		return fmt.Errorf("failed to count manufacturer products: %w", err)
+	}
+	if productCount > 0 {
+		return fmt.Errorf("manufacturer with ID %d is still referenced by %d products", manufacturerID, productCount)
+	}
+
+	result, err := tx.Exec(ctx, "DELETE FROM manufacturers WHERE manufacturer_id = $1", manufacturerID)
+	if err != nil {
+		return fmt.Errorf("failed to delete manufacturer: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("manufacturer with ID %d not found", manufacturerID)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
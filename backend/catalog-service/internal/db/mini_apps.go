@@ -0,0 +1,158 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/expomadeinworld/madeinworld/catalog-service/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// miniAppColumns selects a mini-app with the store types it serves
+const miniAppColumns = `
+        m.code, m.display_name, m.requires_store, m.enforces_stock, m.enforces_moq, m.is_active,
+        m.sort_order, m.created_at, m.updated_at,
+        COALESCE(ARRAY(
+            SELECT ms.store_type_code FROM mini_app_store_types ms
+            JOIN store_types st ON st.code = ms.store_type_code
+            WHERE ms.mini_app_code = m.code
+            ORDER BY st.sort_order
+        ), '{}')`
+
+func scanMiniApp(row pgx.Row) (*models.MiniApp, error) {
+	var m models.MiniApp
+	var storeTypes []string
+	if err := row.Scan(&m.Code, &m.DisplayName, &m.RequiresStore, &m.EnforcesStock, &m.EnforcesMOQ,
+		&m.IsActive, &m.SortOrder, &m.CreatedAt, &m.UpdatedAt, &storeTypes); err != nil {
+		return nil, err
+	}
+	m.StoreTypes = make([]models.StoreType, len(storeTypes))
+	for i, t := range storeTypes {
+		m.StoreTypes[i] = models.StoreType(t)
+	}
+	return &m, nil
+}
+
+// GetMiniApps lists the registered mini-apps, only the active ones unless includeInactive is set
+func (db *Database) GetMiniApps(ctx context.Context, includeInactive bool) ([]models.MiniApp, error) {
+	rows, err := db.Pool.Query(ctx, `
+        SELECT `+miniAppColumns+`
+        FROM mini_apps m
+        WHERE m.is_active OR $1
+        ORDER BY m.sort_order, m.code
+    `, includeInactive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query mini-apps: %w", err)
+	}
+	defer rows.Close()
+
+	miniApps := []models.MiniApp{}
+	for rows.Next() {
+		m, err := scanMiniApp(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan mini-app: %w", err)
+		}
+		miniApps = append(miniApps, *m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate mini-apps: %w", err)
+	}
+
+	return miniApps, nil
+}
+
+// GetMiniApp returns a registered mini-app, active or not
+func (db *Database) GetMiniApp(ctx context.Context, code models.MiniAppType) (*models.MiniApp, error) {
+	m, err := scanMiniApp(db.Pool.QueryRow(ctx, `
+        SELECT `+miniAppColumns+`
+        FROM mini_apps m
+        WHERE m.code = $1
+    `, string(code)))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("mini-app '%s' not found", code)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query mini-app: %w", err)
+	}
+	return m, nil
+}
+
+// CreateMiniApp registers a mini-app with the store types it serves
+func (db *Database) CreateMiniApp(ctx context.Context, req models.CreateMiniAppRequest) error {
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `
+        INSERT INTO mini_apps (code, display_name, requires_store, enforces_stock, enforces_moq, is_active, sort_order)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
+        ON CONFLICT (code) DO NOTHING
+    `, string(req.Code), req.DisplayName, req.RequiresStore, req.EnforcesStock, *req.EnforcesMOQ, *req.IsActive, req.SortOrder)
+	if err != nil {
+		return fmt.Errorf("failed to create mini-app: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("mini-app '%s' already exists", req.Code)
+	}
+
+	if err := setMiniAppStoreTypes(ctx, tx, req.Code, req.StoreTypes); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// UpdateMiniApp replaces the settings and store types of a mini-app
+func (db *Database) UpdateMiniApp(ctx context.Context, code models.MiniAppType, req models.UpdateMiniAppRequest) error {
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `
+        UPDATE mini_apps
+        SET display_name = $2, requires_store = $3, enforces_stock = $4, enforces_moq = $5,
+            is_active = $6, sort_order = $7, updated_at = CURRENT_TIMESTAMP
+        WHERE code = $1
+    `, string(code), req.DisplayName, req.RequiresStore, req.EnforcesStock, *req.EnforcesMOQ, *req.IsActive, req.SortOrder)
+	if err != nil {
+		return fmt.Errorf("failed to update mini-app: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("mini-app '%s' not found", code)
+	}
+
+	if err := setMiniAppStoreTypes(ctx, tx, code, req.StoreTypes); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// setMiniAppStoreTypes replaces the store types a mini-app serves
+func setMiniAppStoreTypes(ctx context.Context, tx pgx.Tx, code models.MiniAppType, storeTypes []models.StoreType) error {
+	if _, err := tx.Exec(ctx, "DELETE FROM mini_app_store_types WHERE mini_app_code = $1", string(code)); err != nil {
+		return fmt.Errorf("failed to clear mini-app store types: %w", err)
+	}
+	for _, storeType := range storeTypes {
+		_, err := tx.Exec(ctx, `
+            INSERT INTO mini_app_store_types (mini_app_code, store_type_code)
+            VALUES ($1, $2)
+            ON CONFLICT DO NOTHING
+        `, string(code), string(storeType))
+		if err != nil {
+			return fmt.Errorf("failed to add store type %s to mini-app: %w", storeType, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,359 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/expomadeinworld/madeinworld/catalog-service/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// stockRequestColumns is the column list shared by stock request reads
+const stockRequestColumns = `
+        sr.request_id, sr.product_id, p.title, p.sku, sr.manufacturer_id, m.company_name,
+        sr.destination_store_id, s.name, sr.quantity_requested, sr.quantity_confirmed,
+        sr.quantity_verified, sr.status, sr.source, sr.requested_by::text, sr.verified_by::text,
+        sr.notes, sr.discrepancy_notes, sr.verified_at, sr.created_at, sr.updated_at
+`
+
+const stockRequestJoins = `
+        FROM stock_requests sr
+        JOIN products p ON p.product_id = sr.product_id
+        JOIN manufacturers m ON m.manufacturer_id = sr.manufacturer_id
+        JOIN stores s ON s.store_id = sr.destination_store_id
+`
+
+func scanStockRequest(row pgx.Row, r *models.StockRequest) error {
+	return row.Scan(
+		&r.ID,
+		&r.ProductID,
+		&r.ProductTitle,
+		&r.ProductSKU,
+		&r.ManufacturerID,
+		&r.ManufacturerName,
+		&r.StoreID,
+		&r.StoreName,
+		&r.QuantityRequested,
+		&r.QuantityConfirmed,
+		&r.QuantityVerified,
+		&r.Status,
+		&r.Source,
+		&r.RequestedBy,
+		&r.VerifiedBy,
+		&r.Notes,
+		&r.DiscrepancyNotes,
+		&r.VerifiedAt,
+		&r.CreatedAt,
+		&r.UpdatedAt,
+	)
+}
+
+// CreateStockRequest raises a manual stock request. The manufacturer is taken from the product.
+func (db *Database) CreateStockRequest(ctx context.Context, req models.CreateStockRequestRequest, requestedBy *string) (int, error) {
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var manufacturerID *int
+	err = tx.QueryRow(ctx, "SELECT manufacturer_id FROM products WHERE product_id = $1", req.ProductID).Scan(&manufacturerID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, fmt.Errorf("product with ID %d not found", req.ProductID)
+		}
+		return 0, fmt.Errorf("failed to query product: %w", err)
+	}
+	if manufacturerID == nil {
+		return 0, fmt.Errorf("product with ID %d has no manufacturer", req.ProductID)
+	}
+
+	var storeExists bool
+	err = tx.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM stores WHERE store_id = $1)", req.StoreID).Scan(&storeExists)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query store: %w", err)
+	}
+	if !storeExists {
+		return 0, fmt.Errorf("store with ID %d not found", req.StoreID)
+	}
+
+	var requestID int
+	err = tx.QueryRow(ctx, `
+        INSERT INTO stock_requests
+            (product_id, manufacturer_id, destination_store_id, quantity_requested, requested_by, source, notes)
+        VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''))
+        RETURNING request_id
+    `, req.ProductID, *manufacturerID, req.StoreID, req.QuantityRequested, requestedBy,
+		models.StockRequestSourceManual, req.Notes).Scan(&requestID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert stock request: %w", err)
+	}
+
+	if err := insertStockRequestHistory(ctx, tx, requestID, nil, models.StockRequestStatusPending, requestedBy, req.Notes); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return requestID, nil
+}
+
+// GetStockRequests lists stock requests matching the filter, newest first
+func (db *Database) GetStockRequests(ctx context.Context, filter models.StockRequestFilter) ([]models.StockRequest, error) {
+	query := "SELECT" + stockRequestColumns + stockRequestJoins + " WHERE 1=1"
+	args := []interface{}{}
+	argIndex := 1
+
+	if filter.Status != "" {
+		query += fmt.Sprintf(" AND sr.status = $%d", argIndex)
+		args = append(args, filter.Status)
+		argIndex++
+	}
+	if filter.StoreID != nil {
+		query += fmt.Sprintf(" AND sr.destination_store_id = $%d", argIndex)
+		args = append(args, *filter.StoreID)
+		argIndex++
+	}
+	if filter.StoreIDs != nil {
+		query += fmt.Sprintf(" AND sr.destination_store_id = ANY($%d)", argIndex)
+		args = append(args, filter.StoreIDs)
+		argIndex++
+	}
+	if filter.ProductID != nil {
+		query += fmt.Sprintf(" AND sr.product_id = $%d", argIndex)
+		args = append(args, *filter.ProductID)
+		argIndex++
+	}
+	if filter.ManufacturerID != nil {
+		query += fmt.Sprintf(" AND sr.manufacturer_id = $%d", argIndex)
+		args = append(args, *filter.ManufacturerID)
+		argIndex++
+	}
+	if filter.Source != "" {
+		query += fmt.Sprintf(" AND sr.source = $%d", argIndex)
+		args = append(args, filter.Source)
+		argIndex++
+	}
+	query += " ORDER BY sr.created_at DESC"
+
+	rows, err := db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query stock requests: %w", err)
+	}
+	defer rows.Close()
+
+	requests := []models.StockRequest{}
+	for rows.Next() {
+		var r models.StockRequest
+		if err := scanStockRequest(rows, &r); err != nil {
+			return nil, fmt.Errorf("failed to scan stock request: %w", err)
+		}
+		requests = append(requests, r)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating stock requests: %w", err)
+	}
+
+	return requests, nil
+}
+
+// GetStockRequestStoreID returns the destination store of a stock request
+func (db *Database) GetStockRequestStoreID(ctx context.Context, requestID int) (int, error) {
+	var storeID int
+	err := db.Pool.QueryRow(ctx,
+		"SELECT destination_store_id FROM stock_requests WHERE request_id = $1",
+		requestID).Scan(&storeID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, fmt.Errorf("stock request with ID %d not found", requestID)
+		}
+		return 0, fmt.Errorf("failed to query stock request: %w", err)
+	}
+	return storeID, nil
+}
+
+// GetStockRequest returns a stock request with its status history
+func (db *Database) GetStockRequest(ctx context.Context, requestID int) (*models.StockRequest, error) {
+	query := "SELECT" + stockRequestColumns + stockRequestJoins + " WHERE sr.request_id = $1"
+
+	var r models.StockRequest
+	if err := scanStockRequest(db.Pool.QueryRow(ctx, query, requestID), &r); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("stock request with ID %d not found", requestID)
+		}
+		return nil, fmt.Errorf("failed to query stock request: %w", err)
+	}
+
+	rows, err := db.Pool.Query(ctx, `
+        SELECT history_id, request_id, old_status, new_status, changed_by::text, note, created_at
+        FROM stock_request_status_history
+        WHERE request_id = $1
+        ORDER BY created_at, history_id
+    `, requestID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query stock request history: %w", err)
+	}
+	defer rows.Close()
+
+	r.StatusHistory = []models.StockRequestStatusChange{}
+	for rows.Next() {
+		var change models.StockRequestStatusChange
+		err := rows.Scan(
+			&change.ID,
+			&change.RequestID,
+			&change.OldStatus,
+			&change.NewStatus,
+			&change.ChangedBy,
+			&change.Note,
+			&change.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan stock request history: %w", err)
+		}
+		r.StatusHistory = append(r.StatusHistory, change)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating stock request history: %w", err)
+	}
+
+	return &r, nil
+}
+
+// UpdateStockRequestStatus moves a request along its workflow and records the change.
+// When manufacturerID is set, only that manufacturer's requests can be changed.
+func (db *Database) UpdateStockRequestStatus(ctx context.Context, requestID int, newStatus models.StockRequestStatus, changedBy *string, note string, manufacturerID *int, quantityConfirmed *int) error {
+	if newStatus == models.StockRequestStatusVerified {
+		return fmt.Errorf("stock requests are verified through verification, not a status change")
+	}
+
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	currentStatus, err := lockStockRequest(ctx, tx, requestID, manufacturerID)
+	if err != nil {
+		return err
+	}
+	if !currentStatus.CanTransitionTo(newStatus) {
+		return fmt.Errorf("cannot move stock request from '%s' to '%s'", currentStatus, newStatus)
+	}
+
+	_, err = tx.Exec(ctx, `
+        UPDATE stock_requests
+        SET status = $2,
+            quantity_confirmed = COALESCE($3, quantity_confirmed),
+            updated_at = CURRENT_TIMESTAMP
+        WHERE request_id = $1
+    `, requestID, newStatus, quantityConfirmed)
+	if err != nil {
+		return fmt.Errorf("failed to update stock request status: %w", err)
+	}
+
+	if err := insertStockRequestHistory(ctx, tx, requestID, &currentStatus, newStatus, changedBy, note); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// VerifyStockRequest records the quantity received for a delivered request and adds it to
+// the destination store's inventory. Store-bound products also get their stock_left raised,
+// since that is the figure order-service sells against.
+func (db *Database) VerifyStockRequest(ctx context.Context, requestID int, verifiedBy *string, req models.VerifyStockRequestRequest) error {
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	currentStatus, err := lockStockRequest(ctx, tx, requestID, nil)
+	if err != nil {
+		return err
+	}
+	if !currentStatus.CanTransitionTo(models.StockRequestStatusVerified) {
+		return fmt.Errorf("cannot move stock request from '%s' to '%s'", currentStatus, models.StockRequestStatusVerified)
+	}
+
+	var productID, storeID int
+	err = tx.QueryRow(ctx, `
+        UPDATE stock_requests
+        SET status = $2,
+            quantity_verified = $3,
+            discrepancy_notes = NULLIF($4, ''),
+            verified_by = $5,
+            verified_at = CURRENT_TIMESTAMP,
+            updated_at = CURRENT_TIMESTAMP
+        WHERE request_id = $1
+        RETURNING product_id, destination_store_id
+    `, requestID, models.StockRequestStatusVerified, req.QuantityVerified, req.DiscrepancyNotes, verifiedBy).Scan(&productID, &storeID)
+	if err != nil {
+		return fmt.Errorf("failed to verify stock request: %w", err)
+	}
+
+	_, err = applyStockChange(ctx, tx, stockChange{
+		ProductID:      productID,
+		StoreID:        storeID,
+		Type:           models.StockMovementReceipt,
+		Change:         req.QuantityVerified,
+		Reason:         fmt.Sprintf("Stock request %d verified", requestID),
+		ActorID:        verifiedBy,
+		StockRequestID: &requestID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to book received stock: %w", err)
+	}
+
+	note := fmt.Sprintf("Verified %d units received", req.QuantityVerified)
+	if req.DiscrepancyNotes != "" {
+		note += ": " + req.DiscrepancyNotes
+	}
+	if err := insertStockRequestHistory(ctx, tx, requestID, &currentStatus, models.StockRequestStatusVerified, verifiedBy, note); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// lockStockRequest locks a request row for update and returns its current status
+func lockStockRequest(ctx context.Context, tx pgx.Tx, requestID int, manufacturerID *int) (models.StockRequestStatus, error) {
+	var status models.StockRequestStatus
+	err := tx.QueryRow(ctx, `
+        SELECT status FROM stock_requests
+        WHERE request_id = $1 AND ($2::int IS NULL OR manufacturer_id = $2)
+        FOR UPDATE
+    `, requestID, manufacturerID).Scan(&status)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", fmt.Errorf("stock request with ID %d not found", requestID)
+		}
+		return "", fmt.Errorf("failed to query stock request: %w", err)
+	}
+	return status, nil
+}
+
+// insertStockRequestHistory appends a status history entry inside the caller's transaction
+func insertStockRequestHistory(ctx context.Context, tx pgx.Tx, requestID int, oldStatus *models.StockRequestStatus, newStatus models.StockRequestStatus, changedBy *string, note string) error {
+	_, err := tx.Exec(ctx, `
+        INSERT INTO stock_request_status_history (request_id, old_status, new_status, changed_by, note)
+        VALUES ($1, $2, $3, $4, NULLIF($5, ''))
+    `, requestID, oldStatus, newStatus, changedBy, note)
+	if err != nil {
+		return fmt.Errorf("failed to record stock request history: %w", err)
+	}
+	return nil
+}
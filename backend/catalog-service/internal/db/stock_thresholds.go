@@ -0,0 +1,353 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/expomadeinworld/madeinworld/catalog-service/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// GetStockThresholds lists configured thresholds, product-wide ones before store ones
+func (db *Database) GetStockThresholds(ctx context.Context, filter models.StockThresholdFilter) ([]models.StockThreshold, error) {
+	query := `
+        SELECT t.threshold_id, t.product_id, p.title, t.store_id, s.name, t.safety_stock,
+               t.reorder_point, t.reorder_quantity, t.updated_at
+        FROM stock_thresholds t
+        JOIN products p ON p.product_id = t.product_id
+        LEFT JOIN stores s ON s.store_id = t.store_id
+        WHERE 1=1
+    `
+	args := []interface{}{}
+	argIndex := 1
+
+	if filter.ProductID != nil {
+		query += fmt.Sprintf(" AND t.product_id = $%d", argIndex)
+		args = append(args, *filter.ProductID)
+		argIndex++
+	}
+	if filter.StoreID != nil {
+		query += fmt.Sprintf(" AND t.store_id = $%d", argIndex)
+		args = append(args, *filter.StoreID)
+		argIndex++
+	}
+	query += " ORDER BY t.product_id, t.store_id NULLS FIRST"
+
+	rows, err := db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query stock thresholds: %w", err)
+	}
+	defer rows.Close()
+
+	thresholds := []models.StockThreshold{}
+	for rows.Next() {
+		var t models.StockThreshold
+		if err := rows.Scan(&t.ID, &t.ProductID, &t.ProductTitle, &t.StoreID, &t.StoreName, &t.SafetyStock,
+			&t.ReorderPoint, &t.ReorderQuantity, &t.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan stock threshold: %w", err)
+		}
+		thresholds = append(thresholds, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating stock thresholds: %w", err)
+	}
+
+	return thresholds, nil
+}
+
+// UpsertStockThreshold sets the threshold of a product, for one store or for all stores
+func (db *Database) UpsertStockThreshold(ctx context.Context, req models.UpsertStockThresholdRequest) (int, error) {
+	var productExists, storeExists bool
+	err := db.Pool.QueryRow(ctx, `
+        SELECT EXISTS (SELECT 1 FROM products WHERE product_id = $1),
+               $2::int IS NULL OR EXISTS (SELECT 1 FROM stores WHERE store_id = $2)
+    `, req.ProductID, req.StoreID).Scan(&productExists, &storeExists)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query product and store: %w", err)
+	}
+	if !productExists {
+		return 0, fmt.Errorf("product with ID %d not found", req.ProductID)
+	}
+	if !storeExists {
+		return 0, fmt.Errorf("store with ID %d not found", *req.StoreID)
+	}
+
+	// The partial unique indexes cannot serve as ON CONFLICT targets for both cases
+	// at once, so update first and insert when nothing matched
+	var thresholdID int
+	err = db.Pool.QueryRow(ctx, `
+        UPDATE stock_thresholds
+        SET safety_stock = $3, reorder_point = $4, reorder_quantity = $5, updated_at = CURRENT_TIMESTAMP
+        WHERE product_id = $1 AND store_id IS NOT DISTINCT FROM $2
+        RETURNING threshold_id
+    `, req.ProductID, req.StoreID, req.SafetyStock, req.ReorderPoint, req.ReorderQuantity).Scan(&thresholdID)
+	if err == nil {
+		return thresholdID, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return 0, fmt.Errorf("failed to update stock threshold: %w", err)
+	}
+
+	err = db.Pool.QueryRow(ctx, `
+        INSERT INTO stock_thresholds (product_id, store_id, safety_stock, reorder_point, reorder_quantity)
+        VALUES ($1, $2, $3, $4, $5)
+        RETURNING threshold_id
+    `, req.ProductID, req.StoreID, req.SafetyStock, req.ReorderPoint, req.ReorderQuantity).Scan(&thresholdID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create stock threshold: %w", err)
+	}
+
+	return thresholdID, nil
+}
+
+// DeleteStockThreshold removes a threshold so the product-wide or default settings apply
+func (db *Database) DeleteStockThreshold(ctx context.Context, thresholdID int) error {
+	result, err := db.Pool.Exec(ctx, "DELETE FROM stock_thresholds WHERE threshold_id = $1", thresholdID)
+	if err != nil {
+		return fmt.Errorf("failed to delete stock threshold: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("stock threshold %d not found", thresholdID)
+	}
+	return nil
+}
+
+// GetSafetyStocks returns the safety stock of each product at the store, or at the product's
+// own store when storeID is nil
+func (db *Database) GetSafetyStocks(ctx context.Context, productIDs []int, storeID *int) (map[int]int, error) {
+	safetyStocks := make(map[int]int, len(productIDs))
+	if len(productIDs) == 0 {
+		return safetyStocks, nil
+	}
+
+	rows, err := db.Pool.Query(ctx, `
+        SELECT p.product_id, stock_safety_stock(p.product_id, COALESCE($2::int, p.store_id))
+        FROM products p
+        WHERE p.product_id = ANY($1::int[])
+    `, productIDs, storeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query safety stock: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var productID, safetyStock int
+		if err := rows.Scan(&productID, &safetyStock); err != nil {
+			return nil, fmt.Errorf("failed to scan safety stock: %w", err)
+		}
+		safetyStocks[productID] = safetyStock
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating safety stock: %w", err)
+	}
+
+	return safetyStocks, nil
+}
+
+// lowStockLevels is the available stock of every active product at every store with its
+// effective reorder settings; $1 and $2 are the default reorder point and quantity
+const lowStockLevels = `
+        levels AS (
+            SELECT i.product_id, i.store_id, p.manufacturer_id,
+                   i.quantity - i.reserved_quantity AS available,
+                   COALESCE(th.reorder_point, $1) AS reorder_point,
+                   COALESCE(th.reorder_quantity, $2) AS reorder_quantity,
+                   COALESCE(th.safety_stock, 5) AS safety_stock
+            FROM inventory i
+            JOIN products p ON p.product_id = i.product_id AND p.is_active = true
+            LEFT JOIN LATERAL (
+                SELECT t.reorder_point, t.reorder_quantity, t.safety_stock
+                FROM stock_thresholds t
+                WHERE t.product_id = i.product_id
+                  AND (t.store_id = i.store_id OR t.store_id IS NULL)
+                ORDER BY t.store_id NULLS LAST
+                LIMIT 1
+            ) th ON true
+        )`
+
+// RunLowStockCheck resolves alerts whose stock recovered, raises stock requests for low
+// stock with a manufacturer and opens an alert for every product and store that reached its
+// reorder point. Admins and the manufacturer's portal user are notified of new alerts.
+func (db *Database) RunLowStockCheck(ctx context.Context, defaults models.LowStockCheckDefaults) (*models.LowStockCheckResult, error) {
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	result := &models.LowStockCheckResult{}
+
+	resolved, err := tx.Exec(ctx, `
+        WITH`+lowStockLevels+`
+        UPDATE low_stock_alerts a
+        SET status = 'resolved', resolved_at = CURRENT_TIMESTAMP
+        FROM levels l
+        WHERE a.status <> 'resolved'
+          AND l.product_id = a.product_id AND l.store_id = a.store_id
+          AND l.available > l.reorder_point
+    `, defaults.ReorderPoint, defaults.ReorderQuantity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve low stock alerts: %w", err)
+	}
+	result.AlertsResolved = int(resolved.RowsAffected())
+
+	err = tx.QueryRow(ctx, `
+        WITH`+lowStockLevels+`,
+        created AS (
+            INSERT INTO stock_requests
+                (product_id, manufacturer_id, destination_store_id, quantity_requested, source, notes)
+            SELECT l.product_id, l.manufacturer_id, l.store_id, l.reorder_quantity, 'low_stock',
+                   'Automatic request: ' || l.available || ' units available'
+            FROM levels l
+            WHERE l.manufacturer_id IS NOT NULL
+              AND l.available <= l.reorder_point
+            ON CONFLICT (product_id, destination_store_id) WHERE status NOT IN ('Verified', 'Cancelled')
+            DO NOTHING
+            RETURNING request_id, notes
+        ),
+        history AS (
+            INSERT INTO stock_request_status_history (request_id, old_status, new_status, note)
+            SELECT request_id, NULL, 'Pending', notes FROM created
+        )
+        SELECT COUNT(*) FROM created
+    `, defaults.ReorderPoint, defaults.ReorderQuantity).Scan(&result.RequestsCreated)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create low stock requests: %w", err)
+	}
+
+	err = tx.QueryRow(ctx, `
+        WITH`+lowStockLevels+`,
+        raised AS (
+            INSERT INTO low_stock_alerts
+                (product_id, store_id, available_quantity, reorder_point, safety_stock, stock_request_id)
+            SELECT l.product_id, l.store_id, l.available, l.reorder_point, l.safety_stock,
+                   (SELECT sr.request_id FROM stock_requests sr
+                    WHERE sr.product_id = l.product_id AND sr.destination_store_id = l.store_id
+                      AND sr.status NOT IN ('Verified', 'Cancelled')
+                    ORDER BY sr.created_at DESC
+                    LIMIT 1)
+            FROM levels l
+            WHERE l.available <= l.reorder_point
+            ON CONFLICT (product_id, store_id) WHERE status <> 'resolved'
+            DO NOTHING
+            RETURNING alert_id, product_id, store_id, available_quantity, reorder_point, stock_request_id
+        ),
+        notified AS (
+            INSERT INTO notifications
+                (recipient_user_id, notification_type, title, message, reference_type, reference_id)
+            SELECT r.user_id, 'low_stock', 'Low stock: ' || p.title,
+                   p.title || ' is running low at ' || s.name || ': ' || a.available_quantity ||
+                   ' units available (reorder point ' || a.reorder_point || ')' ||
+                   CASE WHEN a.stock_request_id IS NOT NULL
+                        THEN '; stock request #' || a.stock_request_id || ' is open.'
+                        ELSE '.' END,
+                   'LowStockAlert', a.alert_id::text
+            FROM raised a
+            JOIN products p ON p.product_id = a.product_id
+            JOIN stores s ON s.store_id = a.store_id
+            JOIN LATERAL (
+                SELECT u.id AS user_id FROM users u WHERE u.role = 'Admin'
+                UNION
+                SELECT m.user_id FROM manufacturers m
+                WHERE m.manufacturer_id = p.manufacturer_id AND m.user_id IS NOT NULL
+            ) r ON true
+        )
+        SELECT COUNT(*) FROM raised
+    `, defaults.ReorderPoint, defaults.ReorderQuantity).Scan(&result.AlertsRaised)
+	if err != nil {
+		return nil, fmt.Errorf("failed to raise low stock alerts: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return result, nil
+}
+
+// GetLowStockAlerts lists alerts, newest first
+func (db *Database) GetLowStockAlerts(ctx context.Context, filter models.LowStockAlertFilter) ([]models.LowStockAlert, error) {
+	query := `
+        SELECT a.alert_id, a.product_id, p.title, p.manufacturer_id, a.store_id, s.name, a.status,
+               a.available_quantity, COALESCE(i.quantity - i.reserved_quantity, 0), a.reorder_point,
+               a.safety_stock, a.stock_request_id, a.acknowledged_by::text, a.acknowledged_at,
+               a.resolved_at, a.created_at
+        FROM low_stock_alerts a
+        JOIN products p ON p.product_id = a.product_id
+        JOIN stores s ON s.store_id = a.store_id
+        LEFT JOIN inventory i ON i.product_id = a.product_id AND i.store_id = a.store_id
+        WHERE 1=1
+    `
+	args := []interface{}{}
+	argIndex := 1
+
+	if filter.Status != "" {
+		query += fmt.Sprintf(" AND a.status = $%d", argIndex)
+		args = append(args, filter.Status)
+		argIndex++
+	}
+	if filter.StoreID != nil {
+		query += fmt.Sprintf(" AND a.store_id = $%d", argIndex)
+		args = append(args, *filter.StoreID)
+		argIndex++
+	}
+	if filter.ProductID != nil {
+		query += fmt.Sprintf(" AND a.product_id = $%d", argIndex)
+		args = append(args, *filter.ProductID)
+		argIndex++
+	}
+	if filter.ManufacturerID != nil {
+		query += fmt.Sprintf(" AND p.manufacturer_id = $%d", argIndex)
+		args = append(args, *filter.ManufacturerID)
+		argIndex++
+	}
+	query += " ORDER BY a.created_at DESC"
+
+	rows, err := db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query low stock alerts: %w", err)
+	}
+	defer rows.Close()
+
+	alerts := []models.LowStockAlert{}
+	for rows.Next() {
+		var a models.LowStockAlert
+		if err := rows.Scan(&a.ID, &a.ProductID, &a.ProductTitle, &a.ManufacturerID, &a.StoreID, &a.StoreName,
+			&a.Status, &a.AvailableQuantity, &a.CurrentAvailable, &a.ReorderPoint, &a.SafetyStock,
+			&a.StockRequestID, &a.AcknowledgedBy, &a.AcknowledgedAt, &a.ResolvedAt, &a.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan low stock alert: %w", err)
+		}
+		alerts = append(alerts, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating low stock alerts: %w", err)
+	}
+
+	return alerts, nil
+}
+
+// AcknowledgeLowStockAlert marks an open alert as seen; it stays unresolved until stock recovers
+func (db *Database) AcknowledgeLowStockAlert(ctx context.Context, alertID int, acknowledgedBy *string) error {
+	var status models.LowStockAlertStatus
+	err := db.Pool.QueryRow(ctx, "SELECT status FROM low_stock_alerts WHERE alert_id = $1", alertID).Scan(&status)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("low stock alert %d not found", alertID)
+		}
+		return fmt.Errorf("failed to query low stock alert: %w", err)
+	}
+	if status != models.LowStockAlertOpen {
+		return fmt.Errorf("cannot acknowledge a %s alert", status)
+	}
+
+	_, err = db.Pool.Exec(ctx, `
+        UPDATE low_stock_alerts
+        SET status = 'acknowledged', acknowledged_by = $2, acknowledged_at = CURRENT_TIMESTAMP
+        WHERE alert_id = $1 AND status = 'open'
+    `, alertID, acknowledgedBy)
+	if err != nil {
+		return fmt.Errorf("failed to acknowledge low stock alert: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/expomadeinworld/madeinworld/catalog-service/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// IsValidTimezone reports whether PostgreSQL knows the IANA timezone name
+func (db *Database) IsValidTimezone(ctx context.Context, name string) (bool, error) {
+	var valid bool
+	err := db.Pool.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM pg_timezone_names WHERE name = $1)", name).Scan(&valid)
+	if err != nil {
+		return false, fmt.Errorf("failed to check timezone: %w", err)
+	}
+	return valid, nil
+}
+
+// GetStoreHours returns a store's weekly hours, upcoming exceptions and whether it is open now
+func (db *Database) GetStoreHours(ctx context.Context, storeID int) (*models.StoreHours, error) {
+	hours := &models.StoreHours{
+		StoreID:    storeID,
+		Weekly:     []models.OpeningHours{},
+		Exceptions: []models.StoreHoursException{},
+	}
+
+	err := db.Pool.QueryRow(ctx, `
+        SELECT timezone, store_is_open(store_id, CURRENT_TIMESTAMP), store_next_open_at(store_id, CURRENT_TIMESTAMP)
+        FROM stores
+        WHERE store_id = $1
+    `, storeID).Scan(&hours.Timezone, &hours.IsOpen, &hours.NextOpenAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("store with ID %d not found", storeID)
+		}
+		return nil, fmt.Errorf("failed to query store: %w", err)
+	}
+
+	rows, err := db.Pool.Query(ctx, `
+        SELECT day_of_week, to_char(opens_at, 'HH24:MI'), to_char(closes_at, 'HH24:MI')
+        FROM store_opening_hours
+        WHERE store_id = $1
+        ORDER BY day_of_week, opens_at
+    `, storeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query opening hours: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var h models.OpeningHours
+		if err := rows.Scan(&h.DayOfWeek, &h.OpensAt, &h.ClosesAt); err != nil {
+			return nil, fmt.Errorf("failed to scan opening hours: %w", err)
+		}
+		hours.Weekly = append(hours.Weekly, h)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read opening hours: %w", err)
+	}
+
+	// Exceptions from today (in the store's timezone) onwards
+	exceptionRows, err := db.Pool.Query(ctx, `
+        SELECT e.exception_id, e.store_id, to_char(e.exception_date, 'YYYY-MM-DD'), e.is_closed,
+               to_char(e.opens_at, 'HH24:MI'), to_char(e.closes_at, 'HH24:MI'), e.reason
+        FROM store_hours_exceptions e
+        JOIN stores s ON s.store_id = e.store_id
+        WHERE e.store_id = $1 AND e.exception_date >= (CURRENT_TIMESTAMP AT TIME ZONE s.timezone)::date
+        ORDER BY e.exception_date
+    `, storeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query hours exceptions: %w", err)
+	}
+	defer exceptionRows.Close()
+
+	for exceptionRows.Next() {
+		var e models.StoreHoursException
+		if err := exceptionRows.Scan(&e.ID, &e.StoreID, &e.Date, &e.IsClosed, &e.OpensAt, &e.ClosesAt, &e.Reason); err != nil {
+			return nil, fmt.Errorf("failed to scan hours exception: %w", err)
+		}
+		hours.Exceptions = append(hours.Exceptions, e)
+	}
+
+	return hours, exceptionRows.Err()
+}
+
+// ReplaceStoreHours sets a store's timezone and replaces its weekly opening hours
+func (db *Database) ReplaceStoreHours(ctx context.Context, storeID int, req models.UpdateStoreHoursRequest) error {
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx,
+		"UPDATE stores SET timezone = $2, updated_at = CURRENT_TIMESTAMP WHERE store_id = $1",
+		storeID, req.Timezone)
+	if err != nil {
+		return fmt.Errorf("failed to update store timezone: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("store with ID %d not found", storeID)
+	}
+
+	if _, err := tx.Exec(ctx, "DELETE FROM store_opening_hours WHERE store_id = $1", storeID); err != nil {
+		return fmt.Errorf("failed to clear opening hours: %w", err)
+	}
+
+	for _, h := range req.Weekly {
+		_, err := tx.Exec(ctx, `
+            INSERT INTO store_opening_hours (store_id, day_of_week, opens_at, closes_at)
+            VALUES ($1, $2, $3::time, $4::time)
+        `, storeID, h.DayOfWeek, h.OpensAt, h.ClosesAt)
+		if err != nil {
+			return fmt.Errorf("failed to insert opening hours: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit opening hours: %w", err)
+	}
+	return nil
+}
+
+// UpsertStoreHoursException adds the exception for a date or replaces the existing one
+func (db *Database) UpsertStoreHoursException(ctx context.Context, storeID int, req models.CreateStoreHoursExceptionRequest) (int, error) {
+	var exists bool
+	if err := db.Pool.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM stores WHERE store_id = $1)", storeID).Scan(&exists); err != nil {
+		return 0, fmt.Errorf("failed to query store: %w", err)
+	}
+	if !exists {
+		return 0, fmt.Errorf("store with ID %d not found", storeID)
+	}
+
+	var exceptionID int
+	err := db.Pool.QueryRow(ctx, `
+        INSERT INTO store_hours_exceptions (store_id, exception_date, is_closed, opens_at, closes_at, reason)
+        VALUES ($1, $2::date, $3, $4::time, $5::time, $6)
+        ON CONFLICT (store_id, exception_date) DO UPDATE SET
+            is_closed = EXCLUDED.is_closed,
+            opens_at = EXCLUDED.opens_at,
+            closes_at = EXCLUDED.closes_at,
+            reason = EXCLUDED.reason
+        RETURNING exception_id
+    `, storeID, req.Date, req.IsClosed, req.OpensAt, req.ClosesAt, req.Reason).Scan(&exceptionID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to save hours exception: %w", err)
+	}
+
+	return exceptionID, nil
+}
+
+// DeleteStoreHoursException removes an exception from a store's calendar
+func (db *Database) DeleteStoreHoursException(ctx context.Context, storeID, exceptionID int) error {
+	result, err := db.Pool.Exec(ctx,
+		"DELETE FROM store_hours_exceptions WHERE store_id = $1 AND exception_id = $2",
+		storeID, exceptionID)
+	if err != nil {
+		return fmt.Errorf("failed to delete hours exception: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("hours exception %d not found", exceptionID)
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/expomadeinworld/madeinworld/catalog-service/internal/models"
+)
+
+// GetStoreProductOverrides lists the product overrides of a store
+func (db *Database) GetStoreProductOverrides(ctx context.Context, storeID int) ([]models.StoreProductOverride, error) {
+	var exists bool
+	if err := db.Pool.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM stores WHERE store_id = $1)", storeID).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("failed to query store: %w", err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("store with ID %d not found", storeID)
+	}
+
+	rows, err := db.Pool.Query(ctx, `
+        SELECT o.store_id, o.product_id, p.title, p.main_price, o.price, o.strikethrough_price,
+               o.is_active, o.updated_at
+        FROM store_product_overrides o
+        JOIN products p ON p.product_id = o.product_id
+        WHERE o.store_id = $1
+        ORDER BY o.product_id
+    `, storeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query store product overrides: %w", err)
+	}
+	defer rows.Close()
+
+	overrides := []models.StoreProductOverride{}
+	for rows.Next() {
+		var o models.StoreProductOverride
+		if err := rows.Scan(&o.StoreID, &o.ProductID, &o.ProductTitle, &o.BasePrice, &o.Price,
+			&o.StrikethroughPrice, &o.IsActive, &o.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan store product override: %w", err)
+		}
+		overrides = append(overrides, o)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate store product overrides: %w", err)
+	}
+
+	return overrides, nil
+}
+
+// UpsertStoreProductOverride sets or replaces a store's override for a product
+func (db *Database) UpsertStoreProductOverride(ctx context.Context, storeID, productID int, req models.UpsertStoreProductOverrideRequest) (*models.StoreProductOverride, error) {
+	var storeExists, productExists bool
+	err := db.Pool.QueryRow(ctx, `
+        SELECT EXISTS (SELECT 1 FROM stores WHERE store_id = $1),
+               EXISTS (SELECT 1 FROM products WHERE product_id = $2)
+    `, storeID, productID).Scan(&storeExists, &productExists)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query store and product: %w", err)
+	}
+	if !storeExists {
+		return nil, fmt.Errorf("store with ID %d not found", storeID)
+	}
+	if !productExists {
+		return nil, fmt.Errorf("product with ID %d not found", productID)
+	}
+
+	isActive := true
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+
+	o := &models.StoreProductOverride{}
+	err = db.Pool.QueryRow(ctx, `
+        WITH saved AS (
+            INSERT INTO store_product_overrides (store_id, product_id, price, strikethrough_price, is_active)
+            VALUES ($1, $2, $3, $4, $5)
+            ON CONFLICT (store_id, product_id) DO UPDATE SET
+                price = EXCLUDED.price,
+                strikethrough_price = EXCLUDED.strikethrough_price,
+                is_active = EXCLUDED.is_active,
+                updated_at = CURRENT_TIMESTAMP
+            RETURNING store_id, product_id, price, strikethrough_price, is_active, updated_at
+        )
+        SELECT s.store_id, s.product_id, p.title, p.main_price, s.price, s.strikethrough_price,
+               s.is_active, s.updated_at
+        FROM saved s
+        JOIN products p ON p.product_id = s.product_id
+    `, storeID, productID, req.Price, req.StrikethroughPrice, isActive).Scan(
+		&o.StoreID, &o.ProductID, &o.ProductTitle, &o.BasePrice, &o.Price,
+		&o.StrikethroughPrice, &o.IsActive, &o.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save store product override: %w", err)
+	}
+
+	return o, nil
+}
+
+// DeleteStoreProductOverride removes a store's override so the product's own values apply again
+func (db *Database) DeleteStoreProductOverride(ctx context.Context, storeID, productID int) error {
+	result, err := db.Pool.Exec(ctx,
+		"DELETE FROM store_product_overrides WHERE store_id = $1 AND product_id = $2",
+		storeID, productID)
+	if err != nil {
+		return fmt.Errorf("failed to delete store product override: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("override for product %d not found", productID)
+	}
+	return nil
+}
+
+// GetStoreProductValues returns what a store sells each of the given products with. Products
+// without an override or inventory row at the store get IsActive true and nil fields.
+func (db *Database) GetStoreProductValues(ctx context.Context, storeID int, productIDs []int) (map[int]models.StoreProductValues, error) {
+	values := make(map[int]models.StoreProductValues, len(productIDs))
+	if len(productIDs) == 0 {
+		return values, nil
+	}
+
+	rows, err := db.Pool.Query(ctx, `
+        SELECT ids.product_id, o.price, o.strikethrough_price, COALESCE(o.is_active, true), inv.available
+        FROM unnest($2::int[]) AS ids(product_id)
+        LEFT JOIN store_product_overrides o ON o.product_id = ids.product_id AND o.store_id = $1
+        LEFT JOIN LATERAL (
+            SELECT SUM(i.quantity - i.reserved_quantity)::int AS available
+            FROM inventory i
+            WHERE i.product_id = ids.product_id AND i.store_id = $1
+        ) inv ON true
+    `, storeID, productIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query store product values: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var productID int
+		var v models.StoreProductValues
+		if err := rows.Scan(&productID, &v.Price, &v.StrikethroughPrice, &v.IsActive, &v.Available); err != nil {
+			return nil, fmt.Errorf("failed to scan store product values: %w", err)
+		}
+		values[productID] = v
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate store product values: %w", err)
+	}
+
+	return values, nil
+}
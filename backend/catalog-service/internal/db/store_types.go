@@ -0,0 +1,86 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/expomadeinworld/madeinworld/catalog-service/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// GetStoreTypes lists the active store types with their display names in the given locale,
+// falling back to the default locale for missing translations
+func (db *Database) GetStoreTypes(ctx context.Context, locale string) ([]models.StoreTypeInfo, error) {
+	rows, err := db.Pool.Query(ctx, `
+        SELECT st.code,
+               COALESCE(ARRAY(
+                   SELECT ms.mini_app_code FROM mini_app_store_types ms
+                   JOIN mini_apps m ON m.code = ms.mini_app_code
+                   WHERE ms.store_type_code = st.code
+                   ORDER BY m.sort_order
+               ), '{}'),
+               st.category_association::text,
+               COALESCE(tr.display_name, def.display_name, st.code),
+               CASE WHEN tr.display_name IS NOT NULL THEN tr.locale ELSE $2 END,
+               st.sort_order
+        FROM store_types st
+        LEFT JOIN store_type_translations tr ON tr.code = st.code AND tr.locale = $1
+        LEFT JOIN store_type_translations def ON def.code = st.code AND def.locale = $2
+        WHERE st.is_active = true
+        ORDER BY st.sort_order, st.code
+    `, locale, models.DefaultLocale)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query store types: %w", err)
+	}
+	defer rows.Close()
+
+	storeTypes := []models.StoreTypeInfo{}
+	for rows.Next() {
+		var t models.StoreTypeInfo
+		var miniAppTypes []string
+		if err := rows.Scan(&t.Code, &miniAppTypes, &t.CategoryAssociation, &t.DisplayName,
+			&t.Locale, &t.SortOrder); err != nil {
+			return nil, fmt.Errorf("failed to scan store type: %w", err)
+		}
+		t.MiniAppTypes = make([]models.MiniAppType, len(miniAppTypes))
+		for i, m := range miniAppTypes {
+			t.MiniAppTypes[i] = models.MiniAppType(m)
+		}
+		storeTypes = append(storeTypes, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate store types: %w", err)
+	}
+
+	return storeTypes, nil
+}
+
+// ParseStoreType validates a store type code received from a client against the active store
+// types of the registry. It also returns the product_categories.store_type_association value
+// of the type, or "" when only categories associated with 'All' apply. The error for an unknown
+// code starts with "invalid store type".
+func (db *Database) ParseStoreType(ctx context.Context, value string) (models.StoreType, string, error) {
+	var association *string
+	err := db.Pool.QueryRow(ctx,
+		"SELECT category_association::text FROM store_types WHERE code = $1 AND is_active = true",
+		value).Scan(&association)
+	if err == nil {
+		if association == nil {
+			return models.StoreType(value), "", nil
+		}
+		return models.StoreType(value), *association, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return "", "", fmt.Errorf("failed to query store type: %w", err)
+	}
+
+	var codes string
+	err = db.Pool.QueryRow(ctx,
+		"SELECT COALESCE(string_agg(code, ', ' ORDER BY sort_order, code), '') FROM store_types WHERE is_active = true",
+	).Scan(&codes)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to query store types: %w", err)
+	}
+	return "", "", fmt.Errorf("invalid store type '%s', expected one of %s", value, codes)
+}
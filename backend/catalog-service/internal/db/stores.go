@@ -0,0 +1,133 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/expomadeinworld/madeinworld/catalog-service/internal/geo"
+	"github.com/expomadeinworld/madeinworld/catalog-service/internal/models"
+)
+
+// StoreSearch describes a geospatial lookup of active stores
+type StoreSearch struct {
+	Box      geo.Box
+	Types    []string   // store_type values; empty matches every type
+	Center   *geo.Point // when set, distance_km is computed and results are ordered by it
+	RadiusKm float64    // when positive, only stores within this distance of Center are returned
+	OpenNow  bool       // only stores open according to their opening hours
+	Limit    int
+}
+
+// distanceExpr is the haversine distance in km from ($1, $2) to the store
+const distanceExpr = `2 * 6371 * asin(least(1, sqrt(
+	power(sin(radians(latitude - $1) / 2), 2) +
+	cos(radians($1)) * cos(radians(latitude)) * power(sin(radians(longitude - $2) / 2), 2))))`
+
+// SearchStores returns active stores inside the search box, using the geohash index to
+// narrow the candidates before the exact box and distance checks
+func (db *Database) SearchStores(ctx context.Context, search StoreSearch) ([]models.Store, error) {
+	var args []interface{}
+	argIndex := 1
+
+	distance := "NULL::double precision"
+	if search.Center != nil {
+		args = append(args, search.Center.Lat, search.Center.Lng)
+		argIndex = 3
+		distance = distanceExpr
+	}
+
+	conditions := []string{"is_active = true"}
+
+	if prefixes := geo.CoverPrefixes(search.Box); len(prefixes) > 0 {
+		ranges := make([]string, len(prefixes))
+		for i, prefix := range prefixes {
+			ranges[i] = fmt.Sprintf("(geohash >= $%d AND geohash < $%d)", argIndex, argIndex+1)
+			args = append(args, prefix, geo.PrefixUpperBound(prefix))
+			argIndex += 2
+		}
+		conditions = append(conditions, "("+strings.Join(ranges, " OR ")+")")
+	}
+
+	conditions = append(conditions, fmt.Sprintf("latitude BETWEEN $%d AND $%d", argIndex, argIndex+1))
+	args = append(args, search.Box.MinLat, search.Box.MaxLat)
+	argIndex += 2
+
+	if search.Box.CrossesAntimeridian() {
+		conditions = append(conditions, fmt.Sprintf("(longitude >= $%d OR longitude <= $%d)", argIndex, argIndex+1))
+	} else {
+		conditions = append(conditions, fmt.Sprintf("longitude BETWEEN $%d AND $%d", argIndex, argIndex+1))
+	}
+	args = append(args, search.Box.MinLng, search.Box.MaxLng)
+	argIndex += 2
+
+	if len(search.Types) > 0 {
+		conditions = append(conditions, fmt.Sprintf("type::text = ANY($%d)", argIndex))
+		args = append(args, search.Types)
+		argIndex++
+	}
+
+	if search.OpenNow {
+		conditions = append(conditions, "store_is_open(store_id, CURRENT_TIMESTAMP)")
+	}
+
+	outerWhere := ""
+	orderBy := "store_id"
+	if search.Center != nil {
+		orderBy = "distance_km, store_id"
+		if search.RadiusKm > 0 {
+			outerWhere = fmt.Sprintf("WHERE distance_km <= $%d", argIndex)
+			args = append(args, search.RadiusKm)
+			argIndex++
+		}
+	}
+
+	query := fmt.Sprintf(`
+        SELECT store_id, name, city, address, latitude, longitude, type, image_url, is_active, timezone, created_at, updated_at,
+               store_is_open(store_id, CURRENT_TIMESTAMP), store_next_open_at(store_id, CURRENT_TIMESTAMP), distance_km
+        FROM (
+            SELECT store_id, name, city, address, latitude, longitude, type, image_url, is_active, timezone, created_at, updated_at,
+                   %s AS distance_km
+            FROM stores
+            WHERE %s
+        ) candidates
+        %s
+        ORDER BY %s
+        LIMIT $%d
+    `, distance, strings.Join(conditions, " AND "), outerWhere, orderBy, argIndex)
+	args = append(args, search.Limit)
+
+	rows, err := db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search stores: %w", err)
+	}
+	defer rows.Close()
+
+	stores := []models.Store{}
+	for rows.Next() {
+		var store models.Store
+		err := rows.Scan(
+			&store.ID,
+			&store.Name,
+			&store.City,
+			&store.Address,
+			&store.Latitude,
+			&store.Longitude,
+			&store.Type,
+			&store.ImageURL,
+			&store.IsActive,
+			&store.Timezone,
+			&store.CreatedAt,
+			&store.UpdatedAt,
+			&store.IsOpen,
+			&store.NextOpenAt,
+			&store.DistanceKm,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan store: %w", err)
+		}
+		stores = append(stores, store)
+	}
+
+	return stores, rows.Err()
+}
@@ -0,0 +1,164 @@
+// Package geo provides the distance, bounding box and geohash helpers used for store lookups.
+// Geohashes here must match the geohash_encode SQL function that maintains stores.geohash.
+package geo
+
+import (
+	"math"
+	"strings"
+)
+
+const (
+	// EarthRadiusKm is the mean Earth radius used for distances
+	EarthRadiusKm = 6371.0
+
+	// MaxGeohashPrecision is the precision stored in stores.geohash
+	MaxGeohashPrecision = 9
+
+	base32 = "0123456789bcdefghjkmnpqrstuvwxyz"
+)
+
+// Point is a latitude/longitude pair in degrees
+type Point struct {
+	Lat, Lng float64
+}
+
+// Box is a latitude/longitude bounding box. MinLng > MaxLng means the box crosses the antimeridian.
+type Box struct {
+	MinLat, MinLng, MaxLat, MaxLng float64
+}
+
+// CrossesAntimeridian reports whether the box wraps around longitude ±180
+func (b Box) CrossesAntimeridian() bool {
+	return b.MinLng > b.MaxLng
+}
+
+// DistanceKm returns the great-circle distance between two points using the haversine formula
+func DistanceKm(lat1, lng1, lat2, lng2 float64) float64 {
+	dLat := radians(lat2 - lat1)
+	dLng := radians(lng2 - lng1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(radians(lat1))*math.Cos(radians(lat2))*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * EarthRadiusKm * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// BoxAround returns the bounding box containing every point within radiusKm of the center
+func BoxAround(lat, lng, radiusKm float64) Box {
+	dLat := radiusKm / EarthRadiusKm * 180 / math.Pi
+	box := Box{MinLat: lat - dLat, MaxLat: lat + dLat, MinLng: -180, MaxLng: 180}
+
+	// Near the poles the circle covers every longitude
+	if box.MinLat <= -90 || box.MaxLat >= 90 {
+		box.MinLat = math.Max(box.MinLat, -90)
+		box.MaxLat = math.Min(box.MaxLat, 90)
+		return box
+	}
+
+	dLng := math.Asin(math.Min(1, math.Sin(radiusKm/EarthRadiusKm)/math.Cos(radians(lat)))) * 180 / math.Pi
+	if dLng >= 180 {
+		return box
+	}
+	box.MinLng = normalizeLng(lng - dLng)
+	box.MaxLng = normalizeLng(lng + dLng)
+	return box
+}
+
+// Encode returns the geohash of a point with the given precision
+func Encode(lat, lng float64, precision int) string {
+	minLat, maxLat := -90.0, 90.0
+	minLng, maxLng := -180.0, 180.0
+
+	var hash strings.Builder
+	bit, ch := 0, 0
+	even := true
+	for hash.Len() < precision {
+		if even {
+			mid := (minLng + maxLng) / 2
+			if lng >= mid {
+				ch |= 1 << (4 - bit)
+				minLng = mid
+			} else {
+				maxLng = mid
+			}
+		} else {
+			mid := (minLat + maxLat) / 2
+			if lat >= mid {
+				ch |= 1 << (4 - bit)
+				minLat = mid
+			} else {
+				maxLat = mid
+			}
+		}
+		even = !even
+
+		if bit < 4 {
+			bit++
+		} else {
+			hash.WriteByte(base32[ch])
+			bit, ch = 0, 0
+		}
+	}
+	return hash.String()
+}
+
+// CoverPrefixes returns geohash prefixes whose cells together contain the box, using the
+// finest precision at which a cell is at least as large as the box (at most four cells).
+// It returns nil when the box is too large or crosses the antimeridian, in which case
+// callers should filter by the box alone.
+func CoverPrefixes(box Box) []string {
+	if box.CrossesAntimeridian() {
+		return nil
+	}
+
+	precision := 0
+	for p := MaxGeohashPrecision; p >= 1; p-- {
+		cellHeight, cellWidth := cellSize(p)
+		if cellHeight >= box.MaxLat-box.MinLat && cellWidth >= box.MaxLng-box.MinLng {
+			precision = p
+			break
+		}
+	}
+	if precision == 0 {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	var prefixes []string
+	for _, lat := range []float64{box.MinLat, box.MaxLat} {
+		for _, lng := range []float64{box.MinLng, box.MaxLng} {
+			prefix := Encode(lat, lng, precision)
+			if !seen[prefix] {
+				seen[prefix] = true
+				prefixes = append(prefixes, prefix)
+			}
+		}
+	}
+	return prefixes
+}
+
+// PrefixUpperBound returns the smallest string greater than every geohash starting with
+// prefix, so a prefix match can be expressed as an indexable range
+func PrefixUpperBound(prefix string) string {
+	return prefix + "{" // '{' sorts right after 'z', the last geohash character
+}
+
+// cellSize returns the height and width in degrees of a geohash cell with the given precision
+func cellSize(precision int) (float64, float64) {
+	bits := precision * 5
+	lngBits := (bits + 1) / 2
+	latBits := bits / 2
+	return 180 / math.Pow(2, float64(latBits)), 360 / math.Pow(2, float64(lngBits))
+}
+
+func normalizeLng(lng float64) float64 {
+	for lng > 180 {
+		lng -= 360
+	}
+	for lng < -180 {
+		lng += 360
+	}
+	return lng
+}
+
+func radians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
@@ -0,0 +1,147 @@
+package models
+
+import "time"
+
+// StockMovementType is the cause of a stock movement
+type StockMovementType string
+
+const (
+	StockMovementInitial     StockMovementType = "initial"
+	StockMovementAdjustment  StockMovementType = "adjustment"
+	StockMovementCount       StockMovementType = "count"
+	StockMovementTransferIn  StockMovementType = "transfer_in"
+	StockMovementTransferOut StockMovementType = "transfer_out"
+	StockMovementSale        StockMovementType = "sale"
+	StockMovementReceipt     StockMovementType = "receipt"
+)
+
+// IsValid reports whether the movement type is known
+func (t StockMovementType) IsValid() bool {
+	switch t {
+	case StockMovementInitial, StockMovementAdjustment, StockMovementCount, StockMovementTransferIn,
+		StockMovementTransferOut, StockMovementSale, StockMovementReceipt:
+		return true
+	}
+	return false
+}
+
+// StockMovement is one immutable entry of the inventory ledger
+type StockMovement struct {
+	ID             int64             `json:"id"`
+	ProductID      int               `json:"product_id"`
+	ProductTitle   string            `json:"product_title"`
+	StoreID        int               `json:"store_id"`
+	StoreName      string            `json:"store_name"`
+	Type           StockMovementType `json:"movement_type"`
+	QuantityChange int               `json:"quantity_change"`
+	QuantityBefore int               `json:"quantity_before"`
+	QuantityAfter  int               `json:"quantity_after"`
+	Reason         *string           `json:"reason"`
+	ActorID        *string           `json:"actor_id"`
+	OrderID        *string           `json:"order_id"`
+	StockRequestID *int              `json:"stock_request_id"`
+	TransferID     *string           `json:"transfer_id"`
+	CreatedAt      time.Time         `json:"created_at"`
+}
+
+// StockMovementFilter holds the optional filters for listing stock movements
+type StockMovementFilter struct {
+	ProductID *int              `form:"product_id"`
+	StoreID   *int              `form:"store_id"`
+	Type      StockMovementType `form:"movement_type"`
+	OrderID   string            `form:"order_id"`
+	DateFrom  string            `form:"date_from"` // YYYY-MM-DD
+	DateTo    string            `form:"date_to"`   // YYYY-MM-DD, inclusive
+	Page      int               `form:"page"`
+	Limit     int               `form:"limit"`
+}
+
+// StockMovementListResponse is a page of stock movements
+type StockMovementListResponse struct {
+	Movements []StockMovement `json:"movements"`
+	Total     int             `json:"total"`
+	Page      int             `json:"page"`
+	Limit     int             `json:"limit"`
+}
+
+// InventoryLevel is the current stock of a product at a store
+type InventoryLevel struct {
+	ProductID        int       `json:"product_id"`
+	ProductTitle     string    `json:"product_title"`
+	ProductSKU       string    `json:"product_sku"`
+	StoreID          int       `json:"store_id"`
+	StoreName        string    `json:"store_name"`
+	Quantity         int       `json:"quantity"`
+	ReservedQuantity int       `json:"reserved_quantity"`
+	Available        int       `json:"available"`
+	LastUpdated      time.Time `json:"last_updated"`
+}
+
+// InventoryFilter holds the optional filters for listing inventory levels
+type InventoryFilter struct {
+	ProductID *int `form:"product_id"`
+	StoreID   *int `form:"store_id"`
+}
+
+// StockAdjustmentRequest adds or removes stock at a store
+type StockAdjustmentRequest struct {
+	ProductID      int    `json:"product_id" binding:"required"`
+	StoreID        int    `json:"store_id" binding:"required"`
+	QuantityChange int    `json:"quantity_change" binding:"required"` // negative removes stock
+	Reason         string `json:"reason" binding:"required"`
+}
+
+// StockTransferRequest moves stock of a product from one store to another
+type StockTransferRequest struct {
+	ProductID   int    `json:"product_id" binding:"required"`
+	FromStoreID int    `json:"from_store_id" binding:"required"`
+	ToStoreID   int    `json:"to_store_id" binding:"required"`
+	Quantity    int    `json:"quantity" binding:"required,min=1"`
+	Reason      string `json:"reason"`
+}
+
+// StockTransferResponse returns both sides of a transfer
+type StockTransferResponse struct {
+	TransferID string        `json:"transfer_id"`
+	Out        StockMovement `json:"out"`
+	In         StockMovement `json:"in"`
+}
+
+// StockCountItem is the counted quantity of one product
+type StockCountItem struct {
+	ProductID       int `json:"product_id" binding:"required"`
+	CountedQuantity int `json:"counted_quantity" binding:"min=0"`
+}
+
+// StockCountRequest records a physical count at a store. Every counted product is set to
+// the counted quantity; products that were not counted are left unchanged.
+type StockCountRequest struct {
+	StoreID int              `json:"store_id" binding:"required"`
+	Items   []StockCountItem `json:"items" binding:"required,min=1,dive"`
+	Reason  string           `json:"reason"`
+}
+
+// StockCountResponse lists the movements a count produced; matching counts produce none
+type StockCountResponse struct {
+	StoreID   int             `json:"store_id"`
+	Counted   int             `json:"counted"`
+	Movements []StockMovement `json:"movements"`
+}
+
+// InventoryDiscrepancy is a product whose stored quantity differs from its ledger
+type InventoryDiscrepancy struct {
+	ProductID      int    `json:"product_id"`
+	ProductTitle   string `json:"product_title"`
+	StoreID        int    `json:"store_id"`
+	StoreName      string `json:"store_name"`
+	Quantity       int    `json:"quantity"`        // inventory.quantity
+	LedgerQuantity int    `json:"ledger_quantity"` // sum of movements
+	Difference     int    `json:"difference"`      // quantity - ledger_quantity
+}
+
+// InventoryReconciliation is the result of checking inventory against the ledger
+type InventoryReconciliation struct {
+	CheckedAt     time.Time              `json:"checked_at"`
+	Checked       int                    `json:"checked"`
+	Discrepancies []InventoryDiscrepancy `json:"discrepancies"`
+}
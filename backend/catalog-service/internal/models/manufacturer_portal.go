@@ -0,0 +1,59 @@
+package models
+
+import "time"
+
+// PriceChangeStatus represents the review state of a manufacturer price proposal
+type PriceChangeStatus string
+
+const (
+	PriceChangeStatusPending   PriceChangeStatus = "pending"
+	PriceChangeStatusApproved  PriceChangeStatus = "approved"
+	PriceChangeStatusRejected  PriceChangeStatus = "rejected"
+	PriceChangeStatusWithdrawn PriceChangeStatus = "withdrawn"
+)
+
+// PriceChangeRequest is a price change proposed by a manufacturer that an admin must approve
+type PriceChangeRequest struct {
+	ID                         int               `json:"id" db:"request_id"`
+	ProductID                  int               `json:"product_id" db:"product_id"`
+	ProductTitle               string            `json:"product_title"`
+	ProductSKU                 string            `json:"product_sku"`
+	ManufacturerID             int               `json:"manufacturer_id" db:"manufacturer_id"`
+	RequestedBy                string            `json:"requested_by" db:"requested_by"`
+	CurrentPrice               float64           `json:"current_price" db:"current_price"`
+	ProposedPrice              float64           `json:"proposed_price" db:"proposed_price"`
+	ProposedStrikethroughPrice *float64          `json:"proposed_strikethrough_price" db:"proposed_strikethrough_price"`
+	Reason                     *string           `json:"reason" db:"reason"`
+	Status                     PriceChangeStatus `json:"status" db:"status"`
+	ReviewedBy                 *string           `json:"reviewed_by" db:"reviewed_by"`
+	ReviewNote                 *string           `json:"review_note" db:"review_note"`
+	ReviewedAt                 *time.Time        `json:"reviewed_at" db:"reviewed_at"`
+	CreatedAt                  time.Time         `json:"created_at" db:"created_at"`
+}
+
+// CreatePriceChangeRequest is the body a manufacturer sends to propose a new price
+type CreatePriceChangeRequest struct {
+	ProposedPrice              float64  `json:"proposed_price" binding:"required,gt=0"`
+	ProposedStrikethroughPrice *float64 `json:"proposed_strikethrough_price"`
+	Reason                     string   `json:"reason"`
+}
+
+// ReviewPriceChangeRequest is the body an admin sends to approve or reject a proposal.
+// The reviewer is taken from the admin's token.
+type ReviewPriceChangeRequest struct {
+	Note string `json:"note"`
+}
+
+// ManufacturerProductUpdate holds the product fields a manufacturer may edit directly.
+// Prices go through PriceChangeRequest; stock, visibility and placement stay with admins.
+type ManufacturerProductUpdate struct {
+	Title                *string `json:"title"`
+	DescriptionShort     *string `json:"description_short"`
+	DescriptionLong      *string `json:"description_long"`
+	MinimumOrderQuantity *int    `json:"minimum_order_quantity"`
+}
+
+// LinkManufacturerUserRequest links a user account with the Manufacturer role to a manufacturer
+type LinkManufacturerUserRequest struct {
+	UserID *string `json:"user_id"`
+}
@@ -0,0 +1,81 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// miniAppCodePattern restricts mini-app codes to identifiers like the built-in ones
+var miniAppCodePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]{1,49}$`)
+
+// MiniApp is a mini_apps registry entry. catalog-service and order-service read the registry
+// instead of hard-coding per mini-app behaviour.
+type MiniApp struct {
+	Code          MiniAppType `json:"code"`
+	DisplayName   string      `json:"display_name"`
+	RequiresStore bool        `json:"requires_store"`
+	EnforcesStock bool        `json:"enforces_stock"`
+	EnforcesMOQ   bool        `json:"enforces_moq"`
+	IsActive      bool        `json:"is_active"`
+	SortOrder     int         `json:"sort_order"`
+	StoreTypes    []StoreType `json:"store_types"` // store types the mini-app serves
+	CreatedAt     time.Time   `json:"created_at"`
+	UpdatedAt     time.Time   `json:"updated_at"`
+}
+
+// AllowsStoreType reports whether products of the mini-app may have the store type. Mini-apps
+// without store types accept any.
+func (m *MiniApp) AllowsStoreType(storeType StoreType) bool {
+	if len(m.StoreTypes) == 0 {
+		return true
+	}
+	for _, t := range m.StoreTypes {
+		if t == storeType {
+			return true
+		}
+	}
+	return false
+}
+
+// UpdateMiniAppRequest replaces the settings of a mini-app
+type UpdateMiniAppRequest struct {
+	DisplayName   string      `json:"display_name" binding:"required,max=100"`
+	RequiresStore bool        `json:"requires_store"`
+	EnforcesStock bool        `json:"enforces_stock"`
+	EnforcesMOQ   *bool       `json:"enforces_moq"` // defaults to true
+	IsActive      *bool       `json:"is_active"`    // defaults to true
+	SortOrder     int         `json:"sort_order"`
+	StoreTypes    []StoreType `json:"store_types"`
+}
+
+// CreateMiniAppRequest registers a new mini-app
+type CreateMiniAppRequest struct {
+	Code MiniAppType `json:"code" binding:"required"`
+	UpdateMiniAppRequest
+}
+
+// Validate checks the settings and fills in the defaults. The store types are checked
+// against the registry by the handler.
+func (r *UpdateMiniAppRequest) Validate() error {
+	if r.RequiresStore && len(r.StoreTypes) == 0 {
+		return fmt.Errorf("a mini-app that requires a store must serve at least one store type")
+	}
+	if r.EnforcesMOQ == nil {
+		enforces := true
+		r.EnforcesMOQ = &enforces
+	}
+	if r.IsActive == nil {
+		active := true
+		r.IsActive = &active
+	}
+	return nil
+}
+
+// Validate checks the code in addition to the settings
+func (r *CreateMiniAppRequest) Validate() error {
+	if !miniAppCodePattern.MatchString(string(r.Code)) {
+		return fmt.Errorf("invalid mini-app code '%s', use letters and digits starting with a letter", r.Code)
+	}
+	return r.UpdateMiniAppRequest.Validate()
+}
@@ -7,28 +7,9 @@ import (
 	"time"
 )
 
-// StoreType represents the type of store
-type StoreType string
-
-const (
-	StoreTypeRetailStore       StoreType = "零售商店"
-	StoreTypeUnmannedStore     StoreType = "无人门店"
-	StoreTypeUnmannedWarehouse StoreType = "无人仓店"
-	StoreTypeExhibitionStore   StoreType = "展销商店"
-	StoreTypeExhibitionMall    StoreType = "展销商城"
-	StoreTypeGroupBuying       StoreType = "团购团批"
-)
-
-// MiniAppType represents the type of mini-app
+// MiniAppType is the code of a mini-app registered in the mini_apps table
 type MiniAppType string
 
-const (
-	MiniAppTypeRetailStore     MiniAppType = "RetailStore"
-	MiniAppTypeUnmannedStore   MiniAppType = "UnmannedStore"
-	MiniAppTypeExhibitionSales MiniAppType = "ExhibitionSales"
-	MiniAppTypeGroupBuying     MiniAppType = "GroupBuying"
-)
-
 // MiniAppTypeArray represents an array of MiniAppType for PostgreSQL array support
 type MiniAppTypeArray []MiniAppType
 
@@ -75,57 +56,61 @@ func (a *MiniAppTypeArray) Scan(value interface{}) error {
 
 // Product represents a product in the catalog
 type Product struct {
-	ID                      int         `json:"id" db:"product_id"`
-	UUID                    string      `json:"uuid" db:"product_uuid"`
-	SKU                     string      `json:"sku" db:"sku"`
-	Title                   string      `json:"title" db:"title"`
-	DescriptionShort        string      `json:"description_short" db:"description_short"`
-	DescriptionLong         string      `json:"description_long" db:"description_long"`
-	ManufacturerID          int         `json:"manufacturer_id" db:"manufacturer_id"`
-	StoreType               StoreType   `json:"store_type" db:"store_type"`
-	MiniAppType             MiniAppType `json:"mini_app_type" db:"mini_app_type"`
-	StoreID                 *int        `json:"store_id" db:"store_id"`
-	MainPrice               float64     `json:"main_price" db:"main_price"`
-	StrikethroughPrice      *float64    `json:"strikethrough_price" db:"strikethrough_price"`
-	CostPrice               *float64    `json:"cost_price,omitempty" db:"cost_price"` // Admin only - excluded from public API
-	StockLeft               int         `json:"stock_left" db:"stock_left"`
-	MinimumOrderQuantity    int         `json:"minimum_order_quantity" db:"minimum_order_quantity"`
-	IsActive                bool        `json:"is_active" db:"is_active"`
-	IsFeatured              bool        `json:"is_featured" db:"is_featured"`
-	IsMiniAppRecommendation bool        `json:"is_mini_app_recommendation" db:"is_mini_app_recommendation"`
-	ImageUrls               []string    `json:"image_urls"`
-	CategoryIds             []string    `json:"category_ids"`
-	SubcategoryIds          []string    `json:"subcategory_ids"`
-	StockQuantity           *int        `json:"stock_quantity"` // Legacy field for backward compatibility
-	CreatedAt               time.Time   `json:"created_at" db:"created_at"`
-	UpdatedAt               time.Time   `json:"updated_at" db:"updated_at"`
+	ID                      int           `json:"id" db:"product_id"`
+	UUID                    string        `json:"uuid" db:"product_uuid"`
+	SKU                     string        `json:"sku" db:"sku"`
+	Title                   string        `json:"title" db:"title"`
+	DescriptionShort        string        `json:"description_short" db:"description_short"`
+	DescriptionLong         string        `json:"description_long" db:"description_long"`
+	ManufacturerID          int           `json:"manufacturer_id" db:"manufacturer_id"`
+	StoreType               StoreType     `json:"store_type" db:"store_type"`
+	MiniAppType             MiniAppType   `json:"mini_app_type" db:"mini_app_type"`
+	StoreID                 *int          `json:"store_id" db:"store_id"`
+	MainPrice               float64       `json:"main_price" db:"main_price"`
+	StrikethroughPrice      *float64      `json:"strikethrough_price" db:"strikethrough_price"`
+	CostPrice               *float64      `json:"cost_price,omitempty" db:"cost_price"` // Admin only - excluded from public API
+	StockLeft               int           `json:"stock_left" db:"stock_left"`
+	SafetyStock             int           `json:"safety_stock"` // Held back from display, see DisplayStock
+	MinimumOrderQuantity    int           `json:"minimum_order_quantity" db:"minimum_order_quantity"`
+	IsActive                bool          `json:"is_active" db:"is_active"`
+	IsFeatured              bool          `json:"is_featured" db:"is_featured"`
+	IsMiniAppRecommendation bool          `json:"is_mini_app_recommendation" db:"is_mini_app_recommendation"`
+	ImageUrls               []string      `json:"image_urls"`
+	CategoryIds             []string      `json:"category_ids"`
+	SubcategoryIds          []string      `json:"subcategory_ids"`
+	StockQuantity           *int          `json:"stock_quantity"`         // Legacy field for backward compatibility
+	Manufacturer            *Manufacturer `json:"manufacturer,omitempty"` // Populated on single-product reads
+	CreatedAt               time.Time     `json:"created_at" db:"created_at"`
+	UpdatedAt               time.Time     `json:"updated_at" db:"updated_at"`
 }
 
 // PublicProduct represents a product for public API (excludes cost_price)
 type PublicProduct struct {
-	ID                      int         `json:"id"`
-	UUID                    string      `json:"uuid"`
-	SKU                     string      `json:"sku"`
-	Title                   string      `json:"title"`
-	DescriptionShort        string      `json:"description_short"`
-	DescriptionLong         string      `json:"description_long"`
-	ManufacturerID          int         `json:"manufacturer_id"`
-	StoreType               StoreType   `json:"store_type"`
-	MiniAppType             MiniAppType `json:"mini_app_type"`
-	StoreID                 *int        `json:"store_id"`
-	MainPrice               float64     `json:"main_price"`
-	StrikethroughPrice      *float64    `json:"strikethrough_price"`
-	StockLeft               int         `json:"stock_left"`
-	MinimumOrderQuantity    int         `json:"minimum_order_quantity"`
-	IsActive                bool        `json:"is_active"`
-	IsFeatured              bool        `json:"is_featured"`
-	IsMiniAppRecommendation bool        `json:"is_mini_app_recommendation"`
-	ImageUrls               []string    `json:"image_urls"`
-	CategoryIds             []string    `json:"category_ids"`
-	SubcategoryIds          []string    `json:"subcategory_ids"`
-	StockQuantity           *int        `json:"stock_quantity"`
-	CreatedAt               time.Time   `json:"created_at"`
-	UpdatedAt               time.Time   `json:"updated_at"`
+	ID                      int           `json:"id"`
+	UUID                    string        `json:"uuid"`
+	SKU                     string        `json:"sku"`
+	Title                   string        `json:"title"`
+	DescriptionShort        string        `json:"description_short"`
+	DescriptionLong         string        `json:"description_long"`
+	ManufacturerID          int           `json:"manufacturer_id"`
+	StoreType               StoreType     `json:"store_type"`
+	MiniAppType             MiniAppType   `json:"mini_app_type"`
+	StoreID                 *int          `json:"store_id"`
+	MainPrice               float64       `json:"main_price"`
+	StrikethroughPrice      *float64      `json:"strikethrough_price"`
+	StockLeft               int           `json:"stock_left"`
+	SafetyStock             int           `json:"safety_stock"`
+	MinimumOrderQuantity    int           `json:"minimum_order_quantity"`
+	IsActive                bool          `json:"is_active"`
+	IsFeatured              bool          `json:"is_featured"`
+	IsMiniAppRecommendation bool          `json:"is_mini_app_recommendation"`
+	ImageUrls               []string      `json:"image_urls"`
+	CategoryIds             []string      `json:"category_ids"`
+	SubcategoryIds          []string      `json:"subcategory_ids"`
+	StockQuantity           *int          `json:"stock_quantity"`
+	Manufacturer            *Manufacturer `json:"manufacturer,omitempty"`
+	CreatedAt               time.Time     `json:"created_at"`
+	UpdatedAt               time.Time     `json:"updated_at"`
 }
 
 // ToPublicProduct converts a Product to PublicProduct (excludes cost_price)
@@ -144,6 +129,7 @@ func (p *Product) ToPublicProduct() PublicProduct {
 		MainPrice:               p.MainPrice,
 		StrikethroughPrice:      p.StrikethroughPrice,
 		StockLeft:               p.StockLeft,
+		SafetyStock:             p.SafetyStock,
 		MinimumOrderQuantity:    p.MinimumOrderQuantity,
 		IsActive:                p.IsActive,
 		IsFeatured:              p.IsFeatured,
@@ -152,15 +138,16 @@ func (p *Product) ToPublicProduct() PublicProduct {
 		CategoryIds:             p.CategoryIds,
 		SubcategoryIds:          p.SubcategoryIds,
 		StockQuantity:           p.StockQuantity,
+		Manufacturer:            p.Manufacturer,
 		CreatedAt:               p.CreatedAt,
 		UpdatedAt:               p.UpdatedAt,
 	}
 }
 
-// DisplayStock returns the stock quantity with buffer applied (actual - 5)
+// DisplayStock returns the stock quantity with the product's safety stock held back
 func (p *Product) DisplayStock() *int {
 	// Use StockLeft field instead of legacy StockQuantity
-	displayStock := p.StockLeft - 5
+	displayStock := p.StockLeft - p.SafetyStock
 	if displayStock < 0 {
 		displayStock = 0
 	}
@@ -221,8 +208,42 @@ type Store struct {
 	Type      StoreType `json:"type" db:"type"`
 	ImageURL  *string   `json:"image_url" db:"image_url"`
 	IsActive  bool      `json:"is_active" db:"is_active"`
+	Timezone  string    `json:"timezone" db:"timezone"` // IANA name used for opening hours (default UTC)
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+
+	// IsOpen and NextOpenAt are computed from the opening hours when stores are listed
+	IsOpen     *bool      `json:"is_open,omitempty" db:"-"`
+	NextOpenAt *time.Time `json:"next_open_at,omitempty" db:"-"`
+
+	// DistanceKm is the distance from the requested location, set by location-based lookups
+	DistanceKm *float64 `json:"distance_km,omitempty" db:"-"`
+}
+
+// NearbyStoresRequest represents query parameters for GET /stores/nearby
+type NearbyStoresRequest struct {
+	Lat         *float64 `form:"lat" binding:"required,latitude"`
+	Lng         *float64 `form:"lng" binding:"required,longitude"`
+	RadiusKm    float64  `form:"radius_km" binding:"omitempty,gt=0,max=500"` // default 10
+	Limit       int      `form:"limit" binding:"omitempty,min=1,max=100"`    // default 20
+	Type        string   `form:"type"`
+	MiniAppType string   `form:"mini_app_type"`
+	OpenNow     bool     `form:"open_now"`
+}
+
+// StoresInBoundsRequest represents query parameters for GET /stores/in-bounds (a map viewport).
+// A min_lng greater than max_lng describes a viewport crossing the antimeridian.
+type StoresInBoundsRequest struct {
+	MinLat      *float64 `form:"min_lat" binding:"required,latitude"`
+	MinLng      *float64 `form:"min_lng" binding:"required,longitude"`
+	MaxLat      *float64 `form:"max_lat" binding:"required,latitude"`
+	MaxLng      *float64 `form:"max_lng" binding:"required,longitude"`
+	UserLat     *float64 `form:"user_lat" binding:"omitempty,latitude"`
+	UserLng     *float64 `form:"user_lng" binding:"omitempty,longitude"`
+	Limit       int      `form:"limit" binding:"omitempty,min=1,max=500"` // default 200
+	Type        string   `form:"type"`
+	MiniAppType string   `form:"mini_app_type"`
+	OpenNow     bool     `form:"open_now"`
 }
 
 // Manufacturer represents a product manufacturer
@@ -232,6 +253,8 @@ type Manufacturer struct {
 	ContactPerson string    `json:"contact_person" db:"contact_person"`
 	ContactEmail  string    `json:"contact_email" db:"contact_email"`
 	Address       string    `json:"address" db:"address"`
+	UserID        *string   `json:"user_id" db:"user_id"` // Linked Manufacturer-role account, managed via /manufacturers/:id/user
+	ProductCount  int       `json:"product_count"`
 	CreatedAt     time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
 }
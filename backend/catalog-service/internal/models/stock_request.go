@@ -0,0 +1,133 @@
+package models
+
+import "time"
+
+// StockRequestStatus mirrors the stock_request_status database enum
+type StockRequestStatus string
+
+const (
+	StockRequestStatusPending                 StockRequestStatus = "Pending"
+	StockRequestStatusConfirmedByManufacturer StockRequestStatus = "Confirmed by Manufacturer"
+	StockRequestStatusReadyForPickup          StockRequestStatus = "Ready for Pickup"
+	StockRequestStatusInTransit               StockRequestStatus = "In Transit"
+	StockRequestStatusDelivered               StockRequestStatus = "Delivered"
+	StockRequestStatusVerified                StockRequestStatus = "Verified"
+	StockRequestStatusCancelled               StockRequestStatus = "Cancelled"
+)
+
+// StockRequestSource records who raised a stock request
+type StockRequestSource string
+
+const (
+	StockRequestSourceManual   StockRequestSource = "manual"
+	StockRequestSourceLowStock StockRequestSource = "low_stock"
+)
+
+// stockRequestTransitions lists the statuses each status may move to.
+// Verified is reached only through verification, which also books the stock.
+var stockRequestTransitions = map[StockRequestStatus][]StockRequestStatus{
+	StockRequestStatusPending:                 {StockRequestStatusConfirmedByManufacturer, StockRequestStatusCancelled},
+	StockRequestStatusConfirmedByManufacturer: {StockRequestStatusReadyForPickup, StockRequestStatusCancelled},
+	StockRequestStatusReadyForPickup:          {StockRequestStatusInTransit, StockRequestStatusCancelled},
+	StockRequestStatusInTransit:               {StockRequestStatusDelivered},
+	StockRequestStatusDelivered:               {StockRequestStatusVerified},
+}
+
+// IsValid reports whether the status is one of the enum values
+func (s StockRequestStatus) IsValid() bool {
+	switch s {
+	case StockRequestStatusPending, StockRequestStatusConfirmedByManufacturer, StockRequestStatusReadyForPickup,
+		StockRequestStatusInTransit, StockRequestStatusDelivered, StockRequestStatusVerified, StockRequestStatusCancelled:
+		return true
+	}
+	return false
+}
+
+// IsOpen reports whether the request still awaits fulfilment
+func (s StockRequestStatus) IsOpen() bool {
+	return s != StockRequestStatusVerified && s != StockRequestStatusCancelled
+}
+
+// CanTransitionTo reports whether moving from s to next is allowed
+func (s StockRequestStatus) CanTransitionTo(next StockRequestStatus) bool {
+	for _, allowed := range stockRequestTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// StockRequest is a replenishment request for one product at one store
+type StockRequest struct {
+	ID                int                        `json:"id" db:"request_id"`
+	ProductID         int                        `json:"product_id" db:"product_id"`
+	ProductTitle      string                     `json:"product_title"`
+	ProductSKU        string                     `json:"product_sku"`
+	ManufacturerID    int                        `json:"manufacturer_id" db:"manufacturer_id"`
+	ManufacturerName  string                     `json:"manufacturer_name"`
+	StoreID           int                        `json:"store_id" db:"destination_store_id"`
+	StoreName         string                     `json:"store_name"`
+	QuantityRequested int                        `json:"quantity_requested" db:"quantity_requested"`
+	QuantityConfirmed *int                       `json:"quantity_confirmed" db:"quantity_confirmed"`
+	QuantityVerified  *int                       `json:"quantity_verified" db:"quantity_verified"`
+	Status            StockRequestStatus         `json:"status" db:"status"`
+	Source            StockRequestSource         `json:"source" db:"source"`
+	RequestedBy       *string                    `json:"requested_by" db:"requested_by"`
+	VerifiedBy        *string                    `json:"verified_by" db:"verified_by"`
+	Notes             *string                    `json:"notes" db:"notes"`
+	DiscrepancyNotes  *string                    `json:"discrepancy_notes" db:"discrepancy_notes"`
+	VerifiedAt        *time.Time                 `json:"verified_at" db:"verified_at"`
+	CreatedAt         time.Time                  `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time                  `json:"updated_at" db:"updated_at"`
+	StatusHistory     []StockRequestStatusChange `json:"status_history,omitempty"`
+}
+
+// StockRequestStatusChange is one entry of a stock request's status history
+type StockRequestStatusChange struct {
+	ID        int                 `json:"id" db:"history_id"`
+	RequestID int                 `json:"request_id" db:"request_id"`
+	OldStatus *StockRequestStatus `json:"old_status" db:"old_status"`
+	NewStatus StockRequestStatus  `json:"new_status" db:"new_status"`
+	ChangedBy *string             `json:"changed_by" db:"changed_by"`
+	Note      *string             `json:"note" db:"note"`
+	CreatedAt time.Time           `json:"created_at" db:"created_at"`
+}
+
+// StockRequestFilter holds the optional filters for listing stock requests
+type StockRequestFilter struct {
+	Status         string `form:"status"`
+	StoreID        *int   `form:"store_id"`
+	ProductID      *int   `form:"product_id"`
+	ManufacturerID *int   `form:"manufacturer_id"`
+	Source         string `form:"source"`
+	// StoreIDs limits the list to these destination stores; nil means all stores
+	StoreIDs []int `form:"-"`
+}
+
+// CreateStockRequestRequest is the body used to raise a stock request manually
+type CreateStockRequestRequest struct {
+	ProductID         int    `json:"product_id" binding:"required"`
+	StoreID           int    `json:"store_id" binding:"required"`
+	QuantityRequested int    `json:"quantity_requested" binding:"required,min=1"`
+	Notes             string `json:"notes"`
+}
+
+// UpdateStockRequestStatusRequest moves a stock request to a new status
+type UpdateStockRequestStatusRequest struct {
+	Status            StockRequestStatus `json:"status" binding:"required"`
+	QuantityConfirmed *int               `json:"quantity_confirmed"`
+	Note              string             `json:"note"`
+}
+
+// ConfirmStockRequestRequest is sent by a manufacturer to confirm a request
+type ConfirmStockRequestRequest struct {
+	QuantityConfirmed *int   `json:"quantity_confirmed"`
+	Note              string `json:"note"`
+}
+
+// VerifyStockRequestRequest records the quantity physically received at the store
+type VerifyStockRequestRequest struct {
+	QuantityVerified int    `json:"quantity_verified" binding:"min=0"`
+	DiscrepancyNotes string `json:"discrepancy_notes"`
+}
@@ -0,0 +1,94 @@
+package models
+
+import "time"
+
+// DefaultSafetyStock is the display buffer for products without a threshold
+const DefaultSafetyStock = 5
+
+// StockThreshold holds the safety stock and reorder settings of a product, for one store
+// or (StoreID nil) for every store without its own threshold
+type StockThreshold struct {
+	ID              int       `json:"id"`
+	ProductID       int       `json:"product_id"`
+	ProductTitle    string    `json:"product_title"`
+	StoreID         *int      `json:"store_id"`
+	StoreName       *string   `json:"store_name"`
+	SafetyStock     int       `json:"safety_stock"`
+	ReorderPoint    int       `json:"reorder_point"`
+	ReorderQuantity int       `json:"reorder_quantity"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// StockThresholdFilter holds the optional filters for listing thresholds
+type StockThresholdFilter struct {
+	ProductID *int `form:"product_id"`
+	StoreID   *int `form:"store_id"`
+}
+
+// UpsertStockThresholdRequest sets the threshold of a product, optionally for one store
+type UpsertStockThresholdRequest struct {
+	ProductID       int  `json:"product_id" binding:"required"`
+	StoreID         *int `json:"store_id"`
+	SafetyStock     int  `json:"safety_stock" binding:"min=0"`
+	ReorderPoint    int  `json:"reorder_point" binding:"min=0"`
+	ReorderQuantity int  `json:"reorder_quantity" binding:"required,min=1"`
+}
+
+// LowStockAlertStatus is the state of a low-stock alert
+type LowStockAlertStatus string
+
+const (
+	LowStockAlertOpen         LowStockAlertStatus = "open"
+	LowStockAlertAcknowledged LowStockAlertStatus = "acknowledged"
+	LowStockAlertResolved     LowStockAlertStatus = "resolved"
+)
+
+// IsValid reports whether the status is known
+func (s LowStockAlertStatus) IsValid() bool {
+	switch s {
+	case LowStockAlertOpen, LowStockAlertAcknowledged, LowStockAlertResolved:
+		return true
+	}
+	return false
+}
+
+// LowStockAlert records a product whose available stock at a store reached its reorder point
+type LowStockAlert struct {
+	ID                int                 `json:"id"`
+	ProductID         int                 `json:"product_id"`
+	ProductTitle      string              `json:"product_title"`
+	ManufacturerID    *int                `json:"manufacturer_id"`
+	StoreID           int                 `json:"store_id"`
+	StoreName         string              `json:"store_name"`
+	Status            LowStockAlertStatus `json:"status"`
+	AvailableQuantity int                 `json:"available_quantity"` // when the alert was raised
+	CurrentAvailable  int                 `json:"current_available"`
+	ReorderPoint      int                 `json:"reorder_point"`
+	SafetyStock       int                 `json:"safety_stock"`
+	StockRequestID    *int                `json:"stock_request_id"`
+	AcknowledgedBy    *string             `json:"acknowledged_by"`
+	AcknowledgedAt    *time.Time          `json:"acknowledged_at"`
+	ResolvedAt        *time.Time          `json:"resolved_at"`
+	CreatedAt         time.Time           `json:"created_at"`
+}
+
+// LowStockAlertFilter holds the optional filters for listing alerts
+type LowStockAlertFilter struct {
+	Status         LowStockAlertStatus `form:"status"`
+	StoreID        *int                `form:"store_id"`
+	ProductID      *int                `form:"product_id"`
+	ManufacturerID *int                `form:"manufacturer_id"`
+}
+
+// LowStockCheckDefaults apply to products without a stock threshold
+type LowStockCheckDefaults struct {
+	ReorderPoint    int
+	ReorderQuantity int
+}
+
+// LowStockCheckResult summarizes one run of the low-stock checker
+type LowStockCheckResult struct {
+	AlertsRaised    int `json:"alerts_raised"`
+	AlertsResolved  int `json:"alerts_resolved"`
+	RequestsCreated int `json:"requests_created"`
+}
@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// OpeningHours is one weekly opening interval in the store's local time
+type OpeningHours struct {
+	DayOfWeek int    `json:"day_of_week" binding:"min=0,max=6"` // 0 = Sunday ... 6 = Saturday
+	OpensAt   string `json:"opens_at" binding:"required"`       // HH:MM
+	ClosesAt  string `json:"closes_at" binding:"required"`      // HH:MM, 24:00 for midnight
+}
+
+// StoreHoursException replaces the weekly hours on a date: closed all day or special hours
+type StoreHoursException struct {
+	ID       int     `json:"id"`
+	StoreID  int     `json:"store_id"`
+	Date     string  `json:"date"` // YYYY-MM-DD
+	IsClosed bool    `json:"is_closed"`
+	OpensAt  *string `json:"opens_at,omitempty"`
+	ClosesAt *string `json:"closes_at,omitempty"`
+	Reason   *string `json:"reason,omitempty"`
+}
+
+// StoreHours is the opening schedule of a store together with its current state
+type StoreHours struct {
+	StoreID    int                   `json:"store_id"`
+	Timezone   string                `json:"timezone"`
+	Weekly     []OpeningHours        `json:"weekly"` // empty means always open
+	Exceptions []StoreHoursException `json:"exceptions"`
+	IsOpen     bool                  `json:"is_open"`
+	NextOpenAt *time.Time            `json:"next_open_at"`
+}
+
+// UpdateStoreHoursRequest replaces a store's timezone and weekly hours
+type UpdateStoreHoursRequest struct {
+	Timezone string         `json:"timezone" binding:"required"`
+	Weekly   []OpeningHours `json:"weekly" binding:"dive"`
+}
+
+// CreateStoreHoursExceptionRequest adds or replaces the exception for a date
+type CreateStoreHoursExceptionRequest struct {
+	Date     string  `json:"date" binding:"required"` // YYYY-MM-DD
+	IsClosed bool    `json:"is_closed"`
+	OpensAt  *string `json:"opens_at"`
+	ClosesAt *string `json:"closes_at"`
+	Reason   *string `json:"reason"`
+}
@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// StoreProductOverride is a store's own price or availability for a product
+type StoreProductOverride struct {
+	StoreID            int       `json:"store_id"`
+	ProductID          int       `json:"product_id"`
+	ProductTitle       string    `json:"product_title"`
+	BasePrice          float64   `json:"base_price"`          // products.main_price
+	Price              *float64  `json:"price"`               // nil keeps the base price
+	StrikethroughPrice *float64  `json:"strikethrough_price"` // nil keeps the product's strikethrough price
+	IsActive           bool      `json:"is_active"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+// UpsertStoreProductOverrideRequest sets a store's override for a product
+type UpsertStoreProductOverrideRequest struct {
+	Price              *float64 `json:"price" binding:"omitempty,gt=0"`
+	StrikethroughPrice *float64 `json:"strikethrough_price" binding:"omitempty,gt=0"`
+	IsActive           *bool    `json:"is_active"` // defaults to true
+}
+
+// StoreProductValues are the values a store sells a product with
+type StoreProductValues struct {
+	Price              *float64 // nil when the store has no price override
+	StrikethroughPrice *float64
+	IsActive           bool // false when the store has deactivated the product
+	Available          *int // quantity - reserved in the store's inventory; nil without an inventory row
+}
+
+// ApplyStoreValues replaces the product's price, stock and active flag with the store's values
+func (p *Product) ApplyStoreValues(v StoreProductValues) {
+	if v.Price != nil {
+		p.MainPrice = *v.Price
+	}
+	if v.StrikethroughPrice != nil {
+		p.StrikethroughPrice = v.StrikethroughPrice
+	}
+	if v.Available != nil {
+		p.StockLeft = *v.Available
+	}
+	p.IsActive = p.IsActive && v.IsActive
+}
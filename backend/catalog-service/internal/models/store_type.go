@@ -0,0 +1,30 @@
+package models
+
+// StoreType is the stable code of a store type. Codes are the only store type values stored
+// in the database and exchanged over the API; valid codes, display names and category
+// associations come from the store_types registry.
+type StoreType string
+
+// Store types seeded by migration 019 that the catalog treats specially. The registry may
+// hold further types.
+const (
+	StoreTypeRetailStore       StoreType = "RetailStore"
+	StoreTypeUnmannedStore     StoreType = "UnmannedStore"
+	StoreTypeUnmannedWarehouse StoreType = "UnmannedWarehouse"
+	StoreTypeExhibitionStore   StoreType = "ExhibitionStore"
+	StoreTypeExhibitionMall    StoreType = "ExhibitionMall"
+	StoreTypeGroupBuying       StoreType = "GroupBuying"
+)
+
+// DefaultLocale is used for store type display names when no locale is requested
+const DefaultLocale = "zh-CN"
+
+// StoreTypeInfo is a store_types registry entry with its display name in the requested locale
+type StoreTypeInfo struct {
+	Code                StoreType     `json:"code"`
+	MiniAppTypes        []MiniAppType `json:"mini_app_types"` // mini-apps serving this store type
+	CategoryAssociation *string       `json:"category_association"`
+	DisplayName         string        `json:"display_name"`
+	Locale              string        `json:"locale"` // locale of DisplayName, DefaultLocale when the requested one is missing
+	SortOrder           int           `json:"sort_order"`
+}
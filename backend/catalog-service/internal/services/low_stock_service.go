@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/expomadeinworld/madeinworld/catalog-service/internal/db"
+	"github.com/expomadeinworld/madeinworld/catalog-service/internal/models"
+)
+
+// Defaults for the automatic low-stock trigger
+const (
+	defaultLowStockThreshold       = 10
+	defaultLowStockReorderQuantity = 50
+	defaultLowStockIntervalMinutes = 60
+)
+
+// LowStockService periodically raises low-stock alerts and stock requests for store inventory that runs low
+type LowStockService struct {
+	db       *db.Database
+	interval time.Duration
+	stopChan chan bool
+}
+
+// NewLowStockService creates a new low-stock service
+func NewLowStockService(database *db.Database, intervalMinutes int) *LowStockService {
+	return &LowStockService{
+		db:       database,
+		interval: time.Duration(intervalMinutes) * time.Minute,
+		stopChan: make(chan bool),
+	}
+}
+
+// LowStockIntervalFromEnv returns LOW_STOCK_CHECK_INTERVAL_MINUTES or the default
+func LowStockIntervalFromEnv() int {
+	return envInt("LOW_STOCK_CHECK_INTERVAL_MINUTES", defaultLowStockIntervalMinutes)
+}
+
+// LowStockDefaultsFromEnv returns the reorder point and quantity for products without a stock threshold
+func LowStockDefaultsFromEnv() models.LowStockCheckDefaults {
+	return models.LowStockCheckDefaults{
+		ReorderPoint:    envInt("LOW_STOCK_THRESHOLD", defaultLowStockThreshold),
+		ReorderQuantity: envInt("LOW_STOCK_REORDER_QUANTITY", defaultLowStockReorderQuantity),
+	}
+}
+
+// Start begins the periodic low-stock check
+func (s *LowStockService) Start() {
+	log.Printf("Starting low stock service with %v interval", s.interval)
+
+	// Run check immediately on start
+	s.runCheck()
+
+	ticker := time.NewTicker(s.interval)
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				s.runCheck()
+			case <-s.stopChan:
+				ticker.Stop()
+				log.Println("Low stock service stopped")
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the low-stock service
+func (s *LowStockService) Stop() {
+	s.stopChan <- true
+}
+
+// runCheck raises alerts and stock requests for every store/product at or below its reorder point
+func (s *LowStockService) runCheck() {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	result, err := s.db.RunLowStockCheck(ctx, LowStockDefaultsFromEnv())
+	if err != nil {
+		log.Printf("Error during low stock check: %v", err)
+		return
+	}
+	if result.AlertsRaised > 0 || result.AlertsResolved > 0 || result.RequestsCreated > 0 {
+		log.Printf("Low stock check raised %d alerts, resolved %d alerts and created %d stock requests",
+			result.AlertsRaised, result.AlertsResolved, result.RequestsCreated)
+	}
+}
+
+// envInt reads a positive integer environment variable with a default
+func envInt(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed <= 0 {
+		log.Printf("Invalid %s value: %s, using default %d", key, value, defaultValue)
+		return defaultValue
+	}
+	return parsed
+}
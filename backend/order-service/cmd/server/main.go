@@ -8,6 +8,7 @@ import (
 
 	"github.com/expomadeinworld/madeinworld/order-service/internal/api"
 	"github.com/expomadeinworld/madeinworld/order-service/internal/db"
+	"github.com/expomadeinworld/madeinworld/order-service/internal/services"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 )
@@ -32,6 +33,34 @@ func main() {
 		defer database.Close()
 	}
 
+	// Keep the analytics rollups read by the admin dashboard up to date
+	if database != nil {
+		rollupService := services.NewRollupService(database, services.RollupIntervalFromEnv())
+		rollupService.Start()
+		defer rollupService.Stop()
+	}
+
+	// Classify abandoned carts and send recovery emails
+	if database != nil {
+		abandonmentService := services.NewCartAbandonmentService(database)
+		abandonmentService.Start()
+		defer abandonmentService.Stop()
+	}
+
+	// Write off loyalty points past their expiry
+	if database != nil {
+		loyaltyExpiryService := services.NewLoyaltyExpiryService(database)
+		loyaltyExpiryService.Start()
+		defer loyaltyExpiryService.Stop()
+	}
+
+	// Notify users of price drops and restocks of wishlisted products
+	if database != nil {
+		wishlistAlertService := services.NewWishlistAlertService(database)
+		wishlistAlertService.Start()
+		defer wishlistAlertService.Stop()
+	}
+
 	// Initialize handlers
 	handler := api.NewHandler(database)
 
@@ -73,6 +102,9 @@ func setupRouter(handler *api.Handler) *gin.Engine {
 	router.Use(gin.Recovery())
 	router.Use(corsMiddleware())
 
+	// Serve proof-of-delivery photos stored locally
+	router.Static("/uploads", "./uploads")
+
 	// Health and readiness endpoints
 	router.GET("/live", func(c *gin.Context) { c.Status(200) })
 	router.GET("/ready", handler.Health)
@@ -82,12 +114,21 @@ func setupRouter(handler *api.Handler) *gin.Engine {
 	// API routes with JWT protection
 	apiGroup := router.Group("/api")
 	apiGroup.Use(api.AuthMiddleware())
+	apiGroup.Use(handler.AccountStatusMiddleware())
 	{
 		// Cart endpoints - mini-app specific
 		apiGroup.GET("/cart/:mini_app_type", handler.GetCart)
 		apiGroup.POST("/cart/:mini_app_type/add", handler.AddToCart)
 		apiGroup.PUT("/cart/:mini_app_type/update", handler.UpdateCartItem)
 		apiGroup.DELETE("/cart/:mini_app_type/remove/:product_id", handler.RemoveFromCart)
+		apiGroup.POST("/cart/:mini_app_type/save-for-later", handler.SaveForLater)
+
+		// Wishlist endpoints
+		apiGroup.GET("/wishlist", handler.GetWishlist)
+		apiGroup.POST("/wishlist", handler.AddToWishlist)
+		apiGroup.PUT("/wishlist/:item_id", handler.UpdateWishlistItem)
+		apiGroup.DELETE("/wishlist/:item_id", handler.RemoveFromWishlist)
+		apiGroup.POST("/wishlist/:item_id/move-to-cart", handler.MoveWishlistItemToCart)
 
 		// Order endpoints - mini-app specific
 		apiGroup.POST("/orders/:mini_app_type", handler.CreateOrder)
@@ -95,31 +136,92 @@ func setupRouter(handler *api.Handler) *gin.Engine {
 
 		// Specific order endpoint (different path to avoid conflict)
 		apiGroup.GET("/order/:order_id", handler.GetOrder)
+
+		// Notification inbox endpoints
+		apiGroup.GET("/notifications", handler.GetNotifications)
+		apiGroup.GET("/notifications/unread-count", handler.GetUnreadNotificationCount)
+		apiGroup.PUT("/notifications/read-all", handler.MarkAllNotificationsRead)
+		apiGroup.PUT("/notifications/:notification_id/read", handler.MarkNotificationRead)
+
+		// Loyalty points endpoints
+		apiGroup.GET("/loyalty", handler.GetMyLoyalty)
+		apiGroup.GET("/loyalty/history", handler.GetMyLoyaltyHistory)
 	}
 
 	// Admin API routes with authentication and admin middleware
 	adminGroup := router.Group("/api/admin")
 	adminGroup.Use(api.AuthMiddleware())
+	adminGroup.Use(handler.AccountStatusMiddleware())
 	adminGroup.Use(api.AdminMiddleware())
+	adminGroup.Use(handler.PartnerScopeMiddleware())
 	{
-		// Order management endpoints
+		// Order and cart views; partners see those of their stores only
 		adminGroup.GET("/orders", handler.GetAdminOrders)
 		adminGroup.GET("/orders/:order_id", handler.GetAdminOrder)
-		adminGroup.PUT("/orders/:order_id/status", handler.UpdateOrderStatus)
-		adminGroup.DELETE("/orders/:order_id", handler.DeleteOrder)
-		adminGroup.POST("/orders/bulk-update", handler.BulkUpdateOrders)
-
-		// Cart management endpoints
 		adminGroup.GET("/carts", handler.GetAdminCarts)
+		adminGroup.GET("/carts/abandoned", handler.GetAbandonedCarts)
 		adminGroup.GET("/carts/:cart_id", handler.GetAdminCart)
-		adminGroup.PUT("/carts/:cart_id/items", handler.UpdateAdminCartItem)
-		adminGroup.DELETE("/carts/:cart_id", handler.DeleteAdminCart)
-
-		// Statistics endpoints
 		adminGroup.GET("/orders/statistics", handler.GetOrderStatistics)
 		adminGroup.GET("/carts/statistics", handler.GetCartStatistics)
 	}
 
+	// Endpoints that change data or span all stores are not available to partners
+	adminOnlyGroup := adminGroup.Group("")
+	adminOnlyGroup.Use(api.AdminOnlyMiddleware())
+	{
+		// Order management endpoints
+		adminOnlyGroup.PUT("/orders/:order_id/status", handler.UpdateOrderStatus)
+		adminOnlyGroup.DELETE("/orders/:order_id", handler.DeleteOrder)
+		adminOnlyGroup.POST("/orders/bulk-update", handler.BulkUpdateOrders)
+
+		// Cart management endpoints
+		adminOnlyGroup.PUT("/carts/:cart_id/items", handler.UpdateAdminCartItem)
+		adminOnlyGroup.DELETE("/carts/:cart_id", handler.DeleteAdminCart)
+
+		// Analytics rollup endpoints
+		adminOnlyGroup.GET("/analytics/rollups", handler.GetRollupStatus)
+		adminOnlyGroup.POST("/analytics/rollups/backfill", handler.BackfillRollups)
+
+		// Shipment management endpoints
+		adminOnlyGroup.GET("/shipments", handler.GetShipments)
+		adminOnlyGroup.POST("/shipments", handler.CreateShipment)
+		adminOnlyGroup.GET("/shipments/:shipment_id", handler.GetShipment)
+		adminOnlyGroup.PUT("/shipments/:shipment_id/assign", handler.AssignShipment)
+
+		// Loyalty program endpoints
+		adminOnlyGroup.GET("/loyalty/rules", handler.GetLoyaltyRules)
+		adminOnlyGroup.POST("/loyalty/rules", handler.CreateLoyaltyRule)
+		adminOnlyGroup.PUT("/loyalty/rules/:rule_id", handler.UpdateLoyaltyRule)
+		adminOnlyGroup.DELETE("/loyalty/rules/:rule_id", handler.DeleteLoyaltyRule)
+		adminOnlyGroup.GET("/loyalty/users/:user_id", handler.GetUserLoyalty)
+		adminOnlyGroup.GET("/loyalty/users/:user_id/history", handler.GetUserLoyaltyHistory)
+		adminOnlyGroup.POST("/loyalty/users/:user_id/adjustments", handler.AdjustUserLoyalty)
+
+		// Wishlist statistics endpoint
+		adminOnlyGroup.GET("/wishlists/statistics", handler.GetWishlistStatistics)
+	}
+
+	// Manufacturer portal routes, scoped to the manufacturer linked to the caller
+	manufacturerGroup := router.Group("/api/manufacturer")
+	manufacturerGroup.Use(api.AuthMiddleware())
+	manufacturerGroup.Use(handler.AccountStatusMiddleware())
+	manufacturerGroup.Use(handler.ManufacturerMiddleware())
+	{
+		manufacturerGroup.GET("/sales", handler.GetManufacturerSales)
+	}
+
+	// 3PL partner routes, scoped to the shipments assigned to the caller
+	threePLGroup := router.Group("/api/3pl")
+	threePLGroup.Use(api.AuthMiddleware())
+	threePLGroup.Use(handler.AccountStatusMiddleware())
+	threePLGroup.Use(handler.RoleMiddleware("3PL"))
+	{
+		threePLGroup.GET("/shipments", handler.GetMyShipments)
+		threePLGroup.GET("/shipments/:shipment_id", handler.GetMyShipment)
+		threePLGroup.POST("/shipments/:shipment_id/pickup", handler.PickUpShipment)
+		threePLGroup.POST("/shipments/:shipment_id/deliver", handler.DeliverShipment)
+	}
+
 	// Root endpoint for basic info
 	router.GET("/", func(c *gin.Context) {
 		c.JSON(200, gin.H{
@@ -0,0 +1,47 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/expomadeinworld/madeinworld/order-service/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// errAddressNotFound is returned when the address (or the default address) is missing from
+// the user's address book
+var errAddressNotFound = errors.New("address not found")
+
+// getDeliveryAddress copies an entry of the user's address book (kept by user-service) for
+// storing on an order. A nil addressID picks the user's default address.
+func (h *Handler) getDeliveryAddress(ctx context.Context, userID string, addressID *string) (*models.DeliveryAddress, error) {
+	var a models.DeliveryAddress
+	err := h.db.Pool.QueryRow(ctx, `
+		SELECT id, label, recipient_name, phone, line1, line2, city, region, postal_code,
+		       country_code, latitude::float8, longitude::float8, delivery_instructions
+		FROM user_addresses
+		WHERE user_id::text = $1 AND ($2::text IS NULL AND is_default OR id::text = $2)
+	`, userID, addressID).Scan(
+		&a.AddressID,
+		&a.Label,
+		&a.RecipientName,
+		&a.Phone,
+		&a.Line1,
+		&a.Line2,
+		&a.City,
+		&a.Region,
+		&a.PostalCode,
+		&a.CountryCode,
+		&a.Latitude,
+		&a.Longitude,
+		&a.DeliveryInstructions,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errAddressNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get address: %w", err)
+	}
+	return &a, nil
+}
@@ -2,13 +2,18 @@ package api
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/expomadeinworld/madeinworld/order-service/internal/models"
+	"github.com/expomadeinworld/madeinworld/order-service/internal/services"
 	"github.com/jackc/pgx/v5"
 )
 
+var errInvalidStatisticsRequest = errors.New("invalid statistics request")
+
 // getAdminOrders retrieves orders with filtering and pagination for admin
 func (h *Handler) getAdminOrders(ctx context.Context, req *models.AdminOrderListRequest) ([]models.AdminOrderResponse, int, error) {
 	// Build WHERE clause
@@ -46,6 +51,12 @@ func (h *Handler) getAdminOrders(ctx context.Context, req *models.AdminOrderList
 		argIndex++
 	}
 
+	if req.StoreIDs != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("o.store_id = ANY($%d)", argIndex))
+		args = append(args, req.StoreIDs)
+		argIndex++
+	}
+
 	if req.DateFrom != "" {
 		whereConditions = append(whereConditions, fmt.Sprintf("o.created_at >= $%d", argIndex))
 		args = append(args, req.DateFrom+" 00:00:00")
@@ -121,7 +132,9 @@ func (h *Handler) getAdminOrders(ctx context.Context, req *models.AdminOrderList
 			o.status,
 			(SELECT COUNT(*) FROM order_items oi WHERE oi.order_id = o.id) as item_count,
 			o.created_at,
-			o.updated_at
+			o.updated_at,
+			o.fulfillment_method,
+			o.delivery_address
 		FROM orders o
 		LEFT JOIN users u ON o.user_id = u.id
 		LEFT JOIN stores s ON o.store_id = s.store_id
@@ -154,6 +167,8 @@ func (h *Handler) getAdminOrders(ctx context.Context, req *models.AdminOrderList
 			&order.ItemCount,
 			&order.CreatedAt,
 			&order.UpdatedAt,
+			&order.FulfillmentMethod,
+			&order.DeliveryAddress,
 		)
 		if err != nil {
 			return nil, 0, fmt.Errorf("failed to scan order: %w", err)
@@ -185,7 +200,9 @@ func (h *Handler) getAdminOrderByID(ctx context.Context, orderID string) (*model
 			o.status,
 			(SELECT COUNT(*) FROM order_items oi WHERE oi.order_id = o.id) as item_count,
 			o.created_at,
-			o.updated_at
+			o.updated_at,
+			o.fulfillment_method,
+			o.delivery_address
 		FROM orders o
 		LEFT JOIN users u ON o.user_id = u.id
 		LEFT JOIN stores s ON o.store_id = s.store_id
@@ -206,6 +223,8 @@ func (h *Handler) getAdminOrderByID(ctx context.Context, orderID string) (*model
 		&order.ItemCount,
 		&order.CreatedAt,
 		&order.UpdatedAt,
+		&order.FulfillmentMethod,
+		&order.DeliveryAddress,
 	)
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -239,7 +258,7 @@ func (h *Handler) updateOrderStatus(ctx context.Context, orderID string, newStat
 
 	// Get current status
 	var currentStatus models.OrderStatus
-	err = tx.QueryRow(ctx, "SELECT status FROM orders WHERE id = $1", orderID).Scan(&currentStatus)
+	err = tx.QueryRow(ctx, "SELECT status FROM orders WHERE id = $1 FOR UPDATE", orderID).Scan(&currentStatus)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return fmt.Errorf("order not found")
@@ -255,6 +274,17 @@ func (h *Handler) updateOrderStatus(ctx context.Context, orderID string, newStat
 		return fmt.Errorf("failed to update order status: %w", err)
 	}
 
+	// Let the customer know when the status actually changed, and award or reverse their
+	// loyalty points
+	if currentStatus != newStatus {
+		if err := notifyOrderStatusChange(ctx, tx, orderID, newStatus); err != nil {
+			return err
+		}
+		if err := applyOrderStatusLoyalty(ctx, tx, orderID, newStatus, changedBy); err != nil {
+			return err
+		}
+	}
+
 	// Commit transaction
 	err = tx.Commit(ctx)
 	if err != nil {
@@ -281,38 +311,59 @@ func (h *Handler) bulkUpdateOrderStatus(ctx context.Context, orderIDs []string,
 	return successCount, nil
 }
 
-// getOrderStatistics retrieves comprehensive order statistics for admin dashboard
-func (h *Handler) getOrderStatistics(ctx context.Context, dateFrom, dateTo string) (*models.OrderStatistics, error) {
+// getOrderStatistics retrieves comprehensive order statistics for admin dashboard.
+// The period covers whole days in the requested timezone; trends are bucketed by the
+// requested granularity and compared against the preceding period of equal length.
+// Figures are read from the analytics rollups maintained by the rollup service: daily
+// rollups for UTC and hourly rollups for other timezones so day boundaries line up.
+// Timezones that are not a whole number of hours from UTC are served from the orders.
+func (h *Handler) getOrderStatistics(ctx context.Context, req *models.OrderStatisticsRequest) (*models.OrderStatistics, error) {
+	period, err := h.resolveStatisticsPeriod(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
 	stats := &models.OrderStatistics{
+		DateFrom:         period.dateFrom,
+		DateTo:           period.dateTo,
+		Granularity:      req.Granularity,
+		Timezone:         req.Timezone,
 		OrdersByStatus:   make(map[models.OrderStatus]int),
 		OrdersByMiniApp:  make(map[models.MiniAppType]int),
 		RevenueByMiniApp: make(map[models.MiniAppType]float64),
 	}
 
-	// Build date filter
-	dateFilter := ""
-	var dateArgs []interface{}
-	if dateFrom != "" && dateTo != "" {
-		dateFilter = "WHERE created_at >= $1 AND created_at <= $2"
-		dateArgs = append(dateArgs, dateFrom+" 00:00:00", dateTo+" 23:59:59")
-	} else if dateFrom != "" {
-		dateFilter = "WHERE created_at >= $1"
-		dateArgs = append(dateArgs, dateFrom+" 00:00:00")
-	} else if dateTo != "" {
-		dateFilter = "WHERE created_at <= $1"
-		dateArgs = append(dateArgs, dateTo+" 23:59:59")
+	if err := h.db.Pool.QueryRow(ctx, "SELECT last_run_at FROM analytics_rollup_state WHERE rollup_name = $1", services.OrderRollupName).Scan(&stats.DataRefreshedAt); err != nil && err != pgx.ErrNoRows {
+		return nil, fmt.Errorf("failed to get rollup state: %w", err)
+	}
+
+	// All queries below share the same rollup granularity and half-open period filter,
+	// limited to the caller's stores for partners
+	orderRollups := period.orderRollups()
+	rollupFilter := "WHERE r.granularity = $1 AND r.bucket_start >= $2 AND r.bucket_start < $3"
+	rollupArgs := []interface{}{period.rollupGranularity, period.start, period.end}
+	prevArgs := []interface{}{period.rollupGranularity, period.prevStart, period.start}
+	trendScope := ""
+	if req.StoreIDs != nil {
+		rollupFilter += " AND r.store_id = ANY($4)"
+		rollupArgs = append(rollupArgs, req.StoreIDs)
+		prevArgs = append(prevArgs, req.StoreIDs)
+		trendScope = "AND r.store_id = ANY($8)"
 	}
 
 	// Get total orders and revenue
-	totalQuery := fmt.Sprintf("SELECT COUNT(*), COALESCE(SUM(total_amount), 0) FROM orders %s", dateFilter)
-	err := h.db.Pool.QueryRow(ctx, totalQuery, dateArgs...).Scan(&stats.TotalOrders, &stats.TotalRevenue)
+	totalQuery := fmt.Sprintf("SELECT COALESCE(SUM(r.order_count), 0), COALESCE(SUM(r.revenue), 0) FROM %s r %s", orderRollups, rollupFilter)
+	err = h.db.Pool.QueryRow(ctx, totalQuery, rollupArgs...).Scan(&stats.TotalOrders, &stats.TotalRevenue)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get total statistics: %w", err)
 	}
+	if stats.TotalOrders > 0 {
+		stats.AverageOrderValue = stats.TotalRevenue / float64(stats.TotalOrders)
+	}
 
 	// Get orders by status
-	statusQuery := fmt.Sprintf("SELECT status, COUNT(*) FROM orders %s GROUP BY status", dateFilter)
-	rows, err := h.db.Pool.Query(ctx, statusQuery, dateArgs...)
+	statusQuery := fmt.Sprintf("SELECT r.status, SUM(r.order_count) FROM %s r %s GROUP BY r.status", orderRollups, rollupFilter)
+	rows, err := h.db.Pool.Query(ctx, statusQuery, rollupArgs...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get status statistics: %w", err)
 	}
@@ -328,8 +379,8 @@ func (h *Handler) getOrderStatistics(ctx context.Context, dateFrom, dateTo strin
 	}
 
 	// Get orders and revenue by mini-app
-	miniAppQuery := fmt.Sprintf("SELECT mini_app_type, COUNT(*), COALESCE(SUM(total_amount), 0) FROM orders %s GROUP BY mini_app_type", dateFilter)
-	rows, err = h.db.Pool.Query(ctx, miniAppQuery, dateArgs...)
+	miniAppQuery := fmt.Sprintf("SELECT r.mini_app_type, SUM(r.order_count), COALESCE(SUM(r.revenue), 0) FROM %s r %s GROUP BY r.mini_app_type", orderRollups, rollupFilter)
+	rows, err = h.db.Pool.Query(ctx, miniAppQuery, rollupArgs...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get mini-app statistics: %w", err)
 	}
@@ -346,16 +397,303 @@ func (h *Handler) getOrderStatistics(ctx context.Context, dateFrom, dateTo strin
 		stats.RevenueByMiniApp[miniAppType] = revenue
 	}
 
-	// Get daily statistics for the last 30 days (simplified for now)
+	// Get orders and revenue by store; store_id 0 holds orders without a store
+	storeQuery := fmt.Sprintf(`
+		SELECT NULLIF(r.store_id, 0), COALESCE(s.name, ''), SUM(r.order_count), COALESCE(SUM(r.revenue), 0)
+		FROM %s r
+		LEFT JOIN stores s ON s.store_id = r.store_id
+		%s
+		GROUP BY r.store_id, s.name
+		ORDER BY COALESCE(SUM(r.revenue), 0) DESC
+	`, orderRollups, rollupFilter)
+	rows, err = h.db.Pool.Query(ctx, storeQuery, rollupArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get store statistics: %w", err)
+	}
+	defer rows.Close()
+
+	stats.OrdersByStore = []models.StoreOrderStats{}
+	for rows.Next() {
+		var storeStats models.StoreOrderStats
+		if err := rows.Scan(&storeStats.StoreID, &storeStats.StoreName, &storeStats.OrderCount, &storeStats.Revenue); err != nil {
+			return nil, fmt.Errorf("failed to scan store statistics: %w", err)
+		}
+		stats.OrdersByStore = append(stats.OrdersByStore, storeStats)
+	}
+
+	// Get order counts and revenue per bucket, including empty buckets.
+	// Buckets are computed on local time in the requested timezone.
+	trendQuery := fmt.Sprintf(`
+		WITH buckets AS (
+			SELECT generate_series(
+				date_trunc($4, $5::date::timestamp),
+				$6::date::timestamp,
+				('1 ' || $4)::interval
+			) AS bucket
+		)
+		SELECT to_char(b.bucket, 'YYYY-MM-DD'), COALESCE(SUM(r.order_count), 0), COALESCE(SUM(r.revenue), 0)
+		FROM buckets b
+		LEFT JOIN %s r
+			ON date_trunc($4, r.bucket_start AT TIME ZONE $7) = b.bucket
+			AND r.granularity = $1 AND r.bucket_start >= $2 AND r.bucket_start < $3
+			%s
+		GROUP BY b.bucket
+		ORDER BY b.bucket
+	`, orderRollups, trendScope)
+	trendArgs := []interface{}{period.rollupGranularity, period.start, period.end,
+		req.Granularity, period.dateFrom, period.dateTo, req.Timezone}
+	if req.StoreIDs != nil {
+		trendArgs = append(trendArgs, req.StoreIDs)
+	}
+	rows, err = h.db.Pool.Query(ctx, trendQuery, trendArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trend statistics: %w", err)
+	}
+	defer rows.Close()
+
 	stats.DailyStats = []models.DailyOrderStats{}
+	for rows.Next() {
+		var bucket models.DailyOrderStats
+		if err := rows.Scan(&bucket.Date, &bucket.OrderCount, &bucket.Revenue); err != nil {
+			return nil, fmt.Errorf("failed to scan trend statistics: %w", err)
+		}
+		stats.DailyStats = append(stats.DailyStats, bucket)
+	}
 
-	// Get top products by order count (simplified for now)
-	// Note: This is a simplified version - can be enhanced later
-	stats.TopProducts = []models.ProductOrderStats{}
+	// Get top products by revenue and by units; cancelled orders are not sales
+	stats.TopProducts, err = h.getTopProducts(ctx, period, "revenue", req.TopLimit, req.StoreIDs)
+	if err != nil {
+		return nil, err
+	}
+	stats.TopProductsByUnits, err = h.getTopProducts(ctx, period, "units", req.TopLimit, req.StoreIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Compare with the previous period of equal length
+	stats.PreviousPeriod = models.PeriodComparison{
+		DateFrom: period.prevDateFrom,
+		DateTo:   period.prevDateTo,
+	}
+	err = h.db.Pool.QueryRow(ctx, totalQuery, prevArgs...).Scan(
+		&stats.PreviousPeriod.TotalOrders, &stats.PreviousPeriod.TotalRevenue)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get previous period statistics: %w", err)
+	}
+	stats.PreviousPeriod.OrdersChangePercent = percentChange(float64(stats.TotalOrders), float64(stats.PreviousPeriod.TotalOrders))
+	stats.PreviousPeriod.RevenueChangePercent = percentChange(stats.TotalRevenue, stats.PreviousPeriod.TotalRevenue)
 
 	return stats, nil
 }
 
+// statisticsPeriod holds the resolved reporting period and the previous period of equal length
+type statisticsPeriod struct {
+	dateFrom, dateTo         string
+	prevDateFrom, prevDateTo string
+	start, end, prevStart    time.Time
+	rollupGranularity        string
+}
+
+// rawStatisticsGranularity reads statistics from the orders instead of the rollups
+const rawStatisticsGranularity = "raw"
+
+// rawOrderRollups presents each order as a rollup row of its own, so the rollup queries
+// also run against the orders
+const rawOrderRollups = `(
+	SELECT 'raw'::text AS granularity, o.created_at AS bucket_start, o.mini_app_type,
+	       COALESCE(o.store_id, 0) AS store_id, o.status, 1 AS order_count, o.total_amount AS revenue
+	FROM orders o
+)`
+
+// orderRollups returns the order rollup source for the period's granularity
+func (p *statisticsPeriod) orderRollups() string {
+	if p.rollupGranularity == rawStatisticsGranularity {
+		return rawOrderRollups
+	}
+	return "analytics_order_rollups"
+}
+
+// resolveStatisticsPeriod validates the request, applies defaults and converts the local
+// dates into absolute period boundaries using the database's timezone data
+func (h *Handler) resolveStatisticsPeriod(ctx context.Context, req *models.OrderStatisticsRequest) (*statisticsPeriod, error) {
+	if req.Granularity == "" {
+		req.Granularity = "day"
+	}
+	if req.Granularity != "day" && req.Granularity != "week" && req.Granularity != "month" {
+		return nil, fmt.Errorf("%w: granularity must be one of: day, week, month", errInvalidStatisticsRequest)
+	}
+	if req.Timezone == "" {
+		req.Timezone = "UTC"
+	}
+	if req.TopLimit == 0 {
+		req.TopLimit = 10
+	}
+
+	var validTimezone bool
+	err := h.db.Pool.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM pg_timezone_names WHERE name = $1)", req.Timezone).Scan(&validTimezone)
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate timezone: %w", err)
+	}
+	if !validTimezone {
+		return nil, fmt.Errorf("%w: unknown timezone '%s'", errInvalidStatisticsRequest, req.Timezone)
+	}
+
+	var today string
+	err = h.db.Pool.QueryRow(ctx, "SELECT to_char(CURRENT_TIMESTAMP AT TIME ZONE $1, 'YYYY-MM-DD')", req.Timezone).Scan(&today)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve current date: %w", err)
+	}
+
+	const layout = "2006-01-02"
+	dateTo := req.DateTo
+	if dateTo == "" {
+		dateTo = today
+	}
+	to, err := time.Parse(layout, dateTo)
+	if err != nil {
+		return nil, fmt.Errorf("%w: date_to must be in YYYY-MM-DD format", errInvalidStatisticsRequest)
+	}
+	from := to.AddDate(0, 0, -29)
+	if req.DateFrom != "" {
+		if from, err = time.Parse(layout, req.DateFrom); err != nil {
+			return nil, fmt.Errorf("%w: date_from must be in YYYY-MM-DD format", errInvalidStatisticsRequest)
+		}
+	}
+	if from.After(to) {
+		return nil, fmt.Errorf("%w: date_from must not be after date_to", errInvalidStatisticsRequest)
+	}
+
+	days := int(to.Sub(from).Hours()/24) + 1
+	prevTo := from.AddDate(0, 0, -1)
+	prevFrom := prevTo.AddDate(0, 0, -(days - 1))
+
+	period := &statisticsPeriod{
+		dateFrom:          from.Format(layout),
+		dateTo:            to.Format(layout),
+		prevDateFrom:      prevFrom.Format(layout),
+		prevDateTo:        prevTo.Format(layout),
+		rollupGranularity: "day",
+	}
+	if req.Timezone != "UTC" {
+		// Daily rollups are cut at UTC midnight
+		period.rollupGranularity = "hour"
+	}
+
+	err = h.db.Pool.QueryRow(ctx, `
+		SELECT $1::date::timestamp AT TIME ZONE $4,
+		       ($2::date + 1)::timestamp AT TIME ZONE $4,
+		       $3::date::timestamp AT TIME ZONE $4
+	`, period.dateFrom, period.dateTo, period.prevDateFrom, req.Timezone).Scan(&period.start, &period.end, &period.prevStart)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve period boundaries: %w", err)
+	}
+
+	if req.Timezone != "UTC" {
+		// Hourly rollups only line up with local days when the zone is a whole number of hours
+		// from UTC throughout the period and the previous one; offsets change at most once a day.
+		// Other zones are served from the orders.
+		var wholeHours bool
+		err = h.db.Pool.QueryRow(ctx, `
+			SELECT COALESCE(bool_and(EXTRACT(EPOCH FROM (t AT TIME ZONE $1) - (t AT TIME ZONE 'UTC'))::int % 3600 = 0), true)
+			FROM generate_series($2::timestamptz, $3::timestamptz, '1 day') t
+		`, req.Timezone, period.prevStart, period.end).Scan(&wholeHours)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check timezone offset: %w", err)
+		}
+		if !wholeHours {
+			period.rollupGranularity = rawStatisticsGranularity
+		}
+	}
+
+	return period, nil
+}
+
+// getTopProducts ranks products sold in the period by revenue or by units. Product rollups
+// are not kept per store, so figures limited to storeIDs are read from the orders, as are
+// periods the rollups cannot serve.
+func (h *Handler) getTopProducts(ctx context.Context, period *statisticsPeriod, rankBy string, limit int, storeIDs []int) ([]models.ProductOrderStats, error) {
+	orderBy := "SUM(r.revenue) DESC, SUM(r.units_sold) DESC"
+	if rankBy == "units" {
+		orderBy = "SUM(r.units_sold) DESC, SUM(r.revenue) DESC"
+	}
+	if storeIDs != nil || period.rollupGranularity == rawStatisticsGranularity {
+		return h.getOrderTopProducts(ctx, period, orderBy, limit, storeIDs)
+	}
+
+	// order_count is summed across buckets; an order spans a single bucket so this is exact
+	query := fmt.Sprintf(`
+		SELECT r.product_id::text, COALESCE(p.sku, ''), COALESCE(p.title, 'Unknown product'),
+		       SUM(r.order_count), SUM(r.units_sold), COALESCE(SUM(r.revenue), 0)
+		FROM analytics_product_rollups r
+		LEFT JOIN products p ON p.product_uuid = r.product_id
+		WHERE r.granularity = $1 AND r.bucket_start >= $2 AND r.bucket_start < $3
+		GROUP BY r.product_id, p.sku, p.title
+		ORDER BY %s
+		LIMIT $4
+	`, orderBy)
+
+	rows, err := h.db.Pool.Query(ctx, query, period.rollupGranularity, period.start, period.end, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get top products: %w", err)
+	}
+	return scanTopProducts(rows)
+}
+
+// getOrderTopProducts ranks products sold in the period from the orders, limited to storeIDs
+// unless nil, counting sales the same way as the product rollups
+func (h *Handler) getOrderTopProducts(ctx context.Context, period *statisticsPeriod, orderBy string, limit int, storeIDs []int) ([]models.ProductOrderStats, error) {
+	query := fmt.Sprintf(`
+		SELECT r.product_id::text, COALESCE(p.sku, ''), COALESCE(p.title, 'Unknown product'),
+		       COUNT(DISTINCT r.order_id), SUM(r.units_sold), COALESCE(SUM(r.revenue), 0)
+		FROM (
+			SELECT oi.product_id, oi.order_id, oi.quantity AS units_sold, oi.price AS revenue
+			FROM order_items oi
+			JOIN orders o ON o.id = oi.order_id
+			WHERE o.status != 'cancelled'
+			  AND o.created_at >= $1 AND o.created_at < $2
+			  AND ($3::int[] IS NULL OR o.store_id = ANY($3))
+		) r
+		LEFT JOIN products p ON p.product_uuid = r.product_id
+		GROUP BY r.product_id, p.sku, p.title
+		ORDER BY %s
+		LIMIT $4
+	`, orderBy)
+
+	rows, err := h.db.Pool.Query(ctx, query, period.start, period.end, storeIDs, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get top products: %w", err)
+	}
+	return scanTopProducts(rows)
+}
+
+// scanTopProducts reads the rows of a top products query
+func scanTopProducts(rows pgx.Rows) ([]models.ProductOrderStats, error) {
+	defer rows.Close()
+
+	products := []models.ProductOrderStats{}
+	for rows.Next() {
+		var product models.ProductOrderStats
+		err := rows.Scan(&product.ProductID, &product.SKU, &product.ProductTitle,
+			&product.OrderCount, &product.UnitsSold, &product.TotalRevenue)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan top products: %w", err)
+		}
+		products = append(products, product)
+	}
+
+	return products, rows.Err()
+}
+
+// percentChange returns the relative change from previous to current in percent,
+// or nil when there is nothing to compare against
+func percentChange(current, previous float64) *float64 {
+	if previous == 0 {
+		return nil
+	}
+	change := (current - previous) / previous * 100
+	return &change
+}
+
 // Admin Cart Database Methods
 
 // getAdminCarts retrieves carts with filtering and pagination for admin
@@ -383,6 +721,12 @@ func (h *Handler) getAdminCarts(ctx context.Context, req *models.AdminCartListRe
 		argIndex++
 	}
 
+	if req.StoreIDs != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("c.store_id = ANY($%d)", argIndex))
+		args = append(args, req.StoreIDs)
+		argIndex++
+	}
+
 	if req.DateFrom != "" {
 		whereConditions = append(whereConditions, fmt.Sprintf("c.created_at >= $%d", argIndex))
 		args = append(args, req.DateFrom+" 00:00:00")
@@ -677,41 +1021,44 @@ func (h *Handler) deleteAdminCart(ctx context.Context, cartID string) error {
 	return nil
 }
 
-// getCartStatistics retrieves comprehensive cart statistics for admin dashboard
-func (h *Handler) getCartStatistics(ctx context.Context, dateFrom, dateTo string) (*models.CartStatistics, error) {
+// getCartStatistics retrieves comprehensive cart statistics for admin dashboard.
+// Totals count carts started in the period and are read from the cart rollups;
+// abandoned carts are a live snapshot and recovery counts cover the period.
+func (h *Handler) getCartStatistics(ctx context.Context, dateFrom, dateTo string, storeIDs []int) (*models.CartStatistics, error) {
 	stats := &models.CartStatistics{
 		CartsByMiniApp:     make(map[models.MiniAppType]int),
 		CartValueByMiniApp: make(map[models.MiniAppType]float64),
 	}
 
-	// Build date filter
-	var dateFilter string
+	// Carts started in the period come from the daily cart rollups (UTC days)
+	rollupFilter := "WHERE r.granularity = 'day'"
 	var args []interface{}
 	argIndex := 1
 
-	if dateFrom != "" && dateTo != "" {
-		dateFilter = fmt.Sprintf("WHERE c.created_at >= $%d AND c.created_at <= $%d", argIndex, argIndex+1)
-		args = append(args, dateFrom+" 00:00:00", dateTo+" 23:59:59")
-		argIndex += 2
-	} else if dateFrom != "" {
-		dateFilter = fmt.Sprintf("WHERE c.created_at >= $%d", argIndex)
-		args = append(args, dateFrom+" 00:00:00")
+	if dateFrom != "" {
+		rollupFilter += fmt.Sprintf(" AND r.bucket_start >= $%d::date::timestamp AT TIME ZONE 'UTC'", argIndex)
+		args = append(args, dateFrom)
+		argIndex++
+	}
+	if dateTo != "" {
+		rollupFilter += fmt.Sprintf(" AND r.bucket_start < ($%d::date + 1)::timestamp AT TIME ZONE 'UTC'", argIndex)
+		args = append(args, dateTo)
 		argIndex++
-	} else if dateTo != "" {
-		dateFilter = fmt.Sprintf("WHERE c.created_at <= $%d", argIndex)
-		args = append(args, dateTo+" 23:59:59")
+	}
+	if storeIDs != nil {
+		rollupFilter += fmt.Sprintf(" AND r.store_id = ANY($%d)", argIndex)
+		args = append(args, storeIDs)
 		argIndex++
 	}
 
 	// Get total carts and total value
 	totalQuery := fmt.Sprintf(`
 		SELECT
-			COUNT(DISTINCT CONCAT(c.user_id, '-', c.mini_app_type)) as total_carts,
-			COALESCE(SUM(p.main_price * c.quantity), 0) as total_value
-		FROM carts c
-		JOIN products p ON c.product_id = p.product_uuid
+			COALESCE(SUM(r.carts_started), 0) as total_carts,
+			COALESCE(SUM(r.cart_value), 0) as total_value
+		FROM analytics_cart_rollups r
 		%s
-	`, dateFilter)
+	`, rollupFilter)
 
 	err := h.db.Pool.QueryRow(ctx, totalQuery, args...).Scan(&stats.TotalCarts, &stats.TotalCartValue)
 	if err != nil {
@@ -726,14 +1073,13 @@ func (h *Handler) getCartStatistics(ctx context.Context, dateFrom, dateTo string
 	// Get statistics by mini-app type
 	miniAppQuery := fmt.Sprintf(`
 		SELECT
-			c.mini_app_type,
-			COUNT(DISTINCT CONCAT(c.user_id, '-', c.mini_app_type)) as cart_count,
-			COALESCE(SUM(p.main_price * c.quantity), 0) as total_value
-		FROM carts c
-		JOIN products p ON c.product_id = p.product_uuid
+			r.mini_app_type,
+			COALESCE(SUM(r.carts_started), 0) as cart_count,
+			COALESCE(SUM(r.cart_value), 0) as total_value
+		FROM analytics_cart_rollups r
 		%s
-		GROUP BY c.mini_app_type
-	`, dateFilter)
+		GROUP BY r.mini_app_type
+	`, rollupFilter)
 
 	rows, err := h.db.Pool.Query(ctx, miniAppQuery, args...)
 	if err != nil {
@@ -752,17 +1098,50 @@ func (h *Handler) getCartStatistics(ctx context.Context, dateFrom, dateTo string
 		stats.CartValueByMiniApp[miniAppType] = value
 	}
 
-	// Get abandoned carts (older than 7 days)
+	// Abandoned carts are a live snapshot of the cart abandonment tracking
 	abandonedQuery := `
-		SELECT COUNT(DISTINCT CONCAT(c.user_id, '-', c.mini_app_type))
-		FROM carts c
-		WHERE c.updated_at < CURRENT_TIMESTAMP - INTERVAL '7 days'
+		SELECT COUNT(*), COALESCE(SUM(lines.cart_value), 0)
+		FROM cart_activity a
+		LEFT JOIN LATERAL (
+			SELECT SUM(p.main_price * c.quantity) AS cart_value
+			FROM carts c
+			LEFT JOIN products p ON p.product_uuid = c.product_id
+			WHERE c.user_id = a.user_id AND c.mini_app_type = a.mini_app_type AND COALESCE(c.store_id, 0) = a.store_id
+		) lines ON true
+		WHERE a.status = 'abandoned'
+		  AND ($1::int[] IS NULL OR a.store_id = ANY($1))
 	`
 
-	err = h.db.Pool.QueryRow(ctx, abandonedQuery).Scan(&stats.AbandonedCarts)
+	err = h.db.Pool.QueryRow(ctx, abandonedQuery, storeIDs).Scan(&stats.AbandonedCarts, &stats.AbandonedCartValue)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get abandoned cart count: %w", err)
 	}
 
+	// Recovery emails and recovered carts in the period
+	recoveryQuery := `
+		SELECT
+			COUNT(*) FILTER (WHERE recovery_email_sent_at >= COALESCE($1::date::timestamp AT TIME ZONE 'UTC', '-infinity')
+			                   AND recovery_email_sent_at < COALESCE(($2::date + 1)::timestamp AT TIME ZONE 'UTC', 'infinity')),
+			COUNT(*) FILTER (WHERE recovered
+			                   AND converted_at >= COALESCE($1::date::timestamp AT TIME ZONE 'UTC', '-infinity')
+			                   AND converted_at < COALESCE(($2::date + 1)::timestamp AT TIME ZONE 'UTC', 'infinity'))
+		FROM cart_activity
+		WHERE recovery_email_sent_at IS NOT NULL
+		  AND ($3::int[] IS NULL OR store_id = ANY($3))
+	`
+
+	err = h.db.Pool.QueryRow(ctx, recoveryQuery, nullableDate(dateFrom), nullableDate(dateTo), storeIDs).Scan(&stats.RecoveryEmailsSent, &stats.RecoveredCarts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cart recovery statistics: %w", err)
+	}
+
 	return stats, nil
 }
+
+// nullableDate returns nil for an empty date filter so SQL can treat it as unbounded
+func nullableDate(date string) *string {
+	if date == "" {
+		return nil
+	}
+	return &date
+}
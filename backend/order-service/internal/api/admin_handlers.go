@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"time"
 
@@ -35,6 +36,7 @@ func (h *Handler) GetAdminOrders(c *gin.Context) {
 	if req.SortOrder == "" {
 		req.SortOrder = "desc"
 	}
+	req.StoreIDs = PartnerStoreIDs(c)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
@@ -79,6 +81,9 @@ func (h *Handler) GetAdminOrder(c *gin.Context) {
 
 	// Get order details
 	order, err := h.getAdminOrderByID(ctx, orderID)
+	if err == nil && !inStoreScope(PartnerStoreIDs(c), order.Order.StoreID) {
+		err = errors.New("order not found")
+	}
 	if err != nil {
 		c.JSON(http.StatusNotFound, models.ErrorResponse{
 			Error:   "Order not found",
@@ -222,16 +227,29 @@ func (h *Handler) BulkUpdateOrders(c *gin.Context) {
 
 // GetOrderStatistics retrieves order statistics for admin dashboard
 func (h *Handler) GetOrderStatistics(c *gin.Context) {
-	// Get optional date range parameters
-	dateFrom := c.Query("date_from")
-	dateTo := c.Query("date_to")
+	var req models.OrderStatisticsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid query parameters",
+			Message: err.Error(),
+		})
+		return
+	}
+	req.StoreIDs = PartnerStoreIDs(c)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
 
 	// Get statistics
-	stats, err := h.getOrderStatistics(ctx, dateFrom, dateTo)
+	stats, err := h.getOrderStatistics(ctx, &req)
 	if err != nil {
+		if errors.Is(err, errInvalidStatisticsRequest) {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "Invalid query parameters",
+				Message: err.Error(),
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Error:   "Failed to get order statistics",
 			Message: err.Error(),
@@ -270,6 +288,7 @@ func (h *Handler) GetAdminCarts(c *gin.Context) {
 	if req.SortOrder == "" {
 		req.SortOrder = "desc"
 	}
+	req.StoreIDs = PartnerStoreIDs(c)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
@@ -314,6 +333,9 @@ func (h *Handler) GetAdminCart(c *gin.Context) {
 
 	// Get cart details
 	cart, err := h.getAdminCartByID(ctx, cartID)
+	if err == nil && !inStoreScope(PartnerStoreIDs(c), cart.Cart.StoreID) {
+		err = errors.New("cart not found")
+	}
 	if err != nil {
 		c.JSON(http.StatusNotFound, models.ErrorResponse{
 			Error:   "Cart not found",
@@ -392,6 +414,59 @@ func (h *Handler) DeleteAdminCart(c *gin.Context) {
 	})
 }
 
+// GetAbandonedCarts retrieves tracked cart sessions by abandonment status for admin
+func (h *Handler) GetAbandonedCarts(c *gin.Context) {
+	var req models.AbandonedCartListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid query parameters",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	// Set defaults
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.Limit == 0 {
+		req.Limit = 20
+	}
+	if req.Status == "" {
+		req.Status = string(models.CartActivityAbandoned)
+	}
+
+	if !models.CartActivityStatus(req.Status).IsValid() {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid status",
+			Message: "Status must be one of: active, abandoned, expired, emptied, converted",
+		})
+		return
+	}
+	req.StoreIDs = PartnerStoreIDs(c)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	carts, total, totalValue, err := h.getAbandonedCarts(ctx, &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to get abandoned carts",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.AbandonedCartListResponse{
+		Carts:      carts,
+		Total:      total,
+		TotalValue: totalValue,
+		Page:       req.Page,
+		Limit:      req.Limit,
+		TotalPages: (total + req.Limit - 1) / req.Limit,
+	})
+}
+
 // GetCartStatistics retrieves cart statistics for admin dashboard
 func (h *Handler) GetCartStatistics(c *gin.Context) {
 	// Get optional date range parameters
@@ -402,7 +477,7 @@ func (h *Handler) GetCartStatistics(c *gin.Context) {
 	defer cancel()
 
 	// Get statistics
-	stats, err := h.getCartStatistics(ctx, dateFrom, dateTo)
+	stats, err := h.getCartStatistics(ctx, dateFrom, dateTo, PartnerStoreIDs(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Error:   "Failed to get cart statistics",
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/expomadeinworld/madeinworld/order-service/internal/models"
+	"github.com/expomadeinworld/madeinworld/order-service/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// GetRollupStatus reports how fresh the analytics rollups are and which range they cover
+func (h *Handler) GetRollupStatus(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var status models.RollupStatusResponse
+	err := h.db.Pool.QueryRow(ctx, `
+		SELECT
+			(SELECT last_run_at FROM analytics_rollup_state WHERE rollup_name = $1),
+			MIN(bucket_start), MAX(bucket_start),
+			COUNT(*) FILTER (WHERE granularity = 'hour'),
+			COUNT(*) FILTER (WHERE granularity = 'day')
+		FROM analytics_order_rollups
+	`, services.OrderRollupName).Scan(&status.LastRunAt, &status.OldestBucket, &status.NewestBucket,
+		&status.HourlyRows, &status.DailyRows)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to get rollup status",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// BackfillRollups recomputes the analytics rollups for a UTC date range in the background
+func (h *Handler) BackfillRollups(c *gin.Context) {
+	var req models.RollupBackfillRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request data",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	from, errFrom := time.Parse("2006-01-02", req.DateFrom)
+	to, errTo := time.Parse("2006-01-02", req.DateTo)
+	if errFrom != nil || errTo != nil || from.After(to) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid date range",
+			Message: "date_from and date_to must be YYYY-MM-DD with date_from not after date_to",
+		})
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+		defer cancel()
+
+		days, err := services.NewRollupService(h.db, 0).Backfill(ctx, from, to)
+		if err != nil {
+			log.Printf("Analytics rollup backfill %s..%s stopped after %d days: %v", req.DateFrom, req.DateTo, days, err)
+			return
+		}
+		log.Printf("Analytics rollup backfill %s..%s completed (%d days)", req.DateFrom, req.DateTo, days)
+	}()
+
+	c.JSON(http.StatusAccepted, models.SuccessResponse{
+		Message: "Rollup backfill started",
+		Data: gin.H{
+			"date_from": req.DateFrom,
+			"date_to":   req.DateTo,
+		},
+	})
+}
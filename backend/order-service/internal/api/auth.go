@@ -1,13 +1,17 @@
 package api
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/expomadeinworld/madeinworld/order-service/internal/models"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/jackc/pgx/v5"
 )
 
 // AuthMiddleware validates JWT tokens
@@ -79,20 +83,28 @@ func GetUserID(c *gin.Context) (string, bool) {
 	return userIDStr, ok
 }
 
-// ValidateMiniAppType validates and returns the mini-app type from URL parameter
-func ValidateMiniAppType(c *gin.Context) (models.MiniAppType, bool) {
-	miniAppTypeStr := c.Param("mini_app_type")
-	miniAppType := models.MiniAppType(miniAppTypeStr)
+// ValidateMiniAppType looks up the mini-app named by the URL parameter in the registry
+func (h *Handler) ValidateMiniAppType(c *gin.Context) (*models.MiniApp, bool) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
 
-	if !miniAppType.IsValid() {
+	miniApp, err := h.getMiniApp(ctx, models.MiniAppType(c.Param("mini_app_type")))
+	if errors.Is(err, errMiniAppNotFound) {
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{
 			Error:   "Invalid mini-app type",
-			Message: "Mini-app type must be one of: RetailStore, UnmannedStore, ExhibitionSales, GroupBuying",
+			Message: "Mini-app type '" + c.Param("mini_app_type") + "' is not registered",
 		})
-		return "", false
+		return nil, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to load mini-app",
+			Message: err.Error(),
+		})
+		return nil, false
 	}
 
-	return miniAppType, true
+	return miniApp, true
 }
 
 // AdminMiddleware ensures the user has admin privileges
@@ -112,3 +124,222 @@ func AdminMiddleware() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// AccountStatusMiddleware rejects tokens of users whose account is no longer active
+// (suspended, deactivated or pending verification). Tokens that do not belong to a users
+// row, such as admin panel tokens, pass.
+func (h *Handler) AccountStatusMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := GetUserID(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+				Error:   "Invalid user",
+				Message: "Could not identify user from token",
+			})
+			c.Abort()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		var status string
+		err := h.db.Pool.QueryRow(ctx,
+			"SELECT user_account_status(status, suspended_until) FROM users WHERE id::text = $1",
+			userID).Scan(&status)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Error:   "Failed to check account status",
+				Message: err.Error(),
+			})
+			c.Abort()
+			return
+		}
+		if err == nil && status != "active" {
+			c.JSON(http.StatusForbidden, models.ErrorResponse{
+				Error:   "Account " + status,
+				Message: "This account is not active",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// ManufacturerMiddleware resolves the manufacturer linked to the authenticated user.
+// Only users with the Manufacturer role that are linked to a manufacturers row pass.
+func (h *Handler) ManufacturerMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := GetUserID(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+				Error:   "Invalid user",
+				Message: "Could not identify user from token",
+			})
+			c.Abort()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		var manufacturerID int
+		err := h.db.Pool.QueryRow(ctx, `
+			SELECT m.manufacturer_id
+			FROM manufacturers m
+			JOIN users u ON u.id = m.user_id
+			WHERE m.user_id = $1 AND u.role = 'Manufacturer'
+		`, userID).Scan(&manufacturerID)
+		if err != nil {
+			c.JSON(http.StatusForbidden, models.ErrorResponse{
+				Error:   "Manufacturer access required",
+				Message: "This endpoint requires a Manufacturer account linked to a manufacturer",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Set("manufacturer_id", manufacturerID)
+		c.Next()
+	}
+}
+
+// RoleMiddleware allows only authenticated users whose account holds one of the given roles
+func (h *Handler) RoleMiddleware(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := GetUserID(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+				Error:   "Invalid user",
+				Message: "Could not identify user from token",
+			})
+			c.Abort()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		var role string
+		err := h.db.Pool.QueryRow(ctx, "SELECT role FROM users WHERE id = $1", userID).Scan(&role)
+		if err == nil {
+			for _, allowed := range roles {
+				if role == allowed {
+					c.Set("user_role", role)
+					c.Next()
+					return
+				}
+			}
+		}
+
+		c.JSON(http.StatusForbidden, models.ErrorResponse{
+			Error:   "Access denied",
+			Message: "This endpoint requires one of the roles: " + strings.Join(roles, ", "),
+		})
+		c.Abort()
+	}
+}
+
+// PartnerScopeMiddleware limits Partner-role users to the stores of their partner assignment:
+// the stores of their region and those assigned to them individually. Other users pass
+// unscoped. Handlers read the scope with PartnerStoreIDs.
+func (h *Handler) PartnerScopeMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, _ := GetUserID(c)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		var role string
+		err := h.db.Pool.QueryRow(ctx, "SELECT role FROM users WHERE id::text = $1", userID).Scan(&role)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Error:   "Failed to check user role",
+				Message: err.Error(),
+			})
+			c.Abort()
+			return
+		}
+		if err != nil || role != "Partner" {
+			c.Next()
+			return
+		}
+
+		var storeIDs []int
+		err = h.db.Pool.QueryRow(ctx, `
+			SELECT COALESCE(array_agg(DISTINCT sc.store_id) FILTER (WHERE sc.store_id IS NOT NULL), '{}')
+			FROM partners p
+			LEFT JOIN partner_store_scope sc ON sc.partner_id = p.partner_id
+			WHERE p.user_id::text = $1
+			GROUP BY p.partner_id
+		`, userID).Scan(&storeIDs)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				c.JSON(http.StatusForbidden, models.ErrorResponse{
+					Error:   "Partner access required",
+					Message: "This account has the Partner role but no partner assignment",
+				})
+			} else {
+				c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+					Error:   "Failed to load partner stores",
+					Message: err.Error(),
+				})
+			}
+			c.Abort()
+			return
+		}
+
+		// A partner without stores sees nothing rather than everything
+		if storeIDs == nil {
+			storeIDs = []int{}
+		}
+		c.Set("partner_store_ids", storeIDs)
+		c.Next()
+	}
+}
+
+// PartnerStoreIDs returns the stores a Partner-role caller is limited to, or nil when the
+// caller is not limited
+func PartnerStoreIDs(c *gin.Context) []int {
+	storeIDs, exists := c.Get("partner_store_ids")
+	if !exists {
+		return nil
+	}
+	ids, _ := storeIDs.([]int)
+	return ids
+}
+
+// AdminOnlyMiddleware rejects callers limited to a partner's stores. It follows
+// PartnerScopeMiddleware on endpoints that change data or span all stores.
+func AdminOnlyMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if PartnerStoreIDs(c) != nil {
+			c.JSON(http.StatusForbidden, models.ErrorResponse{
+				Error:   "Admin access required",
+				Message: "Partners can only view the orders and carts of their stores",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// inStoreScope reports whether a record of storeID is visible to a caller limited to storeIDs
+func inStoreScope(storeIDs []int, storeID *int) bool {
+	if storeIDs == nil {
+		return true
+	}
+	if storeID == nil {
+		return false
+	}
+	for _, id := range storeIDs {
+		if id == *storeID {
+			return true
+		}
+	}
+	return false
+}
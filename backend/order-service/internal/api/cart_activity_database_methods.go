@@ -0,0 +1,163 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/expomadeinworld/madeinworld/order-service/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// cartStoreKey returns the store a cart line is kept under, 0 when the mini-app has no stores.
+// Callers pass the store already scoped with MiniApp.StoreScope.
+func cartStoreKey(storeID *int) int {
+	if storeID != nil {
+		return *storeID
+	}
+	return 0
+}
+
+// recordCartActivity marks a cart as active after an item was added. A cart that was
+// converted, emptied or expired starts a new session with fresh recovery tracking.
+func (h *Handler) recordCartActivity(ctx context.Context, userID string, miniAppType models.MiniAppType, storeID *int) error {
+	_, err := h.db.Pool.Exec(ctx, `
+		INSERT INTO cart_activity (user_id, mini_app_type, store_id)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, mini_app_type, store_id) DO UPDATE SET
+			started_at = CASE WHEN cart_activity.status IN ('converted', 'emptied', 'expired')
+				THEN CURRENT_TIMESTAMP ELSE cart_activity.started_at END,
+			recovery_token = CASE WHEN cart_activity.status IN ('converted', 'emptied', 'expired')
+				THEN NULL ELSE cart_activity.recovery_token END,
+			recovery_email_sent_at = CASE WHEN cart_activity.status IN ('converted', 'emptied', 'expired')
+				THEN NULL ELSE cart_activity.recovery_email_sent_at END,
+			converted_at = NULL,
+			converted_order_id = NULL,
+			recovered = false,
+			abandoned_at = NULL,
+			status = 'active',
+			last_activity_at = CURRENT_TIMESTAMP,
+			updated_at = CURRENT_TIMESTAMP
+	`, userID, string(miniAppType), cartStoreKey(storeID))
+	if err != nil {
+		return fmt.Errorf("failed to record cart activity: %w", err)
+	}
+	return nil
+}
+
+// touchCartActivity marks the user's open carts for a mini-app as active after a change
+func (h *Handler) touchCartActivity(ctx context.Context, userID string, miniAppType models.MiniAppType) error {
+	_, err := h.db.Pool.Exec(ctx, `
+		UPDATE cart_activity
+		SET status = 'active', abandoned_at = NULL, last_activity_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE user_id = $1 AND mini_app_type = $2 AND status IN ('active', 'abandoned')
+	`, userID, string(miniAppType))
+	if err != nil {
+		return fmt.Errorf("failed to record cart activity: %w", err)
+	}
+	return nil
+}
+
+// markCartConverted records that the cart an order was placed from converted. It counts as
+// recovered when a recovery email had been sent for it.
+func markCartConverted(ctx context.Context, tx pgx.Tx, userID string, miniAppType models.MiniAppType, storeID *int, orderID string) error {
+	_, err := tx.Exec(ctx, `
+		UPDATE cart_activity
+		SET status = 'converted', converted_at = CURRENT_TIMESTAMP, converted_order_id = $4,
+		    recovered = recovery_email_sent_at IS NOT NULL, updated_at = CURRENT_TIMESTAMP
+		WHERE user_id = $1 AND mini_app_type = $2 AND store_id = $3 AND status IN ('active', 'abandoned', 'expired')
+	`, userID, string(miniAppType), cartStoreKey(storeID), orderID)
+	if err != nil {
+		return fmt.Errorf("failed to mark cart as converted: %w", err)
+	}
+	return nil
+}
+
+// getAbandonedCarts retrieves tracked cart sessions for admin together with the total
+// count and value of all matching carts
+func (h *Handler) getAbandonedCarts(ctx context.Context, req *models.AbandonedCartListRequest) ([]models.AbandonedCart, int, float64, error) {
+	whereConditions := []string{"a.status = $1"}
+	args := []interface{}{req.Status}
+	argIndex := 2
+
+	if req.MiniAppType != "" {
+		whereConditions = append(whereConditions, fmt.Sprintf("a.mini_app_type = $%d", argIndex))
+		args = append(args, req.MiniAppType)
+		argIndex++
+	}
+
+	if req.StoreID != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("a.store_id = $%d", argIndex))
+		args = append(args, *req.StoreID)
+		argIndex++
+	}
+
+	if req.StoreIDs != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("a.store_id = ANY($%d)", argIndex))
+		args = append(args, req.StoreIDs)
+		argIndex++
+	}
+
+	if req.Recovered != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("a.recovered = $%d", argIndex))
+		args = append(args, *req.Recovered)
+		argIndex++
+	}
+
+	// Open carts are valued from their current lines, converted carts from the order
+	fromClause := `
+		FROM cart_activity a
+		LEFT JOIN users u ON u.id = a.user_id
+		LEFT JOIN stores s ON s.store_id = a.store_id
+		LEFT JOIN orders o ON o.id = a.converted_order_id
+		LEFT JOIN LATERAL (
+			SELECT COUNT(c.id) AS item_count, COALESCE(SUM(p.main_price * c.quantity), 0) AS cart_value
+			FROM carts c
+			LEFT JOIN products p ON p.product_uuid = c.product_id
+			WHERE c.user_id = a.user_id AND c.mini_app_type = a.mini_app_type AND COALESCE(c.store_id, 0) = a.store_id
+		) lines ON true
+		WHERE ` + strings.Join(whereConditions, " AND ")
+
+	var total int
+	var totalValue float64
+	err := h.db.Pool.QueryRow(ctx, `
+		SELECT COUNT(*), COALESCE(SUM(COALESCE(o.total_amount, lines.cart_value)), 0)
+	`+fromClause, args...).Scan(&total, &totalValue)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("failed to count abandoned carts: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT a.id, a.user_id::text, COALESCE(u.email, ''), COALESCE(TRIM(CONCAT(u.first_name, ' ', u.last_name)), ''),
+		       a.mini_app_type, NULLIF(a.store_id, 0), COALESCE(s.name, ''), a.status,
+		       lines.item_count, COALESCE(o.total_amount, lines.cart_value),
+		       a.started_at, a.last_activity_at, a.abandoned_at, a.recovery_email_sent_at,
+		       a.converted_at, a.converted_order_id::text, a.recovered
+		%s
+		ORDER BY a.last_activity_at DESC, a.id DESC
+		LIMIT $%d OFFSET $%d
+	`, fromClause, argIndex, argIndex+1)
+	args = append(args, req.Limit, (req.Page-1)*req.Limit)
+
+	rows, err := h.db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("failed to query abandoned carts: %w", err)
+	}
+	defer rows.Close()
+
+	carts := []models.AbandonedCart{}
+	for rows.Next() {
+		var cart models.AbandonedCart
+		err := rows.Scan(&cart.ID, &cart.UserID, &cart.UserEmail, &cart.UserName,
+			&cart.MiniAppType, &cart.StoreID, &cart.StoreName, &cart.Status,
+			&cart.ItemCount, &cart.CartValue,
+			&cart.StartedAt, &cart.LastActivityAt, &cart.AbandonedAt, &cart.RecoveryEmailSentAt,
+			&cart.ConvertedAt, &cart.ConvertedOrderID, &cart.Recovered)
+		if err != nil {
+			return nil, 0, 0, fmt.Errorf("failed to scan abandoned cart: %w", err)
+		}
+		carts = append(carts, cart)
+	}
+
+	return carts, total, totalValue, rows.Err()
+}
@@ -2,11 +2,42 @@ package api
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/expomadeinworld/madeinworld/order-service/internal/models"
+	"github.com/jackc/pgx/v5"
 )
 
+// errStoreUnavailable is returned when an order targets a missing or inactive store
+var errStoreUnavailable = errors.New("store unavailable")
+
+// orderColumns selects an order in the column order scanned into models.Order
+const orderColumns = `id, user_id, mini_app_type, store_id, fulfillment_method, delivery_address,
+		       total_amount, status, loyalty_points_redeemed, loyalty_discount, created_at, updated_at`
+
+// storeProductColumns selects a product's price, stock, active flag and safety stock as sold at
+// the store joined by storeProductJoins: the store's override price, the store's inventory
+// (quantity - reserved) and the store's active flag win over the product's own values
+const storeProductColumns = `COALESCE(spo.price, p.main_price), COALESCE(inv.available, p.stock_left),
+				p.minimum_order_quantity, p.is_active AND COALESCE(spo.is_active, true), ss.safety_stock`
+
+// storeProductJoins joins the override and inventory of the store given by storeExpr to products p.
+// A NULL store matches nothing, leaving the product's own values; the safety stock then
+// comes from the product's own store.
+func storeProductJoins(storeExpr string) string {
+	return fmt.Sprintf(`LEFT JOIN store_product_overrides spo ON spo.product_id = p.product_id AND spo.store_id = %[1]s
+			LEFT JOIN LATERAL (
+				SELECT SUM(i.quantity - i.reserved_quantity)::int AS available
+				FROM inventory i
+				WHERE i.product_id = p.product_id AND i.store_id = %[1]s
+			) inv ON true
+			CROSS JOIN LATERAL (
+				SELECT stock_safety_stock(p.product_id, COALESCE(%[1]s, p.store_id)) AS safety_stock
+			) ss`, storeExpr)
+}
+
 // getCartItems gets all cart items for a user and mini-app type
 func (h *Handler) getCartItems(ctx context.Context, userID string, miniAppType models.MiniAppType) ([]models.Cart, error) {
 	return h.getCartItemsWithStore(ctx, userID, miniAppType, nil)
@@ -17,16 +48,16 @@ func (h *Handler) getCartItemsWithStore(ctx context.Context, userID string, mini
 	var query string
 	var args []interface{}
 
-	if storeID != nil && miniAppType.RequiresStore() {
+	if storeID != nil {
 		// For location-based mini-apps with store filter
 		// Include items with matching store_id OR NULL store_id (for backward compatibility)
 		query = `
 			SELECT
 				c.id, c.user_id, c.product_id, c.quantity, c.mini_app_type, c.created_at, c.updated_at,
-				p.product_uuid, p.sku, p.title, p.main_price, p.stock_left,
-				p.minimum_order_quantity, p.is_active
+				p.product_uuid, p.sku, p.title, ` + storeProductColumns + `
 			FROM carts c
 			JOIN products p ON c.product_id = p.product_uuid
+			` + storeProductJoins("COALESCE(c.store_id, $3)") + `
 			WHERE c.user_id = $1 AND c.mini_app_type = $2 AND (c.store_id = $3 OR c.store_id IS NULL)
 			ORDER BY c.created_at DESC
 		`
@@ -36,10 +67,10 @@ func (h *Handler) getCartItemsWithStore(ctx context.Context, userID string, mini
 		query = `
 			SELECT
 				c.id, c.user_id, c.product_id, c.quantity, c.mini_app_type, c.created_at, c.updated_at,
-				p.product_uuid, p.sku, p.title, p.main_price, p.stock_left,
-				p.minimum_order_quantity, p.is_active
+				p.product_uuid, p.sku, p.title, ` + storeProductColumns + `
 			FROM carts c
 			JOIN products p ON c.product_id = p.product_uuid
+			` + storeProductJoins("c.store_id") + `
 			WHERE c.user_id = $1 AND c.mini_app_type = $2
 			ORDER BY c.created_at DESC
 		`
@@ -72,6 +103,7 @@ func (h *Handler) getCartItemsWithStore(ctx context.Context, userID string, mini
 			&product.StockLeft,
 			&product.MinimumOrderQuantity,
 			&product.IsActive,
+			&product.SafetyStock,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan cart item: %w", err)
@@ -88,15 +120,26 @@ func (h *Handler) getCartItemsWithStore(ctx context.Context, userID string, mini
 	return items, nil
 }
 
-// updateProductStock reduces product stock levels after order creation
-func (h *Handler) updateProductStock(ctx context.Context, orderItems []models.Cart, miniAppType models.MiniAppType) error {
-	// Only update stock for UnmannedStore mini-app
-	if miniAppType != models.MiniAppTypeUnmannedStore {
+// updateProductStock reduces stock levels after order creation, from the store's inventory
+// when the store keeps one for the product and from products.stock_left otherwise
+func (h *Handler) updateProductStock(ctx context.Context, orderItems []models.Cart, miniApp *models.MiniApp, storeID *int, orderID, userID string) error {
+	// Only update stock for mini-apps that enforce stock
+	if !miniApp.EnforcesStock {
 		return nil
 	}
 
 	// Update stock for each product in the order
 	for _, item := range orderItems {
+		if storeID != nil {
+			updated, err := h.updateStoreInventory(ctx, item, *storeID, orderID, userID)
+			if err != nil {
+				return err
+			}
+			if updated {
+				continue
+			}
+		}
+
 		updateQuery := `
 			UPDATE products
 			SET stock_left = stock_left - $1, updated_at = CURRENT_TIMESTAMP
@@ -119,16 +162,84 @@ func (h *Handler) updateProductStock(ctx context.Context, orderItems []models.Ca
 	return nil
 }
 
+// updateStoreInventory takes an order line out of the store's inventory and records the sale
+// in the stock movement ledger. It reports false when the store keeps no inventory for the product.
+func (h *Handler) updateStoreInventory(ctx context.Context, item models.Cart, storeID int, orderID, userID string) (bool, error) {
+	tx, err := h.db.Pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var productID, before, reserved int
+	err = tx.QueryRow(ctx, `
+		SELECT i.product_id, i.quantity, i.reserved_quantity
+		FROM inventory i
+		JOIN products p ON p.product_id = i.product_id
+		WHERE p.product_uuid = $1 AND i.store_id = $2
+		FOR UPDATE OF i
+	`, item.ProductID, storeID).Scan(&productID, &before, &reserved)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to lock inventory for product %s: %w", item.ProductID, err)
+	}
+	if before-reserved < item.Quantity {
+		return false, fmt.Errorf("insufficient inventory for product %s at store %d (concurrent order may have depleted stock)", item.ProductID, storeID)
+	}
+	after := before - item.Quantity
+
+	if _, err = tx.Exec(ctx, `
+		UPDATE inventory
+		SET quantity = $3, last_updated = CURRENT_TIMESTAMP
+		WHERE product_id = $1 AND store_id = $2
+	`, productID, storeID, after); err != nil {
+		return false, fmt.Errorf("failed to update inventory for product %s: %w", item.ProductID, err)
+	}
+
+	// products.stock_left mirrors the inventory of the product's own store
+	if _, err = tx.Exec(ctx, `
+		UPDATE products
+		SET stock_left = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE product_id = $1 AND store_id = $2
+	`, productID, storeID, after); err != nil {
+		return false, fmt.Errorf("failed to update stock for product %s: %w", item.ProductID, err)
+	}
+
+	if _, err = tx.Exec(ctx, `
+		INSERT INTO stock_movements
+			(product_id, store_id, movement_type, quantity_change, quantity_before, quantity_after,
+			 reason, actor_id, order_id)
+		VALUES ($1, $2, 'sale', $3, $4, $5, 'Order placed', $6, $7)
+	`, productID, storeID, -item.Quantity, before, after, userID, orderID); err != nil {
+		return false, fmt.Errorf("failed to record stock movement for product %s: %w", item.ProductID, err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("failed to commit inventory update: %w", err)
+	}
+
+	return true, nil
+}
+
 // getProduct retrieves a product by ID (using UUID)
 func (h *Handler) getProduct(ctx context.Context, productID string) (*models.Product, error) {
+	return h.getProductAtStore(ctx, productID, nil)
+}
+
+// getProductAtStore retrieves a product by ID (using UUID) with the price, stock and active
+// flag it is sold with at the store; a nil store gives the product's own values
+func (h *Handler) getProductAtStore(ctx context.Context, productID string, storeID *int) (*models.Product, error) {
 	var product models.Product
 	query := `
-		SELECT product_uuid, sku, title, main_price, stock_left, minimum_order_quantity, is_active
-		FROM products
-		WHERE product_uuid = $1
+		SELECT p.product_uuid, p.sku, p.title, ` + storeProductColumns + `
+		FROM products p
+		` + storeProductJoins("$2::int") + `
+		WHERE p.product_uuid = $1
 	`
 
-	err := h.db.Pool.QueryRow(ctx, query, productID).Scan(
+	err := h.db.Pool.QueryRow(ctx, query, productID, storeID).Scan(
 		&product.ID,
 		&product.SKU,
 		&product.Title,
@@ -136,6 +247,7 @@ func (h *Handler) getProduct(ctx context.Context, productID string) (*models.Pro
 		&product.StockLeft,
 		&product.MinimumOrderQuantity,
 		&product.IsActive,
+		&product.SafetyStock,
 	)
 
 	if err != nil {
@@ -150,7 +262,7 @@ func (h *Handler) addItemToCart(ctx context.Context, userID string, miniAppType
 	var checkQuery, updateQuery, insertQuery string
 	var checkArgs, updateArgs, insertArgs []interface{}
 
-	if storeID != nil && miniAppType.RequiresStore() {
+	if storeID != nil {
 		// For location-based mini-apps, include store_id in all operations
 		checkQuery = `
 			SELECT quantity FROM carts
@@ -210,6 +322,11 @@ func (h *Handler) addItemToCart(ctx context.Context, userID string, miniAppType
 		}
 	}
 
+	// Activity tracking is secondary to the cart change itself
+	if err := h.recordCartActivity(ctx, userID, miniAppType, storeID); err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	}
+
 	return nil
 }
 
@@ -230,9 +347,29 @@ func (h *Handler) updateCartItemQuantity(ctx context.Context, userID string, min
 		return fmt.Errorf("cart item not found")
 	}
 
+	// Activity tracking is secondary to the cart change itself
+	if err := h.touchCartActivity(ctx, userID, miniAppType); err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	}
+
 	return nil
 }
 
+// getCartItemStoreID returns the store a cart item was added from, nil when it has none
+func (h *Handler) getCartItemStoreID(ctx context.Context, userID string, miniAppType models.MiniAppType, productID string) (*int, error) {
+	var storeID *int
+	err := h.db.Pool.QueryRow(ctx, `
+		SELECT store_id FROM carts
+		WHERE user_id = $1 AND mini_app_type = $2 AND product_id = $3
+		ORDER BY updated_at DESC
+		LIMIT 1
+	`, userID, string(miniAppType), productID).Scan(&storeID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("failed to get cart item store: %w", err)
+	}
+	return storeID, nil
+}
+
 // removeItemFromCart removes an item from the cart
 func (h *Handler) removeItemFromCart(ctx context.Context, userID string, miniAppType models.MiniAppType, productID string) error {
 	deleteQuery := `
@@ -249,11 +386,16 @@ func (h *Handler) removeItemFromCart(ctx context.Context, userID string, miniApp
 		return fmt.Errorf("cart item not found")
 	}
 
+	// Activity tracking is secondary to the cart change itself
+	if err := h.touchCartActivity(ctx, userID, miniAppType); err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	}
+
 	return nil
 }
 
 // validateStockForCartAddition checks if adding quantity to cart would exceed available stock
-func (h *Handler) validateStockForCartAddition(ctx context.Context, userID string, miniAppType models.MiniAppType, productID string, additionalQuantity int) error {
+func (h *Handler) validateStockForCartAddition(ctx context.Context, userID string, miniAppType models.MiniAppType, productID string, additionalQuantity int, storeID *int) error {
 	// Get current quantity in cart for this product
 	var currentQuantity int
 	checkQuery := `
@@ -267,8 +409,8 @@ func (h *Handler) validateStockForCartAddition(ctx context.Context, userID strin
 		currentQuantity = 0
 	}
 
-	// Get product details
-	product, err := h.getProduct(ctx, productID)
+	// Get product details as sold at the store
+	product, err := h.getProductAtStore(ctx, productID, storeID)
 	if err != nil {
 		return fmt.Errorf("failed to get product: %w", err)
 	}
@@ -281,7 +423,7 @@ func (h *Handler) validateStockForCartAddition(ctx context.Context, userID strin
 	// Calculate total quantity after addition
 	totalQuantity := currentQuantity + additionalQuantity
 
-	// Check against display stock (actual stock - 5 buffer)
+	// Check against display stock (actual stock minus the safety stock)
 	if totalQuantity > product.DisplayStock() {
 		return fmt.Errorf("insufficient stock: requested %d, available %d (including current cart: %d)",
 			totalQuantity, product.DisplayStock(), currentQuantity)
@@ -300,7 +442,7 @@ func (h *Handler) clearCartWithStore(ctx context.Context, userID string, miniApp
 	var deleteQuery string
 	var args []interface{}
 
-	if storeID != nil && miniAppType.RequiresStore() {
+	if storeID != nil {
 		// For location-based mini-apps with store filter
 		// Clear items with matching store_id OR NULL store_id (for backward compatibility)
 		deleteQuery = `DELETE FROM carts WHERE user_id = $1 AND mini_app_type = $2 AND (store_id = $3 OR store_id IS NULL)`
@@ -318,11 +460,12 @@ func (h *Handler) clearCartWithStore(ctx context.Context, userID string, miniApp
 	return nil
 }
 
-// validateCartStockBeforeOrder validates all cart items have sufficient stock before order creation
-func (h *Handler) validateCartStockBeforeOrder(ctx context.Context, cartItems []models.Cart) error {
-	for _, item := range cartItems {
+// validateCartStockBeforeOrder validates all cart items have sufficient stock at the store before
+// order creation and refreshes their products with the store's current price
+func (h *Handler) validateCartStockBeforeOrder(ctx context.Context, cartItems []models.Cart, storeID *int) error {
+	for i, item := range cartItems {
 		// Refresh product data to get latest stock
-		product, err := h.getProduct(ctx, item.ProductID)
+		product, err := h.getProductAtStore(ctx, item.ProductID, storeID)
 		if err != nil {
 			return fmt.Errorf("failed to get product %s: %w", item.ProductID, err)
 		}
@@ -339,14 +482,39 @@ func (h *Handler) validateCartStockBeforeOrder(ctx context.Context, cartItems []
 		}
 
 		// Update the product reference in cart item for accurate pricing
-		item.Product = product
+		cartItems[i].Product = product
 	}
 
 	return nil
 }
 
-// createOrder creates a new order with items
-func (h *Handler) createOrder(ctx context.Context, userID string, miniAppType models.MiniAppType, storeID *int, totalAmount float64, cartItems []models.Cart) (*models.Order, error) {
+// getStoreOpenState reports whether an active store is open according to its opening hours
+// and, when it is closed, when it opens next
+func (h *Handler) getStoreOpenState(ctx context.Context, storeID int) (bool, *time.Time, error) {
+	var isActive, isOpen bool
+	var nextOpenAt *time.Time
+	err := h.db.Pool.QueryRow(ctx, `
+		SELECT is_active, store_is_open(store_id, CURRENT_TIMESTAMP), store_next_open_at(store_id, CURRENT_TIMESTAMP)
+		FROM stores
+		WHERE store_id = $1
+	`, storeID).Scan(&isActive, &isOpen, &nextOpenAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return false, nil, fmt.Errorf("%w: store %d not found", errStoreUnavailable, storeID)
+		}
+		return false, nil, fmt.Errorf("failed to check store opening hours: %w", err)
+	}
+	if !isActive {
+		return false, nil, fmt.Errorf("%w: store %d is not active", errStoreUnavailable, storeID)
+	}
+
+	return isOpen, nextOpenAt, nil
+}
+
+// createOrder creates a new order with items. deliveryAddress is stored on delivery orders
+// and nil for pickups; an empty fulfillmentMethod stores neither. redeemPoints are taken from the user's loyalty balance and discount
+// is deducted from totalAmount; errInsufficientPoints is returned when the balance is short.
+func (h *Handler) createOrder(ctx context.Context, userID string, miniApp *models.MiniApp, storeID *int, fulfillmentMethod string, deliveryAddress *models.DeliveryAddress, totalAmount float64, redeemPoints int, discount float64, cartItems []models.Cart) (*models.Order, error) {
 	// Start transaction
 	tx, err := h.db.Pool.Begin(ctx)
 	if err != nil {
@@ -356,18 +524,29 @@ func (h *Handler) createOrder(ctx context.Context, userID string, miniAppType mo
 
 	// Create order
 	var order models.Order
+	var deliveryAddressID *string
+	if deliveryAddress != nil {
+		deliveryAddressID = &deliveryAddress.AddressID
+	}
 	orderQuery := `
-		INSERT INTO orders (user_id, mini_app_type, total_amount, status)
-		VALUES ($1, $2, $3, $4)
-		RETURNING id, user_id, mini_app_type, total_amount, status, created_at, updated_at
-	`
-
-	err = tx.QueryRow(ctx, orderQuery, userID, string(miniAppType), totalAmount, string(models.OrderStatusPending)).Scan(
+		INSERT INTO orders (user_id, mini_app_type, store_id, total_amount, status,
+		                    fulfillment_method, delivery_address, delivery_address_id,
+		                    loyalty_points_redeemed, loyalty_discount)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7, $8, $9, $10)
+		RETURNING ` + orderColumns
+
+	err = tx.QueryRow(ctx, orderQuery, userID, string(miniApp.Code), storeID, totalAmount-discount, string(models.OrderStatusPending),
+		fulfillmentMethod, deliveryAddress, deliveryAddressID, redeemPoints, discount).Scan(
 		&order.ID,
 		&order.UserID,
 		&order.MiniAppType,
+		&order.StoreID,
+		&order.FulfillmentMethod,
+		&order.DeliveryAddress,
 		&order.TotalAmount,
 		&order.Status,
+		&order.PointsRedeemed,
+		&order.LoyaltyDiscount,
 		&order.CreatedAt,
 		&order.UpdatedAt,
 	)
@@ -375,6 +554,18 @@ func (h *Handler) createOrder(ctx context.Context, userID string, miniAppType mo
 		return nil, fmt.Errorf("failed to create order: %w", err)
 	}
 
+	// Spend the redeemed points on the order
+	if redeemPoints > 0 {
+		_, err = debitPoints(ctx, tx, userID, redeemPoints, loyaltyEntry{
+			entryType: models.LoyaltyEntryRedeem,
+			orderID:   &order.ID,
+			createdBy: userID,
+		}, false, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Create order items
 	var orderItems []models.OrderItem
 	for _, cartItem := range cartItems {
@@ -404,13 +595,18 @@ func (h *Handler) createOrder(ctx context.Context, userID string, miniAppType mo
 		orderItems = append(orderItems, orderItem)
 	}
 
+	// The cart the order was placed from has converted
+	if err = markCartConverted(ctx, tx, userID, miniApp.Code, storeID, order.ID); err != nil {
+		return nil, err
+	}
+
 	// Commit transaction
 	if err = tx.Commit(ctx); err != nil {
 		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
-	// Update product stock levels after successful order creation (only for UnmannedStore)
-	if err = h.updateProductStock(ctx, cartItems, miniAppType); err != nil {
+	// Update product stock levels after successful order creation (only for mini-apps that enforce stock)
+	if err = h.updateProductStock(ctx, cartItems, miniApp, storeID, order.ID, userID); err != nil {
 		// Log error but don't fail the order creation since the order was already committed
 		// In a production system, you might want to implement compensation logic here
 		fmt.Printf("Warning: Failed to update product stock after order creation: %v\n", err)
@@ -423,7 +619,7 @@ func (h *Handler) createOrder(ctx context.Context, userID string, miniAppType mo
 // getUserOrders retrieves all orders for a user and mini-app type
 func (h *Handler) getUserOrders(ctx context.Context, userID string, miniAppType models.MiniAppType) ([]models.Order, error) {
 	query := `
-		SELECT id, user_id, mini_app_type, total_amount, status, created_at, updated_at
+		SELECT ` + orderColumns + `
 		FROM orders
 		WHERE user_id = $1 AND mini_app_type = $2
 		ORDER BY created_at DESC
@@ -442,8 +638,13 @@ func (h *Handler) getUserOrders(ctx context.Context, userID string, miniAppType
 			&order.ID,
 			&order.UserID,
 			&order.MiniAppType,
+			&order.StoreID,
+			&order.FulfillmentMethod,
+			&order.DeliveryAddress,
 			&order.TotalAmount,
 			&order.Status,
+			&order.PointsRedeemed,
+			&order.LoyaltyDiscount,
 			&order.CreatedAt,
 			&order.UpdatedAt,
 		)
@@ -472,7 +673,7 @@ func (h *Handler) getUserOrders(ctx context.Context, userID string, miniAppType
 func (h *Handler) getOrderByID(ctx context.Context, orderID string, userID string) (*models.Order, error) {
 	var order models.Order
 	query := `
-		SELECT id, user_id, mini_app_type, total_amount, status, created_at, updated_at
+		SELECT ` + orderColumns + `
 		FROM orders
 		WHERE id = $1 AND user_id = $2
 	`
@@ -481,8 +682,13 @@ func (h *Handler) getOrderByID(ctx context.Context, orderID string, userID strin
 		&order.ID,
 		&order.UserID,
 		&order.MiniAppType,
+		&order.StoreID,
+		&order.FulfillmentMethod,
+		&order.DeliveryAddress,
 		&order.TotalAmount,
 		&order.Status,
+		&order.PointsRedeemed,
+		&order.LoyaltyDiscount,
 		&order.CreatedAt,
 		&order.UpdatedAt,
 	)
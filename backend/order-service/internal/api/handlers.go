@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -48,10 +49,11 @@ func (h *Handler) Health(c *gin.Context) {
 // GetCart retrieves the user's cart for a specific mini-app
 func (h *Handler) GetCart(c *gin.Context) {
 	// Validate mini-app type
-	miniAppType, ok := ValidateMiniAppType(c)
+	miniApp, ok := h.ValidateMiniAppType(c)
 	if !ok {
 		return
 	}
+	miniAppType := miniApp.Code
 
 	// Get user ID from JWT
 	userID, ok := GetUserID(c)
@@ -87,10 +89,11 @@ func (h *Handler) GetCart(c *gin.Context) {
 // AddToCart adds a product to the user's cart
 func (h *Handler) AddToCart(c *gin.Context) {
 	// Validate mini-app type
-	miniAppType, ok := ValidateMiniAppType(c)
+	miniApp, ok := h.ValidateMiniAppType(c)
 	if !ok {
 		return
 	}
+	miniAppType := miniApp.Code
 
 	// Get user ID from JWT
 	userID, ok := GetUserID(c)
@@ -102,6 +105,15 @@ func (h *Handler) AddToCart(c *gin.Context) {
 		return
 	}
 
+	// Deactivated mini-apps keep their carts and orders readable but take no new ones
+	if !miniApp.IsActive {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Mini-app unavailable",
+			Message: "This mini-app is currently not available",
+		})
+		return
+	}
+
 	// Parse request body
 	var req models.AddToCartRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -113,7 +125,7 @@ func (h *Handler) AddToCart(c *gin.Context) {
 	}
 
 	// Validate store requirement for location-based mini-apps
-	if miniAppType.RequiresStore() && req.StoreID == nil {
+	if miniApp.RequiresStore && req.StoreID == nil {
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{
 			Error:   "Store ID required",
 			Message: "This mini-app requires a store selection",
@@ -124,33 +136,59 @@ func (h *Handler) AddToCart(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
+	// Location-based mini-apps sell at the store's price and stock
+	storeID := miniApp.StoreScope(req.StoreID)
+
+	if !h.checkCartAddition(ctx, c, miniApp, userID, req.ProductID, req.Quantity, storeID) {
+		return
+	}
+
+	// Add item to cart
+	err := h.addItemToCart(ctx, userID, miniAppType, req.ProductID, req.Quantity, storeID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to add item to cart",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: "Item added to cart successfully",
+	})
+}
+
+// checkCartAddition checks that quantity of a product can be added to the user's cart of the
+// mini-app at the store: the product is active there, in stock and the cart total meets its
+// minimum order quantity. It responds with the problem and returns false otherwise.
+func (h *Handler) checkCartAddition(ctx context.Context, c *gin.Context, miniApp *models.MiniApp, userID, productID string, quantity int, storeID *int) bool {
 	// Verify product exists and has stock
-	product, err := h.getProduct(ctx, req.ProductID)
+	product, err := h.getProductAtStore(ctx, productID, storeID)
 	if err != nil {
 		c.JSON(http.StatusNotFound, models.ErrorResponse{
 			Error:   "Product not found",
 			Message: err.Error(),
 		})
-		return
+		return false
 	}
 
-	// Check if product is active
+	// Check if product is active (at the store for location-based mini-apps)
 	if !product.IsActive {
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{
 			Error:   "Product unavailable",
 			Message: "This product is currently not available",
 		})
-		return
+		return false
 	}
 
-	// Check stock availability only for UnmannedStore mini-app
+	// Check stock availability only for mini-apps that enforce stock
 	// All other mini-apps have infinite stock
-	if miniAppType == models.MiniAppTypeUnmannedStore && !product.HasStock() {
+	if miniApp.EnforcesStock && !product.HasStock() {
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{
 			Error:   "Insufficient stock",
 			Message: "This product is currently out of stock",
 		})
-		return
+		return false
 	}
 
 	// Get existing quantity in cart to validate final total against MOQ
@@ -159,67 +197,56 @@ func (h *Handler) AddToCart(c *gin.Context) {
 		SELECT COALESCE(quantity, 0) FROM carts
 		WHERE user_id = $1 AND mini_app_type = $2 AND product_id = $3
 	`
-	err = h.db.Pool.QueryRow(ctx, checkQuery, userID, string(miniAppType), req.ProductID).Scan(&existingQuantity)
+	err = h.db.Pool.QueryRow(ctx, checkQuery, userID, string(miniApp.Code), productID).Scan(&existingQuantity)
 	if err != nil {
 		// If no existing item, current quantity is 0
 		existingQuantity = 0
 	}
 
 	// Calculate final quantity after addition
-	finalQuantity := existingQuantity + req.Quantity
+	finalQuantity := existingQuantity + quantity
 
 	// Check minimum order quantity against final total quantity
-	if finalQuantity < product.MinimumOrderQuantity {
+	if miniApp.EnforcesMOQ && finalQuantity < product.MinimumOrderQuantity {
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{
 			Error:   "Minimum order quantity not met",
 			Message: "Minimum order quantity for this product is " + strconv.Itoa(product.MinimumOrderQuantity),
 		})
-		return
+		return false
 	}
 
-	// Check if requested quantity exceeds available stock (only for UnmannedStore)
-	if miniAppType == models.MiniAppTypeUnmannedStore && req.Quantity > product.DisplayStock() {
+	// Check if requested quantity exceeds available stock (only for mini-apps that enforce stock)
+	if miniApp.EnforcesStock && quantity > product.DisplayStock() {
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{
 			Error:   "Insufficient stock",
 			Message: "Only " + strconv.Itoa(product.DisplayStock()) + " items available",
 		})
-		return
+		return false
 	}
 
-	// Validate stock considering existing cart contents (only for UnmannedStore)
-	if miniAppType == models.MiniAppTypeUnmannedStore {
-		err = h.validateStockForCartAddition(ctx, userID, miniAppType, req.ProductID, req.Quantity)
+	// Validate stock considering existing cart contents (only for mini-apps that enforce stock)
+	if miniApp.EnforcesStock {
+		err = h.validateStockForCartAddition(ctx, userID, miniApp.Code, productID, quantity, storeID)
 		if err != nil {
 			c.JSON(http.StatusBadRequest, models.ErrorResponse{
 				Error:   "Stock validation failed",
 				Message: err.Error(),
 			})
-			return
+			return false
 		}
 	}
 
-	// Add item to cart
-	err = h.addItemToCart(ctx, userID, miniAppType, req.ProductID, req.Quantity, req.StoreID)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "Failed to add item to cart",
-			Message: err.Error(),
-		})
-		return
-	}
-
-	c.JSON(http.StatusOK, models.SuccessResponse{
-		Message: "Item added to cart successfully",
-	})
+	return true
 }
 
 // UpdateCartItem updates the quantity of an item in the cart
 func (h *Handler) UpdateCartItem(c *gin.Context) {
 	// Validate mini-app type
-	miniAppType, ok := ValidateMiniAppType(c)
+	miniApp, ok := h.ValidateMiniAppType(c)
 	if !ok {
 		return
 	}
+	miniAppType := miniApp.Code
 
 	// Get user ID from JWT
 	userID, ok := GetUserID(c)
@@ -260,8 +287,22 @@ func (h *Handler) UpdateCartItem(c *gin.Context) {
 		return
 	}
 
+	// Location-based mini-apps check stock at the store the item was added from
+	var storeID *int
+	var err error
+	if miniApp.RequiresStore {
+		storeID, err = h.getCartItemStoreID(ctx, userID, miniAppType, req.ProductID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Error:   "Failed to get cart item",
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
 	// Verify product exists and has stock
-	product, err := h.getProduct(ctx, req.ProductID)
+	product, err := h.getProductAtStore(ctx, req.ProductID, storeID)
 	if err != nil {
 		c.JSON(http.StatusNotFound, models.ErrorResponse{
 			Error:   "Product not found",
@@ -270,8 +311,8 @@ func (h *Handler) UpdateCartItem(c *gin.Context) {
 		return
 	}
 
-	// Check stock availability (only for UnmannedStore)
-	if miniAppType == models.MiniAppTypeUnmannedStore && req.Quantity > product.DisplayStock() {
+	// Check stock availability (only for mini-apps that enforce stock)
+	if miniApp.EnforcesStock && req.Quantity > product.DisplayStock() {
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{
 			Error:   "Insufficient stock",
 			Message: "Only " + strconv.Itoa(product.DisplayStock()) + " items available",
@@ -297,10 +338,11 @@ func (h *Handler) UpdateCartItem(c *gin.Context) {
 // RemoveFromCart removes an item from the cart
 func (h *Handler) RemoveFromCart(c *gin.Context) {
 	// Validate mini-app type
-	miniAppType, ok := ValidateMiniAppType(c)
+	miniApp, ok := h.ValidateMiniAppType(c)
 	if !ok {
 		return
 	}
+	miniAppType := miniApp.Code
 
 	// Get user ID from JWT
 	userID, ok := GetUserID(c)
@@ -336,10 +378,11 @@ func (h *Handler) RemoveFromCart(c *gin.Context) {
 // CreateOrder creates an order from the user's cart
 func (h *Handler) CreateOrder(c *gin.Context) {
 	// Validate mini-app type
-	miniAppType, ok := ValidateMiniAppType(c)
+	miniApp, ok := h.ValidateMiniAppType(c)
 	if !ok {
 		return
 	}
+	miniAppType := miniApp.Code
 
 	// Get user ID from JWT
 	userID, ok := GetUserID(c)
@@ -351,6 +394,15 @@ func (h *Handler) CreateOrder(c *gin.Context) {
 		return
 	}
 
+	// Deactivated mini-apps keep their carts and orders readable but take no new ones
+	if !miniApp.IsActive {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Mini-app unavailable",
+			Message: "This mini-app is currently not available",
+		})
+		return
+	}
+
 	// Parse request body
 	var req models.CreateOrderRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -362,7 +414,7 @@ func (h *Handler) CreateOrder(c *gin.Context) {
 	}
 
 	// Validate store requirement for location-based mini-apps
-	if miniAppType.RequiresStore() && req.StoreID == nil {
+	if miniApp.RequiresStore && req.StoreID == nil {
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{
 			Error:   "Store ID required",
 			Message: "This mini-app requires a store selection",
@@ -370,11 +422,117 @@ func (h *Handler) CreateOrder(c *gin.Context) {
 		return
 	}
 
+	if req.RedeemPoints < 0 {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request",
+			Message: "redeem_points must not be negative",
+		})
+		return
+	}
+
+	// Orders are delivered to an address book entry or, for location-based mini-apps, picked
+	// up at the store
+	fulfillmentMethod := req.FulfillmentMethod
+	if fulfillmentMethod == "" {
+		fulfillmentMethod = models.FulfillmentDelivery
+		if miniApp.RequiresStore && req.AddressID == nil {
+			fulfillmentMethod = models.FulfillmentPickup
+		}
+	}
+	switch fulfillmentMethod {
+	case models.FulfillmentPickup:
+		if !miniApp.RequiresStore {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "Pickup unavailable",
+				Message: "This mini-app has no store to pick up from, choose an address for delivery",
+			})
+			return
+		}
+		if req.AddressID != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "Invalid request",
+				Message: "address_id is only used for delivery",
+			})
+			return
+		}
+	case models.FulfillmentDelivery:
+	default:
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid fulfillment method",
+			Message: "fulfillment_method must be one of: delivery, pickup",
+		})
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
 
+	// Copy the address now so later edits of the address book do not change the order.
+	// Without an address_id the user's default address is used. Clients that send neither a
+	// fulfillment method nor an address predate the address book; when the user has no
+	// default address their order is placed without fulfillment details, as before.
+	var deliveryAddress *models.DeliveryAddress
+	if fulfillmentMethod == models.FulfillmentDelivery {
+		address, err := h.getDeliveryAddress(ctx, userID, req.AddressID)
+		switch {
+		case err == nil:
+			deliveryAddress = address
+		case errors.Is(err, errAddressNotFound) && req.FulfillmentMethod == "" && req.AddressID == nil:
+			fulfillmentMethod = ""
+		case errors.Is(err, errAddressNotFound):
+			message := "The address was not found in your address book"
+			if req.AddressID == nil {
+				message = "Add a delivery address or choose one with address_id"
+			}
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "Delivery address required",
+				Message: message,
+			})
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Error:   "Failed to get delivery address",
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	// Only location-based mini-apps keep carts and orders per store
+	storeID := miniApp.StoreScope(req.StoreID)
+
+	// Location-based mini-apps are served by the store, so it must be open
+	if miniApp.RequiresStore {
+		isOpen, nextOpenAt, err := h.getStoreOpenState(ctx, *req.StoreID)
+		if err != nil {
+			if errors.Is(err, errStoreUnavailable) {
+				c.JSON(http.StatusBadRequest, models.ErrorResponse{
+					Error:   "Invalid store",
+					Message: err.Error(),
+				})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Error:   "Failed to check store hours",
+				Message: err.Error(),
+			})
+			return
+		}
+		if !isOpen {
+			message := "The store is currently closed"
+			if nextOpenAt != nil {
+				message += ", it opens again at " + nextOpenAt.Format(time.RFC3339)
+			}
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "Store closed",
+				Message: message,
+			})
+			return
+		}
+	}
+
 	// Get cart items (filtered by store for location-based mini-apps)
-	cartItems, err := h.getCartItemsWithStore(ctx, userID, miniAppType, req.StoreID)
+	cartItems, err := h.getCartItemsWithStore(ctx, userID, miniAppType, storeID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Error:   "Failed to get cart items",
@@ -392,9 +550,22 @@ func (h *Handler) CreateOrder(c *gin.Context) {
 		return
 	}
 
-	// Validate stock for all cart items before order creation (only for UnmannedStore)
-	if miniAppType == models.MiniAppTypeUnmannedStore {
-		err = h.validateCartStockBeforeOrder(ctx, cartItems)
+	// Location-based mini-apps cannot order products the store has deactivated
+	if miniApp.RequiresStore {
+		for _, item := range cartItems {
+			if !item.Product.IsActive {
+				c.JSON(http.StatusBadRequest, models.ErrorResponse{
+					Error:   "Product unavailable",
+					Message: "'" + item.Product.Title + "' is not available at this store",
+				})
+				return
+			}
+		}
+	}
+
+	// Validate stock for all cart items before order creation (only for mini-apps that enforce stock)
+	if miniApp.EnforcesStock {
+		err = h.validateCartStockBeforeOrder(ctx, cartItems, storeID)
 		if err != nil {
 			c.JSON(http.StatusBadRequest, models.ErrorResponse{
 				Error:   "Stock validation failed",
@@ -410,9 +581,30 @@ func (h *Handler) CreateOrder(c *gin.Context) {
 		totalAmount += float64(item.Quantity) * item.Product.MainPrice
 	}
 
+	// Loyalty points pay for up to a share of the total
+	var discount float64
+	if req.RedeemPoints > 0 {
+		settings := getLoyaltySettings()
+		if maxPoints := settings.maxRedeemablePoints(totalAmount); req.RedeemPoints > maxPoints {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "Too many points",
+				Message: fmt.Sprintf("At most %d points can be redeemed on this order", maxPoints),
+			})
+			return
+		}
+		discount = settings.redemptionDiscount(req.RedeemPoints)
+	}
+
 	// Create order (we'll implement this method)
-	order, err := h.createOrder(ctx, userID, miniAppType, req.StoreID, totalAmount, cartItems)
+	order, err := h.createOrder(ctx, userID, miniApp, storeID, fulfillmentMethod, deliveryAddress, totalAmount, req.RedeemPoints, discount, cartItems)
 	if err != nil {
+		if errors.Is(err, errInsufficientPoints) {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "Insufficient points",
+				Message: err.Error(),
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Error:   "Failed to create order",
 			Message: err.Error(),
@@ -421,7 +613,7 @@ func (h *Handler) CreateOrder(c *gin.Context) {
 	}
 
 	// Clear cart after successful order creation (only items from submitted store for location-based mini-apps)
-	err = h.clearCartWithStore(ctx, userID, miniAppType, req.StoreID)
+	err = h.clearCartWithStore(ctx, userID, miniAppType, storeID)
 	if err != nil {
 		// Log error but don't fail the order creation
 		// The order was created successfully, cart clearing is secondary
@@ -437,10 +629,11 @@ func (h *Handler) CreateOrder(c *gin.Context) {
 // GetOrders retrieves the user's orders for a specific mini-app
 func (h *Handler) GetOrders(c *gin.Context) {
 	// Validate mini-app type
-	miniAppType, ok := ValidateMiniAppType(c)
+	miniApp, ok := h.ValidateMiniAppType(c)
 	if !ok {
 		return
 	}
+	miniAppType := miniApp.Code
 
 	// Get user ID from JWT
 	userID, ok := GetUserID(c)
@@ -0,0 +1,550 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/expomadeinworld/madeinworld/order-service/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	defaultLoyaltyPointValue       = 0.01
+	defaultLoyaltyMaxRedeemPercent = 50
+	defaultLoyaltyExpiryDays       = 365
+
+	// loyaltyExpiringWithinDays is the window of the expiring points shown with a balance
+	loyaltyExpiringWithinDays = 30
+)
+
+var (
+	errInsufficientPoints   = errors.New("insufficient loyalty points")
+	errLoyaltyUserNotFound  = errors.New("user not found")
+	errLoyaltyRuleNotFound  = errors.New("loyalty rule not found")
+	errLoyaltyRuleConflict  = errors.New("a loyalty rule for this mini-app and store already exists")
+	errInvalidLoyaltyTarget = errors.New("invalid loyalty rule target")
+)
+
+// loyaltySettings holds the program-wide loyalty settings
+type loyaltySettings struct {
+	pointValue       float64 // discount per point redeemed
+	maxRedeemPercent float64 // share of an order total that points can pay
+	expiryDays       int     // lifetime of earned points; 0 keeps them forever
+}
+
+var (
+	loyaltyConfig     loyaltySettings
+	loyaltyConfigOnce sync.Once
+)
+
+// getLoyaltySettings reads LOYALTY_POINT_VALUE, LOYALTY_MAX_REDEEM_PERCENT and
+// LOYALTY_POINTS_EXPIRY_DAYS on first use, after the environment has been loaded
+func getLoyaltySettings() loyaltySettings {
+	loyaltyConfigOnce.Do(func() {
+		loyaltyConfig = loyaltySettings{
+			pointValue:       defaultLoyaltyPointValue,
+			maxRedeemPercent: defaultLoyaltyMaxRedeemPercent,
+			expiryDays:       defaultLoyaltyExpiryDays,
+		}
+		if value, err := strconv.ParseFloat(os.Getenv("LOYALTY_POINT_VALUE"), 64); err == nil && value > 0 {
+			loyaltyConfig.pointValue = value
+		}
+		if value, err := strconv.ParseFloat(os.Getenv("LOYALTY_MAX_REDEEM_PERCENT"), 64); err == nil && value > 0 && value <= 100 {
+			loyaltyConfig.maxRedeemPercent = value
+		}
+		if value, err := strconv.Atoi(os.Getenv("LOYALTY_POINTS_EXPIRY_DAYS")); err == nil && value >= 0 {
+			loyaltyConfig.expiryDays = value
+		}
+	})
+	return loyaltyConfig
+}
+
+// pointsExpiry returns when points credited now expire, nil when they do not
+func (s loyaltySettings) pointsExpiry(days *int) *time.Time {
+	expiryDays := s.expiryDays
+	if days != nil {
+		expiryDays = *days
+	}
+	if expiryDays <= 0 {
+		return nil
+	}
+	expiresAt := time.Now().AddDate(0, 0, expiryDays)
+	return &expiresAt
+}
+
+// redemptionDiscount returns the discount points are worth, in cents precision
+func (s loyaltySettings) redemptionDiscount(points int) float64 {
+	return math.Round(float64(points)*s.pointValue*100) / 100
+}
+
+// maxRedeemablePoints returns how many points may be redeemed on an order total
+func (s loyaltySettings) maxRedeemablePoints(total float64) int {
+	return int(math.Floor(total * s.maxRedeemPercent / 100 / s.pointValue))
+}
+
+// loyaltyEntry describes a ledger entry to write
+type loyaltyEntry struct {
+	entryType models.LoyaltyEntryType
+	orderID   *string
+	ruleID    *int
+	reason    string
+	createdBy string
+	expiresAt *time.Time
+}
+
+// creditPoints adds a lot of points to a user's balance
+func creditPoints(ctx context.Context, tx pgx.Tx, userID string, points int, entry loyaltyEntry) error {
+	_, err := tx.Exec(ctx, `
+		INSERT INTO loyalty_ledger (user_id, entry_type, points, remaining, expires_at, order_id, rule_id, reason, created_by)
+		VALUES ($1, $2, $3, $3, $4, $5, $6, NULLIF($7, ''), NULLIF($8, ''))
+	`, userID, string(entry.entryType), points, entry.expiresAt, entry.orderID, entry.ruleID, entry.reason, entry.createdBy)
+	if err != nil {
+		return fmt.Errorf("failed to credit loyalty points: %w", err)
+	}
+	return nil
+}
+
+// debitPoints takes points from a user's unexpired lots, those of preferOrderID first and
+// then soonest expiry first, and records the debit with what it took from each lot. When
+// partial is set it takes what is there if the balance is short, otherwise it fails with
+// errInsufficientPoints. It returns the points taken.
+func debitPoints(ctx context.Context, tx pgx.Tx, userID string, points int, entry loyaltyEntry, partial bool, preferOrderID *string) (int, error) {
+	rows, err := tx.Query(ctx, `
+		SELECT entry_id, remaining
+		FROM loyalty_ledger
+		WHERE user_id = $1 AND remaining > 0 AND (expires_at IS NULL OR expires_at > CURRENT_TIMESTAMP)
+		ORDER BY order_id IS NOT DISTINCT FROM $2 DESC, expires_at NULLS LAST, entry_id
+		FOR UPDATE
+	`, userID, preferOrderID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to lock loyalty points: %w", err)
+	}
+
+	type allocation struct {
+		lotID  int64
+		points int
+	}
+	var allocations []allocation
+	taken := 0
+	for rows.Next() {
+		var lotID int64
+		var remaining int
+		if err := rows.Scan(&lotID, &remaining); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan loyalty points: %w", err)
+		}
+		if taken < points {
+			take := min(remaining, points-taken)
+			allocations = append(allocations, allocation{lotID, take})
+			taken += take
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to read loyalty points: %w", err)
+	}
+
+	if taken < points && !partial {
+		return 0, fmt.Errorf("%w: %d available, %d requested", errInsufficientPoints, taken, points)
+	}
+
+	var debitID int64
+	err = tx.QueryRow(ctx, `
+		INSERT INTO loyalty_ledger (user_id, entry_type, points, order_id, reason, created_by)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), NULLIF($6, ''))
+		RETURNING entry_id
+	`, userID, string(entry.entryType), -taken, entry.orderID, entry.reason, entry.createdBy).Scan(&debitID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to debit loyalty points: %w", err)
+	}
+
+	for _, a := range allocations {
+		if _, err := tx.Exec(ctx, "UPDATE loyalty_ledger SET remaining = remaining - $2 WHERE entry_id = $1", a.lotID, a.points); err != nil {
+			return 0, fmt.Errorf("failed to update loyalty points: %w", err)
+		}
+		_, err := tx.Exec(ctx, `
+			INSERT INTO loyalty_allocations (debit_entry_id, lot_entry_id, points)
+			VALUES ($1, $2, $3)
+		`, debitID, a.lotID, a.points)
+		if err != nil {
+			return 0, fmt.Errorf("failed to record loyalty allocation: %w", err)
+		}
+	}
+
+	return taken, nil
+}
+
+// orderLoyaltyEntry returns the points of an order's entry of the given type, or false when
+// the order has none
+func orderLoyaltyEntry(ctx context.Context, tx pgx.Tx, orderID string, entryType models.LoyaltyEntryType) (int64, int, bool, error) {
+	var entryID int64
+	var points int
+	err := tx.QueryRow(ctx,
+		"SELECT entry_id, points FROM loyalty_ledger WHERE order_id = $1 AND entry_type = $2",
+		orderID, string(entryType)).Scan(&entryID, &points)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, 0, false, nil
+	}
+	if err != nil {
+		return 0, 0, false, fmt.Errorf("failed to get order loyalty entry: %w", err)
+	}
+	return entryID, points, true, nil
+}
+
+// awardOrderPoints credits the points a delivered order earns under the most specific
+// active rule for its mini-app and store. An order earns once; a cancelled order that is
+// later delivered earns nothing again.
+func awardOrderPoints(ctx context.Context, tx pgx.Tx, orderID string) error {
+	for _, entryType := range []models.LoyaltyEntryType{models.LoyaltyEntryEarn, models.LoyaltyEntryEarnReversal} {
+		_, _, exists, err := orderLoyaltyEntry(ctx, tx, orderID, entryType)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return nil
+		}
+	}
+
+	var userID string
+	var miniAppType string
+	var storeID *int
+	var totalAmount float64
+	err := tx.QueryRow(ctx,
+		"SELECT user_id, mini_app_type, store_id, total_amount FROM orders WHERE id = $1",
+		orderID).Scan(&userID, &miniAppType, &storeID, &totalAmount)
+	if err != nil {
+		return fmt.Errorf("failed to get order for loyalty points: %w", err)
+	}
+
+	var ruleID int
+	var pointsPerUnit, minOrderAmount float64
+	err = tx.QueryRow(ctx, `
+		SELECT rule_id, points_per_unit, min_order_amount
+		FROM loyalty_rules
+		WHERE is_active
+		  AND (mini_app_type IS NULL OR mini_app_type = $1)
+		  AND (store_id IS NULL OR store_id = $2)
+		ORDER BY store_id IS NOT NULL DESC, mini_app_type IS NOT NULL DESC
+		LIMIT 1
+	`, miniAppType, storeID).Scan(&ruleID, &pointsPerUnit, &minOrderAmount)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get loyalty rule: %w", err)
+	}
+
+	points := int(math.Floor(totalAmount * pointsPerUnit))
+	if totalAmount < minOrderAmount || points <= 0 {
+		return nil
+	}
+
+	err = creditPoints(ctx, tx, userID, points, loyaltyEntry{
+		entryType: models.LoyaltyEntryEarn,
+		orderID:   &orderID,
+		ruleID:    &ruleID,
+		createdBy: "system",
+		expiresAt: getLoyaltySettings().pointsExpiry(nil),
+	})
+	if err != nil {
+		return err
+	}
+
+	shortID := orderID
+	if len(shortID) > 8 {
+		shortID = shortID[:8]
+	}
+	return insertNotification(ctx, tx, userID, models.NotificationTypeLoyalty, "Points earned",
+		fmt.Sprintf("You earned %d points for order #%s.", points, shortID),
+		models.NotificationReferenceOrder, orderID)
+}
+
+// reverseOrderPoints undoes the loyalty effects of a cancelled order: redeemed points go back
+// to the lots they came from, then earned points are taken back as far as the balance allows
+func reverseOrderPoints(ctx context.Context, tx pgx.Tx, orderID, changedBy string) error {
+	var userID string
+	if err := tx.QueryRow(ctx, "SELECT user_id FROM orders WHERE id = $1", orderID).Scan(&userID); err != nil {
+		return fmt.Errorf("failed to get order for loyalty points: %w", err)
+	}
+
+	redeemID, redeemed, hasRedeem, err := orderLoyaltyEntry(ctx, tx, orderID, models.LoyaltyEntryRedeem)
+	if err != nil {
+		return err
+	}
+	_, _, refunded, err := orderLoyaltyEntry(ctx, tx, orderID, models.LoyaltyEntryRedeemRefund)
+	if err != nil {
+		return err
+	}
+	if hasRedeem && !refunded {
+		// Lots that expired meanwhile are expired again by the next expiry run
+		_, err := tx.Exec(ctx, `
+			UPDATE loyalty_ledger l
+			SET remaining = l.remaining + a.points
+			FROM loyalty_allocations a
+			WHERE a.debit_entry_id = $1 AND l.entry_id = a.lot_entry_id
+		`, redeemID)
+		if err != nil {
+			return fmt.Errorf("failed to refund loyalty points: %w", err)
+		}
+		_, err = tx.Exec(ctx, `
+			INSERT INTO loyalty_ledger (user_id, entry_type, points, order_id, reason, created_by)
+			VALUES ($1, $2, $3, $4, 'Order cancelled', NULLIF($5, ''))
+		`, userID, string(models.LoyaltyEntryRedeemRefund), -redeemed, orderID, changedBy)
+		if err != nil {
+			return fmt.Errorf("failed to record loyalty refund: %w", err)
+		}
+	}
+
+	_, earned, hasEarn, err := orderLoyaltyEntry(ctx, tx, orderID, models.LoyaltyEntryEarn)
+	if err != nil {
+		return err
+	}
+	_, _, reversed, err := orderLoyaltyEntry(ctx, tx, orderID, models.LoyaltyEntryEarnReversal)
+	if err != nil {
+		return err
+	}
+	if hasEarn && !reversed {
+		_, err := debitPoints(ctx, tx, userID, earned, loyaltyEntry{
+			entryType: models.LoyaltyEntryEarnReversal,
+			orderID:   &orderID,
+			reason:    "Order cancelled",
+			createdBy: changedBy,
+		}, true, &orderID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// applyOrderStatusLoyalty awards or reverses loyalty points when an order changes status
+func applyOrderStatusLoyalty(ctx context.Context, tx pgx.Tx, orderID string, newStatus models.OrderStatus, changedBy string) error {
+	switch newStatus {
+	case models.OrderStatusDelivered:
+		return awardOrderPoints(ctx, tx, orderID)
+	case models.OrderStatusCancelled:
+		return reverseOrderPoints(ctx, tx, orderID, changedBy)
+	default:
+		return nil
+	}
+}
+
+// checkLoyaltyUser returns errLoyaltyUserNotFound unless userID is an existing user
+func (h *Handler) checkLoyaltyUser(ctx context.Context, userID string) error {
+	var exists bool
+	if err := h.db.Pool.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM users WHERE id::text = $1)", userID).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if !exists {
+		return errLoyaltyUserNotFound
+	}
+	return nil
+}
+
+// getLoyaltyAccount returns a user's point balance
+func (h *Handler) getLoyaltyAccount(ctx context.Context, userID string) (*models.LoyaltyAccount, error) {
+	settings := getLoyaltySettings()
+	account := &models.LoyaltyAccount{
+		UserID:           userID,
+		ExpiringWithin:   loyaltyExpiringWithinDays,
+		PointValue:       settings.pointValue,
+		MaxRedeemPercent: settings.maxRedeemPercent,
+	}
+
+	err := h.db.Pool.QueryRow(ctx, `
+		SELECT COALESCE(SUM(remaining), 0),
+		       COALESCE(SUM(remaining) FILTER (WHERE expires_at <= CURRENT_TIMESTAMP + make_interval(days => $2)), 0),
+		       MIN(expires_at)
+		FROM loyalty_ledger
+		WHERE user_id = $1 AND remaining > 0 AND (expires_at IS NULL OR expires_at > CURRENT_TIMESTAMP)
+	`, userID, loyaltyExpiringWithinDays).Scan(&account.Balance, &account.ExpiringPoints, &account.NextExpiryAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get loyalty balance: %w", err)
+	}
+
+	account.BalanceValue = settings.redemptionDiscount(account.Balance)
+	return account, nil
+}
+
+// getLoyaltyHistory retrieves a page of a user's ledger, newest first
+func (h *Handler) getLoyaltyHistory(ctx context.Context, userID string, req *models.LoyaltyHistoryRequest) ([]models.LoyaltyEntry, int, error) {
+	var total int
+	err := h.db.Pool.QueryRow(ctx, `
+		SELECT COUNT(*) FROM loyalty_ledger
+		WHERE user_id = $1 AND ($2 = '' OR entry_type = $2)
+	`, userID, req.Type).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count loyalty entries: %w", err)
+	}
+
+	rows, err := h.db.Pool.Query(ctx, `
+		SELECT entry_id, entry_type, points, remaining, expires_at, order_id::text, reason, created_by, created_at
+		FROM loyalty_ledger
+		WHERE user_id = $1 AND ($2 = '' OR entry_type = $2)
+		ORDER BY created_at DESC, entry_id DESC
+		LIMIT $3 OFFSET $4
+	`, userID, req.Type, req.Limit, (req.Page-1)*req.Limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query loyalty entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []models.LoyaltyEntry{}
+	for rows.Next() {
+		var e models.LoyaltyEntry
+		err := rows.Scan(&e.ID, &e.Type, &e.Points, &e.Remaining, &e.ExpiresAt, &e.OrderID,
+			&e.Reason, &e.CreatedBy, &e.CreatedAt)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan loyalty entry: %w", err)
+		}
+		entries = append(entries, e)
+	}
+
+	return entries, total, rows.Err()
+}
+
+// adjustLoyaltyPoints adds (positive) or removes (negative) points on behalf of an admin
+func (h *Handler) adjustLoyaltyPoints(ctx context.Context, userID string, req *models.LoyaltyAdjustmentRequest, adminUserID string) error {
+	tx, err := h.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var exists bool
+	if err := tx.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM users WHERE id::text = $1)", userID).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if !exists {
+		return errLoyaltyUserNotFound
+	}
+
+	entry := loyaltyEntry{
+		entryType: models.LoyaltyEntryAdjust,
+		reason:    strings.TrimSpace(req.Reason),
+		createdBy: adminUserID,
+	}
+	if req.Points > 0 {
+		entry.expiresAt = getLoyaltySettings().pointsExpiry(req.ExpiresInDays)
+		err = creditPoints(ctx, tx, userID, req.Points, entry)
+	} else {
+		_, err = debitPoints(ctx, tx, userID, -req.Points, entry, false, nil)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// loyaltyRuleColumns selects a rule in the column order scanned by scanLoyaltyRule
+const loyaltyRuleColumns = `r.rule_id, r.mini_app_type, r.store_id, COALESCE(s.name, ''), r.points_per_unit,
+		       r.min_order_amount, r.is_active, r.created_by, r.created_at, r.updated_at`
+
+func scanLoyaltyRule(row pgx.Row, rule *models.LoyaltyRule) error {
+	return row.Scan(&rule.ID, &rule.MiniAppType, &rule.StoreID, &rule.StoreName, &rule.PointsPerUnit,
+		&rule.MinOrderAmount, &rule.IsActive, &rule.CreatedBy, &rule.CreatedAt, &rule.UpdatedAt)
+}
+
+// getLoyaltyRules lists the earning rules, general rules first
+func (h *Handler) getLoyaltyRules(ctx context.Context) ([]models.LoyaltyRule, error) {
+	rows, err := h.db.Pool.Query(ctx, `
+		SELECT `+loyaltyRuleColumns+`
+		FROM loyalty_rules r
+		LEFT JOIN stores s ON s.store_id = r.store_id
+		ORDER BY r.store_id NULLS FIRST, r.mini_app_type NULLS FIRST
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query loyalty rules: %w", err)
+	}
+	defer rows.Close()
+
+	rules := []models.LoyaltyRule{}
+	for rows.Next() {
+		var rule models.LoyaltyRule
+		if err := scanLoyaltyRule(rows, &rule); err != nil {
+			return nil, fmt.Errorf("failed to scan loyalty rule: %w", err)
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, rows.Err()
+}
+
+// getLoyaltyRule retrieves an earning rule
+func (h *Handler) getLoyaltyRule(ctx context.Context, ruleID int) (*models.LoyaltyRule, error) {
+	var rule models.LoyaltyRule
+	err := scanLoyaltyRule(h.db.Pool.QueryRow(ctx, `
+		SELECT `+loyaltyRuleColumns+`
+		FROM loyalty_rules r
+		LEFT JOIN stores s ON s.store_id = r.store_id
+		WHERE r.rule_id = $1
+	`, ruleID), &rule)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errLoyaltyRuleNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get loyalty rule: %w", err)
+	}
+	return &rule, nil
+}
+
+// saveLoyaltyRule creates a rule, or replaces rule ruleID when it is not zero
+func (h *Handler) saveLoyaltyRule(ctx context.Context, ruleID int, req *models.LoyaltyRuleRequest, adminUserID string) (*models.LoyaltyRule, error) {
+	isActive := req.IsActive == nil || *req.IsActive
+
+	var err error
+	if ruleID == 0 {
+		err = h.db.Pool.QueryRow(ctx, `
+			INSERT INTO loyalty_rules (mini_app_type, store_id, points_per_unit, min_order_amount, is_active, created_by)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING rule_id
+		`, req.MiniAppType, req.StoreID, req.PointsPerUnit, req.MinOrderAmount, isActive, adminUserID).Scan(&ruleID)
+	} else {
+		err = h.db.Pool.QueryRow(ctx, `
+			UPDATE loyalty_rules
+			SET mini_app_type = $2, store_id = $3, points_per_unit = $4, min_order_amount = $5,
+			    is_active = $6, updated_at = CURRENT_TIMESTAMP
+			WHERE rule_id = $1
+			RETURNING rule_id
+		`, ruleID, req.MiniAppType, req.StoreID, req.PointsPerUnit, req.MinOrderAmount, isActive).Scan(&ruleID)
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, errLoyaltyRuleNotFound
+		case strings.Contains(err.Error(), "idx_loyalty_rules_scope"):
+			return nil, errLoyaltyRuleConflict
+		case strings.Contains(err.Error(), "loyalty_rules_mini_app_type_fkey"):
+			return nil, fmt.Errorf("%w: mini-app type is not registered", errInvalidLoyaltyTarget)
+		case strings.Contains(err.Error(), "loyalty_rules_store_id_fkey"):
+			return nil, fmt.Errorf("%w: store not found", errInvalidLoyaltyTarget)
+		}
+		return nil, fmt.Errorf("failed to save loyalty rule: %w", err)
+	}
+
+	return h.getLoyaltyRule(ctx, ruleID)
+}
+
+// deleteLoyaltyRule removes an earning rule; points already earned under it stay
+func (h *Handler) deleteLoyaltyRule(ctx context.Context, ruleID int) error {
+	tag, err := h.db.Pool.Exec(ctx, "DELETE FROM loyalty_rules WHERE rule_id = $1", ruleID)
+	if err != nil {
+		return fmt.Errorf("failed to delete loyalty rule: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errLoyaltyRuleNotFound
+	}
+	return nil
+}
@@ -0,0 +1,324 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/expomadeinworld/madeinworld/order-service/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// GetMyLoyalty returns the authenticated user's point balance
+func (h *Handler) GetMyLoyalty(c *gin.Context) {
+	userID, ok := GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "Invalid user",
+			Message: "Could not extract user ID from token",
+		})
+		return
+	}
+
+	h.respondLoyaltyAccount(c, userID)
+}
+
+// GetMyLoyaltyHistory returns the authenticated user's loyalty ledger
+func (h *Handler) GetMyLoyaltyHistory(c *gin.Context) {
+	userID, ok := GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "Invalid user",
+			Message: "Could not extract user ID from token",
+		})
+		return
+	}
+
+	h.respondLoyaltyHistory(c, userID)
+}
+
+// GetUserLoyalty returns a user's point balance for admin
+func (h *Handler) GetUserLoyalty(c *gin.Context) {
+	userID, ok := h.loyaltyUserParam(c)
+	if !ok {
+		return
+	}
+
+	h.respondLoyaltyAccount(c, userID)
+}
+
+// GetUserLoyaltyHistory returns a user's loyalty ledger for admin
+func (h *Handler) GetUserLoyaltyHistory(c *gin.Context) {
+	userID, ok := h.loyaltyUserParam(c)
+	if !ok {
+		return
+	}
+
+	h.respondLoyaltyHistory(c, userID)
+}
+
+// AdjustUserLoyalty adds points to or removes points from a user's balance
+func (h *Handler) AdjustUserLoyalty(c *gin.Context) {
+	adminUserID, ok := GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "Invalid user",
+			Message: "Could not extract user ID from token",
+		})
+		return
+	}
+
+	var req models.LoyaltyAdjustmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request data",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if req.ExpiresInDays != nil && req.Points < 0 {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request data",
+			Message: "expires_in_days only applies to added points",
+		})
+		return
+	}
+
+	userID := c.Param("user_id")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := h.adjustLoyaltyPoints(ctx, userID, &req, adminUserID); err != nil {
+		respondLoyaltyError(c, err, "Failed to adjust loyalty points")
+		return
+	}
+
+	account, err := h.getLoyaltyAccount(ctx, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to get loyalty balance",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: "Loyalty points adjusted",
+		Data:    account,
+	})
+}
+
+// GetLoyaltyRules lists the loyalty earning rules
+func (h *Handler) GetLoyaltyRules(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	rules, err := h.getLoyaltyRules(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to get loyalty rules",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"rules": rules,
+	})
+}
+
+// CreateLoyaltyRule adds a loyalty earning rule
+func (h *Handler) CreateLoyaltyRule(c *gin.Context) {
+	h.saveLoyaltyRuleFromRequest(c, 0, http.StatusCreated)
+}
+
+// UpdateLoyaltyRule replaces a loyalty earning rule
+func (h *Handler) UpdateLoyaltyRule(c *gin.Context) {
+	ruleID, ok := parseLoyaltyRuleID(c)
+	if !ok {
+		return
+	}
+
+	h.saveLoyaltyRuleFromRequest(c, ruleID, http.StatusOK)
+}
+
+// DeleteLoyaltyRule removes a loyalty earning rule
+func (h *Handler) DeleteLoyaltyRule(c *gin.Context) {
+	ruleID, ok := parseLoyaltyRuleID(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := h.deleteLoyaltyRule(ctx, ruleID); err != nil {
+		respondLoyaltyError(c, err, "Failed to delete loyalty rule")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: "Loyalty rule deleted",
+	})
+}
+
+func (h *Handler) saveLoyaltyRuleFromRequest(c *gin.Context, ruleID int, status int) {
+	adminUserID, ok := GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "Invalid user",
+			Message: "Could not extract user ID from token",
+		})
+		return
+	}
+
+	var req models.LoyaltyRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request data",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	rule, err := h.saveLoyaltyRule(ctx, ruleID, &req, adminUserID)
+	if err != nil {
+		respondLoyaltyError(c, err, "Failed to save loyalty rule")
+		return
+	}
+
+	c.JSON(status, rule)
+}
+
+func (h *Handler) respondLoyaltyAccount(c *gin.Context, userID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	account, err := h.getLoyaltyAccount(ctx, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to get loyalty balance",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, account)
+}
+
+func (h *Handler) respondLoyaltyHistory(c *gin.Context, userID string) {
+	var req models.LoyaltyHistoryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid query parameters",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if req.Type != "" && !models.LoyaltyEntryType(req.Type).IsValid() {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid type",
+			Message: "Type must be one of: earn, redeem, earn_reversal, redeem_refund, expire, adjust",
+		})
+		return
+	}
+
+	// Set defaults
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.Limit == 0 {
+		req.Limit = 20
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	entries, total, err := h.getLoyaltyHistory(ctx, userID, &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to get loyalty history",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.LoyaltyHistoryResponse{
+		Entries:    entries,
+		Total:      total,
+		Page:       req.Page,
+		Limit:      req.Limit,
+		TotalPages: (total + req.Limit - 1) / req.Limit,
+	})
+}
+
+// loyaltyUserParam reads the user_id path parameter and checks the user exists
+func (h *Handler) loyaltyUserParam(c *gin.Context) (string, bool) {
+	userID := c.Param("user_id")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := h.checkLoyaltyUser(ctx, userID); err != nil {
+		respondLoyaltyError(c, err, "Failed to get user")
+		return "", false
+	}
+	return userID, true
+}
+
+// parseLoyaltyRuleID reads the rule_id path parameter
+func parseLoyaltyRuleID(c *gin.Context) (int, bool) {
+	ruleID, err := strconv.Atoi(c.Param("rule_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid rule ID",
+			Message: "Rule ID must be a number",
+		})
+		return 0, false
+	}
+	return ruleID, true
+}
+
+// respondLoyaltyError maps loyalty errors to HTTP responses
+func respondLoyaltyError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, errLoyaltyUserNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   "User not found",
+			Message: err.Error(),
+		})
+	case errors.Is(err, errLoyaltyRuleNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   "Loyalty rule not found",
+			Message: err.Error(),
+		})
+	case errors.Is(err, errLoyaltyRuleConflict):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error:   "Loyalty rule already exists",
+			Message: err.Error(),
+		})
+	case errors.Is(err, errInsufficientPoints):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error:   "Insufficient points",
+			Message: err.Error(),
+		})
+	case errors.Is(err, errInvalidLoyaltyTarget):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request data",
+			Message: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   fallback,
+			Message: err.Error(),
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package api
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/expomadeinworld/madeinworld/order-service/internal/models"
+)
+
+// getManufacturerSales aggregates non-cancelled order items for every product of a manufacturer.
+// Products without sales in the period are included with zero figures.
+func (h *Handler) getManufacturerSales(ctx context.Context, manufacturerID int, req *models.ManufacturerSalesRequest) (*models.ManufacturerSalesResponse, error) {
+	orderFilter := "o.status != 'cancelled'"
+	args := []interface{}{manufacturerID}
+	argIndex := 2
+
+	if req.DateFrom != "" {
+		orderFilter += fmt.Sprintf(" AND o.created_at >= $%d", argIndex)
+		args = append(args, req.DateFrom+" 00:00:00")
+		argIndex++
+	}
+	if req.DateTo != "" {
+		orderFilter += fmt.Sprintf(" AND o.created_at <= $%d", argIndex)
+		args = append(args, req.DateTo+" 23:59:59")
+		argIndex++
+	}
+	if req.MiniAppType != "" {
+		orderFilter += fmt.Sprintf(" AND o.mini_app_type = $%d", argIndex)
+		args = append(args, req.MiniAppType)
+		argIndex++
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			p.product_id, p.product_uuid, p.sku, p.title,
+			COALESCE(s.units_sold, 0), COALESCE(s.order_count, 0), COALESCE(s.revenue, 0), s.last_sold_at
+		FROM products p
+		LEFT JOIN (
+			SELECT
+				oi.product_id,
+				SUM(oi.quantity) AS units_sold,
+				COUNT(DISTINCT oi.order_id) AS order_count,
+				SUM(oi.price) AS revenue,
+				MAX(o.created_at) AS last_sold_at
+			FROM order_items oi
+			JOIN orders o ON o.id = oi.order_id
+			WHERE %s
+			GROUP BY oi.product_id
+		) s ON s.product_id = p.product_uuid
+		WHERE p.manufacturer_id = $1
+		ORDER BY COALESCE(s.revenue, 0) DESC, p.title
+	`, orderFilter)
+
+	rows, err := h.db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query manufacturer sales: %w", err)
+	}
+	defer rows.Close()
+
+	response := &models.ManufacturerSalesResponse{
+		ManufacturerID: manufacturerID,
+		DateFrom:       req.DateFrom,
+		DateTo:         req.DateTo,
+		Products:       []models.ManufacturerProductSales{},
+	}
+
+	for rows.Next() {
+		var sales models.ManufacturerProductSales
+		err := rows.Scan(
+			&sales.ProductID,
+			&sales.ProductUUID,
+			&sales.SKU,
+			&sales.ProductTitle,
+			&sales.UnitsSold,
+			&sales.OrderCount,
+			&sales.Revenue,
+			&sales.LastSoldAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan manufacturer sales: %w", err)
+		}
+		response.TotalUnits += sales.UnitsSold
+		response.TotalRevenue += sales.Revenue
+		response.Products = append(response.Products, sales)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating manufacturer sales: %w", err)
+	}
+
+	return response, nil
+}
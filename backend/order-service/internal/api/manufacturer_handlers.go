@@ -0,0 +1,54 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/expomadeinworld/madeinworld/order-service/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// GetManufacturerSales returns per-product sales for the caller's manufacturer
+func (h *Handler) GetManufacturerSales(c *gin.Context) {
+	var req models.ManufacturerSalesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request parameters",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	if req.MiniAppType != "" {
+		if _, err := h.getMiniApp(ctx, models.MiniAppType(req.MiniAppType)); err != nil {
+			if errors.Is(err, errMiniAppNotFound) {
+				c.JSON(http.StatusBadRequest, models.ErrorResponse{
+					Error:   "Invalid mini-app type",
+					Message: "Mini-app type '" + req.MiniAppType + "' is not registered",
+				})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Error:   "Failed to load mini-app",
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	sales, err := h.getManufacturerSales(ctx, c.GetInt("manufacturer_id"), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to get sales",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, sales)
+}
@@ -0,0 +1,30 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/expomadeinworld/madeinworld/order-service/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// errMiniAppNotFound is returned for codes missing from the mini-app registry
+var errMiniAppNotFound = errors.New("mini-app not found")
+
+// getMiniApp loads a mini-app from the registry, active or not
+func (h *Handler) getMiniApp(ctx context.Context, code models.MiniAppType) (*models.MiniApp, error) {
+	var m models.MiniApp
+	err := h.db.Pool.QueryRow(ctx, `
+		SELECT code, display_name, requires_store, enforces_stock, enforces_moq, is_active
+		FROM mini_apps
+		WHERE code = $1
+	`, string(code)).Scan(&m.Code, &m.DisplayName, &m.RequiresStore, &m.EnforcesStock, &m.EnforcesMOQ, &m.IsActive)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errMiniAppNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mini-app: %w", err)
+	}
+	return &m, nil
+}
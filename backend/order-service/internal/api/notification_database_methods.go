@@ -0,0 +1,145 @@
+package api
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/expomadeinworld/madeinworld/order-service/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// execer is satisfied by both the connection pool and a transaction, so notifications
+// can be written as part of the change that triggers them
+type execer interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+}
+
+// insertNotification adds a notification to a user's inbox
+func insertNotification(ctx context.Context, q execer, recipientUserID, notificationType, title, message string, refType models.NotificationReferenceType, refID string) error {
+	_, err := q.Exec(ctx, `
+		INSERT INTO notifications (recipient_user_id, notification_type, title, message, reference_type, reference_id)
+		VALUES ($1, $2, $3, $4, $5::notification_reference_type, $6)
+	`, recipientUserID, notificationType, title, message, string(refType), refID)
+	if err != nil {
+		return fmt.Errorf("failed to create notification: %w", err)
+	}
+	return nil
+}
+
+// notifyOrderStatusChange tells the customer that their order moved to a new status
+func notifyOrderStatusChange(ctx context.Context, tx pgx.Tx, orderID string, newStatus models.OrderStatus) error {
+	var userID string
+	if err := tx.QueryRow(ctx, "SELECT user_id FROM orders WHERE id = $1", orderID).Scan(&userID); err != nil {
+		return fmt.Errorf("failed to get order owner: %w", err)
+	}
+
+	title, message := orderStatusNotificationText(orderID, newStatus)
+	return insertNotification(ctx, tx, userID, models.NotificationTypeOrderStatus, title, message,
+		models.NotificationReferenceOrder, orderID)
+}
+
+// orderStatusNotificationText returns the inbox title and message for an order status
+func orderStatusNotificationText(orderID string, status models.OrderStatus) (string, string) {
+	shortID := orderID
+	if len(shortID) > 8 {
+		shortID = shortID[:8]
+	}
+
+	switch status {
+	case models.OrderStatusConfirmed:
+		return "Order confirmed", fmt.Sprintf("Your order #%s has been confirmed.", shortID)
+	case models.OrderStatusProcessing:
+		return "Order processing", fmt.Sprintf("Your order #%s is being prepared.", shortID)
+	case models.OrderStatusShipped:
+		return "Order shipped", fmt.Sprintf("Your order #%s is on its way.", shortID)
+	case models.OrderStatusDelivered:
+		return "Order delivered", fmt.Sprintf("Your order #%s has been delivered.", shortID)
+	case models.OrderStatusCancelled:
+		return "Order cancelled", fmt.Sprintf("Your order #%s has been cancelled.", shortID)
+	default:
+		return "Order updated", fmt.Sprintf("Your order #%s is now %s.", shortID, status)
+	}
+}
+
+// getNotifications retrieves a page of the user's notifications together with the
+// total number matching the filter and the unread count
+func (h *Handler) getNotifications(ctx context.Context, userID string, req *models.NotificationListRequest) ([]models.Notification, int, int, error) {
+	var total, unread int
+	err := h.db.Pool.QueryRow(ctx, `
+		SELECT COUNT(*) FILTER (WHERE NOT $2 OR NOT is_read),
+		       COUNT(*) FILTER (WHERE NOT is_read)
+		FROM notifications
+		WHERE recipient_user_id = $1
+	`, userID, req.UnreadOnly).Scan(&total, &unread)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("failed to count notifications: %w", err)
+	}
+
+	rows, err := h.db.Pool.Query(ctx, `
+		SELECT notification_id, COALESCE(notification_type, ''), title, message,
+		       reference_type, reference_id, COALESCE(is_read, false), read_at, created_at
+		FROM notifications
+		WHERE recipient_user_id = $1 AND (NOT $2 OR NOT is_read)
+		ORDER BY created_at DESC, notification_id DESC
+		LIMIT $3 OFFSET $4
+	`, userID, req.UnreadOnly, req.Limit, (req.Page-1)*req.Limit)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("failed to query notifications: %w", err)
+	}
+	defer rows.Close()
+
+	notifications := []models.Notification{}
+	for rows.Next() {
+		var n models.Notification
+		err := rows.Scan(&n.ID, &n.Type, &n.Title, &n.Message, &n.ReferenceType,
+			&n.ReferenceID, &n.IsRead, &n.ReadAt, &n.CreatedAt)
+		if err != nil {
+			return nil, 0, 0, fmt.Errorf("failed to scan notification: %w", err)
+		}
+		notifications = append(notifications, n)
+	}
+
+	return notifications, total, unread, rows.Err()
+}
+
+// getUnreadNotificationCount returns how many unread notifications the user has
+func (h *Handler) getUnreadNotificationCount(ctx context.Context, userID string) (int, error) {
+	var count int
+	err := h.db.Pool.QueryRow(ctx,
+		"SELECT COUNT(*) FROM notifications WHERE recipient_user_id = $1 AND NOT is_read",
+		userID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count unread notifications: %w", err)
+	}
+	return count, nil
+}
+
+// markNotificationRead marks one of the user's notifications as read
+func (h *Handler) markNotificationRead(ctx context.Context, userID string, notificationID int) error {
+	tag, err := h.db.Pool.Exec(ctx, `
+		UPDATE notifications
+		SET is_read = true, read_at = COALESCE(read_at, CURRENT_TIMESTAMP)
+		WHERE notification_id = $1 AND recipient_user_id = $2
+	`, notificationID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to mark notification as read: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("notification not found")
+	}
+	return nil
+}
+
+// markAllNotificationsRead marks every unread notification of the user as read
+func (h *Handler) markAllNotificationsRead(ctx context.Context, userID string) (int, error) {
+	tag, err := h.db.Pool.Exec(ctx, `
+		UPDATE notifications
+		SET is_read = true, read_at = CURRENT_TIMESTAMP
+		WHERE recipient_user_id = $1 AND NOT is_read
+	`, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark notifications as read: %w", err)
+	}
+	return int(tag.RowsAffected()), nil
+}
@@ -0,0 +1,400 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/expomadeinworld/madeinworld/order-service/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	errShipmentNotFound          = errors.New("shipment not found")
+	errInvalidShipmentTransition = errors.New("shipment cannot move to the requested status")
+	errInvalidShipmentRequest    = errors.New("invalid shipment request")
+)
+
+// shipmentSelect is the column list and joins shared by shipment reads.
+// The destination store comes from the order for customer deliveries and from the
+// stock request for replenishment deliveries.
+const shipmentSelect = `
+	SELECT s.shipment_id, s.order_id::text, s.request_id, s.assigned_3pl_id::text,
+	       COALESCE(u.email, ''), s.status, COALESCE(o.store_id, sr.destination_store_id),
+	       COALESCE(st.name, ''), s.assigned_at, s.pickup_timestamp, s.delivery_timestamp,
+	       s.proof_of_delivery_url, s.delivery_notes, s.created_at, s.updated_at
+	FROM shipments s
+	LEFT JOIN users u ON u.id = s.assigned_3pl_id
+	LEFT JOIN orders o ON o.id = s.order_id
+	LEFT JOIN stock_requests sr ON sr.request_id = s.request_id
+	LEFT JOIN stores st ON st.store_id = COALESCE(o.store_id, sr.destination_store_id)
+`
+
+func scanShipment(row pgx.Row, s *models.Shipment) error {
+	return row.Scan(
+		&s.ID, &s.OrderID, &s.StockRequestID, &s.Assigned3PLID,
+		&s.Assigned3PLEmail, &s.Status, &s.DestinationStoreID,
+		&s.DestinationStoreName, &s.AssignedAt, &s.PickupTimestamp, &s.DeliveryTimestamp,
+		&s.ProofOfDeliveryURL, &s.DeliveryNotes, &s.CreatedAt, &s.UpdatedAt,
+	)
+}
+
+// getShipments retrieves shipments with filtering and pagination.
+// When threePLID is set only shipments assigned to that 3PL user are returned.
+func (h *Handler) getShipments(ctx context.Context, req *models.ShipmentListRequest, threePLID *string) ([]models.Shipment, int, error) {
+	var whereConditions []string
+	var args []interface{}
+	argIndex := 1
+
+	if threePLID != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("s.assigned_3pl_id = $%d", argIndex))
+		args = append(args, *threePLID)
+		argIndex++
+	} else if req.Assigned3PLID != "" {
+		whereConditions = append(whereConditions, fmt.Sprintf("s.assigned_3pl_id = $%d", argIndex))
+		args = append(args, req.Assigned3PLID)
+		argIndex++
+	}
+
+	if req.Status != "" {
+		whereConditions = append(whereConditions, fmt.Sprintf("s.status::text = $%d", argIndex))
+		args = append(args, req.Status)
+		argIndex++
+	}
+
+	if req.OrderID != "" {
+		whereConditions = append(whereConditions, fmt.Sprintf("s.order_id::text = $%d", argIndex))
+		args = append(args, req.OrderID)
+		argIndex++
+	}
+
+	if req.StockRequestID != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("s.request_id = $%d", argIndex))
+		args = append(args, *req.StockRequestID)
+		argIndex++
+	}
+
+	whereClause := ""
+	if len(whereConditions) > 0 {
+		whereClause = "WHERE " + strings.Join(whereConditions, " AND ")
+	}
+
+	var total int
+	countQuery := "SELECT COUNT(*) FROM shipments s " + whereClause
+	if err := h.db.Pool.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count shipments: %w", err)
+	}
+
+	offset := (req.Page - 1) * req.Limit
+	query := fmt.Sprintf("%s %s ORDER BY s.created_at DESC LIMIT $%d OFFSET $%d",
+		shipmentSelect, whereClause, argIndex, argIndex+1)
+	args = append(args, req.Limit, offset)
+
+	rows, err := h.db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query shipments: %w", err)
+	}
+	defer rows.Close()
+
+	shipments := []models.Shipment{}
+	for rows.Next() {
+		var shipment models.Shipment
+		if err := scanShipment(rows, &shipment); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan shipment: %w", err)
+		}
+		shipments = append(shipments, shipment)
+	}
+
+	return shipments, total, rows.Err()
+}
+
+// getShipmentByID retrieves a single shipment, optionally restricted to a 3PL user
+func (h *Handler) getShipmentByID(ctx context.Context, shipmentID int, threePLID *string) (*models.Shipment, error) {
+	query := shipmentSelect + " WHERE s.shipment_id = $1 AND ($2::uuid IS NULL OR s.assigned_3pl_id = $2::uuid)"
+
+	var shipment models.Shipment
+	if err := scanShipment(h.db.Pool.QueryRow(ctx, query, shipmentID, threePLID), &shipment); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errShipmentNotFound
+		}
+		return nil, fmt.Errorf("failed to get shipment: %w", err)
+	}
+
+	return &shipment, nil
+}
+
+// createShipment creates a shipment for an order or a stock request and assigns it to a 3PL user
+func (h *Handler) createShipment(ctx context.Context, req *models.CreateShipmentRequest) (int, error) {
+	if err := h.ensure3PLUser(ctx, req.Assigned3PLID); err != nil {
+		return 0, err
+	}
+
+	if req.OrderID != nil {
+		var status models.OrderStatus
+		err := h.db.Pool.QueryRow(ctx, "SELECT status FROM orders WHERE id = $1", *req.OrderID).Scan(&status)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return 0, fmt.Errorf("%w: order not found", errInvalidShipmentRequest)
+			}
+			return 0, fmt.Errorf("failed to get order: %w", err)
+		}
+		if status == models.OrderStatusCancelled || status == models.OrderStatusDelivered {
+			return 0, fmt.Errorf("%w: order is already %s", errInvalidShipmentRequest, status)
+		}
+	} else {
+		var status string
+		err := h.db.Pool.QueryRow(ctx, "SELECT status::text FROM stock_requests WHERE request_id = $1", *req.StockRequestID).Scan(&status)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return 0, fmt.Errorf("%w: stock request not found", errInvalidShipmentRequest)
+			}
+			return 0, fmt.Errorf("failed to get stock request: %w", err)
+		}
+		if status != "Ready for Pickup" {
+			return 0, fmt.Errorf("%w: stock request must be 'Ready for Pickup' to be shipped, current status is '%s'", errInvalidShipmentRequest, status)
+		}
+	}
+
+	tx, err := h.db.Pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var shipmentID int
+	err = tx.QueryRow(ctx, `
+		INSERT INTO shipments (order_id, request_id, assigned_3pl_id)
+		VALUES ($1, $2, $3)
+		RETURNING shipment_id
+	`, req.OrderID, req.StockRequestID, req.Assigned3PLID).Scan(&shipmentID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create shipment: %w", err)
+	}
+
+	if err := notifyShipmentAssigned(ctx, tx, shipmentID, req.Assigned3PLID); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return shipmentID, nil
+}
+
+// assignShipment hands a shipment that has not been picked up yet to another 3PL user
+func (h *Handler) assignShipment(ctx context.Context, shipmentID int, threePLID string) error {
+	if err := h.ensure3PLUser(ctx, threePLID); err != nil {
+		return err
+	}
+
+	tx, err := h.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE shipments
+		SET assigned_3pl_id = $1, assigned_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE shipment_id = $2 AND status = 'Assigned'
+	`, threePLID, shipmentID)
+	if err != nil {
+		return fmt.Errorf("failed to assign shipment: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		var exists bool
+		if err := h.db.Pool.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM shipments WHERE shipment_id = $1)", shipmentID).Scan(&exists); err == nil && !exists {
+			return errShipmentNotFound
+		}
+		return fmt.Errorf("%w: only shipments that have not been picked up can be reassigned", errInvalidShipmentTransition)
+	}
+
+	if err := notifyShipmentAssigned(ctx, tx, shipmentID, threePLID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// advanceShipment moves a shipment assigned to the 3PL user to its next status, stamps the
+// matching timestamp and carries the order or stock request along with it.
+func (h *Handler) advanceShipment(ctx context.Context, shipmentID int, threePLID string, newStatus models.ShipmentStatus, proofURL, notes string) error {
+	tx, err := h.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var currentStatus models.ShipmentStatus
+	var orderID *string
+	var requestID *int
+	err = tx.QueryRow(ctx, `
+		SELECT status, order_id::text, request_id
+		FROM shipments
+		WHERE shipment_id = $1 AND assigned_3pl_id = $2
+		FOR UPDATE
+	`, shipmentID, threePLID).Scan(&currentStatus, &orderID, &requestID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return errShipmentNotFound
+		}
+		return fmt.Errorf("failed to get shipment: %w", err)
+	}
+
+	if currentStatus.Next() != newStatus {
+		return fmt.Errorf("%w: '%s' to '%s'", errInvalidShipmentTransition, currentStatus, newStatus)
+	}
+
+	switch newStatus {
+	case models.ShipmentStatusPickedUp:
+		_, err = tx.Exec(ctx, `
+			UPDATE shipments
+			SET status = 'Picked Up', pickup_timestamp = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+			WHERE shipment_id = $1
+		`, shipmentID)
+	case models.ShipmentStatusDelivered:
+		_, err = tx.Exec(ctx, `
+			UPDATE shipments
+			SET status = 'Delivered', delivery_timestamp = CURRENT_TIMESTAMP,
+			    proof_of_delivery_url = $2, delivery_notes = NULLIF($3, ''), updated_at = CURRENT_TIMESTAMP
+			WHERE shipment_id = $1
+		`, shipmentID, proofURL, notes)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update shipment: %w", err)
+	}
+
+	if orderID != nil {
+		err = syncOrderWithShipment(ctx, tx, *orderID, newStatus)
+	} else if requestID != nil {
+		err = syncStockRequestWithShipment(ctx, tx, *requestID, newStatus, threePLID, shipmentID)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// syncOrderWithShipment moves the order to shipped on pickup and to delivered on delivery
+func syncOrderWithShipment(ctx context.Context, tx pgx.Tx, orderID string, shipmentStatus models.ShipmentStatus) error {
+	var orderStatus models.OrderStatus
+	switch shipmentStatus {
+	case models.ShipmentStatusPickedUp:
+		orderStatus = models.OrderStatusShipped
+	case models.ShipmentStatusDelivered:
+		orderStatus = models.OrderStatusDelivered
+	default:
+		return nil
+	}
+
+	var currentStatus models.OrderStatus
+	if err := tx.QueryRow(ctx, "SELECT status FROM orders WHERE id = $1 FOR UPDATE", orderID).Scan(&currentStatus); err != nil {
+		return fmt.Errorf("failed to get order status: %w", err)
+	}
+	if currentStatus == models.OrderStatusCancelled {
+		return fmt.Errorf("%w: order %s has been cancelled", errInvalidShipmentTransition, orderID)
+	}
+
+	_, err := tx.Exec(ctx,
+		"UPDATE orders SET status = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2",
+		orderStatus, orderID)
+	if err != nil {
+		return fmt.Errorf("failed to update order status: %w", err)
+	}
+
+	if currentStatus == orderStatus {
+		return nil
+	}
+	if err := applyOrderStatusLoyalty(ctx, tx, orderID, orderStatus, "system"); err != nil {
+		return err
+	}
+	return notifyOrderStatusChange(ctx, tx, orderID, orderStatus)
+}
+
+// syncStockRequestWithShipment moves the stock request to In Transit on pickup and to
+// Delivered on delivery, recording the change in the stock request history
+func syncStockRequestWithShipment(ctx context.Context, tx pgx.Tx, requestID int, shipmentStatus models.ShipmentStatus, changedBy string, shipmentID int) error {
+	var fromStatus, toStatus string
+	switch shipmentStatus {
+	case models.ShipmentStatusPickedUp:
+		fromStatus, toStatus = "Ready for Pickup", "In Transit"
+	case models.ShipmentStatusDelivered:
+		fromStatus, toStatus = "In Transit", "Delivered"
+	default:
+		return nil
+	}
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE stock_requests
+		SET status = $1::stock_request_status, updated_at = CURRENT_TIMESTAMP
+		WHERE request_id = $2 AND status = $3::stock_request_status
+	`, toStatus, requestID, fromStatus)
+	if err != nil {
+		return fmt.Errorf("failed to update stock request status: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%w: stock request %d is not '%s'", errInvalidShipmentTransition, requestID, fromStatus)
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO stock_request_status_history (request_id, old_status, new_status, changed_by, note)
+		VALUES ($1, $2::stock_request_status, $3::stock_request_status, $4, $5)
+	`, requestID, fromStatus, toStatus, changedBy, fmt.Sprintf("Shipment %d %s", shipmentID, strings.ToLower(string(shipmentStatus))))
+	if err != nil {
+		return fmt.Errorf("failed to record stock request history: %w", err)
+	}
+
+	// Tell whoever raised the request that it is on its way or has arrived
+	var requestedBy *string
+	if err := tx.QueryRow(ctx, "SELECT requested_by::text FROM stock_requests WHERE request_id = $1", requestID).Scan(&requestedBy); err != nil {
+		return fmt.Errorf("failed to get stock request: %w", err)
+	}
+	if requestedBy == nil {
+		return nil
+	}
+
+	title := fmt.Sprintf("Stock request #%d in transit", requestID)
+	message := fmt.Sprintf("Shipment %d for stock request #%d has been picked up.", shipmentID, requestID)
+	if shipmentStatus == models.ShipmentStatusDelivered {
+		title = fmt.Sprintf("Stock request #%d delivered", requestID)
+		message = fmt.Sprintf("Shipment %d for stock request #%d has been delivered and is awaiting verification.", shipmentID, requestID)
+	}
+	return insertNotification(ctx, tx, *requestedBy, models.NotificationTypeShipment, title, message,
+		models.NotificationReferenceShipment, strconv.Itoa(shipmentID))
+}
+
+// notifyShipmentAssigned tells a 3PL user that a shipment has been assigned to them
+func notifyShipmentAssigned(ctx context.Context, q execer, shipmentID int, threePLID string) error {
+	return insertNotification(ctx, q, threePLID, models.NotificationTypeShipment,
+		"New shipment assigned",
+		fmt.Sprintf("Shipment %d has been assigned to you and is ready for pickup.", shipmentID),
+		models.NotificationReferenceShipment, strconv.Itoa(shipmentID))
+}
+
+// ensure3PLUser checks that the user exists and holds the 3PL role
+func (h *Handler) ensure3PLUser(ctx context.Context, userID string) error {
+	var role string
+	err := h.db.Pool.QueryRow(ctx, "SELECT role FROM users WHERE id = $1", userID).Scan(&role)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return fmt.Errorf("%w: user %s not found", errInvalidShipmentRequest, userID)
+		}
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if role != "3PL" {
+		return fmt.Errorf("%w: user %s does not have the 3PL role", errInvalidShipmentRequest, userID)
+	}
+	return nil
+}
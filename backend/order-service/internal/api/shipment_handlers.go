@@ -0,0 +1,385 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/expomadeinworld/madeinworld/order-service/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// GetShipments retrieves all shipments with filtering and pagination for admin
+func (h *Handler) GetShipments(c *gin.Context) {
+	h.listShipments(c, nil)
+}
+
+// GetMyShipments retrieves the shipments assigned to the authenticated 3PL user
+func (h *Handler) GetMyShipments(c *gin.Context) {
+	userID, ok := GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "Invalid user",
+			Message: "Could not extract user ID from token",
+		})
+		return
+	}
+
+	h.listShipments(c, &userID)
+}
+
+func (h *Handler) listShipments(c *gin.Context, threePLID *string) {
+	var req models.ShipmentListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid query parameters",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if req.Status != "" && !models.ShipmentStatus(req.Status).IsValid() {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid status",
+			Message: "Status must be one of: Assigned, Picked Up, Delivered",
+		})
+		return
+	}
+
+	// Set defaults
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.Limit == 0 {
+		req.Limit = 20
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	shipments, total, err := h.getShipments(ctx, &req, threePLID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to get shipments",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ShipmentListResponse{
+		Shipments:  shipments,
+		Total:      total,
+		Page:       req.Page,
+		Limit:      req.Limit,
+		TotalPages: (total + req.Limit - 1) / req.Limit,
+	})
+}
+
+// GetShipment retrieves a specific shipment by ID for admin
+func (h *Handler) GetShipment(c *gin.Context) {
+	h.showShipment(c, nil)
+}
+
+// GetMyShipment retrieves a shipment assigned to the authenticated 3PL user
+func (h *Handler) GetMyShipment(c *gin.Context) {
+	userID, ok := GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "Invalid user",
+			Message: "Could not extract user ID from token",
+		})
+		return
+	}
+
+	h.showShipment(c, &userID)
+}
+
+func (h *Handler) showShipment(c *gin.Context, threePLID *string) {
+	shipmentID, ok := parseShipmentID(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	shipment, err := h.getShipmentByID(ctx, shipmentID, threePLID)
+	if err != nil {
+		respondShipmentError(c, err, "Failed to get shipment")
+		return
+	}
+
+	c.JSON(http.StatusOK, shipment)
+}
+
+// CreateShipment creates a shipment for an order or a stock request and assigns it to a 3PL user
+func (h *Handler) CreateShipment(c *gin.Context) {
+	var req models.CreateShipmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request data",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if (req.OrderID == nil) == (req.StockRequestID == nil) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request data",
+			Message: "Exactly one of order_id or stock_request_id is required",
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	shipmentID, err := h.createShipment(ctx, &req)
+	if err != nil {
+		if strings.Contains(err.Error(), "idx_shipments_") {
+			c.JSON(http.StatusConflict, models.ErrorResponse{
+				Error:   "Shipment already exists",
+				Message: "A shipment has already been created for this order or stock request",
+			})
+			return
+		}
+		respondShipmentError(c, err, "Failed to create shipment")
+		return
+	}
+
+	shipment, err := h.getShipmentByID(ctx, shipmentID, nil)
+	if err != nil {
+		respondShipmentError(c, err, "Failed to get shipment")
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.SuccessResponse{
+		Message: "Shipment created successfully",
+		Data:    shipment,
+	})
+}
+
+// AssignShipment reassigns a shipment that has not been picked up yet to another 3PL user
+func (h *Handler) AssignShipment(c *gin.Context) {
+	shipmentID, ok := parseShipmentID(c)
+	if !ok {
+		return
+	}
+
+	var req models.AssignShipmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request data",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := h.assignShipment(ctx, shipmentID, req.Assigned3PLID); err != nil {
+		respondShipmentError(c, err, "Failed to assign shipment")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: "Shipment assigned successfully",
+	})
+}
+
+// PickUpShipment marks a shipment assigned to the authenticated 3PL user as picked up
+func (h *Handler) PickUpShipment(c *gin.Context) {
+	shipmentID, ok := parseShipmentID(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "Invalid user",
+			Message: "Could not extract user ID from token",
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := h.advanceShipment(ctx, shipmentID, userID, models.ShipmentStatusPickedUp, "", ""); err != nil {
+		respondShipmentError(c, err, "Failed to update shipment")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: "Shipment picked up",
+	})
+}
+
+// DeliverShipment marks a shipment assigned to the authenticated 3PL user as delivered.
+// It expects a multipart form with a proof-of-delivery "photo" and optional "notes".
+func (h *Handler) DeliverShipment(c *gin.Context) {
+	shipmentID, ok := parseShipmentID(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "Invalid user",
+			Message: "Could not extract user ID from token",
+		})
+		return
+	}
+
+	if err := c.Request.ParseMultipartForm(10 << 20); err != nil { // 10 MB max
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request data",
+			Message: "Expected a multipart form with a proof-of-delivery photo",
+		})
+		return
+	}
+
+	fileHeader, err := c.FormFile("photo")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Proof of delivery required",
+			Message: "A proof-of-delivery photo must be uploaded in the 'photo' field",
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// Make sure the shipment belongs to the caller before storing anything
+	if _, err := h.getShipmentByID(ctx, shipmentID, &userID); err != nil {
+		respondShipmentError(c, err, "Failed to get shipment")
+		return
+	}
+
+	proofURL, err := saveProofOfDelivery(shipmentID, fileHeader)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Failed to store proof of delivery",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err := h.advanceShipment(ctx, shipmentID, userID, models.ShipmentStatusDelivered, proofURL, c.PostForm("notes")); err != nil {
+		respondShipmentError(c, err, "Failed to update shipment")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: "Shipment delivered",
+		Data: gin.H{
+			"proof_of_delivery_url": proofURL,
+		},
+	})
+}
+
+// parseShipmentID reads the shipment_id path parameter
+func parseShipmentID(c *gin.Context) (int, bool) {
+	shipmentID, err := strconv.Atoi(c.Param("shipment_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid shipment ID",
+			Message: "Shipment ID must be a number",
+		})
+		return 0, false
+	}
+	return shipmentID, true
+}
+
+// respondShipmentError maps shipment errors to HTTP responses
+func respondShipmentError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, errShipmentNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   "Shipment not found",
+			Message: err.Error(),
+		})
+	case errors.Is(err, errInvalidShipmentTransition):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error:   "Invalid status transition",
+			Message: err.Error(),
+		})
+	case errors.Is(err, errInvalidShipmentRequest):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request data",
+			Message: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   fallback,
+			Message: err.Error(),
+		})
+	}
+}
+
+// saveProofOfDelivery stores a proof-of-delivery photo under uploads/shipments and returns its URL
+func saveProofOfDelivery(shipmentID int, fileHeader *multipart.FileHeader) (string, error) {
+	file, err := fileHeader.Open()
+	if err != nil {
+		return "", fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	// Read first 512 bytes to detect content type
+	buffer := make([]byte, 512)
+	n, err := file.Read(buffer)
+	if err != nil && err != io.EOF {
+		return "", fmt.Errorf("failed to read file: %w", err)
+	}
+
+	var ext string
+	switch http.DetectContentType(buffer[:n]) {
+	case "image/jpeg":
+		ext = ".jpg"
+	case "image/png":
+		ext = ".png"
+	case "image/webp":
+		ext = ".webp"
+	default:
+		return "", fmt.Errorf("invalid file type, only JPEG, PNG and WebP photos are allowed")
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", fmt.Errorf("failed to read file: %w", err)
+	}
+
+	uploadPath := fmt.Sprintf("uploads/shipments/%d_%d%s", shipmentID, time.Now().UnixNano(), ext)
+	if err := os.MkdirAll(filepath.Dir(uploadPath), 0755); err != nil {
+		return "", fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	dst, err := os.Create(uploadPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to create file: %w", err)
+	}
+	defer dst.Close()
+
+	if _, err := io.Copy(dst, file); err != nil {
+		return "", fmt.Errorf("failed to save file: %w", err)
+	}
+
+	// Use environment variable for base URL or default to localhost for development
+	baseURL := os.Getenv("SERVICE_BASE_URL")
+	if baseURL == "" {
+		baseURL = "http://localhost:8082"
+	}
+
+	return fmt.Sprintf("%s/%s", baseURL, uploadPath), nil
+}
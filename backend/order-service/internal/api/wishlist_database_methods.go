@@ -0,0 +1,284 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/expomadeinworld/madeinworld/order-service/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	errWishlistItemNotFound = errors.New("wishlist item not found")
+	errCartItemNotFound     = errors.New("cart item not found")
+)
+
+// wishlistItemColumns selects a wishlist item w with its product p as sold at the item's
+// store, in the column order scanned by scanWishlistItem
+const wishlistItemColumns = `w.id, w.product_id, w.mini_app_type, w.store_id, w.quantity, w.saved_from_cart,
+		       w.notify_back_in_stock, w.notify_price_drop, w.price_when_added, w.created_at, w.updated_at,
+		       p.product_uuid, p.sku, p.title, ` + storeProductColumns + `,
+		       COALESCE(m.enforces_stock, false)`
+
+// wishlistItemJoins joins the product, its price and stock at the item's store and the
+// mini-app of the item, or of the product for items on the general wishlist
+var wishlistItemJoins = `JOIN products p ON p.product_uuid = w.product_id
+		` + storeProductJoins("w.store_id") + `
+		LEFT JOIN mini_apps m ON m.code = COALESCE(w.mini_app_type, p.mini_app_type)`
+
+func scanWishlistItem(row pgx.Row) (*models.WishlistItem, error) {
+	var item models.WishlistItem
+	var product models.Product
+	var enforcesStock bool
+	err := row.Scan(&item.ID, &item.ProductID, &item.MiniAppType, &item.StoreID, &item.Quantity, &item.SavedFromCart,
+		&item.NotifyBackInStock, &item.NotifyPriceDrop, &item.PriceWhenAdded, &item.CreatedAt, &item.UpdatedAt,
+		&product.ID, &product.SKU, &product.Title, &product.MainPrice, &product.StockLeft,
+		&product.MinimumOrderQuantity, &product.IsActive, &product.SafetyStock, &enforcesStock)
+	if err != nil {
+		return nil, err
+	}
+	item.InStock = wishlistInStock(&product, enforcesStock)
+	item.Product = &product
+	return &item, nil
+}
+
+// wishlistInStock reports whether a wishlisted product can be bought: it is active and, when
+// its mini-app enforces stock, has display stock
+func wishlistInStock(product *models.Product, enforcesStock bool) bool {
+	return product.IsActive && (!enforcesStock || product.HasStock())
+}
+
+// getWishlist retrieves a user's wishlist, newest first
+func (h *Handler) getWishlist(ctx context.Context, userID string, req *models.WishlistRequest) ([]models.WishlistItem, error) {
+	rows, err := h.db.Pool.Query(ctx, `
+		SELECT `+wishlistItemColumns+`
+		FROM wishlist_items w
+		`+wishlistItemJoins+`
+		WHERE w.user_id = $1
+		  AND ($2 = '' OR ($2 = 'general' AND w.mini_app_type IS NULL) OR w.mini_app_type = $2)
+		  AND ($3::boolean IS NULL OR w.saved_from_cart = $3)
+		ORDER BY w.created_at DESC
+	`, userID, req.MiniAppType, req.SavedForLater)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query wishlist: %w", err)
+	}
+	defer rows.Close()
+
+	items := []models.WishlistItem{}
+	for rows.Next() {
+		item, err := scanWishlistItem(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan wishlist item: %w", err)
+		}
+		items = append(items, *item)
+	}
+
+	return items, rows.Err()
+}
+
+// getWishlistItem retrieves an item of a user's wishlist
+func (h *Handler) getWishlistItem(ctx context.Context, userID, itemID string) (*models.WishlistItem, error) {
+	item, err := scanWishlistItem(h.db.Pool.QueryRow(ctx, `
+		SELECT `+wishlistItemColumns+`
+		FROM wishlist_items w
+		`+wishlistItemJoins+`
+		WHERE w.id::text = $1 AND w.user_id = $2
+	`, itemID, userID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errWishlistItemNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wishlist item: %w", err)
+	}
+	return item, nil
+}
+
+// wishlistEntry describes a wishlist item to add
+type wishlistEntry struct {
+	productID         string
+	miniAppType       *models.MiniAppType
+	storeID           *int
+	quantity          int
+	savedFromCart     bool
+	notifyBackInStock bool
+	notifyPriceDrop   bool
+	price             float64 // current price, remembered for price-drop alerts
+	inStock           *bool   // current availability when known, remembered for back-in-stock alerts
+}
+
+// upsertWishlistItem adds a product to a user's wishlist, or updates the item when the
+// product is already on it for the same mini-app and store. It returns the item ID.
+func upsertWishlistItem(ctx context.Context, tx pgx.Tx, userID string, entry wishlistEntry) (string, error) {
+	var miniAppType *string
+	if entry.miniAppType != nil {
+		code := string(*entry.miniAppType)
+		miniAppType = &code
+	}
+
+	var itemID string
+	err := tx.QueryRow(ctx, `
+		INSERT INTO wishlist_items
+			(user_id, product_id, mini_app_type, store_id, quantity, saved_from_cart,
+			 notify_back_in_stock, notify_price_drop, price_when_added, last_seen_price, last_seen_in_stock)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $9, $10)
+		ON CONFLICT (user_id, product_id, COALESCE(mini_app_type, ''), COALESCE(store_id, 0)) DO UPDATE
+		SET quantity = EXCLUDED.quantity,
+		    saved_from_cart = wishlist_items.saved_from_cart OR EXCLUDED.saved_from_cart,
+		    notify_back_in_stock = EXCLUDED.notify_back_in_stock,
+		    notify_price_drop = EXCLUDED.notify_price_drop,
+		    updated_at = CURRENT_TIMESTAMP
+		RETURNING id::text
+	`, userID, entry.productID, miniAppType, entry.storeID, entry.quantity, entry.savedFromCart,
+		entry.notifyBackInStock, entry.notifyPriceDrop, entry.price, entry.inStock).Scan(&itemID)
+	if err != nil {
+		return "", fmt.Errorf("failed to save wishlist item: %w", err)
+	}
+	return itemID, nil
+}
+
+// addToWishlist adds a product to a user's wishlist and returns the item
+func (h *Handler) addToWishlist(ctx context.Context, userID string, entry wishlistEntry) (*models.WishlistItem, error) {
+	tx, err := h.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	itemID, err := upsertWishlistItem(ctx, tx, userID, entry)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return h.getWishlistItem(ctx, userID, itemID)
+}
+
+// updateWishlistItem changes the quantity or alerts of a wishlist item
+func (h *Handler) updateWishlistItem(ctx context.Context, userID, itemID string, req *models.UpdateWishlistItemRequest) (*models.WishlistItem, error) {
+	tag, err := h.db.Pool.Exec(ctx, `
+		UPDATE wishlist_items
+		SET quantity = COALESCE($3, quantity),
+		    notify_back_in_stock = COALESCE($4, notify_back_in_stock),
+		    notify_price_drop = COALESCE($5, notify_price_drop),
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE id::text = $1 AND user_id = $2
+	`, itemID, userID, req.Quantity, req.NotifyBackInStock, req.NotifyPriceDrop)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update wishlist item: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, errWishlistItemNotFound
+	}
+
+	return h.getWishlistItem(ctx, userID, itemID)
+}
+
+// removeFromWishlist removes an item from a user's wishlist
+func (h *Handler) removeFromWishlist(ctx context.Context, userID, itemID string) error {
+	tag, err := h.db.Pool.Exec(ctx, "DELETE FROM wishlist_items WHERE id::text = $1 AND user_id = $2", itemID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to remove wishlist item: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errWishlistItemNotFound
+	}
+	return nil
+}
+
+// saveCartItemForLater moves a product out of a user's cart into the wishlist, keeping the
+// mini-app, store and quantity. entry carries the product's current price and availability.
+func (h *Handler) saveCartItemForLater(ctx context.Context, userID string, miniAppType models.MiniAppType, entry wishlistEntry) (*models.WishlistItem, error) {
+	tx, err := h.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// A product is in the cart once per store; it is saved under the store it was added
+	// from last
+	var quantity int
+	var storeID *int
+	err = tx.QueryRow(ctx, `
+		WITH removed AS (
+			DELETE FROM carts
+			WHERE user_id = $1 AND mini_app_type = $2 AND product_id = $3
+			RETURNING quantity, store_id, updated_at
+		)
+		SELECT quantity, store_id FROM removed
+		ORDER BY updated_at DESC
+		LIMIT 1
+	`, userID, string(miniAppType), entry.productID).Scan(&quantity, &storeID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errCartItemNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to remove cart item: %w", err)
+	}
+
+	entry.miniAppType = &miniAppType
+	entry.storeID = storeID
+	entry.quantity = quantity
+	entry.savedFromCart = true
+	itemID, err := upsertWishlistItem(ctx, tx, userID, entry)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	// Activity tracking is secondary to the cart change itself
+	if err := h.touchCartActivity(ctx, userID, miniAppType); err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	}
+
+	return h.getWishlistItem(ctx, userID, itemID)
+}
+
+// getWishlistStatistics summarizes wishlists and lists the most wished products
+func (h *Handler) getWishlistStatistics(ctx context.Context, req *models.WishlistStatisticsRequest) (*models.WishlistStatistics, error) {
+	stats := &models.WishlistStatistics{}
+
+	const filter = `($1 = '' OR w.mini_app_type = $1) AND ($2::int IS NULL OR w.store_id = $2)`
+
+	err := h.db.Pool.QueryRow(ctx, `
+		SELECT COUNT(*), COUNT(*) FILTER (WHERE w.saved_from_cart), COUNT(DISTINCT w.user_id)
+		FROM wishlist_items w
+		WHERE `+filter, req.MiniAppType, req.StoreID).Scan(&stats.TotalItems, &stats.SavedForLaterItems, &stats.Users)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wishlist totals: %w", err)
+	}
+
+	rows, err := h.db.Pool.Query(ctx, `
+		SELECT p.product_uuid, p.sku, p.title,
+		       COUNT(DISTINCT w.user_id),
+		       COUNT(*) FILTER (WHERE w.saved_from_cart),
+		       COALESCE(SUM(w.quantity), 0)
+		FROM wishlist_items w
+		JOIN products p ON p.product_uuid = w.product_id
+		WHERE `+filter+`
+		GROUP BY p.product_uuid, p.sku, p.title
+		ORDER BY COUNT(DISTINCT w.user_id) DESC, SUM(w.quantity) DESC, p.title
+		LIMIT $3
+	`, req.MiniAppType, req.StoreID, req.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query most wished products: %w", err)
+	}
+	defer rows.Close()
+
+	stats.TopProducts = []models.WishedProduct{}
+	for rows.Next() {
+		var p models.WishedProduct
+		if err := rows.Scan(&p.ProductID, &p.SKU, &p.Title, &p.WishlistCount, &p.SavedForLaterCount, &p.TotalQuantity); err != nil {
+			return nil, fmt.Errorf("failed to scan wished product: %w", err)
+		}
+		stats.TopProducts = append(stats.TopProducts, p)
+	}
+
+	return stats, rows.Err()
+}
@@ -0,0 +1,427 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/expomadeinworld/madeinworld/order-service/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// GetWishlist retrieves the authenticated user's wishlist
+func (h *Handler) GetWishlist(c *gin.Context) {
+	userID, ok := GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "Invalid user",
+			Message: "Could not extract user ID from token",
+		})
+		return
+	}
+
+	var req models.WishlistRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid query parameters",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	items, err := h.getWishlist(ctx, userID, &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to get wishlist",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: "Wishlist retrieved successfully",
+		Data: models.WishlistResponse{
+			Items: items,
+		},
+	})
+}
+
+// AddToWishlist adds a product to the authenticated user's wishlist, or updates the item
+// when the product is already on it for the same mini-app and store
+func (h *Handler) AddToWishlist(c *gin.Context) {
+	userID, ok := GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "Invalid user",
+			Message: "Could not extract user ID from token",
+		})
+		return
+	}
+
+	var req models.AddToWishlistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	entry := wishlistEntry{
+		productID:         req.ProductID,
+		miniAppType:       req.MiniAppType,
+		quantity:          1,
+		notifyBackInStock: req.NotifyBackInStock == nil || *req.NotifyBackInStock,
+		notifyPriceDrop:   req.NotifyPriceDrop == nil || *req.NotifyPriceDrop,
+	}
+	if req.Quantity > 0 {
+		entry.quantity = req.Quantity
+	}
+
+	// Items of a mini-app are priced like its cart, at the store for location-based mini-apps
+	var miniApp *models.MiniApp
+	if req.MiniAppType != nil {
+		var err error
+		miniApp, err = h.getMiniApp(ctx, *req.MiniAppType)
+		if err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, errMiniAppNotFound) {
+				status = http.StatusBadRequest
+			}
+			c.JSON(status, models.ErrorResponse{
+				Error:   "Invalid mini-app type",
+				Message: err.Error(),
+			})
+			return
+		}
+		if miniApp.RequiresStore && req.StoreID == nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "Store ID required",
+				Message: "This mini-app requires a store selection",
+			})
+			return
+		}
+		entry.storeID = miniApp.StoreScope(req.StoreID)
+	}
+
+	product, err := h.getProductAtStore(ctx, req.ProductID, entry.storeID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   "Product not found",
+			Message: err.Error(),
+		})
+		return
+	}
+	entry.price = product.MainPrice
+	// Availability on the general wishlist depends on the product's own mini-app and is
+	// first recorded by the wishlist alert check
+	if miniApp != nil {
+		inStock := wishlistInStock(product, miniApp.EnforcesStock)
+		entry.inStock = &inStock
+	}
+
+	item, err := h.addToWishlist(ctx, userID, entry)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to add item to wishlist",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: "Item added to wishlist successfully",
+		Data:    item,
+	})
+}
+
+// UpdateWishlistItem changes the quantity or alerts of a wishlist item
+func (h *Handler) UpdateWishlistItem(c *gin.Context) {
+	userID, ok := GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "Invalid user",
+			Message: "Could not extract user ID from token",
+		})
+		return
+	}
+
+	var req models.UpdateWishlistItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	item, err := h.updateWishlistItem(ctx, userID, c.Param("item_id"), &req)
+	if err != nil {
+		respondWishlistError(c, err, "Failed to update wishlist item")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: "Wishlist item updated successfully",
+		Data:    item,
+	})
+}
+
+// RemoveFromWishlist removes an item from the authenticated user's wishlist
+func (h *Handler) RemoveFromWishlist(c *gin.Context) {
+	userID, ok := GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "Invalid user",
+			Message: "Could not extract user ID from token",
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := h.removeFromWishlist(ctx, userID, c.Param("item_id")); err != nil {
+		respondWishlistError(c, err, "Failed to remove item from wishlist")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: "Item removed from wishlist successfully",
+	})
+}
+
+// SaveForLater moves a product out of the user's cart for a mini-app into the wishlist
+func (h *Handler) SaveForLater(c *gin.Context) {
+	miniApp, ok := h.ValidateMiniAppType(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "Invalid user",
+			Message: "Could not extract user ID from token",
+		})
+		return
+	}
+
+	var req models.SaveForLaterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// The price and availability to remember are those of the store the item was added from
+	var storeID *int
+	var err error
+	if miniApp.RequiresStore {
+		storeID, err = h.getCartItemStoreID(ctx, userID, miniApp.Code, req.ProductID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Error:   "Failed to get cart item",
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	product, err := h.getProductAtStore(ctx, req.ProductID, storeID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   "Product not found",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	inStock := wishlistInStock(product, miniApp.EnforcesStock)
+	item, err := h.saveCartItemForLater(ctx, userID, miniApp.Code, wishlistEntry{
+		productID:         req.ProductID,
+		notifyBackInStock: true,
+		notifyPriceDrop:   true,
+		price:             product.MainPrice,
+		inStock:           &inStock,
+	})
+	if err != nil {
+		respondWishlistError(c, err, "Failed to save item for later")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: "Item saved for later",
+		Data:    item,
+	})
+}
+
+// MoveWishlistItemToCart adds a wishlist item to the cart of its mini-app and removes it
+// from the wishlist. Items on the general wishlist name the mini-app in the request.
+func (h *Handler) MoveWishlistItemToCart(c *gin.Context) {
+	userID, ok := GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "Invalid user",
+			Message: "Could not extract user ID from token",
+		})
+		return
+	}
+
+	var req models.MoveToCartRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	item, err := h.getWishlistItem(ctx, userID, c.Param("item_id"))
+	if err != nil {
+		respondWishlistError(c, err, "Failed to get wishlist item")
+		return
+	}
+
+	miniAppType, storeID := item.MiniAppType, item.StoreID
+	if miniAppType == nil {
+		miniAppType, storeID = req.MiniAppType, req.StoreID
+	}
+	if miniAppType == nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Mini-app type required",
+			Message: "Items on the general wishlist need a mini_app_type to move to a cart",
+		})
+		return
+	}
+
+	miniApp, err := h.getMiniApp(ctx, *miniAppType)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, errMiniAppNotFound) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, models.ErrorResponse{
+			Error:   "Invalid mini-app type",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	// Deactivated mini-apps keep their carts and orders readable but take no new ones
+	if !miniApp.IsActive {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Mini-app unavailable",
+			Message: "This mini-app is currently not available",
+		})
+		return
+	}
+
+	if miniApp.RequiresStore && storeID == nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Store ID required",
+			Message: "This mini-app requires a store selection",
+		})
+		return
+	}
+	storeID = miniApp.StoreScope(storeID)
+
+	quantity := item.Quantity
+	if req.Quantity > 0 {
+		quantity = req.Quantity
+	}
+
+	if !h.checkCartAddition(ctx, c, miniApp, userID, item.ProductID, quantity, storeID) {
+		return
+	}
+
+	if err := h.addItemToCart(ctx, userID, miniApp.Code, item.ProductID, quantity, storeID); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to add item to cart",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err := h.removeFromWishlist(ctx, userID, item.ID); err != nil && !errors.Is(err, errWishlistItemNotFound) {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to remove item from wishlist",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: "Item moved to cart successfully",
+	})
+}
+
+// GetWishlistStatistics returns wishlist totals and the most wished products for admin
+func (h *Handler) GetWishlistStatistics(c *gin.Context) {
+	var req models.WishlistStatisticsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid query parameters",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	// Set defaults
+	if req.Limit == 0 {
+		req.Limit = 10
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	stats, err := h.getWishlistStatistics(ctx, &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to get wishlist statistics",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// respondWishlistError maps wishlist errors to HTTP responses
+func respondWishlistError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, errWishlistItemNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   "Wishlist item not found",
+			Message: err.Error(),
+		})
+	case errors.Is(err, errCartItemNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   "Cart item not found",
+			Message: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   fallback,
+			Message: err.Error(),
+		})
+	}
+}
@@ -4,29 +4,27 @@ import (
 	"time"
 )
 
-// MiniAppType represents the type of mini-app
+// MiniAppType is the code of a mini-app registered in the mini_apps table
 type MiniAppType string
 
-const (
-	MiniAppTypeRetailStore     MiniAppType = "RetailStore"
-	MiniAppTypeUnmannedStore   MiniAppType = "UnmannedStore"
-	MiniAppTypeExhibitionSales MiniAppType = "ExhibitionSales"
-	MiniAppTypeGroupBuying     MiniAppType = "GroupBuying"
-)
-
-// IsValid checks if the mini-app type is valid
-func (m MiniAppType) IsValid() bool {
-	switch m {
-	case MiniAppTypeRetailStore, MiniAppTypeUnmannedStore, MiniAppTypeExhibitionSales, MiniAppTypeGroupBuying:
-		return true
-	default:
-		return false
-	}
+// MiniApp is the part of a mini_apps registry entry that drives cart and order behaviour.
+// The registry is edited through catalog-service.
+type MiniApp struct {
+	Code          MiniAppType `json:"code"`
+	DisplayName   string      `json:"display_name"`
+	RequiresStore bool        `json:"requires_store"` // carts and orders are kept per store
+	EnforcesStock bool        `json:"enforces_stock"` // carts and orders are limited to available stock
+	EnforcesMOQ   bool        `json:"enforces_moq"`   // products' minimum_order_quantity applies
+	IsActive      bool        `json:"is_active"`
 }
 
-// RequiresStore returns true if the mini-app type requires a store_id
-func (m MiniAppType) RequiresStore() bool {
-	return m == MiniAppTypeUnmannedStore || m == MiniAppTypeExhibitionSales
+// StoreScope returns the store carts and orders of the mini-app are kept under, nil when the
+// mini-app does not require a store
+func (m *MiniApp) StoreScope(storeID *int) *int {
+	if !m.RequiresStore {
+		return nil
+	}
+	return storeID
 }
 
 // OrderStatus represents the status of an order
@@ -70,14 +68,43 @@ type CartItem struct {
 
 // Order represents a completed order
 type Order struct {
-	ID          string      `json:"id" db:"id"`
-	UserID      string      `json:"user_id" db:"user_id"`
-	MiniAppType MiniAppType `json:"mini_app_type" db:"mini_app_type"`
-	TotalAmount float64     `json:"total_amount" db:"total_amount"`
-	Status      OrderStatus `json:"status" db:"status"`
-	Items       []OrderItem `json:"items"`
-	CreatedAt   time.Time   `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time   `json:"updated_at" db:"updated_at"`
+	ID                string           `json:"id" db:"id"`
+	UserID            string           `json:"user_id" db:"user_id"`
+	MiniAppType       MiniAppType      `json:"mini_app_type" db:"mini_app_type"`
+	StoreID           *int             `json:"store_id,omitempty" db:"store_id"`
+	FulfillmentMethod *string          `json:"fulfillment_method,omitempty" db:"fulfillment_method"` // nil for orders placed before delivery details
+	DeliveryAddress   *DeliveryAddress `json:"delivery_address,omitempty" db:"delivery_address"`
+	TotalAmount       float64          `json:"total_amount" db:"total_amount"`
+	Status            OrderStatus      `json:"status" db:"status"`
+	PointsRedeemed    int              `json:"loyalty_points_redeemed,omitempty" db:"loyalty_points_redeemed"`
+	LoyaltyDiscount   float64          `json:"loyalty_discount,omitempty" db:"loyalty_discount"` // already deducted from TotalAmount
+	Items             []OrderItem      `json:"items"`
+	CreatedAt         time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time        `json:"updated_at" db:"updated_at"`
+}
+
+// Fulfillment methods of an order
+const (
+	FulfillmentDelivery = "delivery" // delivered to DeliveryAddress
+	FulfillmentPickup   = "pickup"   // picked up at the order's store
+)
+
+// DeliveryAddress is the copy of a user-service address book entry stored on an order, so
+// later edits of the address book do not change where the order goes
+type DeliveryAddress struct {
+	AddressID            string   `json:"address_id,omitempty"`
+	Label                *string  `json:"label,omitempty"`
+	RecipientName        string   `json:"recipient_name,omitempty"`
+	Phone                *string  `json:"phone,omitempty"`
+	Line1                string   `json:"line1,omitempty"`
+	Line2                *string  `json:"line2,omitempty"`
+	City                 string   `json:"city"`
+	Region               *string  `json:"region,omitempty"`
+	PostalCode           *string  `json:"postal_code,omitempty"`
+	CountryCode          string   `json:"country_code"`
+	Latitude             *float64 `json:"latitude,omitempty"`
+	Longitude            *float64 `json:"longitude,omitempty"`
+	DeliveryInstructions *string  `json:"delivery_instructions,omitempty"`
 }
 
 // OrderItem represents an item in an order
@@ -100,11 +127,12 @@ type Product struct {
 	StockLeft            int     `json:"stock_left" db:"stock_left"`
 	MinimumOrderQuantity int     `json:"minimum_order_quantity" db:"minimum_order_quantity"`
 	IsActive             bool    `json:"is_active" db:"is_active"`
+	SafetyStock          int     `json:"safety_stock"` // Configured in catalog-service stock thresholds
 }
 
-// DisplayStock returns the stock quantity with buffer applied (actual - 5)
+// DisplayStock returns the stock quantity with the product's safety stock held back
 func (p *Product) DisplayStock() int {
-	displayStock := p.StockLeft - 5
+	displayStock := p.StockLeft - p.SafetyStock
 	if displayStock < 0 {
 		displayStock = 0
 	}
@@ -134,7 +162,10 @@ type UpdateCartItemRequest struct {
 
 // CreateOrderRequest represents a request to create an order
 type CreateOrderRequest struct {
-	StoreID *int `json:"store_id,omitempty"` // Required for location-based mini-apps
+	StoreID           *int    `json:"store_id,omitempty"`           // Required for location-based mini-apps
+	FulfillmentMethod string  `json:"fulfillment_method,omitempty"` // delivery or pickup; defaults to pickup for location-based mini-apps without address_id
+	AddressID         *string `json:"address_id,omitempty"`         // Address book entry to deliver to; defaults to the user's default address
+	RedeemPoints      int     `json:"redeem_points,omitempty"`      // Loyalty points to redeem as a discount
 }
 
 // ErrorResponse represents an error response
@@ -165,6 +196,7 @@ type AdminOrderListRequest struct {
 	Search      string `form:"search"`     // Search in order ID, user email, product names
 	SortBy      string `form:"sort_by"`    // created_at, total_amount, status
 	SortOrder   string `form:"sort_order"` // asc, desc
+	StoreIDs    []int  `form:"-"`          // set for partners, limits results to their stores
 }
 
 // AdminOrderResponse represents an order in admin list view
@@ -181,6 +213,9 @@ type AdminOrderResponse struct {
 	ItemCount   int         `json:"item_count"`
 	CreatedAt   time.Time   `json:"created_at"`
 	UpdatedAt   time.Time   `json:"updated_at"`
+
+	FulfillmentMethod *string          `json:"fulfillment_method,omitempty"`
+	DeliveryAddress   *DeliveryAddress `json:"delivery_address,omitempty"`
 }
 
 // AdminOrderListResponse represents the response for admin order listing
@@ -223,20 +258,39 @@ type BulkUpdateOrdersRequest struct {
 	Reason   string      `json:"reason,omitempty"`
 }
 
+// OrderStatisticsRequest represents query parameters for the admin order statistics
+type OrderStatisticsRequest struct {
+	DateFrom    string `form:"date_from"`   // YYYY-MM-DD format, defaults to 29 days before date_to
+	DateTo      string `form:"date_to"`     // YYYY-MM-DD format, defaults to today in the timezone
+	Granularity string `form:"granularity"` // day, week, month (default day)
+	Timezone    string `form:"timezone"`    // IANA name such as Europe/Rome (default UTC)
+	TopLimit    int    `form:"top_limit" binding:"omitempty,min=1,max=50"`
+	StoreIDs    []int  `form:"-"` // set for partners, limits figures to their stores
+}
+
 // OrderStatistics represents order statistics for admin dashboard
 type OrderStatistics struct {
-	TotalOrders      int                     `json:"total_orders"`
-	TotalRevenue     float64                 `json:"total_revenue"`
-	OrdersByStatus   map[OrderStatus]int     `json:"orders_by_status"`
-	OrdersByMiniApp  map[MiniAppType]int     `json:"orders_by_mini_app"`
-	RevenueByMiniApp map[MiniAppType]float64 `json:"revenue_by_mini_app"`
-	DailyStats       []DailyOrderStats       `json:"daily_stats"`
-	TopProducts      []ProductOrderStats     `json:"top_products"`
+	DateFrom           string                  `json:"date_from"`
+	DateTo             string                  `json:"date_to"`
+	Granularity        string                  `json:"granularity"`
+	Timezone           string                  `json:"timezone"`
+	TotalOrders        int                     `json:"total_orders"`
+	TotalRevenue       float64                 `json:"total_revenue"`
+	AverageOrderValue  float64                 `json:"average_order_value"`
+	OrdersByStatus     map[OrderStatus]int     `json:"orders_by_status"`
+	OrdersByMiniApp    map[MiniAppType]int     `json:"orders_by_mini_app"`
+	RevenueByMiniApp   map[MiniAppType]float64 `json:"revenue_by_mini_app"`
+	OrdersByStore      []StoreOrderStats       `json:"orders_by_store"`
+	DailyStats         []DailyOrderStats       `json:"daily_stats"` // One entry per granularity bucket
+	TopProducts        []ProductOrderStats     `json:"top_products"`
+	TopProductsByUnits []ProductOrderStats     `json:"top_products_by_units"`
+	PreviousPeriod     PeriodComparison        `json:"previous_period"`
+	DataRefreshedAt    *time.Time              `json:"data_refreshed_at"` // Last analytics rollup refresh
 }
 
-// DailyOrderStats represents daily order statistics
+// DailyOrderStats represents order statistics for one time bucket
 type DailyOrderStats struct {
-	Date       string  `json:"date"`
+	Date       string  `json:"date"` // Start of the bucket in the requested timezone
 	OrderCount int     `json:"order_count"`
 	Revenue    float64 `json:"revenue"`
 }
@@ -244,11 +298,31 @@ type DailyOrderStats struct {
 // ProductOrderStats represents product order statistics
 type ProductOrderStats struct {
 	ProductID    string  `json:"product_id"`
+	SKU          string  `json:"sku"`
 	ProductTitle string  `json:"product_title"`
 	OrderCount   int     `json:"order_count"`
+	UnitsSold    int     `json:"units_sold"`
 	TotalRevenue float64 `json:"total_revenue"`
 }
 
+// StoreOrderStats represents order statistics for one store
+type StoreOrderStats struct {
+	StoreID    *int    `json:"store_id"` // nil for orders without a store
+	StoreName  string  `json:"store_name"`
+	OrderCount int     `json:"order_count"`
+	Revenue    float64 `json:"revenue"`
+}
+
+// PeriodComparison compares the requested period with the previous period of equal length
+type PeriodComparison struct {
+	DateFrom             string   `json:"date_from"`
+	DateTo               string   `json:"date_to"`
+	TotalOrders          int      `json:"total_orders"`
+	TotalRevenue         float64  `json:"total_revenue"`
+	OrdersChangePercent  *float64 `json:"orders_change_percent"`  // nil when the previous period had no orders
+	RevenueChangePercent *float64 `json:"revenue_change_percent"` // nil when the previous period had no revenue
+}
+
 // Admin Cart Models
 
 // AdminCartListRequest represents request parameters for admin cart listing
@@ -263,6 +337,7 @@ type AdminCartListRequest struct {
 	Search      string `form:"search"`     // Search in user email, product names
 	SortBy      string `form:"sort_by"`    // created_at, updated_at, total_value
 	SortOrder   string `form:"sort_order"` // asc, desc
+	StoreIDs    []int  `form:"-"`          // set for partners, limits results to their stores
 }
 
 // AdminCartResponse represents a cart in admin list view
@@ -308,5 +383,438 @@ type CartStatistics struct {
 	AverageCartValue   float64                 `json:"average_cart_value"`
 	CartsByMiniApp     map[MiniAppType]int     `json:"carts_by_mini_app"`
 	CartValueByMiniApp map[MiniAppType]float64 `json:"cart_value_by_mini_app"`
-	AbandonedCarts     int                     `json:"abandoned_carts"` // Carts older than 7 days
+	AbandonedCarts     int                     `json:"abandoned_carts"`      // Carts currently classified as abandoned
+	AbandonedCartValue float64                 `json:"abandoned_cart_value"` // Current value of abandoned carts
+	RecoveryEmailsSent int                     `json:"recovery_emails_sent"` // Recovery emails sent in the period
+	RecoveredCarts     int                     `json:"recovered_carts"`      // Carts converted after a recovery email in the period
+}
+
+// Cart Abandonment Models
+
+// CartActivityStatus represents where a cart session is in its lifecycle
+type CartActivityStatus string
+
+const (
+	CartActivityActive    CartActivityStatus = "active"
+	CartActivityAbandoned CartActivityStatus = "abandoned"
+	CartActivityExpired   CartActivityStatus = "expired"
+	CartActivityEmptied   CartActivityStatus = "emptied"
+	CartActivityConverted CartActivityStatus = "converted"
+)
+
+// IsValid checks if the cart activity status is valid
+func (s CartActivityStatus) IsValid() bool {
+	switch s {
+	case CartActivityActive, CartActivityAbandoned, CartActivityExpired, CartActivityEmptied, CartActivityConverted:
+		return true
+	default:
+		return false
+	}
+}
+
+// AbandonedCartListRequest represents query parameters for the abandoned cart list
+type AbandonedCartListRequest struct {
+	Page        int    `form:"page" binding:"omitempty,min=1"`
+	Limit       int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Status      string `form:"status"` // defaults to abandoned
+	MiniAppType string `form:"mini_app_type"`
+	StoreID     *int   `form:"store_id"`
+	Recovered   *bool  `form:"recovered"`
+	StoreIDs    []int  `form:"-"` // set for partners, limits results to their stores
+}
+
+// AbandonedCart represents a tracked cart session in the admin abandoned cart list
+type AbandonedCart struct {
+	ID                  int                `json:"id"`
+	UserID              string             `json:"user_id"`
+	UserEmail           string             `json:"user_email"`
+	UserName            string             `json:"user_name"`
+	MiniAppType         MiniAppType        `json:"mini_app_type"`
+	StoreID             *int               `json:"store_id,omitempty"`
+	StoreName           string             `json:"store_name,omitempty"`
+	Status              CartActivityStatus `json:"status"`
+	ItemCount           int                `json:"item_count"`
+	CartValue           float64            `json:"cart_value"` // order total once converted
+	StartedAt           time.Time          `json:"started_at"`
+	LastActivityAt      time.Time          `json:"last_activity_at"`
+	AbandonedAt         *time.Time         `json:"abandoned_at,omitempty"`
+	RecoveryEmailSentAt *time.Time         `json:"recovery_email_sent_at,omitempty"`
+	ConvertedAt         *time.Time         `json:"converted_at,omitempty"`
+	ConvertedOrderID    *string            `json:"converted_order_id,omitempty"`
+	Recovered           bool               `json:"recovered"`
+}
+
+// AbandonedCartListResponse represents the response for the abandoned cart list
+type AbandonedCartListResponse struct {
+	Carts      []AbandonedCart `json:"carts"`
+	Total      int             `json:"total"`
+	TotalValue float64         `json:"total_value"`
+	Page       int             `json:"page"`
+	Limit      int             `json:"limit"`
+	TotalPages int             `json:"total_pages"`
+}
+
+// Manufacturer Portal Models
+
+// ManufacturerSalesRequest represents query parameters for a manufacturer's sales report
+type ManufacturerSalesRequest struct {
+	DateFrom    string `form:"date_from"` // YYYY-MM-DD format
+	DateTo      string `form:"date_to"`   // YYYY-MM-DD format
+	MiniAppType string `form:"mini_app_type"`
+}
+
+// ManufacturerProductSales represents sales figures for one of a manufacturer's products
+type ManufacturerProductSales struct {
+	ProductID    int        `json:"product_id"`
+	ProductUUID  string     `json:"product_uuid"`
+	SKU          string     `json:"sku"`
+	ProductTitle string     `json:"product_title"`
+	UnitsSold    int        `json:"units_sold"`
+	OrderCount   int        `json:"order_count"`
+	Revenue      float64    `json:"revenue"`
+	LastSoldAt   *time.Time `json:"last_sold_at,omitempty"`
+}
+
+// ManufacturerSalesResponse represents the sales report returned to a manufacturer
+type ManufacturerSalesResponse struct {
+	ManufacturerID int                        `json:"manufacturer_id"`
+	DateFrom       string                     `json:"date_from,omitempty"`
+	DateTo         string                     `json:"date_to,omitempty"`
+	TotalUnits     int                        `json:"total_units"`
+	TotalRevenue   float64                    `json:"total_revenue"`
+	Products       []ManufacturerProductSales `json:"products"`
+}
+
+// Analytics Rollup Models
+
+// RollupBackfillRequest represents a request to recompute analytics rollups for a date range
+type RollupBackfillRequest struct {
+	DateFrom string `json:"date_from" binding:"required"` // YYYY-MM-DD format, UTC
+	DateTo   string `json:"date_to" binding:"required"`   // YYYY-MM-DD format, UTC
+}
+
+// RollupStatusResponse represents the state of the analytics rollups
+type RollupStatusResponse struct {
+	LastRunAt    *time.Time `json:"last_run_at"`
+	OldestBucket *time.Time `json:"oldest_bucket"`
+	NewestBucket *time.Time `json:"newest_bucket"`
+	HourlyRows   int        `json:"hourly_rows"`
+	DailyRows    int        `json:"daily_rows"`
+}
+
+// Shipment Models
+
+// ShipmentStatus mirrors the shipment_status database enum
+type ShipmentStatus string
+
+const (
+	ShipmentStatusAssigned  ShipmentStatus = "Assigned"
+	ShipmentStatusPickedUp  ShipmentStatus = "Picked Up"
+	ShipmentStatusDelivered ShipmentStatus = "Delivered"
+)
+
+// IsValid checks if the shipment status is valid
+func (s ShipmentStatus) IsValid() bool {
+	switch s {
+	case ShipmentStatusAssigned, ShipmentStatusPickedUp, ShipmentStatusDelivered:
+		return true
+	default:
+		return false
+	}
+}
+
+// Next returns the status a shipment advances to, or an empty status once delivered
+func (s ShipmentStatus) Next() ShipmentStatus {
+	switch s {
+	case ShipmentStatusAssigned:
+		return ShipmentStatusPickedUp
+	case ShipmentStatusPickedUp:
+		return ShipmentStatusDelivered
+	default:
+		return ""
+	}
+}
+
+// Shipment represents a 3PL delivery of either a customer order or a stock request
+type Shipment struct {
+	ID                   int            `json:"id" db:"shipment_id"`
+	OrderID              *string        `json:"order_id,omitempty" db:"order_id"`
+	StockRequestID       *int           `json:"stock_request_id,omitempty" db:"request_id"`
+	Assigned3PLID        *string        `json:"assigned_3pl_id" db:"assigned_3pl_id"`
+	Assigned3PLEmail     string         `json:"assigned_3pl_email,omitempty"`
+	Status               ShipmentStatus `json:"status" db:"status"`
+	DestinationStoreID   *int           `json:"destination_store_id,omitempty"`
+	DestinationStoreName string         `json:"destination_store_name,omitempty"`
+	AssignedAt           time.Time      `json:"assigned_at" db:"assigned_at"`
+	PickupTimestamp      *time.Time     `json:"pickup_timestamp,omitempty" db:"pickup_timestamp"`
+	DeliveryTimestamp    *time.Time     `json:"delivery_timestamp,omitempty" db:"delivery_timestamp"`
+	ProofOfDeliveryURL   *string        `json:"proof_of_delivery_url,omitempty" db:"proof_of_delivery_url"`
+	DeliveryNotes        *string        `json:"delivery_notes,omitempty" db:"delivery_notes"`
+	CreatedAt            time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt            time.Time      `json:"updated_at" db:"updated_at"`
+}
+
+// CreateShipmentRequest represents a request to create a shipment for an order or a stock request
+type CreateShipmentRequest struct {
+	OrderID        *string `json:"order_id,omitempty"`
+	StockRequestID *int    `json:"stock_request_id,omitempty"`
+	Assigned3PLID  string  `json:"assigned_3pl_id" binding:"required"`
+}
+
+// AssignShipmentRequest represents a request to reassign a shipment to another 3PL user
+type AssignShipmentRequest struct {
+	Assigned3PLID string `json:"assigned_3pl_id" binding:"required"`
+}
+
+// ShipmentListRequest represents request parameters for shipment listing
+type ShipmentListRequest struct {
+	Page           int    `form:"page" binding:"omitempty,min=1"`
+	Limit          int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Status         string `form:"status"`
+	Assigned3PLID  string `form:"assigned_3pl_id"`
+	OrderID        string `form:"order_id"`
+	StockRequestID *int   `form:"stock_request_id"`
+}
+
+// ShipmentListResponse represents the response for shipment listing
+type ShipmentListResponse struct {
+	Shipments  []Shipment `json:"shipments"`
+	Total      int        `json:"total"`
+	Page       int        `json:"page"`
+	Limit      int        `json:"limit"`
+	TotalPages int        `json:"total_pages"`
+}
+
+// Notification Models
+
+// NotificationReferenceType mirrors the notification_reference_type database enum
+type NotificationReferenceType string
+
+const (
+	NotificationReferenceStockRequest NotificationReferenceType = "StockRequest"
+	NotificationReferenceShipment     NotificationReferenceType = "Shipment"
+	NotificationReferenceOrder        NotificationReferenceType = "Order"
+	NotificationReferenceProduct      NotificationReferenceType = "Product"
+)
+
+// Notification types shown in the in-app inbox
+const (
+	NotificationTypeOrderStatus = "order_status"
+	NotificationTypeShipment    = "shipment"
+	NotificationTypeLowStock    = "low_stock"
+	NotificationTypeLoyalty     = "loyalty"
+	NotificationTypeWishlist    = "wishlist"
+)
+
+// Notification represents an in-app notification for a user
+type Notification struct {
+	ID            int                        `json:"id" db:"notification_id"`
+	Type          string                     `json:"type" db:"notification_type"`
+	Title         string                     `json:"title" db:"title"`
+	Message       string                     `json:"message" db:"message"`
+	ReferenceType *NotificationReferenceType `json:"reference_type,omitempty" db:"reference_type"`
+	ReferenceID   *string                    `json:"reference_id,omitempty" db:"reference_id"`
+	IsRead        bool                       `json:"is_read" db:"is_read"`
+	ReadAt        *time.Time                 `json:"read_at,omitempty" db:"read_at"`
+	CreatedAt     time.Time                  `json:"created_at" db:"created_at"`
+}
+
+// NotificationListRequest represents request parameters for the notification inbox
+type NotificationListRequest struct {
+	Page       int  `form:"page" binding:"omitempty,min=1"`
+	Limit      int  `form:"limit" binding:"omitempty,min=1,max=100"`
+	UnreadOnly bool `form:"unread_only"`
+}
+
+// NotificationListResponse represents the response for the notification inbox
+type NotificationListResponse struct {
+	Notifications []Notification `json:"notifications"`
+	Total         int            `json:"total"`
+	UnreadCount   int            `json:"unread_count"`
+	Page          int            `json:"page"`
+	Limit         int            `json:"limit"`
+	TotalPages    int            `json:"total_pages"`
+}
+
+// Loyalty Models
+
+// LoyaltyEntryType is the kind of change a loyalty ledger entry records
+type LoyaltyEntryType string
+
+const (
+	LoyaltyEntryEarn         LoyaltyEntryType = "earn"          // points for a delivered order
+	LoyaltyEntryRedeem       LoyaltyEntryType = "redeem"        // points spent on an order
+	LoyaltyEntryEarnReversal LoyaltyEntryType = "earn_reversal" // earned points taken back when the order is cancelled
+	LoyaltyEntryRedeemRefund LoyaltyEntryType = "redeem_refund" // spent points handed back when the order is cancelled
+	LoyaltyEntryExpire       LoyaltyEntryType = "expire"        // points past their expiry
+	LoyaltyEntryAdjust       LoyaltyEntryType = "adjust"        // manual change by an admin
+)
+
+// IsValid checks if the loyalty entry type is valid
+func (t LoyaltyEntryType) IsValid() bool {
+	switch t {
+	case LoyaltyEntryEarn, LoyaltyEntryRedeem, LoyaltyEntryEarnReversal, LoyaltyEntryRedeemRefund, LoyaltyEntryExpire, LoyaltyEntryAdjust:
+		return true
+	default:
+		return false
+	}
+}
+
+// LoyaltyRule sets how many points orders of a mini-app and store earn. Nil MiniAppType or
+// StoreID applies to all; the most specific active rule wins.
+type LoyaltyRule struct {
+	ID             int          `json:"id"`
+	MiniAppType    *MiniAppType `json:"mini_app_type,omitempty"`
+	StoreID        *int         `json:"store_id,omitempty"`
+	StoreName      string       `json:"store_name,omitempty"`
+	PointsPerUnit  float64      `json:"points_per_unit"`  // points per currency unit paid
+	MinOrderAmount float64      `json:"min_order_amount"` // orders below earn nothing
+	IsActive       bool         `json:"is_active"`
+	CreatedBy      *string      `json:"created_by,omitempty"`
+	CreatedAt      time.Time    `json:"created_at"`
+	UpdatedAt      time.Time    `json:"updated_at"`
+}
+
+// LoyaltyRuleRequest creates or replaces a loyalty rule
+type LoyaltyRuleRequest struct {
+	MiniAppType    *MiniAppType `json:"mini_app_type,omitempty"`
+	StoreID        *int         `json:"store_id,omitempty" binding:"omitempty,min=1"`
+	PointsPerUnit  float64      `json:"points_per_unit" binding:"min=0,max=1000"`
+	MinOrderAmount float64      `json:"min_order_amount" binding:"min=0"`
+	IsActive       *bool        `json:"is_active,omitempty"` // defaults to true
+}
+
+// LoyaltyEntry is a loyalty ledger entry. Points are negative for debits; Remaining is what
+// is left to spend of a credit.
+type LoyaltyEntry struct {
+	ID        int64            `json:"id"`
+	Type      LoyaltyEntryType `json:"type"`
+	Points    int              `json:"points"`
+	Remaining int              `json:"remaining,omitempty"`
+	ExpiresAt *time.Time       `json:"expires_at,omitempty"`
+	OrderID   *string          `json:"order_id,omitempty"`
+	Reason    *string          `json:"reason,omitempty"`
+	CreatedBy *string          `json:"created_by,omitempty"`
+	CreatedAt time.Time        `json:"created_at"`
+}
+
+// LoyaltyAccount is a user's point balance and what redeeming it is worth
+type LoyaltyAccount struct {
+	UserID           string     `json:"user_id"`
+	Balance          int        `json:"balance"`
+	BalanceValue     float64    `json:"balance_value"`
+	ExpiringPoints   int        `json:"expiring_points"` // points expiring within ExpiringWithinDays
+	ExpiringWithin   int        `json:"expiring_within_days"`
+	NextExpiryAt     *time.Time `json:"next_expiry_at,omitempty"`
+	PointValue       float64    `json:"point_value"`        // discount per point redeemed
+	MaxRedeemPercent float64    `json:"max_redeem_percent"` // share of an order total points can pay
+}
+
+// LoyaltyHistoryRequest represents request parameters for the loyalty ledger
+type LoyaltyHistoryRequest struct {
+	Page  int    `form:"page" binding:"omitempty,min=1"`
+	Limit int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Type  string `form:"type"`
+}
+
+// LoyaltyHistoryResponse represents a page of the loyalty ledger
+type LoyaltyHistoryResponse struct {
+	Entries    []LoyaltyEntry `json:"entries"`
+	Total      int            `json:"total"`
+	Page       int            `json:"page"`
+	Limit      int            `json:"limit"`
+	TotalPages int            `json:"total_pages"`
+}
+
+// LoyaltyAdjustmentRequest adds points to or removes points from a user's balance
+type LoyaltyAdjustmentRequest struct {
+	Points        int    `json:"points" binding:"required,min=-1000000,max=1000000"` // negative to remove
+	Reason        string `json:"reason" binding:"required,max=500"`
+	ExpiresInDays *int   `json:"expires_in_days,omitempty" binding:"omitempty,min=1,max=3650"` // added points only; defaults to the standard expiry
+}
+
+// Wishlist Models
+
+// WishlistItem is a product on a user's wishlist. Items without a mini-app are on the general
+// wishlist; SavedFromCart marks items moved out of a cart to buy later.
+type WishlistItem struct {
+	ID                string       `json:"id"`
+	ProductID         string       `json:"product_id"`
+	MiniAppType       *MiniAppType `json:"mini_app_type,omitempty"`
+	StoreID           *int         `json:"store_id,omitempty"`
+	Quantity          int          `json:"quantity"`
+	SavedFromCart     bool         `json:"saved_from_cart"`
+	NotifyBackInStock bool         `json:"notify_back_in_stock"`
+	NotifyPriceDrop   bool         `json:"notify_price_drop"`
+	PriceWhenAdded    *float64     `json:"price_when_added,omitempty"`
+	InStock           bool         `json:"in_stock"` // active and, for mini-apps that enforce stock, available
+	Product           *Product     `json:"product,omitempty"`
+	CreatedAt         time.Time    `json:"created_at"`
+	UpdatedAt         time.Time    `json:"updated_at"`
+}
+
+// WishlistRequest represents query parameters for the wishlist
+type WishlistRequest struct {
+	MiniAppType   string `form:"mini_app_type"`   // "general" for items without a mini-app
+	SavedForLater *bool  `form:"saved_for_later"` // only items saved from a cart, or only the others
+}
+
+// WishlistResponse represents the wishlist
+type WishlistResponse struct {
+	Items []WishlistItem `json:"items"`
+}
+
+// AddToWishlistRequest adds a product to the wishlist, or updates it when it is already there
+type AddToWishlistRequest struct {
+	ProductID         string       `json:"product_id" binding:"required"`
+	MiniAppType       *MiniAppType `json:"mini_app_type,omitempty"`
+	StoreID           *int         `json:"store_id,omitempty"`                                    // Required for location-based mini-apps
+	Quantity          int          `json:"quantity,omitempty" binding:"omitempty,min=1,max=1000"` // defaults to 1
+	NotifyBackInStock *bool        `json:"notify_back_in_stock,omitempty"`                        // defaults to true
+	NotifyPriceDrop   *bool        `json:"notify_price_drop,omitempty"`                           // defaults to true
+}
+
+// UpdateWishlistItemRequest changes the quantity or alerts of a wishlist item
+type UpdateWishlistItemRequest struct {
+	Quantity          *int  `json:"quantity,omitempty" binding:"omitempty,min=1,max=1000"`
+	NotifyBackInStock *bool `json:"notify_back_in_stock,omitempty"`
+	NotifyPriceDrop   *bool `json:"notify_price_drop,omitempty"`
+}
+
+// SaveForLaterRequest moves a cart item to the wishlist
+type SaveForLaterRequest struct {
+	ProductID string `json:"product_id" binding:"required"`
+}
+
+// MoveToCartRequest moves a wishlist item to a cart. MiniAppType and StoreID are only used
+// when the item has none.
+type MoveToCartRequest struct {
+	MiniAppType *MiniAppType `json:"mini_app_type,omitempty"`
+	StoreID     *int         `json:"store_id,omitempty"`
+	Quantity    int          `json:"quantity,omitempty" binding:"omitempty,min=1"` // defaults to the item's quantity
+}
+
+// WishlistStatisticsRequest represents query parameters for the wishlist statistics
+type WishlistStatisticsRequest struct {
+	MiniAppType string `form:"mini_app_type"`
+	StoreID     *int   `form:"store_id" binding:"omitempty,min=1"`
+	Limit       int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// WishedProduct is a product with how often it is wishlisted
+type WishedProduct struct {
+	ProductID          string `json:"product_id"`
+	SKU                string `json:"sku"`
+	Title              string `json:"title"`
+	WishlistCount      int    `json:"wishlist_count"` // users with the product on their wishlist
+	SavedForLaterCount int    `json:"saved_for_later_count"`
+	TotalQuantity      int    `json:"total_quantity"`
+}
+
+// WishlistStatistics summarizes wishlists for the admin dashboard
+type WishlistStatistics struct {
+	TotalItems         int             `json:"total_items"`
+	SavedForLaterItems int             `json:"saved_for_later_items"`
+	Users              int             `json:"users"`
+	TopProducts        []WishedProduct `json:"top_products"`
 }
@@ -0,0 +1,250 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/expomadeinworld/madeinworld/order-service/internal/db"
+)
+
+const (
+	defaultAbandonmentCheckMinutes = 15
+	defaultCartAbandonmentHours    = 24
+	defaultCartExpiryDays          = 30
+	defaultCartRecoveryURL         = "madeinworld://cart"
+
+	// recoveryEmailBatchSize caps the emails sent per run
+	recoveryEmailBatchSize = 100
+)
+
+// CartAbandonmentService classifies idle carts as abandoned or expired and sends one
+// recovery email per abandoned cart session
+type CartAbandonmentService struct {
+	db           *db.Database
+	interval     time.Duration
+	abandonAfter time.Duration
+	expireAfter  time.Duration
+	recoveryURL  string
+	sender       *RecoveryEmailSender
+	stopChan     chan bool
+}
+
+// NewCartAbandonmentService creates a cart abandonment service configured from the environment:
+// CART_ABANDONMENT_CHECK_INTERVAL_MINUTES, CART_ABANDONMENT_HOURS, CART_EXPIRY_DAYS and CART_RECOVERY_URL
+func NewCartAbandonmentService(database *db.Database) *CartAbandonmentService {
+	recoveryURL := os.Getenv("CART_RECOVERY_URL")
+	if recoveryURL == "" {
+		recoveryURL = defaultCartRecoveryURL
+	}
+
+	return &CartAbandonmentService{
+		db:           database,
+		interval:     time.Duration(positiveIntFromEnv("CART_ABANDONMENT_CHECK_INTERVAL_MINUTES", defaultAbandonmentCheckMinutes)) * time.Minute,
+		abandonAfter: time.Duration(positiveIntFromEnv("CART_ABANDONMENT_HOURS", defaultCartAbandonmentHours)) * time.Hour,
+		expireAfter:  time.Duration(positiveIntFromEnv("CART_EXPIRY_DAYS", defaultCartExpiryDays)) * 24 * time.Hour,
+		recoveryURL:  recoveryURL,
+		sender:       NewRecoveryEmailSender(),
+		stopChan:     make(chan bool),
+	}
+}
+
+// positiveIntFromEnv returns the named environment variable or the fallback when it is unset or invalid
+func positiveIntFromEnv(name string, fallback int) int {
+	if value, err := strconv.Atoi(os.Getenv(name)); err == nil && value > 0 {
+		return value
+	}
+	return fallback
+}
+
+// Start begins the periodic abandonment check
+func (s *CartAbandonmentService) Start() {
+	log.Printf("Starting cart abandonment service with %v interval (abandoned after %v, expired after %v)",
+		s.interval, s.abandonAfter, s.expireAfter)
+	if !s.sender.Enabled() {
+		log.Println("INTERNAL_API_KEY not set, cart recovery emails are disabled")
+	}
+
+	// Run check immediately on start
+	s.runCheck()
+
+	ticker := time.NewTicker(s.interval)
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				s.runCheck()
+			case <-s.stopChan:
+				ticker.Stop()
+				log.Println("Cart abandonment service stopped")
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the cart abandonment service
+func (s *CartAbandonmentService) Stop() {
+	s.stopChan <- true
+}
+
+func (s *CartAbandonmentService) runCheck() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	if err := s.classify(ctx); err != nil {
+		log.Printf("Error classifying abandoned carts: %v", err)
+		return
+	}
+
+	if !s.sender.Enabled() {
+		return
+	}
+
+	sent, err := s.sendRecoveryEmails(ctx)
+	if err != nil {
+		log.Printf("Error sending cart recovery emails: %v", err)
+	}
+	if sent > 0 {
+		log.Printf("Sent %d cart recovery emails", sent)
+	}
+}
+
+// classify moves carts between the active, abandoned, expired and emptied states
+func (s *CartAbandonmentService) classify(ctx context.Context) error {
+	_, err := s.db.Pool.Exec(ctx, `
+		UPDATE cart_activity a
+		SET status = 'emptied', updated_at = CURRENT_TIMESTAMP
+		WHERE a.status IN ('active', 'abandoned', 'expired')
+		  AND NOT EXISTS (
+			SELECT 1 FROM carts c
+			WHERE c.user_id = a.user_id AND c.mini_app_type = a.mini_app_type AND COALESCE(c.store_id, 0) = a.store_id
+		  )
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to mark emptied carts: %w", err)
+	}
+
+	_, err = s.db.Pool.Exec(ctx, `
+		UPDATE cart_activity
+		SET status = 'abandoned', abandoned_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE status = 'active' AND last_activity_at < CURRENT_TIMESTAMP - make_interval(secs => $1)
+	`, s.abandonAfter.Seconds())
+	if err != nil {
+		return fmt.Errorf("failed to mark abandoned carts: %w", err)
+	}
+
+	_, err = s.db.Pool.Exec(ctx, `
+		UPDATE cart_activity
+		SET status = 'expired', updated_at = CURRENT_TIMESTAMP
+		WHERE status = 'abandoned' AND last_activity_at < CURRENT_TIMESTAMP - make_interval(secs => $1)
+	`, s.expireAfter.Seconds())
+	if err != nil {
+		return fmt.Errorf("failed to mark expired carts: %w", err)
+	}
+
+	return nil
+}
+
+type pendingRecovery struct {
+	id          int
+	email       string
+	fullName    string
+	miniAppType string
+	storeID     int
+	itemCount   int
+	cartValue   float64
+}
+
+// sendRecoveryEmails emails users whose abandoned carts have not been reminded yet and
+// returns the number of emails sent
+func (s *CartAbandonmentService) sendRecoveryEmails(ctx context.Context) (int, error) {
+	rows, err := s.db.Pool.Query(ctx, `
+		SELECT a.id, u.email, COALESCE(TRIM(CONCAT(u.first_name, ' ', u.last_name)), ''),
+		       a.mini_app_type, a.store_id, COUNT(c.id), COALESCE(SUM(p.main_price * c.quantity), 0)
+		FROM cart_activity a
+		JOIN users u ON u.id = a.user_id
+		JOIN carts c ON c.user_id = a.user_id AND c.mini_app_type = a.mini_app_type AND COALESCE(c.store_id, 0) = a.store_id
+		LEFT JOIN products p ON p.product_uuid = c.product_id
+		WHERE a.status = 'abandoned' AND a.recovery_email_sent_at IS NULL AND COALESCE(u.email, '') != ''
+		GROUP BY a.id, u.email, u.first_name, u.last_name
+		ORDER BY a.abandoned_at
+		LIMIT $1
+	`, recoveryEmailBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find carts to recover: %w", err)
+	}
+
+	var pending []pendingRecovery
+	for rows.Next() {
+		var p pendingRecovery
+		if err := rows.Scan(&p.id, &p.email, &p.fullName, &p.miniAppType, &p.storeID, &p.itemCount, &p.cartValue); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan cart to recover: %w", err)
+		}
+		pending = append(pending, p)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to read carts to recover: %w", err)
+	}
+
+	sent := 0
+	for _, p := range pending {
+		token, err := newRecoveryToken()
+		if err != nil {
+			return sent, err
+		}
+
+		err = s.sender.Send(ctx, CartRecoveryEmail{
+			Email:       p.email,
+			FullName:    p.fullName,
+			ItemCount:   p.itemCount,
+			CartValue:   p.cartValue,
+			RecoveryURL: s.recoveryLink(p.miniAppType, p.storeID, token),
+		})
+		if err != nil {
+			log.Printf("Failed to send recovery email for cart %d: %v", p.id, err)
+			continue
+		}
+
+		_, err = s.db.Pool.Exec(ctx, `
+			UPDATE cart_activity
+			SET recovery_token = $2, recovery_email_sent_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+			WHERE id = $1
+		`, p.id, token)
+		if err != nil {
+			return sent, fmt.Errorf("failed to record recovery email for cart %d: %w", p.id, err)
+		}
+		sent++
+	}
+
+	return sent, nil
+}
+
+// recoveryLink returns the deep link that opens the cart in the app
+func (s *CartAbandonmentService) recoveryLink(miniAppType string, storeID int, token string) string {
+	query := url.Values{}
+	query.Set("mini_app_type", miniAppType)
+	if storeID > 0 {
+		query.Set("store_id", strconv.Itoa(storeID))
+	}
+	query.Set("recovery_token", token)
+	return s.recoveryURL + "?" + query.Encode()
+}
+
+// newRecoveryToken returns a random token identifying a recovery email
+func newRecoveryToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate recovery token: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/expomadeinworld/madeinworld/order-service/internal/db"
+)
+
+const (
+	defaultLoyaltyExpiryMinutes = 60
+
+	// loyaltyExpiryBatchSize caps the lots expired per statement
+	loyaltyExpiryBatchSize = 1000
+)
+
+// LoyaltyExpiryService writes off loyalty points that are past their expiry
+type LoyaltyExpiryService struct {
+	db       *db.Database
+	interval time.Duration
+	stopChan chan bool
+}
+
+// NewLoyaltyExpiryService creates a loyalty expiry service that runs every
+// LOYALTY_EXPIRY_INTERVAL_MINUTES
+func NewLoyaltyExpiryService(database *db.Database) *LoyaltyExpiryService {
+	return &LoyaltyExpiryService{
+		db:       database,
+		interval: time.Duration(positiveIntFromEnv("LOYALTY_EXPIRY_INTERVAL_MINUTES", defaultLoyaltyExpiryMinutes)) * time.Minute,
+		stopChan: make(chan bool),
+	}
+}
+
+// Start begins the periodic expiry run
+func (s *LoyaltyExpiryService) Start() {
+	log.Printf("Starting loyalty expiry service with %v interval", s.interval)
+
+	// Run immediately on start
+	s.runExpiry()
+
+	ticker := time.NewTicker(s.interval)
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				s.runExpiry()
+			case <-s.stopChan:
+				ticker.Stop()
+				log.Println("Loyalty expiry service stopped")
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the loyalty expiry service
+func (s *LoyaltyExpiryService) Stop() {
+	s.stopChan <- true
+}
+
+func (s *LoyaltyExpiryService) runExpiry() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	total := 0
+	for {
+		expired, err := s.expireBatch(ctx)
+		if err != nil {
+			log.Printf("Error expiring loyalty points: %v", err)
+			break
+		}
+		total += expired
+		if expired < loyaltyExpiryBatchSize {
+			break
+		}
+	}
+	if total > 0 {
+		log.Printf("Expired %d loyalty point lots", total)
+	}
+}
+
+// expireBatch zeroes a batch of expired lots and records one expire entry per user. Lots
+// locked by a redemption in progress are left for the next run.
+func (s *LoyaltyExpiryService) expireBatch(ctx context.Context) (int, error) {
+	var expired int
+	err := s.db.Pool.QueryRow(ctx, `
+		WITH lots AS (
+			SELECT entry_id, user_id, remaining
+			FROM loyalty_ledger
+			WHERE remaining > 0 AND expires_at <= CURRENT_TIMESTAMP
+			ORDER BY expires_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		), zeroed AS (
+			UPDATE loyalty_ledger l
+			SET remaining = 0
+			FROM lots
+			WHERE l.entry_id = lots.entry_id
+			RETURNING lots.user_id, lots.remaining
+		), entries AS (
+			INSERT INTO loyalty_ledger (user_id, entry_type, points, reason, created_by)
+			SELECT user_id, 'expire', -SUM(remaining), 'Points expired', 'system'
+			FROM zeroed
+			GROUP BY user_id
+		)
+		SELECT COUNT(*) FROM zeroed
+	`, loyaltyExpiryBatchSize).Scan(&expired)
+	if err != nil {
+		return 0, fmt.Errorf("failed to expire loyalty points: %w", err)
+	}
+	return expired, nil
+}
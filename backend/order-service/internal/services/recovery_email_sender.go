@@ -0,0 +1,74 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// CartRecoveryEmail is the payload of auth-service's cart recovery email endpoint
+type CartRecoveryEmail struct {
+	Email       string  `json:"email"`
+	FullName    string  `json:"full_name"`
+	ItemCount   int     `json:"item_count"`
+	CartValue   float64 `json:"cart_value"`
+	RecoveryURL string  `json:"recovery_url"`
+}
+
+// RecoveryEmailSender delivers cart recovery emails through auth-service, which owns the
+// SMTP configuration
+type RecoveryEmailSender struct {
+	baseURL string
+	apiKey  string
+	client  *http.Client
+}
+
+// NewRecoveryEmailSender creates a sender from AUTH_SERVICE_URL and INTERNAL_API_KEY
+func NewRecoveryEmailSender() *RecoveryEmailSender {
+	baseURL := os.Getenv("AUTH_SERVICE_URL")
+	if baseURL == "" {
+		baseURL = "http://localhost:8081"
+	}
+
+	return &RecoveryEmailSender{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  os.Getenv("INTERNAL_API_KEY"),
+		client:  &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// Enabled reports whether the internal API key needed to call auth-service is configured
+func (s *RecoveryEmailSender) Enabled() bool {
+	return s.apiKey != ""
+}
+
+// Send asks auth-service to deliver a cart recovery email
+func (s *RecoveryEmailSender) Send(ctx context.Context, email CartRecoveryEmail) error {
+	body, err := json.Marshal(email)
+	if err != nil {
+		return fmt.Errorf("failed to encode recovery email: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.baseURL+"/api/internal/emails/cart-recovery", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build recovery email request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Internal-API-Key", s.apiKey)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send recovery email: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("auth service returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,412 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/expomadeinworld/madeinworld/order-service/internal/db"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	// OrderRollupName identifies the order-service rollups in analytics_rollup_state
+	OrderRollupName = "orders"
+
+	defaultRollupIntervalMinutes = 5
+
+	// rollupOverlap is re-read on every run so rows committed while the previous run was
+	// in progress are not missed
+	rollupOverlap = time.Hour
+)
+
+// RollupService keeps the hourly and daily order, product and cart rollups up to date
+type RollupService struct {
+	db       *db.Database
+	interval time.Duration
+	stopChan chan bool
+}
+
+// NewRollupService creates a new rollup service
+func NewRollupService(database *db.Database, intervalMinutes int) *RollupService {
+	return &RollupService{
+		db:       database,
+		interval: time.Duration(intervalMinutes) * time.Minute,
+		stopChan: make(chan bool),
+	}
+}
+
+// RollupIntervalFromEnv returns ANALYTICS_ROLLUP_INTERVAL_MINUTES or the default
+func RollupIntervalFromEnv() int {
+	if value, err := strconv.Atoi(os.Getenv("ANALYTICS_ROLLUP_INTERVAL_MINUTES")); err == nil && value > 0 {
+		return value
+	}
+	return defaultRollupIntervalMinutes
+}
+
+// Start begins the periodic rollup refresh
+func (s *RollupService) Start() {
+	log.Printf("Starting analytics rollup service with %v interval", s.interval)
+
+	ticker := time.NewTicker(s.interval)
+
+	go func() {
+		// Refresh right away, in the background: the first refresh backfills all history
+		// and must not hold up the server start
+		s.runRefresh()
+
+		for {
+			select {
+			case <-ticker.C:
+				s.runRefresh()
+			case <-s.stopChan:
+				ticker.Stop()
+				log.Println("Analytics rollup service stopped")
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the rollup service
+func (s *RollupService) Stop() {
+	s.stopChan <- true
+}
+
+func (s *RollupService) runRefresh() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	if err := s.Refresh(ctx); err != nil {
+		log.Printf("Error refreshing analytics rollups: %v", err)
+	}
+}
+
+// Refresh incrementally updates the rollups. Order hours are recomputed when an order
+// in them was created or updated since the last run, so status changes on old orders
+// are picked up. Cart hours are recomputed for the recent window only, because cart
+// rows are deleted at checkout. The first run backfills everything.
+func (s *RollupService) Refresh(ctx context.Context) error {
+	var now time.Time
+	if err := s.db.Pool.QueryRow(ctx, "SELECT CURRENT_TIMESTAMP").Scan(&now); err != nil {
+		return fmt.Errorf("failed to read database time: %w", err)
+	}
+
+	lastRun, err := s.LastRunAt(ctx)
+	if err != nil {
+		return err
+	}
+	if lastRun == nil {
+		var earliest *time.Time
+		err := s.db.Pool.QueryRow(ctx, "SELECT LEAST((SELECT MIN(created_at) FROM orders), (SELECT MIN(created_at) FROM carts))").Scan(&earliest)
+		if err != nil {
+			return fmt.Errorf("failed to find earliest activity: %w", err)
+		}
+		if earliest == nil {
+			earliest = &now
+		}
+		log.Printf("No analytics rollups yet, backfilling from %s", earliest.UTC().Format(time.RFC3339))
+		if _, err := s.Backfill(ctx, *earliest, now); err != nil {
+			return err
+		}
+		return s.saveLastRun(ctx, now)
+	}
+
+	since := lastRun.Add(-rollupOverlap)
+
+	rows, err := s.db.Pool.Query(ctx, `
+		SELECT DISTINCT date_trunc('hour', created_at AT TIME ZONE 'UTC') AT TIME ZONE 'UTC'
+		FROM orders
+		WHERE created_at >= $1 OR updated_at >= $1
+	`, since)
+	if err != nil {
+		return fmt.Errorf("failed to find changed order hours: %w", err)
+	}
+	orderHours, err := pgx.CollectRows(rows, pgx.RowTo[time.Time])
+	if err != nil {
+		return fmt.Errorf("failed to read changed order hours: %w", err)
+	}
+
+	cartHours := hourRange(since, now)
+
+	tx, err := s.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockRollups(ctx, tx); err != nil {
+		return err
+	}
+	if err := refreshOrderHours(ctx, tx, orderHours); err != nil {
+		return err
+	}
+	if err := refreshCartHours(ctx, tx, cartHours); err != nil {
+		return err
+	}
+	if err := refreshDays(ctx, tx, daysOf(append(orderHours, cartHours...))); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit rollups: %w", err)
+	}
+
+	return s.saveLastRun(ctx, now)
+}
+
+// Backfill recomputes every hourly and daily rollup between from and to, one day per
+// transaction. Cart rollups only reflect carts that still exist. It returns the number
+// of days processed.
+func (s *RollupService) Backfill(ctx context.Context, from, to time.Time) (int, error) {
+	days := 0
+	for day := truncateDay(from); !day.After(to); day = day.AddDate(0, 0, 1) {
+		hours := hourRange(day, day.Add(23*time.Hour))
+
+		tx, err := s.db.Pool.Begin(ctx)
+		if err != nil {
+			return days, fmt.Errorf("failed to start transaction: %w", err)
+		}
+
+		err = lockRollups(ctx, tx)
+		if err == nil {
+			err = refreshOrderHours(ctx, tx, hours)
+		}
+		if err == nil {
+			err = refreshCartHours(ctx, tx, hours)
+		}
+		if err == nil {
+			err = refreshDays(ctx, tx, []time.Time{day})
+		}
+		if err == nil {
+			err = tx.Commit(ctx)
+		}
+		if err != nil {
+			tx.Rollback(ctx)
+			return days, fmt.Errorf("failed to backfill %s: %w", day.Format("2006-01-02"), err)
+		}
+		days++
+	}
+
+	return days, nil
+}
+
+// LastRunAt returns the watermark of the last successful refresh, or nil before the first one
+func (s *RollupService) LastRunAt(ctx context.Context) (*time.Time, error) {
+	var lastRun time.Time
+	err := s.db.Pool.QueryRow(ctx, "SELECT last_run_at FROM analytics_rollup_state WHERE rollup_name = $1", OrderRollupName).Scan(&lastRun)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read rollup state: %w", err)
+	}
+	return &lastRun, nil
+}
+
+func (s *RollupService) saveLastRun(ctx context.Context, runAt time.Time) error {
+	_, err := s.db.Pool.Exec(ctx, `
+		INSERT INTO analytics_rollup_state (rollup_name, last_run_at, updated_at)
+		VALUES ($1, $2, CURRENT_TIMESTAMP)
+		ON CONFLICT (rollup_name) DO UPDATE SET last_run_at = EXCLUDED.last_run_at, updated_at = CURRENT_TIMESTAMP
+	`, OrderRollupName, runAt)
+	if err != nil {
+		return fmt.Errorf("failed to save rollup state: %w", err)
+	}
+	return nil
+}
+
+// lockRollups serialises rollup writers across service replicas and manual backfills
+func lockRollups(ctx context.Context, tx pgx.Tx) error {
+	if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock(hashtext('analytics_rollups'))"); err != nil {
+		return fmt.Errorf("failed to lock rollups: %w", err)
+	}
+	return nil
+}
+
+// refreshOrderHours recomputes the hourly order and product rollups for the given hours
+func refreshOrderHours(ctx context.Context, tx pgx.Tx, hours []time.Time) error {
+	if len(hours) == 0 {
+		return nil
+	}
+
+	from, to := hourBounds(hours)
+
+	if _, err := tx.Exec(ctx, "DELETE FROM analytics_order_rollups WHERE granularity = 'hour' AND bucket_start = ANY($1)", hours); err != nil {
+		return fmt.Errorf("failed to clear order rollups: %w", err)
+	}
+	_, err := tx.Exec(ctx, `
+		INSERT INTO analytics_order_rollups
+			(granularity, bucket_start, mini_app_type, store_id, status, order_count, revenue, units_sold)
+		SELECT 'hour', date_trunc('hour', o.created_at AT TIME ZONE 'UTC') AT TIME ZONE 'UTC',
+		       o.mini_app_type, COALESCE(o.store_id, 0), o.status,
+		       COUNT(*), COALESCE(SUM(o.total_amount), 0), COALESCE(SUM(i.units), 0)
+		FROM orders o
+		LEFT JOIN LATERAL (
+			SELECT SUM(oi.quantity) AS units FROM order_items oi WHERE oi.order_id = o.id
+		) i ON true
+		WHERE o.created_at >= $2 AND o.created_at < $3
+		  AND date_trunc('hour', o.created_at AT TIME ZONE 'UTC') AT TIME ZONE 'UTC' = ANY($1)
+		GROUP BY 2, 3, 4, 5
+	`, hours, from, to)
+	if err != nil {
+		return fmt.Errorf("failed to refresh order rollups: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, "DELETE FROM analytics_product_rollups WHERE granularity = 'hour' AND bucket_start = ANY($1)", hours); err != nil {
+		return fmt.Errorf("failed to clear product rollups: %w", err)
+	}
+	_, err = tx.Exec(ctx, `
+		INSERT INTO analytics_product_rollups
+			(granularity, bucket_start, product_id, order_count, units_sold, revenue)
+		SELECT 'hour', date_trunc('hour', o.created_at AT TIME ZONE 'UTC') AT TIME ZONE 'UTC',
+		       oi.product_id, COUNT(DISTINCT oi.order_id), SUM(oi.quantity), COALESCE(SUM(oi.price), 0)
+		FROM order_items oi
+		JOIN orders o ON o.id = oi.order_id
+		WHERE o.status != 'cancelled'
+		  AND o.created_at >= $2 AND o.created_at < $3
+		  AND date_trunc('hour', o.created_at AT TIME ZONE 'UTC') AT TIME ZONE 'UTC' = ANY($1)
+		GROUP BY 2, 3
+	`, hours, from, to)
+	if err != nil {
+		return fmt.Errorf("failed to refresh product rollups: %w", err)
+	}
+
+	return nil
+}
+
+// refreshCartHours recomputes the hourly cart rollups for the given hours. A cart is
+// started in the hour its first line was added.
+func refreshCartHours(ctx context.Context, tx pgx.Tx, hours []time.Time) error {
+	if len(hours) == 0 {
+		return nil
+	}
+
+	if _, err := tx.Exec(ctx, "DELETE FROM analytics_cart_rollups WHERE granularity = 'hour' AND bucket_start = ANY($1)", hours); err != nil {
+		return fmt.Errorf("failed to clear cart rollups: %w", err)
+	}
+	_, err := tx.Exec(ctx, `
+		INSERT INTO analytics_cart_rollups
+			(granularity, bucket_start, mini_app_type, store_id, items_added, cart_value)
+		SELECT 'hour', date_trunc('hour', c.created_at AT TIME ZONE 'UTC') AT TIME ZONE 'UTC',
+		       c.mini_app_type, COALESCE(c.store_id, 0),
+		       SUM(c.quantity), COALESCE(SUM(p.main_price * c.quantity), 0)
+		FROM carts c
+		LEFT JOIN products p ON p.product_uuid = c.product_id
+		WHERE date_trunc('hour', c.created_at AT TIME ZONE 'UTC') AT TIME ZONE 'UTC' = ANY($1)
+		GROUP BY 2, 3, 4
+	`, hours)
+	if err != nil {
+		return fmt.Errorf("failed to refresh cart rollups: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO analytics_cart_rollups (granularity, bucket_start, mini_app_type, store_id, carts_started)
+		SELECT 'hour', date_trunc('hour', started_at AT TIME ZONE 'UTC') AT TIME ZONE 'UTC',
+		       mini_app_type, store_id, COUNT(*)
+		FROM (
+			SELECT c.mini_app_type, COALESCE(MIN(c.store_id), 0) AS store_id, MIN(c.created_at) AS started_at
+			FROM carts c
+			GROUP BY c.user_id, c.mini_app_type
+		) started
+		WHERE date_trunc('hour', started_at AT TIME ZONE 'UTC') AT TIME ZONE 'UTC' = ANY($1)
+		GROUP BY 2, 3, 4
+		ON CONFLICT (granularity, bucket_start, mini_app_type, store_id)
+		DO UPDATE SET carts_started = EXCLUDED.carts_started, refreshed_at = CURRENT_TIMESTAMP
+	`, hours)
+	if err != nil {
+		return fmt.Errorf("failed to refresh started cart rollups: %w", err)
+	}
+
+	return nil
+}
+
+// refreshDays rebuilds the daily rollups of the given UTC days from the hourly rollups
+func refreshDays(ctx context.Context, tx pgx.Tx, days []time.Time) error {
+	if len(days) == 0 {
+		return nil
+	}
+
+	statements := []string{
+		`DELETE FROM analytics_order_rollups WHERE granularity = 'day' AND bucket_start = ANY($1)`,
+		`INSERT INTO analytics_order_rollups
+			(granularity, bucket_start, mini_app_type, store_id, status, order_count, revenue, units_sold)
+		SELECT 'day', date_trunc('day', bucket_start AT TIME ZONE 'UTC') AT TIME ZONE 'UTC',
+		       mini_app_type, store_id, status, SUM(order_count), SUM(revenue), SUM(units_sold)
+		FROM analytics_order_rollups
+		WHERE granularity = 'hour'
+		  AND date_trunc('day', bucket_start AT TIME ZONE 'UTC') AT TIME ZONE 'UTC' = ANY($1)
+		GROUP BY 2, 3, 4, 5`,
+		`DELETE FROM analytics_product_rollups WHERE granularity = 'day' AND bucket_start = ANY($1)`,
+		`INSERT INTO analytics_product_rollups
+			(granularity, bucket_start, product_id, order_count, units_sold, revenue)
+		SELECT 'day', date_trunc('day', bucket_start AT TIME ZONE 'UTC') AT TIME ZONE 'UTC',
+		       product_id, SUM(order_count), SUM(units_sold), SUM(revenue)
+		FROM analytics_product_rollups
+		WHERE granularity = 'hour'
+		  AND date_trunc('day', bucket_start AT TIME ZONE 'UTC') AT TIME ZONE 'UTC' = ANY($1)
+		GROUP BY 2, 3`,
+		`DELETE FROM analytics_cart_rollups WHERE granularity = 'day' AND bucket_start = ANY($1)`,
+		`INSERT INTO analytics_cart_rollups
+			(granularity, bucket_start, mini_app_type, store_id, carts_started, items_added, cart_value)
+		SELECT 'day', date_trunc('day', bucket_start AT TIME ZONE 'UTC') AT TIME ZONE 'UTC',
+		       mini_app_type, store_id, SUM(carts_started), SUM(items_added), SUM(cart_value)
+		FROM analytics_cart_rollups
+		WHERE granularity = 'hour'
+		  AND date_trunc('day', bucket_start AT TIME ZONE 'UTC') AT TIME ZONE 'UTC' = ANY($1)
+		GROUP BY 2, 3, 4`,
+	}
+
+	for _, statement := range statements {
+		if _, err := tx.Exec(ctx, statement, days); err != nil {
+			return fmt.Errorf("failed to refresh daily rollups: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// hourRange returns every UTC hour start from the hour containing from up to to
+func hourRange(from, to time.Time) []time.Time {
+	var hours []time.Time
+	for hour := from.UTC().Truncate(time.Hour); !hour.After(to); hour = hour.Add(time.Hour) {
+		hours = append(hours, hour)
+	}
+	return hours
+}
+
+// hourBounds returns the half-open time range covering all given hours
+func hourBounds(hours []time.Time) (time.Time, time.Time) {
+	from, to := hours[0], hours[0]
+	for _, hour := range hours[1:] {
+		if hour.Before(from) {
+			from = hour
+		}
+		if hour.After(to) {
+			to = hour
+		}
+	}
+	return from, to.Add(time.Hour)
+}
+
+// daysOf returns the distinct UTC days containing the given hours
+func daysOf(hours []time.Time) []time.Time {
+	seen := make(map[time.Time]bool)
+	var days []time.Time
+	for _, hour := range hours {
+		day := truncateDay(hour)
+		if !seen[day] {
+			seen[day] = true
+			days = append(days, day)
+		}
+	}
+	return days
+}
+
+func truncateDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,220 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/expomadeinworld/madeinworld/order-service/internal/db"
+	"github.com/expomadeinworld/madeinworld/order-service/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	defaultWishlistAlertMinutes = 15
+
+	// wishlistAlertBatchSize caps the wishlist items checked per transaction
+	wishlistAlertBatchSize = 500
+)
+
+// WishlistAlertService notifies users when a wishlisted product drops in price or comes back
+// in stock. Catalog changes are picked up by comparing each item's current price and
+// availability with what the previous check saw.
+type WishlistAlertService struct {
+	db       *db.Database
+	interval time.Duration
+	stopChan chan bool
+}
+
+// NewWishlistAlertService creates a wishlist alert service that runs every
+// WISHLIST_ALERT_INTERVAL_MINUTES
+func NewWishlistAlertService(database *db.Database) *WishlistAlertService {
+	return &WishlistAlertService{
+		db:       database,
+		interval: time.Duration(positiveIntFromEnv("WISHLIST_ALERT_INTERVAL_MINUTES", defaultWishlistAlertMinutes)) * time.Minute,
+		stopChan: make(chan bool),
+	}
+}
+
+// Start begins the periodic wishlist check
+func (s *WishlistAlertService) Start() {
+	log.Printf("Starting wishlist alert service with %v interval", s.interval)
+
+	// Run immediately on start
+	s.runCheck()
+
+	ticker := time.NewTicker(s.interval)
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				s.runCheck()
+			case <-s.stopChan:
+				ticker.Stop()
+				log.Println("Wishlist alert service stopped")
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the wishlist alert service
+func (s *WishlistAlertService) Stop() {
+	s.stopChan <- true
+}
+
+func (s *WishlistAlertService) runCheck() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	notified := 0
+	for {
+		checked, sent, err := s.checkBatch(ctx)
+		if err != nil {
+			log.Printf("Error checking wishlist alerts: %v", err)
+			break
+		}
+		notified += sent
+		if checked < wishlistAlertBatchSize {
+			break
+		}
+	}
+	if notified > 0 {
+		log.Printf("Sent %d wishlist notifications", notified)
+	}
+}
+
+// wishlistChange is a wishlist item whose price or availability changed since the last check
+type wishlistChange struct {
+	itemID            string
+	userID            string
+	productID         string
+	title             string
+	notifyBackInStock bool
+	notifyPriceDrop   bool
+	lastPrice         *float64
+	lastInStock       *bool
+	price             float64
+	inStock           bool
+}
+
+// checkBatch records the current price and availability of a batch of changed items and
+// notifies their users of price drops and products back in stock. Items locked by a
+// concurrent change are left for the next run. It returns the items checked and the
+// notifications sent.
+func (s *WishlistAlertService) checkBatch(ctx context.Context) (int, int, error) {
+	tx, err := s.db.Pool.Begin(ctx)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Availability follows the cart rules: the product is active at the item's store and,
+	// when the mini-app enforces stock, has stock beyond its safety stock
+	rows, err := tx.Query(ctx, `
+		SELECT id::text, user_id::text, product_uuid::text, title, notify_back_in_stock, notify_price_drop,
+		       last_seen_price, last_seen_in_stock, price, in_stock
+		FROM (
+			SELECT w.id, w.user_id, p.product_uuid, p.title, w.notify_back_in_stock, w.notify_price_drop,
+			       w.last_seen_price, w.last_seen_in_stock,
+			       COALESCE(spo.price, p.main_price) AS price,
+			       COALESCE(p.is_active, false) AND COALESCE(spo.is_active, true) AND (
+			           NOT COALESCE(m.enforces_stock, false)
+			           OR COALESCE(inv.available, p.stock_left) - stock_safety_stock(p.product_id, COALESCE(w.store_id, p.store_id)) > 0
+			       ) AS in_stock
+			FROM wishlist_items w
+			JOIN products p ON p.product_uuid = w.product_id
+			LEFT JOIN store_product_overrides spo ON spo.product_id = p.product_id AND spo.store_id = w.store_id
+			LEFT JOIN LATERAL (
+				SELECT SUM(i.quantity - i.reserved_quantity)::int AS available
+				FROM inventory i
+				WHERE i.product_id = p.product_id AND i.store_id = w.store_id
+			) inv ON true
+			LEFT JOIN mini_apps m ON m.code = COALESCE(w.mini_app_type, p.mini_app_type)
+		) items
+		WHERE last_seen_price IS DISTINCT FROM price OR last_seen_in_stock IS DISTINCT FROM in_stock
+		ORDER BY id
+		LIMIT $1
+	`, wishlistAlertBatchSize)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to query wishlist changes: %w", err)
+	}
+
+	var changes []wishlistChange
+	for rows.Next() {
+		var ch wishlistChange
+		err := rows.Scan(&ch.itemID, &ch.userID, &ch.productID, &ch.title, &ch.notifyBackInStock, &ch.notifyPriceDrop,
+			&ch.lastPrice, &ch.lastInStock, &ch.price, &ch.inStock)
+		if err != nil {
+			rows.Close()
+			return 0, 0, fmt.Errorf("failed to scan wishlist change: %w", err)
+		}
+		changes = append(changes, ch)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, 0, fmt.Errorf("failed to read wishlist changes: %w", err)
+	}
+
+	checked, sent := 0, 0
+	for _, ch := range changes {
+		// Skip items a user is editing or removing; the next run sees them again
+		var locked string
+		err := tx.QueryRow(ctx, "SELECT id::text FROM wishlist_items WHERE id = $1::uuid FOR UPDATE SKIP LOCKED", ch.itemID).Scan(&locked)
+		if errors.Is(err, pgx.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to lock wishlist item: %w", err)
+		}
+		checked++
+
+		// Unknown previous values only set the baseline
+		if ch.notifyBackInStock && ch.inStock && ch.lastInStock != nil && !*ch.lastInStock {
+			if err := insertWishlistNotification(ctx, tx, ch, "Back in stock",
+				fmt.Sprintf("%s from your wishlist is back in stock.", ch.title)); err != nil {
+				return 0, 0, err
+			}
+			sent++
+		}
+		if ch.notifyPriceDrop && ch.lastPrice != nil && ch.price < *ch.lastPrice {
+			if err := insertWishlistNotification(ctx, tx, ch, "Price drop",
+				fmt.Sprintf("%s from your wishlist is now %.2f, down from %.2f.", ch.title, ch.price, *ch.lastPrice)); err != nil {
+				return 0, 0, err
+			}
+			sent++
+		}
+
+		_, err = tx.Exec(ctx, `
+			UPDATE wishlist_items SET last_seen_price = $2, last_seen_in_stock = $3
+			WHERE id = $1::uuid
+		`, ch.itemID, ch.price, ch.inStock)
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to update wishlist item: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, 0, fmt.Errorf("failed to commit wishlist alerts: %w", err)
+	}
+
+	// A full batch of skipped items would otherwise be read again in this run
+	if checked == 0 {
+		return 0, 0, nil
+	}
+	return len(changes), sent, nil
+}
+
+func insertWishlistNotification(ctx context.Context, tx pgx.Tx, ch wishlistChange, title, message string) error {
+	_, err := tx.Exec(ctx, `
+		INSERT INTO notifications (recipient_user_id, notification_type, title, message, reference_type, reference_id)
+		VALUES ($1, $2, $3, $4, $5::notification_reference_type, $6)
+	`, ch.userID, models.NotificationTypeWishlist, title, message, string(models.NotificationReferenceProduct), ch.productID)
+	if err != nil {
+		return fmt.Errorf("failed to create wishlist notification: %w", err)
+	}
+	return nil
+}
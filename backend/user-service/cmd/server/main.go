@@ -6,6 +6,7 @@ import (
 
 	"user-service/internal/api"
 	"user-service/internal/db"
+	"user-service/internal/services"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
@@ -31,6 +32,27 @@ func main() {
 		defer database.Close()
 	}
 
+	// Keep the registration rollups read by the analytics endpoint up to date
+	if database != nil {
+		rollupService := services.NewRegistrationRollupService(database, services.RollupIntervalFromEnv())
+		rollupService.Start()
+		defer rollupService.Stop()
+	}
+
+	// Process data export and erasure requests in the background
+	if database != nil {
+		privacyService := services.NewPrivacyRequestService(database, services.PrivacyWorkerIntervalFromEnv())
+		privacyService.Start()
+		defer privacyService.Stop()
+	}
+
+	// Re-evaluate the members of user segments
+	if database != nil {
+		segmentService := services.NewSegmentRefreshService(database, services.SegmentRefreshIntervalFromEnv())
+		segmentService.Start()
+		defer segmentService.Stop()
+	}
+
 	// Initialize handlers
 	handler := api.NewHandler(database)
 
@@ -70,17 +92,74 @@ func setupRouter(handler *api.Handler) *gin.Engine {
 	// Admin API routes with authentication and admin middleware
 	adminGroup := router.Group("/api/admin")
 	adminGroup.Use(api.AuthMiddleware())
+	adminGroup.Use(handler.AccountStatusMiddleware())
 	adminGroup.Use(api.AdminMiddleware())
+	adminGroup.Use(handler.PartnerExclusionMiddleware())
 	{
 		// User management endpoints
 		adminGroup.GET("/users", handler.GetUsers)
 		adminGroup.POST("/users", handler.CreateUser)
 		adminGroup.GET("/users/analytics", handler.GetUserAnalytics)
+		adminGroup.GET("/users/export", handler.ExportUsers)
 		adminGroup.GET("/users/:user_id", handler.GetUser)
 		adminGroup.PUT("/users/:user_id", handler.UpdateUser)
 		adminGroup.DELETE("/users/:user_id", handler.DeleteUser)
 		adminGroup.POST("/users/:user_id/status", handler.UpdateUserStatus)
+		adminGroup.GET("/users/:user_id/status-history", handler.GetUserStatusHistory)
+		adminGroup.GET("/users/:user_id/addresses", handler.GetUserAddresses)
 		adminGroup.POST("/users/bulk-update", handler.BulkUpdateUsers)
+
+		// Personal data export and erasure on behalf of a user
+		adminGroup.POST("/users/:user_id/privacy/exports", handler.RequestUserDataExport)
+		adminGroup.POST("/users/:user_id/privacy/erasure", handler.RequestUserErasure)
+		adminGroup.GET("/privacy-requests", handler.GetPrivacyRequests)
+		adminGroup.GET("/privacy-requests/:request_id/download", handler.DownloadDataExport)
+
+		// User segments (saved audiences)
+		adminGroup.GET("/segments", handler.GetSegments)
+		adminGroup.POST("/segments", handler.CreateSegment)
+		adminGroup.GET("/segments/fields", handler.GetSegmentFields)
+		adminGroup.POST("/segments/preview", handler.PreviewSegment)
+		adminGroup.GET("/segments/:segment_id", handler.GetSegment)
+		adminGroup.PUT("/segments/:segment_id", handler.UpdateSegment)
+		adminGroup.DELETE("/segments/:segment_id", handler.DeleteSegment)
+		adminGroup.POST("/segments/:segment_id/refresh", handler.RefreshSegment)
+		adminGroup.GET("/segments/:segment_id/members", handler.GetSegmentMembers)
+		adminGroup.GET("/segments/:segment_id/export", handler.ExportSegmentMembers)
+
+		// Partners and the regions whose stores they see
+		adminGroup.GET("/partners", handler.GetPartners)
+		adminGroup.POST("/partners", handler.CreatePartner)
+		adminGroup.GET("/partners/:partner_id", handler.GetPartner)
+		adminGroup.DELETE("/partners/:partner_id", handler.DeletePartner)
+		adminGroup.PUT("/partners/:partner_id/region", handler.SetPartnerRegion)
+		adminGroup.PUT("/partners/:partner_id/stores", handler.SetPartnerStores)
+		adminGroup.GET("/regions", handler.GetRegions)
+		adminGroup.PUT("/regions/:region/stores", handler.SetRegionStores)
+	}
+
+	// Address book of the signed-in user
+	addressGroup := router.Group("/api/addresses")
+	addressGroup.Use(api.AuthMiddleware())
+	addressGroup.Use(handler.AccountStatusMiddleware())
+	{
+		addressGroup.GET("", handler.GetMyAddresses)
+		addressGroup.POST("", handler.CreateMyAddress)
+		addressGroup.GET("/:address_id", handler.GetMyAddress)
+		addressGroup.PUT("/:address_id", handler.UpdateMyAddress)
+		addressGroup.DELETE("/:address_id", handler.DeleteMyAddress)
+		addressGroup.POST("/:address_id/default", handler.SetMyDefaultAddress)
+	}
+
+	// Self-service privacy routes. The account status is not checked so suspended and
+	// deactivated users can still get a copy of their data or have it erased.
+	privacyGroup := router.Group("/api/privacy")
+	privacyGroup.Use(api.AuthMiddleware())
+	{
+		privacyGroup.POST("/exports", handler.RequestMyDataExport)
+		privacyGroup.POST("/erasure", handler.RequestMyErasure)
+		privacyGroup.GET("/requests", handler.GetMyPrivacyRequests)
+		privacyGroup.GET("/requests/:request_id/download", handler.DownloadMyDataExport)
 	}
 
 	return router
@@ -0,0 +1,192 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"user-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetMyAddresses handles GET /api/addresses
+func (h *Handler) GetMyAddresses(c *gin.Context) {
+	userID, ok := requireTokenUserID(c)
+	if !ok {
+		return
+	}
+	h.respondAddresses(c, userID)
+}
+
+// GetMyAddress handles GET /api/addresses/{address_id}
+func (h *Handler) GetMyAddress(c *gin.Context) {
+	userID, ok := requireTokenUserID(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	address, err := h.userRepo.GetAddress(ctx, userID, c.Param("address_id"))
+	if err != nil {
+		respondAddressError(c, "Failed to retrieve address", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, address)
+}
+
+// CreateMyAddress handles POST /api/addresses
+func (h *Handler) CreateMyAddress(c *gin.Context) {
+	userID, ok := requireTokenUserID(c)
+	if !ok {
+		return
+	}
+
+	req, ok := bindAddressRequest(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	address, err := h.userRepo.CreateAddress(ctx, userID, req)
+	if err != nil {
+		respondAddressError(c, "Failed to create address", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, address)
+}
+
+// UpdateMyAddress handles PUT /api/addresses/{address_id}
+func (h *Handler) UpdateMyAddress(c *gin.Context) {
+	userID, ok := requireTokenUserID(c)
+	if !ok {
+		return
+	}
+
+	req, ok := bindAddressRequest(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	address, err := h.userRepo.UpdateAddress(ctx, userID, c.Param("address_id"), req)
+	if err != nil {
+		respondAddressError(c, "Failed to update address", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, address)
+}
+
+// SetMyDefaultAddress handles POST /api/addresses/{address_id}/default
+func (h *Handler) SetMyDefaultAddress(c *gin.Context) {
+	userID, ok := requireTokenUserID(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	address, err := h.userRepo.SetDefaultAddress(ctx, userID, c.Param("address_id"))
+	if err != nil {
+		respondAddressError(c, "Failed to set default address", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, address)
+}
+
+// DeleteMyAddress handles DELETE /api/addresses/{address_id}
+func (h *Handler) DeleteMyAddress(c *gin.Context) {
+	userID, ok := requireTokenUserID(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := h.userRepo.DeleteAddress(ctx, userID, c.Param("address_id")); err != nil {
+		respondAddressError(c, "Failed to delete address", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: "Address deleted successfully",
+	})
+}
+
+// GetUserAddresses handles GET /api/admin/users/{user_id}/addresses
+func (h *Handler) GetUserAddresses(c *gin.Context) {
+	h.respondAddresses(c, c.Param("user_id"))
+}
+
+// respondAddresses responds with the address book of a user
+func (h *Handler) respondAddresses(c *gin.Context, userID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	addresses, err := h.userRepo.GetAddresses(ctx, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to retrieve addresses",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, addresses)
+}
+
+// bindAddressRequest reads and validates an address body
+func bindAddressRequest(c *gin.Context) (models.AddressRequest, bool) {
+	var req models.AddressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+		return req, false
+	}
+
+	if err := req.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid address",
+			Message: err.Error(),
+		})
+		return req, false
+	}
+
+	return req, true
+}
+
+// respondAddressError maps address book errors to responses
+func respondAddressError(c *gin.Context, message string, err error) {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   "Address not found",
+			Message: err.Error(),
+		})
+	case strings.Contains(err.Error(), "address book is full"):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error:   message,
+			Message: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   message,
+			Message: err.Error(),
+		})
+	}
+}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
@@ -39,7 +40,31 @@ func (h *Handler) GetUsers(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	// Parse query parameters
+	params, err := parseUserSearchParams(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid query parameters",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	// Get users from repository
+	response, err := h.userRepo.GetUsers(ctx, params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to retrieve users",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// parseUserSearchParams reads the filters, ordering and pagination of the user list. Unknown
+// roles, statuses and sorts are ignored as before; the newer filters reject invalid values.
+func parseUserSearchParams(c *gin.Context) (models.UserSearchParams, error) {
 	params := models.UserSearchParams{
 		Page:  1,
 		Limit: 20,
@@ -91,17 +116,86 @@ func (h *Handler) GetUsers(c *gin.Context) {
 		params.Order = order
 	}
 
-	// Get users from repository
-	response, err := h.userRepo.GetUsers(ctx, params)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "Failed to retrieve users",
-			Message: err.Error(),
-		})
-		return
+	var err error
+	if params.CreatedFrom, err = parseDateParam(c, "created_from", false); err != nil {
+		return params, err
+	}
+	if params.CreatedTo, err = parseDateParam(c, "created_to", true); err != nil {
+		return params, err
+	}
+	if params.LastLoginFrom, err = parseDateParam(c, "last_login_from", false); err != nil {
+		return params, err
+	}
+	if params.LastLoginTo, err = parseDateParam(c, "last_login_to", true); err != nil {
+		return params, err
 	}
 
-	c.JSON(http.StatusOK, response)
+	if value := c.Query("min_orders"); value != "" {
+		minOrders, err := strconv.Atoi(value)
+		if err != nil || minOrders < 0 {
+			return params, fmt.Errorf("min_orders must be a non-negative integer")
+		}
+		params.MinOrders = &minOrders
+	}
+
+	if value := c.Query("min_spent"); value != "" {
+		minSpent, err := strconv.ParseFloat(value, 64)
+		if err != nil || minSpent < 0 {
+			return params, fmt.Errorf("min_spent must be a non-negative number")
+		}
+		params.MinSpent = &minSpent
+	}
+
+	params.MiniAppType = strings.TrimSpace(c.Query("mini_app_type"))
+
+	if value := c.Query("marketing_consent"); value != "" {
+		consent, err := strconv.ParseBool(value)
+		if err != nil {
+			return params, fmt.Errorf("marketing_consent must be true or false")
+		}
+		params.MarketingConsent = &consent
+	}
+
+	params.SegmentID = strings.TrimSpace(c.Query("segment_id"))
+
+	// Any cursor parameter, even an empty one for the first page, switches to keyset paging
+	if value, ok := c.GetQuery("cursor"); ok {
+		params.UseCursor = true
+		if value != "" {
+			cursor, err := models.DecodeUserCursor(value)
+			if err != nil {
+				return params, err
+			}
+			if cursor.Sort != params.Sort || cursor.Order != params.Order {
+				return params, fmt.Errorf("cursor was issued for another sort order")
+			}
+			params.Cursor = cursor
+		}
+	}
+
+	return params, nil
+}
+
+// parseDateParam reads an optional RFC 3339 timestamp or YYYY-MM-DD date. A date used as
+// an upper bound covers the whole day.
+func parseDateParam(c *gin.Context, name string, upperBound bool) (*time.Time, error) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be a date (YYYY-MM-DD) or an RFC 3339 timestamp", name)
+	}
+	if upperBound {
+		t = t.AddDate(0, 0, 1).Add(-time.Microsecond)
+	}
+	return &t, nil
 }
 
 // CreateUser handles POST /api/admin/users
@@ -240,9 +334,31 @@ func (h *Handler) UpdateUser(c *gin.Context) {
 		return
 	}
 
-	// Update user in repository
-	err := h.userRepo.UpdateUser(ctx, userID, updates)
-	if err != nil {
+	// Validate status if provided
+	var statusUpdate *models.UserStatusUpdateRequest
+	if updates.Status != nil {
+		statusUpdate = &models.UserStatusUpdateRequest{Status: *updates.Status}
+		if err := statusUpdate.Validate(); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "Invalid status",
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	hasProfileUpdates := updates.FullName != nil || updates.Email != nil || updates.Role != nil
+	if !hasProfileUpdates && statusUpdate == nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request body",
+			Message: "No fields to update",
+		})
+		return
+	}
+
+	// Update user in repository; profile and status change together, and a status change
+	// is recorded in the status history
+	if err := h.userRepo.UpdateUser(ctx, userID, updates, statusUpdate, getActor(c)); err != nil {
 		if strings.Contains(err.Error(), "not found") {
 			c.JSON(http.StatusNotFound, models.ErrorResponse{
 				Error:   "User not found",
@@ -276,8 +392,8 @@ func (h *Handler) DeleteUser(c *gin.Context) {
 		return
 	}
 
-	// Delete user from repository
-	err := h.userRepo.DeleteUser(ctx, userID)
+	// Erase the user's personal data; the anonymized row and their orders are kept
+	err := h.userRepo.DeleteUser(ctx, userID, getActor(c))
 	if err != nil {
 		if strings.Contains(err.Error(), "not found") {
 			c.JSON(http.StatusNotFound, models.ErrorResponse{
@@ -286,6 +402,13 @@ func (h *Handler) DeleteUser(c *gin.Context) {
 			})
 			return
 		}
+		if strings.Contains(err.Error(), "already") {
+			c.JSON(http.StatusConflict, models.ErrorResponse{
+				Error:   "Failed to delete user",
+				Message: err.Error(),
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Error:   "Failed to delete user",
 			Message: err.Error(),
@@ -294,12 +417,14 @@ func (h *Handler) DeleteUser(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, models.SuccessResponse{
-		Message: "User deleted successfully",
+		Message: "User personal data erased successfully",
 	})
 }
 
 // UpdateUserStatus handles POST /api/admin/users/{user_id}/status
 func (h *Handler) UpdateUserStatus(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
 
 	userID := c.Param("user_id")
 	if userID == "" {
@@ -319,27 +444,94 @@ func (h *Handler) UpdateUserStatus(c *gin.Context) {
 		return
 	}
 
-	// Validate status
-	if !models.ValidateUserStatus(string(statusUpdate.Status)) {
+	if err := statusUpdate.Validate(); err != nil {
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{
 			Error:   "Invalid status",
-			Message: "The specified status is not valid",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err := h.userRepo.UpdateUserStatus(ctx, userID, statusUpdate, getActor(c)); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Error:   "User not found",
+				Message: "The specified user does not exist",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to update user status",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	user, err := h.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to retrieve user",
+			Message: err.Error(),
 		})
 		return
 	}
 
-	// For now, we'll just log the status update since we don't have a status field in the database
-	// In a real implementation, you would update the user's status field
 	c.JSON(http.StatusOK, models.SuccessResponse{
 		Message: "User status updated successfully",
-		Data: gin.H{
-			"user_id": userID,
-			"status":  statusUpdate.Status,
-			"reason":  statusUpdate.Reason,
-		},
+		Data:    user,
 	})
 }
 
+// GetUserStatusHistory handles GET /api/admin/users/{user_id}/status-history
+func (h *Handler) GetUserStatusHistory(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	userID := c.Param("user_id")
+
+	// Make sure the user exists so an unknown ID is not reported as an empty history
+	if _, err := h.userRepo.GetUserByID(ctx, userID); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Error:   "User not found",
+				Message: "The specified user does not exist",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to retrieve user",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	history, err := h.userRepo.GetUserStatusHistory(ctx, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to retrieve status history",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+// getActor identifies the admin making a change, for the audit trail
+func getActor(c *gin.Context) string {
+	if email, ok := c.Get("email"); ok {
+		if s, ok := email.(string); ok && s != "" {
+			return s
+		}
+	}
+	if userID, ok := c.Get("user_id"); ok {
+		if s, ok := userID.(string); ok && s != "" {
+			return s
+		}
+	}
+	return "admin"
+}
+
 // GetUserAnalytics handles GET /api/admin/users/analytics
 func (h *Handler) GetUserAnalytics(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -372,28 +564,17 @@ func (h *Handler) BulkUpdateUsers(c *gin.Context) {
 		return
 	}
 
-	// Validate operation
-	validOperations := []string{"status_update", "role_update", "delete"}
-	isValidOperation := false
-	for _, op := range validOperations {
-		if bulkUpdate.Operation == op {
-			isValidOperation = true
-			break
-		}
-	}
-
-	if !isValidOperation {
+	if len(bulkUpdate.UserIDs) > models.MaxBulkUserIDs {
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "Invalid operation",
-			Message: "Operation must be one of: status_update, role_update, delete",
+			Error:   "Too many users",
+			Message: "A bulk operation can touch at most " + strconv.Itoa(models.MaxBulkUserIDs) + " users",
 		})
 		return
 	}
 
 	// Validate parameters based on operation
-	updates := make(map[string]interface{})
 	switch bulkUpdate.Operation {
-	case "role_update":
+	case models.BulkOperationRoleUpdate:
 		if bulkUpdate.Role == nil {
 			c.JSON(http.StatusBadRequest, models.ErrorResponse{
 				Error:   "Missing role",
@@ -408,8 +589,7 @@ func (h *Handler) BulkUpdateUsers(c *gin.Context) {
 			})
 			return
 		}
-		updates["role"] = string(*bulkUpdate.Role)
-	case "status_update":
+	case models.BulkOperationStatusUpdate:
 		if bulkUpdate.Status == nil {
 			c.JSON(http.StatusBadRequest, models.ErrorResponse{
 				Error:   "Missing status",
@@ -417,18 +597,29 @@ func (h *Handler) BulkUpdateUsers(c *gin.Context) {
 			})
 			return
 		}
-		if !models.ValidateUserStatus(string(*bulkUpdate.Status)) {
+		statusUpdate := models.UserStatusUpdateRequest{
+			Status:         *bulkUpdate.Status,
+			Reason:         bulkUpdate.Reason,
+			SuspendedUntil: bulkUpdate.SuspendedUntil,
+		}
+		if err := statusUpdate.Validate(); err != nil {
 			c.JSON(http.StatusBadRequest, models.ErrorResponse{
 				Error:   "Invalid status",
-				Message: "The specified status is not valid",
+				Message: err.Error(),
 			})
 			return
 		}
-		updates["status"] = string(*bulkUpdate.Status)
+	case models.BulkOperationDelete:
+	default:
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid operation",
+			Message: "Operation must be one of: status_update, role_update, delete",
+		})
+		return
 	}
 
 	// Perform bulk update
-	err := h.userRepo.BulkUpdateUsers(ctx, bulkUpdate.UserIDs, bulkUpdate.Operation, updates)
+	result, err := h.userRepo.BulkUpdateUsers(ctx, bulkUpdate, getActor(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Error:   "Failed to perform bulk update",
@@ -437,11 +628,12 @@ func (h *Handler) BulkUpdateUsers(c *gin.Context) {
 		return
 	}
 
+	message := "Bulk update completed"
+	if bulkUpdate.DryRun {
+		message = "Bulk update dry run completed, nothing was changed"
+	}
 	c.JSON(http.StatusOK, models.SuccessResponse{
-		Message: "Bulk update completed successfully",
-		Data: gin.H{
-			"operation":      bulkUpdate.Operation,
-			"affected_users": len(bulkUpdate.UserIDs),
-		},
+		Message: message,
+		Data:    result,
 	})
 }
@@ -1,9 +1,13 @@
 package api
 
 import (
+	"context"
+	"database/sql"
+	"errors"
 	"net/http"
 	"os"
 	"strings"
+	"time"
 
 	"user-service/internal/models"
 
@@ -77,6 +81,40 @@ func AuthMiddleware() gin.HandlerFunc {
 	}
 }
 
+// AccountStatusMiddleware rejects tokens of users whose account is no longer active
+// (suspended, deactivated or pending verification). Tokens that do not belong to a users
+// row, such as admin panel tokens, pass.
+func (h *Handler) AccountStatusMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, _ := c.Get("user_id")
+		userIDStr, _ := userID.(string)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		status, err := h.userRepo.GetUserAccountStatus(ctx, userIDStr)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Error:   "Failed to check account status",
+				Message: err.Error(),
+			})
+			c.Abort()
+			return
+		}
+		if err == nil && status != models.StatusActive {
+			c.JSON(http.StatusForbidden, models.ErrorResponse{
+				Error:   "Account " + string(status),
+				Message: "This account is not active",
+				Code:    "ACCOUNT_NOT_ACTIVE",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
 // AdminMiddleware ensures the user has admin privileges
 func AdminMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -95,6 +133,38 @@ func AdminMiddleware() gin.HandlerFunc {
 	}
 }
 
+// PartnerExclusionMiddleware rejects Partner-role users. Partners use the admin panel for the
+// order and cart views of their stores in order-service, not for user management.
+func (h *Handler) PartnerExclusionMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, _ := c.Get("user_id")
+		userIDStr, _ := userID.(string)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		role, err := h.userRepo.GetUserRole(ctx, userIDStr)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Error:   "Failed to check user role",
+				Message: err.Error(),
+			})
+			c.Abort()
+			return
+		}
+		if err == nil && role == models.RolePartner {
+			c.JSON(http.StatusForbidden, models.ErrorResponse{
+				Error:   "Admin access required",
+				Message: "Partners cannot access this endpoint",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
 // CORSMiddleware handles CORS headers
 func CORSMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -0,0 +1,266 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"user-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetPartners handles GET /api/admin/partners
+func (h *Handler) GetPartners(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	partners, err := h.userRepo.GetPartners(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to retrieve partners",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, partners)
+}
+
+// GetPartner handles GET /api/admin/partners/{partner_id}
+func (h *Handler) GetPartner(c *gin.Context) {
+	partnerID, ok := partnerIDParam(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	partner, err := h.userRepo.GetPartner(ctx, partnerID)
+	if err != nil {
+		respondPartnerError(c, "Failed to retrieve partner", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, partner)
+}
+
+// CreatePartner handles POST /api/admin/partners. The user gets the Partner role.
+func (h *Handler) CreatePartner(c *gin.Context) {
+	var req models.PartnerCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid partner",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	partner, err := h.userRepo.CreatePartner(ctx, req, getActor(c))
+	if err != nil {
+		respondPartnerError(c, "Failed to create partner", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, partner)
+}
+
+// DeletePartner handles DELETE /api/admin/partners/{partner_id}. The user goes back to the
+// Customer role.
+func (h *Handler) DeletePartner(c *gin.Context) {
+	partnerID, ok := partnerIDParam(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := h.userRepo.DeletePartner(ctx, partnerID); err != nil {
+		respondPartnerError(c, "Failed to delete partner", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: "Partner deleted successfully",
+	})
+}
+
+// SetPartnerRegion handles PUT /api/admin/partners/{partner_id}/region
+func (h *Handler) SetPartnerRegion(c *gin.Context) {
+	partnerID, ok := partnerIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req models.PartnerRegionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+		return
+	}
+	req.Validate()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	partner, err := h.userRepo.SetPartnerRegion(ctx, partnerID, req.RegionAssigned)
+	if err != nil {
+		respondPartnerError(c, "Failed to update partner region", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, partner)
+}
+
+// SetPartnerStores handles PUT /api/admin/partners/{partner_id}/stores. The list replaces the
+// stores assigned to the partner individually; stores of the partner's region stay in scope.
+func (h *Handler) SetPartnerStores(c *gin.Context) {
+	partnerID, ok := partnerIDParam(c)
+	if !ok {
+		return
+	}
+
+	req, ok := bindStoreAssignment(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	partner, err := h.userRepo.SetPartnerStores(ctx, partnerID, req.StoreIDs, getActor(c))
+	if err != nil {
+		respondPartnerError(c, "Failed to update partner stores", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, partner)
+}
+
+// GetRegions handles GET /api/admin/regions
+func (h *Handler) GetRegions(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	regions, err := h.userRepo.GetRegions(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to retrieve regions",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, regions)
+}
+
+// SetRegionStores handles PUT /api/admin/regions/{region}/stores. The listed stores become
+// the stores of the region; an empty list dissolves it.
+func (h *Handler) SetRegionStores(c *gin.Context) {
+	region := strings.TrimSpace(c.Param("region"))
+	if region == "" || len(region) > 255 {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid region",
+			Message: "Region must be between 1 and 255 characters",
+		})
+		return
+	}
+
+	req, ok := bindStoreAssignment(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := h.userRepo.SetRegionStores(ctx, region, req.StoreIDs)
+	if err != nil {
+		respondPartnerError(c, "Failed to update region stores", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// partnerIDParam reads the partner_id path parameter
+func partnerIDParam(c *gin.Context) (int, bool) {
+	partnerID, err := strconv.Atoi(c.Param("partner_id"))
+	if err != nil || partnerID <= 0 {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid partner ID",
+			Message: "Partner ID must be a positive integer",
+		})
+		return 0, false
+	}
+	return partnerID, true
+}
+
+// bindStoreAssignment reads and validates a store assignment body
+func bindStoreAssignment(c *gin.Context) (models.StoreAssignmentRequest, bool) {
+	var req models.StoreAssignmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+		return req, false
+	}
+
+	if err := req.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid store assignment",
+			Message: err.Error(),
+		})
+		return req, false
+	}
+
+	return req, true
+}
+
+// respondPartnerError maps partner and region errors to responses
+func respondPartnerError(c *gin.Context, message string, err error) {
+	switch {
+	case strings.Contains(err.Error(), "store") && strings.Contains(err.Error(), "not found"):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid store assignment",
+			Message: err.Error(),
+		})
+	case strings.Contains(err.Error(), "user not found"):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   "User not found",
+			Message: err.Error(),
+		})
+	case strings.Contains(err.Error(), "not found"):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   "Partner not found",
+			Message: err.Error(),
+		})
+	case strings.Contains(err.Error(), "already a partner"):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error:   message,
+			Message: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   message,
+			Message: err.Error(),
+		})
+	}
+}
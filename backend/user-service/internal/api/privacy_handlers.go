@@ -0,0 +1,255 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"user-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultPrivacyRequestLimit = 50
+	maxPrivacyRequestLimit     = 200
+)
+
+// RequestMyDataExport handles POST /api/privacy/exports
+func (h *Handler) RequestMyDataExport(c *gin.Context) {
+	userID, ok := requireTokenUserID(c)
+	if !ok {
+		return
+	}
+	h.createPrivacyRequest(c, userID, models.PrivacyRequestExport, models.PrivacySourceSelf)
+}
+
+// RequestMyErasure handles POST /api/privacy/erasure
+func (h *Handler) RequestMyErasure(c *gin.Context) {
+	userID, ok := requireTokenUserID(c)
+	if !ok {
+		return
+	}
+	h.createPrivacyRequest(c, userID, models.PrivacyRequestErasure, models.PrivacySourceSelf)
+}
+
+// GetMyPrivacyRequests handles GET /api/privacy/requests
+func (h *Handler) GetMyPrivacyRequests(c *gin.Context) {
+	userID, ok := requireTokenUserID(c)
+	if !ok {
+		return
+	}
+
+	params, ok := bindPrivacyRequestListParams(c)
+	if !ok {
+		return
+	}
+	params.UserID = userID
+
+	h.listPrivacyRequests(c, params)
+}
+
+// DownloadMyDataExport handles GET /api/privacy/requests/{request_id}/download
+func (h *Handler) DownloadMyDataExport(c *gin.Context) {
+	userID, ok := requireTokenUserID(c)
+	if !ok {
+		return
+	}
+	h.downloadDataExport(c, userID)
+}
+
+// RequestUserDataExport handles POST /api/admin/users/{user_id}/privacy/exports
+func (h *Handler) RequestUserDataExport(c *gin.Context) {
+	h.createPrivacyRequest(c, c.Param("user_id"), models.PrivacyRequestExport, models.PrivacySourceAdmin)
+}
+
+// RequestUserErasure handles POST /api/admin/users/{user_id}/privacy/erasure
+func (h *Handler) RequestUserErasure(c *gin.Context) {
+	h.createPrivacyRequest(c, c.Param("user_id"), models.PrivacyRequestErasure, models.PrivacySourceAdmin)
+}
+
+// GetPrivacyRequests handles GET /api/admin/privacy-requests
+func (h *Handler) GetPrivacyRequests(c *gin.Context) {
+	params, ok := bindPrivacyRequestListParams(c)
+	if !ok {
+		return
+	}
+	h.listPrivacyRequests(c, params)
+}
+
+// DownloadDataExport handles GET /api/admin/privacy-requests/{request_id}/download
+func (h *Handler) DownloadDataExport(c *gin.Context) {
+	h.downloadDataExport(c, "")
+}
+
+// createPrivacyRequest queues an export or erasure request for the background worker
+func (h *Handler) createPrivacyRequest(c *gin.Context, userID string, requestType models.PrivacyRequestType, source string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var body models.PrivacyRequestCreateRequest
+	if err := c.ShouldBindJSON(&body); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	// Users acting on their own data are recorded as 'self', not by the email an erasure removes
+	actor := getActor(c)
+	if source == models.PrivacySourceSelf {
+		actor = models.PrivacySourceSelf
+	}
+
+	req, err := h.userRepo.CreatePrivacyRequest(ctx, userID, requestType, source, actor, strings.TrimSpace(body.Reason))
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Error:   "User not found",
+				Message: "The specified user does not exist",
+			})
+			return
+		}
+		if strings.Contains(err.Error(), "already") {
+			c.JSON(http.StatusConflict, models.ErrorResponse{
+				Error:   "Privacy request not accepted",
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to create privacy request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, req)
+}
+
+// listPrivacyRequests responds with the privacy requests matching params
+func (h *Handler) listPrivacyRequests(c *gin.Context, params models.PrivacyRequestListParams) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	requests, err := h.userRepo.GetPrivacyRequests(ctx, params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to retrieve privacy requests",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, requests)
+}
+
+// downloadDataExport sends the ZIP bundle of an export request. When ownerID is set the request
+// must belong to that user.
+func (h *Handler) downloadDataExport(c *gin.Context, ownerID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	requestID := c.Param("request_id")
+
+	req, err := h.userRepo.GetPrivacyRequest(ctx, requestID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "invalid input syntax") {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Error:   "Privacy request not found",
+				Message: "The specified privacy request does not exist",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to retrieve privacy request",
+			Message: err.Error(),
+		})
+		return
+	}
+	if ownerID != "" && req.UserID != ownerID {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   "Privacy request not found",
+			Message: "The specified privacy request does not exist",
+		})
+		return
+	}
+
+	bundle, err := h.userRepo.GetExportBundle(ctx, requestID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Error:   "Export not available",
+				Message: "The export is not ready yet, has failed or has expired",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to retrieve export",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="user-data-`+req.ID+`.zip"`)
+	c.Data(http.StatusOK, "application/zip", bundle)
+}
+
+// bindPrivacyRequestListParams reads the filters of a privacy request listing
+func bindPrivacyRequestListParams(c *gin.Context) (models.PrivacyRequestListParams, bool) {
+	var params models.PrivacyRequestListParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid query parameters",
+			Message: err.Error(),
+		})
+		return params, false
+	}
+
+	switch params.Type {
+	case "", models.PrivacyRequestExport, models.PrivacyRequestErasure:
+	default:
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid type",
+			Message: "type must be one of: export, erasure",
+		})
+		return params, false
+	}
+
+	switch params.Status {
+	case "", models.PrivacyStatusPending, models.PrivacyStatusProcessing, models.PrivacyStatusCompleted, models.PrivacyStatusFailed:
+	default:
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid status",
+			Message: "status must be one of: pending, processing, completed, failed",
+		})
+		return params, false
+	}
+
+	if params.Limit <= 0 {
+		params.Limit = defaultPrivacyRequestLimit
+	}
+	if params.Limit > maxPrivacyRequestLimit {
+		params.Limit = maxPrivacyRequestLimit
+	}
+
+	return params, true
+}
+
+// requireTokenUserID returns the user ID of the token, responding 401 when there is none
+func requireTokenUserID(c *gin.Context) (string, bool) {
+	userID, _ := c.Get("user_id")
+	userIDStr, _ := userID.(string)
+	if userIDStr == "" {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "The token does not identify a user",
+		})
+		return "", false
+	}
+	return userIDStr, true
+}
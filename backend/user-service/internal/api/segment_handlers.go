@@ -0,0 +1,253 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"user-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// segmentEvaluationTimeout bounds the requests that evaluate segment rules against all users
+const segmentEvaluationTimeout = 2 * time.Minute
+
+// GetSegmentFields handles GET /api/admin/segments/fields
+func (h *Handler) GetSegmentFields(c *gin.Context) {
+	c.JSON(http.StatusOK, models.SegmentFields)
+}
+
+// GetSegments handles GET /api/admin/segments
+func (h *Handler) GetSegments(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	segments, err := h.userRepo.GetSegments(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to retrieve segments",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, segments)
+}
+
+// GetSegment handles GET /api/admin/segments/{segment_id}
+func (h *Handler) GetSegment(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	segment, err := h.userRepo.GetSegment(ctx, c.Param("segment_id"))
+	if err != nil {
+		respondSegmentError(c, "Failed to retrieve segment", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, segment)
+}
+
+// CreateSegment handles POST /api/admin/segments
+func (h *Handler) CreateSegment(c *gin.Context) {
+	req, ok := bindSegmentRequest(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), segmentEvaluationTimeout)
+	defer cancel()
+
+	segment, err := h.userRepo.CreateSegment(ctx, req, getActor(c))
+	if err != nil {
+		respondSegmentError(c, "Failed to create segment", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, segment)
+}
+
+// UpdateSegment handles PUT /api/admin/segments/{segment_id}
+func (h *Handler) UpdateSegment(c *gin.Context) {
+	req, ok := bindSegmentRequest(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), segmentEvaluationTimeout)
+	defer cancel()
+
+	segment, err := h.userRepo.UpdateSegment(ctx, c.Param("segment_id"), req)
+	if err != nil {
+		respondSegmentError(c, "Failed to update segment", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, segment)
+}
+
+// DeleteSegment handles DELETE /api/admin/segments/{segment_id}
+func (h *Handler) DeleteSegment(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := h.userRepo.DeleteSegment(ctx, c.Param("segment_id")); err != nil {
+		respondSegmentError(c, "Failed to delete segment", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: "Segment deleted successfully",
+	})
+}
+
+// PreviewSegment handles POST /api/admin/segments/preview. It counts the users a rule set
+// matches without saving it.
+func (h *Handler) PreviewSegment(c *gin.Context) {
+	var rules models.SegmentRules
+	if err := c.ShouldBindJSON(&rules); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+		return
+	}
+	if err := rules.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid segment rules",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), segmentEvaluationTimeout)
+	defer cancel()
+
+	count, err := h.userRepo.PreviewSegment(ctx, rules)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to preview segment",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SegmentPreviewResponse{MemberCount: count})
+}
+
+// RefreshSegment handles POST /api/admin/segments/{segment_id}/refresh
+func (h *Handler) RefreshSegment(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), segmentEvaluationTimeout)
+	defer cancel()
+
+	segment, err := h.userRepo.RefreshSegment(ctx, c.Param("segment_id"))
+	if err != nil {
+		respondSegmentError(c, "Failed to refresh segment", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, segment)
+}
+
+// GetSegmentMembers handles GET /api/admin/segments/{segment_id}/members. It takes the query
+// parameters of GET /api/admin/users, limited to the members of the segment.
+func (h *Handler) GetSegmentMembers(c *gin.Context) {
+	params, ok := h.segmentMemberParams(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	response, err := h.userRepo.GetUsers(ctx, params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to retrieve segment members",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ExportSegmentMembers handles GET /api/admin/segments/{segment_id}/export, the CSV of
+// GET /api/admin/users/export limited to the members of the segment
+func (h *Handler) ExportSegmentMembers(c *gin.Context) {
+	params, ok := h.segmentMemberParams(c)
+	if !ok {
+		return
+	}
+
+	h.streamUserExport(c, params, "segment-"+params.SegmentID)
+}
+
+// segmentMemberParams reads the user list parameters for the members of an existing segment
+func (h *Handler) segmentMemberParams(c *gin.Context) (models.UserSearchParams, bool) {
+	params, err := parseUserSearchParams(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid query parameters",
+			Message: err.Error(),
+		})
+		return params, false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	segment, err := h.userRepo.GetSegment(ctx, c.Param("segment_id"))
+	if err != nil {
+		respondSegmentError(c, "Failed to retrieve segment", err)
+		return params, false
+	}
+
+	params.SegmentID = segment.ID
+	return params, true
+}
+
+// bindSegmentRequest reads and validates a segment body
+func bindSegmentRequest(c *gin.Context) (models.SegmentRequest, bool) {
+	var req models.SegmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+		return req, false
+	}
+
+	if err := req.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid segment",
+			Message: err.Error(),
+		})
+		return req, false
+	}
+
+	return req, true
+}
+
+// respondSegmentError maps segment errors to responses
+func respondSegmentError(c *gin.Context, message string, err error) {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   "Segment not found",
+			Message: err.Error(),
+		})
+	case strings.Contains(err.Error(), "already exists"):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error:   message,
+			Message: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   message,
+			Message: err.Error(),
+		})
+	}
+}
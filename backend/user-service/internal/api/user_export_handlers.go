@@ -0,0 +1,129 @@
+package api
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"user-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+var userExportHeader = []string{
+	"id", "username", "email", "first_name", "last_name", "phone", "locale", "marketing_consent",
+	"role", "status", "created_at", "last_login", "order_count", "total_spent",
+}
+
+// ExportUsers handles GET /api/admin/users/export. It streams the users matching the same
+// filters and ordering as GET /api/admin/users as CSV, without pagination.
+func (h *Handler) ExportUsers(c *gin.Context) {
+	params, err := parseUserSearchParams(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid query parameters",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	h.streamUserExport(c, params, "users")
+}
+
+// streamUserExport writes the users matching params as a CSV attachment named after prefix
+func (h *Handler) streamUserExport(c *gin.Context, params models.UserSearchParams, prefix string) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Minute)
+	defer cancel()
+
+	// The response starts with the first user so that a failing query can still be reported
+	writer := csv.NewWriter(c.Writer)
+	started := false
+	start := func() error {
+		started = true
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-%s.csv"`, prefix, time.Now().UTC().Format("20060102-150405")))
+		c.Status(http.StatusOK)
+		return writer.Write(userExportHeader)
+	}
+
+	count := 0
+	err := h.userRepo.ExportUsers(ctx, params, func(row *models.UserExportRow) error {
+		if !started {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		count++
+		if err := writer.Write(userExportRecord(row)); err != nil {
+			return err
+		}
+		if count%500 == 0 {
+			writer.Flush()
+		}
+		return writer.Error()
+	})
+	if err == nil && !started {
+		err = start()
+	}
+	if err != nil {
+		if !started {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Error:   "Failed to export users",
+				Message: err.Error(),
+			})
+			return
+		}
+		// Headers are already sent; the truncated file is all the client gets
+		log.Printf("Export of %s by %s failed after %d users: %v", prefix, getActor(c), count, err)
+		writer.Flush()
+		return
+	}
+
+	writer.Flush()
+	log.Printf("Export of %s by %s: %d users", prefix, getActor(c), count)
+}
+
+// userExportRecord formats a user as a CSV record
+func userExportRecord(row *models.UserExportRow) []string {
+	lastLogin := ""
+	if row.LastLogin != nil {
+		lastLogin = row.LastLogin.UTC().Format(time.RFC3339)
+	}
+
+	return []string{
+		row.ID,
+		csvText(row.Username),
+		csvText(row.Email),
+		csvText(stringValue(row.FirstName)),
+		csvText(stringValue(row.LastName)),
+		csvText(stringValue(row.Phone)),
+		stringValue(row.Locale),
+		strconv.FormatBool(row.MarketingConsent),
+		string(row.Role),
+		string(row.Status),
+		row.CreatedAt.UTC().Format(time.RFC3339),
+		lastLogin,
+		strconv.Itoa(row.OrderCount),
+		strconv.FormatFloat(row.TotalSpent, 'f', 2, 64),
+	}
+}
+
+// csvText keeps user-entered text from being read as a formula by spreadsheet applications
+func csvText(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
@@ -0,0 +1,248 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"user-service/internal/models"
+)
+
+const addressColumns = `
+	id, user_id, label, recipient_name, phone, line1, line2, city, region, postal_code,
+	country_code, latitude, longitude, delivery_instructions, is_default, created_at, updated_at`
+
+func scanAddress(row rowScanner) (*models.Address, error) {
+	var address models.Address
+	err := row.Scan(
+		&address.ID,
+		&address.UserID,
+		&address.Label,
+		&address.RecipientName,
+		&address.Phone,
+		&address.Line1,
+		&address.Line2,
+		&address.City,
+		&address.Region,
+		&address.PostalCode,
+		&address.CountryCode,
+		&address.Latitude,
+		&address.Longitude,
+		&address.DeliveryInstructions,
+		&address.IsDefault,
+		&address.CreatedAt,
+		&address.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &address, nil
+}
+
+// GetAddresses lists a user's addresses, the default one first
+func (r *UserRepository) GetAddresses(ctx context.Context, userID string) ([]models.Address, error) {
+	rows, err := r.db.DB.QueryContext(ctx, `
+		SELECT `+addressColumns+`
+		FROM user_addresses
+		WHERE user_id::text = $1
+		ORDER BY is_default DESC, created_at
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query addresses: %w", err)
+	}
+	defer rows.Close()
+
+	addresses := []models.Address{}
+	for rows.Next() {
+		address, err := scanAddress(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan address: %w", err)
+		}
+		addresses = append(addresses, *address)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over addresses: %w", err)
+	}
+
+	return addresses, nil
+}
+
+// GetAddress retrieves one of a user's addresses
+func (r *UserRepository) GetAddress(ctx context.Context, userID, addressID string) (*models.Address, error) {
+	address, err := scanAddress(r.db.DB.QueryRowContext(ctx, `
+		SELECT `+addressColumns+`
+		FROM user_addresses
+		WHERE id::text = $1 AND user_id::text = $2
+	`, addressID, userID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("address not found")
+		}
+		return nil, fmt.Errorf("failed to get address: %w", err)
+	}
+	return address, nil
+}
+
+// CreateAddress adds an address to a user's address book. The first address becomes the
+// default even when req does not ask for it.
+func (r *UserRepository) CreateAddress(ctx context.Context, userID string, req models.AddressRequest) (*models.Address, error) {
+	tx, err := r.db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock the user so concurrent requests agree on the count and the default
+	var count int
+	err = tx.QueryRowContext(ctx, `
+		SELECT (SELECT COUNT(*) FROM user_addresses WHERE user_id = u.id)
+		FROM users u WHERE u.id::text = $1 FOR UPDATE
+	`, userID).Scan(&count)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("user not found")
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if count >= models.MaxAddressesPerUser {
+		return nil, fmt.Errorf("address book is full: at most %d addresses are allowed", models.MaxAddressesPerUser)
+	}
+
+	isDefault := req.IsDefault || count == 0
+	if isDefault {
+		if _, err := tx.ExecContext(ctx, "UPDATE user_addresses SET is_default = FALSE WHERE user_id = $1 AND is_default", userID); err != nil {
+			return nil, fmt.Errorf("failed to clear default address: %w", err)
+		}
+	}
+
+	address, err := scanAddress(tx.QueryRowContext(ctx, `
+		INSERT INTO user_addresses (
+			user_id, label, recipient_name, phone, line1, line2, city, region, postal_code,
+			country_code, latitude, longitude, delivery_instructions, is_default
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		RETURNING `+addressColumns,
+		userID, req.Label, req.RecipientName, req.Phone, req.Line1, req.Line2, req.City, req.Region,
+		req.PostalCode, req.CountryCode, req.Latitude, req.Longitude, req.DeliveryInstructions, isDefault))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create address: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return address, nil
+}
+
+// UpdateAddress replaces the contents of one of a user's addresses. Orders keep the copy
+// taken when they were placed.
+func (r *UserRepository) UpdateAddress(ctx context.Context, userID, addressID string, req models.AddressRequest) (*models.Address, error) {
+	tx, err := r.db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if req.IsDefault {
+		if _, err := tx.ExecContext(ctx, "UPDATE user_addresses SET is_default = FALSE WHERE user_id::text = $1 AND is_default AND id::text <> $2", userID, addressID); err != nil {
+			return nil, fmt.Errorf("failed to clear default address: %w", err)
+		}
+	}
+
+	address, err := scanAddress(tx.QueryRowContext(ctx, `
+		UPDATE user_addresses
+		SET label = $3, recipient_name = $4, phone = $5, line1 = $6, line2 = $7, city = $8, region = $9,
+		    postal_code = $10, country_code = $11, latitude = $12, longitude = $13,
+		    delivery_instructions = $14, is_default = $15, updated_at = now()
+		WHERE id::text = $1 AND user_id::text = $2
+		RETURNING `+addressColumns,
+		addressID, userID, req.Label, req.RecipientName, req.Phone, req.Line1, req.Line2, req.City, req.Region,
+		req.PostalCode, req.CountryCode, req.Latitude, req.Longitude, req.DeliveryInstructions, req.IsDefault))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("address not found")
+		}
+		return nil, fmt.Errorf("failed to update address: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return address, nil
+}
+
+// SetDefaultAddress makes one of a user's addresses the default
+func (r *UserRepository) SetDefaultAddress(ctx context.Context, userID, addressID string) (*models.Address, error) {
+	tx, err := r.db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "UPDATE user_addresses SET is_default = FALSE WHERE user_id::text = $1 AND is_default AND id::text <> $2", userID, addressID); err != nil {
+		return nil, fmt.Errorf("failed to clear default address: %w", err)
+	}
+
+	address, err := scanAddress(tx.QueryRowContext(ctx, `
+		UPDATE user_addresses
+		SET is_default = TRUE, updated_at = now()
+		WHERE id::text = $1 AND user_id::text = $2
+		RETURNING `+addressColumns,
+		addressID, userID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("address not found")
+		}
+		return nil, fmt.Errorf("failed to set default address: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return address, nil
+}
+
+// DeleteAddress removes one of a user's addresses. When it was the default, the oldest
+// remaining address takes over.
+func (r *UserRepository) DeleteAddress(ctx context.Context, userID, addressID string) error {
+	tx, err := r.db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var wasDefault bool
+	err = tx.QueryRowContext(ctx, `
+		DELETE FROM user_addresses
+		WHERE id::text = $1 AND user_id::text = $2
+		RETURNING is_default
+	`, addressID, userID).Scan(&wasDefault)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("address not found")
+		}
+		return fmt.Errorf("failed to delete address: %w", err)
+	}
+
+	if wasDefault {
+		_, err = tx.ExecContext(ctx, `
+			UPDATE user_addresses SET is_default = TRUE, updated_at = now()
+			WHERE id = (
+				SELECT id FROM user_addresses WHERE user_id::text = $1 ORDER BY created_at LIMIT 1
+			)
+		`, userID)
+		if err != nil {
+			return fmt.Errorf("failed to promote default address: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,366 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"user-service/internal/models"
+
+	"github.com/lib/pq"
+)
+
+// storeIDArray converts store IDs for use as an integer[] parameter
+func storeIDArray(storeIDs []int) interface{} {
+	ids := make([]int64, len(storeIDs))
+	for i, id := range storeIDs {
+		ids[i] = int64(id)
+	}
+	return pq.Array(ids)
+}
+
+// GetPartners lists all partners with the stores they may see
+func (r *UserRepository) GetPartners(ctx context.Context) ([]models.Partner, error) {
+	return r.queryPartners(ctx, "")
+}
+
+// GetPartner retrieves a partner with the stores they may see
+func (r *UserRepository) GetPartner(ctx context.Context, partnerID int) (*models.Partner, error) {
+	partners, err := r.queryPartners(ctx, "WHERE p.partner_id = $1", partnerID)
+	if err != nil {
+		return nil, err
+	}
+	if len(partners) == 0 {
+		return nil, fmt.Errorf("partner not found")
+	}
+	return &partners[0], nil
+}
+
+// queryPartners loads the partners matching where along with their store scope
+func (r *UserRepository) queryPartners(ctx context.Context, where string, args ...interface{}) ([]models.Partner, error) {
+	rows, err := r.db.DB.QueryContext(ctx, `
+		SELECT p.partner_id, p.user_id, u.username, u.email, p.region_assigned, p.created_at, p.updated_at
+		FROM partners p
+		JOIN users u ON u.id = p.user_id
+		`+where+`
+		ORDER BY LOWER(u.username)
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query partners: %w", err)
+	}
+	defer rows.Close()
+
+	partners := []models.Partner{}
+	index := make(map[int]int)
+	for rows.Next() {
+		var partner models.Partner
+		err := rows.Scan(
+			&partner.ID,
+			&partner.UserID,
+			&partner.Username,
+			&partner.Email,
+			&partner.RegionAssigned,
+			&partner.CreatedAt,
+			&partner.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan partner: %w", err)
+		}
+		partner.Stores = []models.PartnerStore{}
+		index[partner.ID] = len(partners)
+		partners = append(partners, partner)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over partners: %w", err)
+	}
+	if len(partners) == 0 {
+		return partners, nil
+	}
+
+	partnerIDs := make([]int, 0, len(partners))
+	for _, partner := range partners {
+		partnerIDs = append(partnerIDs, partner.ID)
+	}
+
+	// A store both in the region and assigned individually is listed once, as assigned
+	storeRows, err := r.db.DB.QueryContext(ctx, `
+		SELECT DISTINCT ON (sc.partner_id, s.store_id)
+		       sc.partner_id, s.store_id, s.name, s.city, s.region, sc.source
+		FROM partner_store_scope sc
+		JOIN stores s ON s.store_id = sc.store_id
+		WHERE sc.partner_id = ANY($1)
+		ORDER BY sc.partner_id, s.store_id, sc.source
+	`, storeIDArray(partnerIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query partner stores: %w", err)
+	}
+	defer storeRows.Close()
+
+	for storeRows.Next() {
+		var partnerID int
+		var store models.PartnerStore
+		if err := storeRows.Scan(&partnerID, &store.StoreID, &store.Name, &store.City, &store.Region, &store.Source); err != nil {
+			return nil, fmt.Errorf("failed to scan partner store: %w", err)
+		}
+		partner := &partners[index[partnerID]]
+		partner.Stores = append(partner.Stores, store)
+	}
+	if err = storeRows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over partner stores: %w", err)
+	}
+
+	return partners, nil
+}
+
+// CreatePartner makes an existing user a partner: it gives them the Partner role and records
+// their region and individually assigned stores
+func (r *UserRepository) CreatePartner(ctx context.Context, req models.PartnerCreateRequest, actor string) (*models.Partner, error) {
+	tx, err := r.db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var erasedAt sql.NullTime
+	err = tx.QueryRowContext(ctx, "SELECT erased_at FROM users WHERE id::text = $1 FOR UPDATE", req.UserID).Scan(&erasedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("user not found")
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if erasedAt.Valid {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	var partnerID int
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO partners (user_id, region_assigned)
+		VALUES ($1, $2)
+		RETURNING partner_id
+	`, req.UserID, req.RegionAssigned).Scan(&partnerID)
+	if err != nil {
+		if strings.Contains(err.Error(), "idx_partners_user") {
+			return nil, fmt.Errorf("user is already a partner")
+		}
+		return nil, fmt.Errorf("failed to create partner: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, "UPDATE users SET role = 'Partner', updated_at = now() WHERE id = $1", req.UserID); err != nil {
+		return nil, fmt.Errorf("failed to update user role: %w", err)
+	}
+
+	if err := assignPartnerStores(ctx, tx, partnerID, req.StoreIDs, actor); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return r.GetPartner(ctx, partnerID)
+}
+
+// SetPartnerRegion assigns a region to a partner; a nil region removes it
+func (r *UserRepository) SetPartnerRegion(ctx context.Context, partnerID int, region *string) (*models.Partner, error) {
+	result, err := r.db.DB.ExecContext(ctx,
+		"UPDATE partners SET region_assigned = $2, updated_at = now() WHERE partner_id = $1",
+		partnerID, region)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update partner region: %w", err)
+	}
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return nil, fmt.Errorf("partner not found")
+	}
+
+	return r.GetPartner(ctx, partnerID)
+}
+
+// SetPartnerStores replaces the stores assigned to a partner individually
+func (r *UserRepository) SetPartnerStores(ctx context.Context, partnerID int, storeIDs []int, actor string) (*models.Partner, error) {
+	tx, err := r.db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, "UPDATE partners SET updated_at = now() WHERE partner_id = $1", partnerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update partner: %w", err)
+	}
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return nil, fmt.Errorf("partner not found")
+	}
+
+	// Stores kept in the assignment keep their original assigned_at
+	if _, err := tx.ExecContext(ctx,
+		"DELETE FROM partner_stores WHERE partner_id = $1 AND NOT (store_id = ANY($2))",
+		partnerID, storeIDArray(storeIDs)); err != nil {
+		return nil, fmt.Errorf("failed to remove partner stores: %w", err)
+	}
+
+	if err := assignPartnerStores(ctx, tx, partnerID, storeIDs, actor); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return r.GetPartner(ctx, partnerID)
+}
+
+// assignPartnerStores adds stores to a partner, skipping those already assigned
+func assignPartnerStores(ctx context.Context, tx *sql.Tx, partnerID int, storeIDs []int, actor string) error {
+	if len(storeIDs) == 0 {
+		return nil
+	}
+	if err := checkStoresExist(ctx, tx, storeIDs); err != nil {
+		return err
+	}
+
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO partner_stores (partner_id, store_id, assigned_by)
+		SELECT $1, store_id, $3 FROM unnest($2::integer[]) AS store_id
+		ON CONFLICT (partner_id, store_id) DO NOTHING
+	`, partnerID, storeIDArray(storeIDs), actor)
+	if err != nil {
+		return fmt.Errorf("failed to assign partner stores: %w", err)
+	}
+	return nil
+}
+
+// checkStoresExist reports the first of storeIDs that does not exist
+func checkStoresExist(ctx context.Context, tx *sql.Tx, storeIDs []int) error {
+	var missing sql.NullInt64
+	err := tx.QueryRowContext(ctx, `
+		SELECT MIN(id) FROM unnest($1::integer[]) AS id
+		WHERE NOT EXISTS (SELECT 1 FROM stores s WHERE s.store_id = id)
+	`, storeIDArray(storeIDs)).Scan(&missing)
+	if err != nil {
+		return fmt.Errorf("failed to check stores: %w", err)
+	}
+	if missing.Valid {
+		return fmt.Errorf("store %d not found", missing.Int64)
+	}
+	return nil
+}
+
+// DeletePartner removes a partner and returns the user to the Customer role
+func (r *UserRepository) DeletePartner(ctx context.Context, partnerID int) error {
+	tx, err := r.db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var userID string
+	err = tx.QueryRowContext(ctx, "DELETE FROM partners WHERE partner_id = $1 RETURNING user_id", partnerID).Scan(&userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("partner not found")
+		}
+		return fmt.Errorf("failed to delete partner: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		"UPDATE users SET role = 'Customer', updated_at = now() WHERE id = $1 AND role = 'Partner'", userID); err != nil {
+		return fmt.Errorf("failed to update user role: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetRegions lists the regions named by stores or partners. Names are compared ignoring case.
+func (r *UserRepository) GetRegions(ctx context.Context) ([]models.Region, error) {
+	rows, err := r.db.DB.QueryContext(ctx, `
+		SELECT MIN(name), SUM(store_count), SUM(partner_count)
+		FROM (
+			SELECT region AS name, 1 AS store_count, 0 AS partner_count FROM stores WHERE region IS NOT NULL
+			UNION ALL
+			SELECT region_assigned, 0, 1 FROM partners WHERE region_assigned IS NOT NULL
+		) named
+		GROUP BY LOWER(name)
+		ORDER BY LOWER(name)
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query regions: %w", err)
+	}
+	defer rows.Close()
+
+	regions := []models.Region{}
+	for rows.Next() {
+		var region models.Region
+		if err := rows.Scan(&region.Name, &region.StoreCount, &region.PartnerCount); err != nil {
+			return nil, fmt.Errorf("failed to scan region: %w", err)
+		}
+		regions = append(regions, region)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over regions: %w", err)
+	}
+
+	return regions, nil
+}
+
+// SetRegionStores makes storeIDs the stores of a region. Stores of the region missing from
+// the list leave it; listed stores move to it from any other region.
+func (r *UserRepository) SetRegionStores(ctx context.Context, region string, storeIDs []int) (*models.Region, error) {
+	tx, err := r.db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := checkStoresExist(ctx, tx, storeIDs); err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE stores SET region = NULL, updated_at = now()
+		WHERE LOWER(region) = LOWER($1) AND NOT (store_id = ANY($2))
+	`, region, storeIDArray(storeIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to remove stores from region: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE stores SET region = $1, updated_at = now()
+		WHERE store_id = ANY($2) AND region IS DISTINCT FROM $1
+	`, region, storeIDArray(storeIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to add stores to region: %w", err)
+	}
+
+	result := &models.Region{Name: region}
+	err = tx.QueryRowContext(ctx, `
+		SELECT (SELECT COUNT(*) FROM stores WHERE LOWER(region) = LOWER($1)),
+		       (SELECT COUNT(*) FROM partners WHERE LOWER(region_assigned) = LOWER($1))
+	`, region).Scan(&result.StoreCount, &result.PartnerCount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count region: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return result, nil
+}
+
+// GetUserRole returns the role of a user; sql.ErrNoRows is wrapped when the token does not
+// belong to a users row
+func (r *UserRepository) GetUserRole(ctx context.Context, userID string) (models.UserRole, error) {
+	var role models.UserRole
+	err := r.db.DB.QueryRowContext(ctx, "SELECT role FROM users WHERE id::text = $1", userID).Scan(&role)
+	if err != nil {
+		return "", fmt.Errorf("failed to get user role: %w", err)
+	}
+	return role, nil
+}
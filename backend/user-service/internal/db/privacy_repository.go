@@ -0,0 +1,528 @@
+package db
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"user-service/internal/models"
+)
+
+// ExportBundleTTL is how long a generated data export can be downloaded
+const ExportBundleTTL = 7 * 24 * time.Hour
+
+const privacyRequestColumns = `
+	id, user_id, request_type, status, source, requested_by, reason, error,
+	export_bundle IS NOT NULL AND export_expires_at > now(), export_expires_at,
+	created_at, started_at, completed_at`
+
+// rowScanner is implemented by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanPrivacyRequest(row rowScanner) (*models.PrivacyRequest, error) {
+	var req models.PrivacyRequest
+	err := row.Scan(
+		&req.ID,
+		&req.UserID,
+		&req.Type,
+		&req.Status,
+		&req.Source,
+		&req.RequestedBy,
+		&req.Reason,
+		&req.Error,
+		&req.DownloadAvailable,
+		&req.ExportExpiresAt,
+		&req.CreatedAt,
+		&req.StartedAt,
+		&req.CompletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+// CreatePrivacyRequest queues an export or erasure request for the background worker. A user
+// can only have one open request of each type.
+func (r *UserRepository) CreatePrivacyRequest(ctx context.Context, userID string, requestType models.PrivacyRequestType, source, requestedBy, reason string) (*models.PrivacyRequest, error) {
+	var erasedAt sql.NullTime
+	err := r.db.DB.QueryRowContext(ctx, "SELECT erased_at FROM users WHERE id::text = $1", userID).Scan(&erasedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("user not found")
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if erasedAt.Valid {
+		return nil, fmt.Errorf("the personal data of this user has already been erased")
+	}
+
+	var reasonValue *string
+	if reason != "" {
+		reasonValue = &reason
+	}
+
+	req, err := scanPrivacyRequest(r.db.DB.QueryRowContext(ctx, `
+		INSERT INTO privacy_requests (user_id, request_type, source, requested_by, reason)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING `+privacyRequestColumns,
+		userID, string(requestType), source, requestedBy, reasonValue))
+	if err != nil {
+		if strings.Contains(err.Error(), "idx_privacy_requests_open") {
+			return nil, fmt.Errorf("a %s request for this user is already in progress", requestType)
+		}
+		return nil, fmt.Errorf("failed to create privacy request: %w", err)
+	}
+	return req, nil
+}
+
+// GetPrivacyRequests lists privacy requests, newest first
+func (r *UserRepository) GetPrivacyRequests(ctx context.Context, params models.PrivacyRequestListParams) ([]models.PrivacyRequest, error) {
+	var whereConditions []string
+	var args []interface{}
+	argIndex := 1
+
+	if params.UserID != "" {
+		whereConditions = append(whereConditions, fmt.Sprintf("user_id::text = $%d", argIndex))
+		args = append(args, params.UserID)
+		argIndex++
+	}
+	if params.Type != "" {
+		whereConditions = append(whereConditions, fmt.Sprintf("request_type = $%d", argIndex))
+		args = append(args, string(params.Type))
+		argIndex++
+	}
+	if params.Status != "" {
+		whereConditions = append(whereConditions, fmt.Sprintf("status = $%d", argIndex))
+		args = append(args, string(params.Status))
+		argIndex++
+	}
+
+	query := "SELECT " + privacyRequestColumns + " FROM privacy_requests"
+	if len(whereConditions) > 0 {
+		query += " WHERE " + strings.Join(whereConditions, " AND ")
+	}
+	query += fmt.Sprintf(" ORDER BY created_at DESC LIMIT $%d", argIndex)
+	args = append(args, params.Limit)
+
+	rows, err := r.db.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query privacy requests: %w", err)
+	}
+	defer rows.Close()
+
+	requests := []models.PrivacyRequest{}
+	for rows.Next() {
+		req, err := scanPrivacyRequest(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan privacy request: %w", err)
+		}
+		requests = append(requests, *req)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over privacy requests: %w", err)
+	}
+
+	return requests, nil
+}
+
+// GetPrivacyRequest retrieves a privacy request by ID
+func (r *UserRepository) GetPrivacyRequest(ctx context.Context, requestID string) (*models.PrivacyRequest, error) {
+	req, err := scanPrivacyRequest(r.db.DB.QueryRowContext(ctx,
+		"SELECT "+privacyRequestColumns+" FROM privacy_requests WHERE id::text = $1", requestID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("privacy request not found")
+		}
+		return nil, fmt.Errorf("failed to get privacy request: %w", err)
+	}
+	return req, nil
+}
+
+// GetExportBundle returns the ZIP bundle of a completed export that has not expired
+func (r *UserRepository) GetExportBundle(ctx context.Context, requestID string) ([]byte, error) {
+	var bundle []byte
+	err := r.db.DB.QueryRowContext(ctx, `
+		SELECT export_bundle FROM privacy_requests
+		WHERE id::text = $1 AND request_type = 'export' AND status = 'completed'
+		  AND export_bundle IS NOT NULL AND export_expires_at > now()
+	`, requestID).Scan(&bundle)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("export bundle not found or expired")
+		}
+		return nil, fmt.Errorf("failed to get export bundle: %w", err)
+	}
+	return bundle, nil
+}
+
+// ClaimNextPrivacyRequest marks the oldest pending request as processing and returns it, or
+// nil when nothing is pending. Concurrent workers never claim the same request.
+func (r *UserRepository) ClaimNextPrivacyRequest(ctx context.Context) (*models.PrivacyRequest, error) {
+	req, err := scanPrivacyRequest(r.db.DB.QueryRowContext(ctx, `
+		UPDATE privacy_requests
+		SET status = 'processing', started_at = now()
+		WHERE id = (
+			SELECT id FROM privacy_requests
+			WHERE status = 'pending'
+			ORDER BY created_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+privacyRequestColumns))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim privacy request: %w", err)
+	}
+	return req, nil
+}
+
+// CompleteExport stores the bundle of an export request and marks it completed
+func (r *UserRepository) CompleteExport(ctx context.Context, requestID string, bundle []byte) error {
+	_, err := r.db.DB.ExecContext(ctx, `
+		UPDATE privacy_requests
+		SET status = 'completed', export_bundle = $2, export_expires_at = $3, completed_at = now(), error = NULL
+		WHERE id = $1
+	`, requestID, bundle, time.Now().Add(ExportBundleTTL))
+	if err != nil {
+		return fmt.Errorf("failed to complete export: %w", err)
+	}
+	return nil
+}
+
+// FailPrivacyRequest marks a request as failed with the reason
+func (r *UserRepository) FailPrivacyRequest(ctx context.Context, requestID string, reason string) error {
+	_, err := r.db.DB.ExecContext(ctx, `
+		UPDATE privacy_requests
+		SET status = 'failed', error = $2, completed_at = now()
+		WHERE id = $1
+	`, requestID, reason)
+	if err != nil {
+		return fmt.Errorf("failed to mark privacy request as failed: %w", err)
+	}
+	return nil
+}
+
+// ClearExpiredExportBundles drops export bundles past their expiry; the requests stay in the log
+func (r *UserRepository) ClearExpiredExportBundles(ctx context.Context) (int64, error) {
+	result, err := r.db.DB.ExecContext(ctx, `
+		UPDATE privacy_requests
+		SET export_bundle = NULL
+		WHERE export_bundle IS NOT NULL AND export_expires_at <= now()
+	`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to clear expired export bundles: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// BuildExportBundle collects the personal data of a user into a ZIP with one JSON file per
+// section
+func (r *UserRepository) BuildExportBundle(ctx context.Context, userID string) ([]byte, error) {
+	export, err := r.CollectUserData(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []struct {
+		name string
+		data interface{}
+	}{
+		{"profile.json", export.Profile},
+		{"orders.json", export.Orders},
+		{"addresses.json", export.Addresses},
+		{"carts.json", export.Carts},
+		{"sessions.json", export.Sessions},
+		{"notifications.json", export.Notifications},
+		{"status_history.json", export.StatusHistory},
+		{"segments.json", export.Segments},
+		{"loyalty.json", export.Loyalty},
+		{"wishlist.json", export.Wishlist},
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for _, file := range files {
+		w, err := archive.CreateHeader(&zip.FileHeader{
+			Name:     file.name,
+			Method:   zip.Deflate,
+			Modified: export.ExportedAt,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to add %s to export: %w", file.name, err)
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(file.data); err != nil {
+			return nil, fmt.Errorf("failed to write %s to export: %w", file.name, err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finish export: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// CollectUserData gathers the personal data held about a user
+func (r *UserRepository) CollectUserData(ctx context.Context, userID string) (*models.UserDataExport, error) {
+	export := &models.UserDataExport{ExportedAt: time.Now().UTC()}
+
+	var profile []map[string]interface{}
+	if err := r.queryJSON(ctx, &profile, `
+		SELECT id, username, email, pending_email, phone, first_name, last_name, locale, avatar_url,
+		       marketing_consent, marketing_consent_updated_at, role, status, created_at, updated_at, last_login
+		FROM users WHERE id = $1
+	`, userID); err != nil {
+		return nil, fmt.Errorf("failed to export profile: %w", err)
+	}
+	if len(profile) == 0 {
+		return nil, fmt.Errorf("user not found")
+	}
+	export.Profile = profile[0]
+
+	if err := r.queryJSON(ctx, &export.Orders, `
+		SELECT o.id, o.mini_app_type, o.store_id, o.fulfillment_method, o.delivery_address,
+		       o.total_amount, o.loyalty_points_redeemed, o.loyalty_discount, o.status, o.created_at, o.updated_at,
+		       COALESCE((
+		           SELECT json_agg(json_build_object(
+		               'product_id', oi.product_id, 'title', p.title,
+		               'quantity', oi.quantity, 'price', oi.price) ORDER BY oi.created_at)
+		           FROM order_items oi
+		           LEFT JOIN products p ON p.product_uuid = oi.product_id
+		           WHERE oi.order_id = o.id
+		       ), '[]') AS items
+		FROM orders o WHERE o.user_id = $1
+		ORDER BY o.created_at
+	`, userID); err != nil {
+		return nil, fmt.Errorf("failed to export orders: %w", err)
+	}
+
+	if err := r.queryJSON(ctx, &export.Addresses, `
+		SELECT label, recipient_name, phone, line1, line2, city, region, postal_code, country_code,
+		       latitude, longitude, delivery_instructions, is_default, created_at, updated_at
+		FROM user_addresses WHERE user_id = $1
+		ORDER BY created_at
+	`, userID); err != nil {
+		return nil, fmt.Errorf("failed to export addresses: %w", err)
+	}
+
+	if err := r.queryJSON(ctx, &export.Carts, `
+		SELECT c.product_id, p.title, c.quantity, c.mini_app_type, c.store_id, c.created_at, c.updated_at
+		FROM carts c
+		LEFT JOIN products p ON p.product_uuid = c.product_id
+		WHERE c.user_id = $1
+		ORDER BY c.created_at
+	`, userID); err != nil {
+		return nil, fmt.Errorf("failed to export carts: %w", err)
+	}
+
+	// Sign-ins are recorded as the verification codes sent to the user's email
+	if err := r.queryJSON(ctx, &export.Sessions, `
+		SELECT v.created_at, v.ip_address, v.used, v.expires_at
+		FROM user_verification_codes v
+		JOIN users u ON u.email = v.email
+		WHERE u.id = $1
+		ORDER BY v.created_at
+	`, userID); err != nil {
+		return nil, fmt.Errorf("failed to export sessions: %w", err)
+	}
+
+	if err := r.queryJSON(ctx, &export.Notifications, `
+		SELECT notification_id, title, message, reference_type, reference_id, is_read, created_at
+		FROM notifications WHERE recipient_user_id = $1
+		ORDER BY created_at
+	`, userID); err != nil {
+		return nil, fmt.Errorf("failed to export notifications: %w", err)
+	}
+
+	if err := r.queryJSON(ctx, &export.StatusHistory, `
+		SELECT from_status, to_status, reason, suspended_until, created_at
+		FROM user_status_history WHERE user_id = $1
+		ORDER BY created_at
+	`, userID); err != nil {
+		return nil, fmt.Errorf("failed to export status history: %w", err)
+	}
+
+	// Marketing audiences the user currently belongs to
+	if err := r.queryJSON(ctx, &export.Segments, `
+		SELECT s.name, s.description, m.added_at
+		FROM user_segment_members m
+		JOIN user_segments s ON s.id = m.segment_id
+		WHERE m.user_id = $1
+		ORDER BY m.added_at
+	`, userID); err != nil {
+		return nil, fmt.Errorf("failed to export segments: %w", err)
+	}
+
+	if err := r.queryJSON(ctx, &export.Loyalty, `
+		SELECT entry_type, points, remaining, expires_at, order_id, reason, created_at
+		FROM loyalty_ledger WHERE user_id = $1
+		ORDER BY created_at, entry_id
+	`, userID); err != nil {
+		return nil, fmt.Errorf("failed to export loyalty points: %w", err)
+	}
+
+	if err := r.queryJSON(ctx, &export.Wishlist, `
+		SELECT w.product_id, p.title, w.mini_app_type, w.store_id, w.quantity, w.saved_from_cart,
+		       w.notify_back_in_stock, w.notify_price_drop, w.created_at, w.updated_at
+		FROM wishlist_items w
+		LEFT JOIN products p ON p.product_uuid = w.product_id
+		WHERE w.user_id = $1
+		ORDER BY w.created_at
+	`, userID); err != nil {
+		return nil, fmt.Errorf("failed to export wishlist: %w", err)
+	}
+
+	return export, nil
+}
+
+// queryJSON runs a query and decodes its rows as JSON objects into dest
+func (r *UserRepository) queryJSON(ctx context.Context, dest *[]map[string]interface{}, query string, args ...interface{}) error {
+	var data []byte
+	err := r.db.DB.QueryRowContext(ctx,
+		"SELECT COALESCE(json_agg(t), '[]') FROM ("+query+") t", args...).Scan(&data)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, dest)
+}
+
+// EraseUser anonymizes the personal fields of a user and deletes their carts, sessions and
+// notifications. Orders are kept for accounting, attached to the anonymized user. The erasure
+// request is marked completed in the same transaction.
+func (r *UserRepository) EraseUser(ctx context.Context, userID, requestID, actor string) error {
+	tx, err := r.db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var email, fromStatus string
+	var erasedAt sql.NullTime
+	err = tx.QueryRowContext(ctx, `
+		SELECT email, user_account_status(status, suspended_until), erased_at
+		FROM users WHERE id = $1 FOR UPDATE
+	`, userID).Scan(&email, &fromStatus, &erasedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("user not found")
+		}
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	// Requests queued by the user before actors were recorded as 'self' carry their email
+	if actor == email {
+		actor = models.PrivacySourceSelf
+	}
+
+	if !erasedAt.Valid {
+		cleanups := []struct {
+			what  string
+			query string
+			arg   interface{}
+		}{
+			{"carts", "DELETE FROM carts WHERE user_id = $1", userID},
+			{"cart sessions", "DELETE FROM cart_activity WHERE user_id = $1", userID},
+			{"notifications", "DELETE FROM notifications WHERE recipient_user_id = $1", userID},
+			{"addresses", "DELETE FROM user_addresses WHERE user_id = $1", userID},
+			{"segment memberships", "DELETE FROM user_segment_members WHERE user_id = $1", userID},
+			{"partner assignment", "DELETE FROM partners WHERE user_id = $1", userID},
+			{"loyalty points", "DELETE FROM loyalty_ledger WHERE user_id = $1", userID},
+			{"wishlist", "DELETE FROM wishlist_items WHERE user_id = $1", userID},
+			// Orders keep where they were delivered to, down to the city, for accounting
+			{"order delivery details", `UPDATE orders
+				SET delivery_address = jsonb_strip_nulls(jsonb_build_object(
+					'city', delivery_address->'city',
+					'region', delivery_address->'region',
+					'country_code', delivery_address->'country_code'))
+				WHERE user_id = $1 AND delivery_address IS NOT NULL`, userID},
+			{"sign-in codes", "DELETE FROM user_verification_codes WHERE email = $1", email},
+			{"export bundles", "UPDATE privacy_requests SET export_bundle = NULL WHERE user_id = $1", userID},
+			// The user's own requests and status changes may name them by email
+			{"privacy request actors", "UPDATE privacy_requests SET requested_by = 'self' WHERE user_id = $1 AND source = 'self'", userID},
+		}
+		for _, cleanup := range cleanups {
+			if _, err := tx.ExecContext(ctx, cleanup.query, cleanup.arg); err != nil {
+				return fmt.Errorf("failed to erase %s: %w", cleanup.what, err)
+			}
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			UPDATE user_status_history SET changed_by = 'self'
+			WHERE user_id = $1 AND changed_by = $2
+		`, userID, email)
+		if err != nil {
+			return fmt.Errorf("failed to erase status change actors: %w", err)
+		}
+
+		// users_backup is a leftover copy of the users table that only some databases have
+		var hasBackup bool
+		if err := tx.QueryRowContext(ctx, "SELECT to_regclass('public.users_backup') IS NOT NULL").Scan(&hasBackup); err != nil {
+			return fmt.Errorf("failed to check for users_backup: %w", err)
+		}
+		if hasBackup {
+			if _, err := tx.ExecContext(ctx, "DELETE FROM users_backup WHERE user_id = $1", userID); err != nil {
+				return fmt.Errorf("failed to erase backup copy: %w", err)
+			}
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			UPDATE users
+			SET username = 'erased-' || id::text,
+			    email = 'erased-' || id::text || '@erased.invalid',
+			    password_hash = NULL, first_name = NULL, last_name = NULL, last_login = NULL,
+			    phone = NULL, locale = NULL, avatar_url = NULL, marketing_consent = FALSE,
+			    pending_email = NULL, pending_email_requested_at = NULL,
+			    status = 'deactivated', status_reason = 'Personal data erased', suspended_until = NULL,
+			    status_changed_at = now(), status_changed_by = $2, erased_at = now(), updated_at = now()
+			WHERE id = $1
+		`, userID, actor)
+		if err != nil {
+			return fmt.Errorf("failed to anonymize user: %w", err)
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO user_status_history (user_id, from_status, to_status, reason, changed_by)
+			VALUES ($1, $2, 'deactivated', 'Personal data erased', $3)
+		`, userID, fromStatus, actor)
+		if err != nil {
+			return fmt.Errorf("failed to record status change: %w", err)
+		}
+
+		// Exports still waiting would only contain anonymized data
+		_, err = tx.ExecContext(ctx, `
+			UPDATE privacy_requests
+			SET status = 'failed', error = 'The personal data of the user was erased', completed_at = now()
+			WHERE user_id = $1 AND request_type = 'export' AND status IN ('pending', 'processing')
+		`, userID)
+		if err != nil {
+			return fmt.Errorf("failed to close pending exports: %w", err)
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE privacy_requests
+		SET status = 'completed', completed_at = now(), error = NULL
+		WHERE id = $1
+	`, requestID)
+	if err != nil {
+		return fmt.Errorf("failed to complete erasure request: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
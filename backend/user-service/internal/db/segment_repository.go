@@ -0,0 +1,292 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"user-service/internal/models"
+
+	"github.com/lib/pq"
+)
+
+const segmentColumns = `
+	id, name, description, rules, member_count, last_evaluated_at, created_by, created_at, updated_at`
+
+// segmentFieldSQL is how a segment field is read: either a scalar expression over the user u,
+// or a column of related rows (from) that any of them must match
+type segmentFieldSQL struct {
+	expr   string
+	from   string
+	column string
+}
+
+var segmentFieldSQLs = map[string]segmentFieldSQL{
+	"role":                   {expr: "u.role::text"},
+	"status":                 {expr: "user_account_status(u.status, u.suspended_until)"},
+	"locale":                 {expr: "u.locale"},
+	"marketing_consent":      {expr: "u.marketing_consent"},
+	"city":                   {from: "user_addresses a WHERE a.user_id = u.id", column: "a.city"},
+	"country_code":           {from: "user_addresses a WHERE a.user_id = u.id", column: "a.country_code"},
+	"signed_up_at":           {expr: "u.created_at"},
+	"last_login_at":          {expr: "u.last_login"},
+	"days_since_last_login":  {expr: "EXTRACT(EPOCH FROM now() - COALESCE(u.last_login, u.created_at)) / 86400"},
+	"order_count":            {expr: "(SELECT COUNT(*) FROM orders o WHERE o.user_id = u.id)"},
+	"total_spent":            {expr: "(SELECT COALESCE(SUM(o.total_amount), 0) FROM orders o WHERE o.user_id = u.id)"},
+	"last_order_at":          {expr: "(SELECT MAX(o.created_at) FROM orders o WHERE o.user_id = u.id)"},
+	"days_since_last_order":  {expr: "EXTRACT(EPOCH FROM now() - (SELECT MAX(o.created_at) FROM orders o WHERE o.user_id = u.id)) / 86400"},
+	"ordered_mini_app":       {from: "orders o WHERE o.user_id = u.id", column: "o.mini_app_type"},
+	"ordered_store_city":     {from: "orders o JOIN stores s ON s.store_id = o.store_id WHERE o.user_id = u.id", column: "s.city"},
+	"cart_item_count":        {expr: "(SELECT COALESCE(SUM(c.quantity), 0) FROM carts c WHERE c.user_id = u.id)"},
+	"cart_mini_app":          {from: "carts c WHERE c.user_id = u.id", column: "c.mini_app_type"},
+	"days_since_cart_update": {expr: "EXTRACT(EPOCH FROM now() - (SELECT MAX(c.updated_at) FROM carts c WHERE c.user_id = u.id)) / 86400"},
+}
+
+var segmentComparisons = map[string]string{
+	"eq": "=", "neq": "<>", "gt": ">", "gte": ">=", "lt": "<", "lte": "<=",
+	"before": "<", "after": ">",
+}
+
+// segmentCondition builds the condition of a rule set over the user u. Rule values are
+// appended to args, after any placeholders the caller already uses.
+func segmentCondition(rules models.SegmentRules, args []interface{}) (string, []interface{}, error) {
+	arg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	var conditions []string
+	for i, rule := range rules.Rules {
+		value, err := rule.ParseValue()
+		if err != nil {
+			return "", nil, fmt.Errorf("rule %d: %w", i+1, err)
+		}
+		field, ok := segmentFieldSQLs[rule.Field]
+		if !ok {
+			return "", nil, fmt.Errorf("rule %d: field %q cannot be evaluated", i+1, rule.Field)
+		}
+
+		negate := rule.Op == "neq" || rule.Op == "not_in"
+		var condition string
+		switch values := value.(type) {
+		case []string:
+			placeholder := arg(pq.Array(values))
+			if field.from != "" {
+				// Any related row matches; the negation is that none does
+				condition = fmt.Sprintf("EXISTS (SELECT 1 FROM %s AND LOWER(%s::text) = ANY(%s::text[]))",
+					field.from, field.column, placeholder)
+				if negate {
+					condition = "NOT " + condition
+				}
+			} else if negate {
+				condition = fmt.Sprintf("(%s IS NULL OR LOWER(%s) <> ALL(%s::text[]))", field.expr, field.expr, placeholder)
+			} else {
+				condition = fmt.Sprintf("LOWER(%s) = ANY(%s::text[])", field.expr, placeholder)
+			}
+		case float64:
+			condition = fmt.Sprintf("(%s)::numeric %s %s::numeric", field.expr, segmentComparisons[rule.Op], arg(values))
+		default:
+			condition = fmt.Sprintf("%s %s %s", field.expr, segmentComparisons[rule.Op], arg(values))
+		}
+		conditions = append(conditions, condition)
+	}
+
+	joiner := " AND "
+	if rules.Match == "any" {
+		joiner = " OR "
+	}
+
+	// Erased users are never part of an audience
+	return "u.erased_at IS NULL AND (" + strings.Join(conditions, joiner) + ")", args, nil
+}
+
+func scanSegment(row rowScanner) (*models.Segment, error) {
+	var segment models.Segment
+	var rules []byte
+	var lastEvaluatedAt sql.NullTime
+	err := row.Scan(
+		&segment.ID,
+		&segment.Name,
+		&segment.Description,
+		&rules,
+		&segment.MemberCount,
+		&lastEvaluatedAt,
+		&segment.CreatedBy,
+		&segment.CreatedAt,
+		&segment.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(rules, &segment.Rules); err != nil {
+		return nil, fmt.Errorf("failed to decode segment rules: %w", err)
+	}
+	if lastEvaluatedAt.Valid {
+		segment.LastEvaluatedAt = &lastEvaluatedAt.Time
+	}
+	return &segment, nil
+}
+
+// GetSegments lists all segments by name
+func (r *UserRepository) GetSegments(ctx context.Context) ([]models.Segment, error) {
+	rows, err := r.db.DB.QueryContext(ctx, "SELECT "+segmentColumns+" FROM user_segments ORDER BY LOWER(name)")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query segments: %w", err)
+	}
+	defer rows.Close()
+
+	segments := []models.Segment{}
+	for rows.Next() {
+		segment, err := scanSegment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan segment: %w", err)
+		}
+		segments = append(segments, *segment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over segments: %w", err)
+	}
+
+	return segments, nil
+}
+
+// GetSegment retrieves a segment by ID
+func (r *UserRepository) GetSegment(ctx context.Context, segmentID string) (*models.Segment, error) {
+	segment, err := scanSegment(r.db.DB.QueryRowContext(ctx,
+		"SELECT "+segmentColumns+" FROM user_segments WHERE id::text = $1", segmentID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("segment not found")
+		}
+		return nil, fmt.Errorf("failed to get segment: %w", err)
+	}
+	return segment, nil
+}
+
+// CreateSegment saves a segment and evaluates its members
+func (r *UserRepository) CreateSegment(ctx context.Context, req models.SegmentRequest, actor string) (*models.Segment, error) {
+	rules, err := json.Marshal(req.Rules)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode segment rules: %w", err)
+	}
+
+	var segmentID string
+	err = r.db.DB.QueryRowContext(ctx, `
+		INSERT INTO user_segments (name, description, rules, created_by)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`, req.Name, req.Description, string(rules), actor).Scan(&segmentID)
+	if err != nil {
+		if strings.Contains(err.Error(), "idx_user_segments_name") {
+			return nil, fmt.Errorf("a segment named %q already exists", req.Name)
+		}
+		return nil, fmt.Errorf("failed to create segment: %w", err)
+	}
+
+	return r.RefreshSegment(ctx, segmentID)
+}
+
+// UpdateSegment replaces the name, description and rules of a segment and re-evaluates its
+// members
+func (r *UserRepository) UpdateSegment(ctx context.Context, segmentID string, req models.SegmentRequest) (*models.Segment, error) {
+	rules, err := json.Marshal(req.Rules)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode segment rules: %w", err)
+	}
+
+	result, err := r.db.DB.ExecContext(ctx, `
+		UPDATE user_segments
+		SET name = $2, description = $3, rules = $4, updated_at = now()
+		WHERE id::text = $1
+	`, segmentID, req.Name, req.Description, string(rules))
+	if err != nil {
+		if strings.Contains(err.Error(), "idx_user_segments_name") {
+			return nil, fmt.Errorf("a segment named %q already exists", req.Name)
+		}
+		return nil, fmt.Errorf("failed to update segment: %w", err)
+	}
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return nil, fmt.Errorf("segment not found")
+	}
+
+	return r.RefreshSegment(ctx, segmentID)
+}
+
+// DeleteSegment deletes a segment and its members
+func (r *UserRepository) DeleteSegment(ctx context.Context, segmentID string) error {
+	result, err := r.db.DB.ExecContext(ctx, "DELETE FROM user_segments WHERE id::text = $1", segmentID)
+	if err != nil {
+		return fmt.Errorf("failed to delete segment: %w", err)
+	}
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return fmt.Errorf("segment not found")
+	}
+	return nil
+}
+
+// PreviewSegment counts the users a rule set currently matches, without saving anything
+func (r *UserRepository) PreviewSegment(ctx context.Context, rules models.SegmentRules) (int, error) {
+	condition, args, err := segmentCondition(rules, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	if err := r.db.DB.QueryRowContext(ctx, "SELECT COUNT(*) FROM users u WHERE "+condition, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to evaluate segment: %w", err)
+	}
+	return count, nil
+}
+
+// RefreshSegment re-evaluates the rules of a segment and replaces its members. Users who
+// still match keep their added_at.
+func (r *UserRepository) RefreshSegment(ctx context.Context, segmentID string) (*models.Segment, error) {
+	tx, err := r.db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock the segment so concurrent refreshes of it run one after the other
+	segment, err := scanSegment(tx.QueryRowContext(ctx,
+		"SELECT "+segmentColumns+" FROM user_segments WHERE id::text = $1 FOR UPDATE", segmentID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("segment not found")
+		}
+		return nil, fmt.Errorf("failed to get segment: %w", err)
+	}
+
+	condition, args, err := segmentCondition(segment.Rules, []interface{}{segment.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	segment, err = scanSegment(tx.QueryRowContext(ctx, `
+		WITH matches AS (
+			SELECT u.id AS user_id FROM users u WHERE `+condition+`
+		), removed AS (
+			DELETE FROM user_segment_members m
+			WHERE m.segment_id = $1 AND m.user_id NOT IN (SELECT user_id FROM matches)
+		), added AS (
+			INSERT INTO user_segment_members (segment_id, user_id)
+			SELECT $1, user_id FROM matches
+			ON CONFLICT (segment_id, user_id) DO NOTHING
+		)
+		UPDATE user_segments
+		SET member_count = (SELECT COUNT(*) FROM matches), last_evaluated_at = now()
+		WHERE id = $1
+		RETURNING `+segmentColumns, args...))
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate segment: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return segment, nil
+}
@@ -32,94 +32,167 @@ func hashPassword(password string) (string, error) {
 	return string(hashedBytes), nil
 }
 
-// GetUsers retrieves users with pagination, search, and filtering
-func (r *UserRepository) GetUsers(ctx context.Context, params models.UserSearchParams) (*models.UserListResponse, error) {
-	// Build the base query
-	baseQuery := `
-		SELECT u.id, u.username, u.email, u.password_hash,
-		       u.first_name, u.last_name, u.role, u.status, u.last_login,
-		       u.created_at, u.updated_at,
-		       COALESCE(order_stats.order_count, 0) as order_count,
-		       COALESCE(order_stats.total_spent, 0) as total_spent
-		FROM users u
-		LEFT JOIN (
-			SELECT user_id,
-			       COUNT(*) as order_count,
-			       SUM(total_amount) as total_spent
-			FROM orders
-			GROUP BY user_id
-		) order_stats ON u.id = order_stats.user_id
-	`
+// userSortKey is an ordering of the user list: a non-null key and the SQL type its text form
+// is cast back to when comparing against a cursor
+type userSortKey struct {
+	expr    string
+	sqlType string
+}
+
+var userSortKeys = map[string]userSortKey{
+	"created_at":  {"u.created_at", "timestamptz"},
+	"last_login":  {"COALESCE(u.last_login, '-infinity'::timestamptz)", "timestamptz"},
+	"full_name":   {"LOWER(COALESCE(u.first_name || ' ' || u.last_name, u.username))", "text"},
+	"email":       {"u.email", "text"},
+	"role":        {"u.role::text", "text"},
+	"order_count": {"order_stats.order_count", "bigint"},
+	"total_spent": {"order_stats.total_spent", "numeric"},
+}
+
+// userOrderStatsJoin computes the order statistics of each listed user. Being LATERAL, only
+// the orders of the users that are actually read are aggregated.
+const userOrderStatsJoin = `
+	LEFT JOIN LATERAL (
+		SELECT COUNT(*) AS order_count,
+		       COALESCE(SUM(o.total_amount), 0) AS total_spent
+		FROM orders o
+		WHERE o.user_id = u.id
+	) order_stats ON TRUE`
+
+// escapeLike escapes the LIKE wildcards of a search term
+func escapeLike(term string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(term)
+}
 
-	// Build WHERE clause
-	var whereConditions []string
+// userFilters builds the WHERE conditions shared by the user list, its count and the export.
+// Placeholders are numbered from 1 in the order of the returned arguments.
+func userFilters(params models.UserSearchParams) ([]string, []interface{}) {
+	var conditions []string
 	var args []interface{}
-	argIndex := 1
+	arg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
 
 	if params.Search != "" {
-		whereConditions = append(whereConditions, fmt.Sprintf(`
-			(LOWER(u.username) LIKE LOWER($%d) OR
-			 LOWER(u.email) LIKE LOWER($%d) OR
-			 LOWER(u.first_name) LIKE LOWER($%d) OR
-			 LOWER(u.last_name) LIKE LOWER($%d))`, argIndex, argIndex, argIndex, argIndex))
-		args = append(args, "%"+params.Search+"%")
-		argIndex++
+		// Served by the trigram index idx_users_search_trgm
+		conditions = append(conditions, fmt.Sprintf(
+			"user_search_text(u.username, u.email, u.first_name, u.last_name, u.phone) LIKE %s",
+			arg("%"+escapeLike(strings.ToLower(params.Search))+"%")))
 	}
 
 	if params.Role != nil {
-		whereConditions = append(whereConditions, fmt.Sprintf("u.role = $%d", argIndex))
-		args = append(args, string(*params.Role))
-		argIndex++
+		conditions = append(conditions, "u.role = "+arg(string(*params.Role)))
 	}
 
-	// Add WHERE clause if we have conditions
-	if len(whereConditions) > 0 {
-		baseQuery += " WHERE " + strings.Join(whereConditions, " AND ")
-	}
-
-	// Add ORDER BY clause
-	orderBy := "u.created_at"
-	if params.Sort != "" {
-		switch params.Sort {
-		case "full_name":
-			orderBy = "u.full_name"
-		case "email":
-			orderBy = "u.email"
-		case "last_login":
-			orderBy = "u.last_login"
-		case "role":
-			orderBy = "u.role"
-		case "order_count":
-			orderBy = "order_count"
-		case "total_spent":
-			orderBy = "total_spent"
-		}
+	if params.Status != nil {
+		conditions = append(conditions, "user_account_status(u.status, u.suspended_until) = "+arg(string(*params.Status)))
 	}
 
-	order := "DESC"
+	if params.CreatedFrom != nil {
+		conditions = append(conditions, "u.created_at >= "+arg(*params.CreatedFrom))
+	}
+	if params.CreatedTo != nil {
+		conditions = append(conditions, "u.created_at <= "+arg(*params.CreatedTo))
+	}
+	if params.LastLoginFrom != nil {
+		conditions = append(conditions, "u.last_login >= "+arg(*params.LastLoginFrom))
+	}
+	if params.LastLoginTo != nil {
+		conditions = append(conditions, "u.last_login <= "+arg(*params.LastLoginTo))
+	}
+
+	if params.MinOrders != nil {
+		conditions = append(conditions, "order_stats.order_count >= "+arg(*params.MinOrders))
+	}
+	if params.MinSpent != nil {
+		conditions = append(conditions, "order_stats.total_spent >= "+arg(*params.MinSpent))
+	}
+
+	if params.MiniAppType != "" {
+		conditions = append(conditions, fmt.Sprintf(
+			"EXISTS (SELECT 1 FROM orders mo WHERE mo.user_id = u.id AND mo.mini_app_type = %s)",
+			arg(params.MiniAppType)))
+	}
+
+	if params.MarketingConsent != nil {
+		conditions = append(conditions, "u.marketing_consent = "+arg(*params.MarketingConsent))
+	}
+
+	if params.SegmentID != "" {
+		conditions = append(conditions, fmt.Sprintf(
+			"EXISTS (SELECT 1 FROM user_segment_members sm WHERE sm.user_id = u.id AND sm.segment_id::text = %s)",
+			arg(params.SegmentID)))
+	}
+
+	return conditions, args
+}
+
+// userListQuery builds the ordered query of the users matching params, after params.Cursor
+// when one is given. The sort key of each user, as text, is selected after columns.
+func userListQuery(params models.UserSearchParams, columns string) (string, []interface{}) {
+	sortKey, ok := userSortKeys[params.Sort]
+	if !ok {
+		sortKey = userSortKeys["created_at"]
+	}
+
+	order, compare := "DESC", "<"
 	if params.Order == "asc" {
-		order = "ASC"
+		order, compare = "ASC", ">"
+	}
+
+	conditions, args := userFilters(params)
+	if params.Cursor != nil {
+		args = append(args, params.Cursor.SortKey, params.Cursor.ID)
+		conditions = append(conditions, fmt.Sprintf("(%s, u.id) %s ($%d::%s, $%d::uuid)",
+			sortKey.expr, compare, len(args)-1, sortKey.sqlType, len(args)))
+	}
+
+	query := fmt.Sprintf("SELECT %s, (%s)::text AS sort_key FROM users u %s", columns, sortKey.expr, userOrderStatsJoin)
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
 	}
 
-	baseQuery += fmt.Sprintf(" ORDER BY %s %s", orderBy, order)
+	// The ID breaks ties so pages neither skip nor repeat users
+	query += fmt.Sprintf(" ORDER BY %s %s, u.id %s", sortKey.expr, order, order)
+
+	return query, args
+}
+
+// GetUsers retrieves users with search and filtering, paginated either by page number or,
+// when params.UseCursor is set, by keyset cursor
+func (r *UserRepository) GetUsers(ctx context.Context, params models.UserSearchParams) (*models.UserListResponse, error) {
+	query, args := userListQuery(params, `
+		u.id, u.username, u.email, u.password_hash,
+		u.first_name, u.last_name, u.role, user_account_status(u.status, u.suspended_until),
+		u.status_reason, u.suspended_until, u.status_changed_at, u.status_changed_by, u.last_login,
+		u.created_at, u.updated_at,
+		order_stats.order_count, order_stats.total_spent`)
 
-	// Add pagination
-	baseQuery += fmt.Sprintf(" LIMIT $%d OFFSET $%d", argIndex, argIndex+1)
-	args = append(args, params.Limit, (params.Page-1)*params.Limit)
+	// Add pagination; a cursor page reads one extra user to tell whether another page follows
+	if params.UseCursor {
+		query += fmt.Sprintf(" LIMIT $%d", len(args)+1)
+		args = append(args, params.Limit+1)
+	} else {
+		query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+		args = append(args, params.Limit, (params.Page-1)*params.Limit)
+	}
 
-	log.Printf("Executing query: %s with args: %v", baseQuery, args)
+	log.Printf("Executing query: %s with args: %v", query, args)
 
 	// Execute the query
-	rows, err := r.db.DB.QueryContext(ctx, baseQuery, args...)
+	rows, err := r.db.DB.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query users: %w", err)
 	}
 	defer rows.Close()
 
 	var users []models.User
+	var sortKeys []string
 	for rows.Next() {
 		var user models.User
 		var lastLogin sql.NullTime
+		var sortKey string
 		err := rows.Scan(
 			&user.ID,
 			&user.Username,
@@ -129,11 +202,16 @@ func (r *UserRepository) GetUsers(ctx context.Context, params models.UserSearchP
 			&user.LastName,
 			&user.Role,
 			&user.Status,
+			&user.StatusReason,
+			&user.SuspendedUntil,
+			&user.StatusChangedAt,
+			&user.StatusChangedBy,
 			&lastLogin,
 			&user.CreatedAt,
 			&user.UpdatedAt,
 			&user.OrderCount,
 			&user.TotalSpent,
+			&sortKey,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan user: %w", err)
@@ -151,12 +229,25 @@ func (r *UserRepository) GetUsers(ctx context.Context, params models.UserSearchP
 		}
 
 		users = append(users, user)
+		sortKeys = append(sortKeys, sortKey)
 	}
 
 	if err = rows.Err(); err != nil {
 		return nil, fmt.Errorf("error iterating over users: %w", err)
 	}
 
+	var nextCursor string
+	if params.UseCursor && len(users) > params.Limit {
+		users = users[:params.Limit]
+		last := users[params.Limit-1]
+		nextCursor = models.EncodeUserCursor(models.UserCursor{
+			Sort:    params.Sort,
+			Order:   params.Order,
+			SortKey: sortKeys[params.Limit-1],
+			ID:      last.ID,
+		})
+	}
+
 	// Get total count
 	total, err := r.getUserCount(ctx, params)
 	if err != nil {
@@ -171,36 +262,20 @@ func (r *UserRepository) GetUsers(ctx context.Context, params models.UserSearchP
 		Page:       params.Page,
 		Limit:      params.Limit,
 		TotalPages: totalPages,
+		NextCursor: nextCursor,
 	}, nil
 }
 
 // getUserCount gets the total count of users matching the search criteria
 func (r *UserRepository) getUserCount(ctx context.Context, params models.UserSearchParams) (int, error) {
 	query := "SELECT COUNT(*) FROM users u"
-
-	var whereConditions []string
-	var args []interface{}
-	argIndex := 1
-
-	if params.Search != "" {
-		whereConditions = append(whereConditions, fmt.Sprintf(`
-			(LOWER(u.username) LIKE LOWER($%d) OR
-			 LOWER(u.email) LIKE LOWER($%d) OR
-			 LOWER(u.first_name) LIKE LOWER($%d) OR
-			 LOWER(u.last_name) LIKE LOWER($%d) OR
-			 u.phone LIKE $%d)`, argIndex, argIndex, argIndex, argIndex, argIndex))
-		args = append(args, "%"+params.Search+"%")
-		argIndex++
+	if params.MinOrders != nil || params.MinSpent != nil {
+		query += userOrderStatsJoin
 	}
 
-	if params.Role != nil {
-		whereConditions = append(whereConditions, fmt.Sprintf("u.role = $%d", argIndex))
-		args = append(args, string(*params.Role))
-		argIndex++
-	}
-
-	if len(whereConditions) > 0 {
-		query += " WHERE " + strings.Join(whereConditions, " AND ")
+	conditions, args := userFilters(params)
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
 	}
 
 	var count int
@@ -212,11 +287,63 @@ func (r *UserRepository) getUserCount(ctx context.Context, params models.UserSea
 	return count, nil
 }
 
+// ExportUsers streams every user matching params, in the listing order, to fn. Pagination
+// fields are ignored. Iteration stops at the first error returned by fn.
+func (r *UserRepository) ExportUsers(ctx context.Context, params models.UserSearchParams, fn func(*models.UserExportRow) error) error {
+	params.Cursor = nil
+	query, args := userListQuery(params, `
+		u.id, u.username, u.email, u.first_name, u.last_name, u.phone, u.locale,
+		u.marketing_consent, u.role, user_account_status(u.status, u.suspended_until),
+		u.last_login, u.created_at, order_stats.order_count, order_stats.total_spent`)
+
+	rows, err := r.db.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query users: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row models.UserExportRow
+		var sortKey string
+		err := rows.Scan(
+			&row.ID,
+			&row.Username,
+			&row.Email,
+			&row.FirstName,
+			&row.LastName,
+			&row.Phone,
+			&row.Locale,
+			&row.MarketingConsent,
+			&row.Role,
+			&row.Status,
+			&row.LastLogin,
+			&row.CreatedAt,
+			&row.OrderCount,
+			&row.TotalSpent,
+			&sortKey,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to scan user: %w", err)
+		}
+
+		if err := fn(&row); err != nil {
+			return err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("error iterating over users: %w", err)
+	}
+
+	return nil
+}
+
 // GetUserByID retrieves a user by ID with order statistics
 func (r *UserRepository) GetUserByID(ctx context.Context, userID string) (*models.User, error) {
 	query := `
 		SELECT u.id, u.username, u.email, u.password_hash,
-		       u.first_name, u.last_name, u.role, u.status, u.last_login,
+		       u.first_name, u.last_name, u.role, user_account_status(u.status, u.suspended_until),
+		       u.status_reason, u.suspended_until, u.status_changed_at, u.status_changed_by, u.last_login,
 		       u.created_at, u.updated_at,
 		       COALESCE(order_stats.order_count, 0) as order_count,
 		       COALESCE(order_stats.total_spent, 0) as total_spent
@@ -243,6 +370,10 @@ func (r *UserRepository) GetUserByID(ctx context.Context, userID string) (*model
 		&user.LastName,
 		&user.Role,
 		&user.Status,
+		&user.StatusReason,
+		&user.SuspendedUntil,
+		&user.StatusChangedAt,
+		&user.StatusChangedBy,
 		&lastLogin,
 		&user.CreatedAt,
 		&user.UpdatedAt,
@@ -315,8 +446,40 @@ func (r *UserRepository) CreateUser(ctx context.Context, req models.UserCreateRe
 	return &user, nil
 }
 
-// UpdateUser updates user information
-func (r *UserRepository) UpdateUser(ctx context.Context, userID string, updates models.UserUpdateRequest) error {
+// UpdateUser updates user information and, when status is set, the account status in the
+// same transaction. The status change is recorded as in UpdateUserStatus.
+func (r *UserRepository) UpdateUser(ctx context.Context, userID string, updates models.UserUpdateRequest, status *models.UserStatusUpdateRequest, actor string) error {
+	hasProfileUpdates := updates.FullName != nil || updates.Email != nil || updates.Role != nil
+	if !hasProfileUpdates && status == nil {
+		return fmt.Errorf("no fields to update")
+	}
+
+	tx, err := r.db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if hasProfileUpdates {
+		if err := updateUserFields(ctx, tx, userID, updates); err != nil {
+			return err
+		}
+	}
+	if status != nil {
+		if err := updateUserStatus(ctx, tx, userID, *status, actor); err != nil {
+			return err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// updateUserFields applies the profile and role fields of updates to a user
+func updateUserFields(ctx context.Context, tx *sql.Tx, userID string, updates models.UserUpdateRequest) error {
 	var setParts []string
 	var args []interface{}
 	argIndex := 1
@@ -349,12 +512,6 @@ func (r *UserRepository) UpdateUser(ctx context.Context, userID string, updates
 		argIndex++
 	}
 
-	if updates.Status != nil {
-		setParts = append(setParts, fmt.Sprintf("status = $%d", argIndex))
-		args = append(args, string(*updates.Status))
-		argIndex++
-	}
-
 	if len(setParts) == 0 {
 		return fmt.Errorf("no fields to update")
 	}
@@ -369,7 +526,7 @@ func (r *UserRepository) UpdateUser(ctx context.Context, userID string, updates
 
 	query := fmt.Sprintf("UPDATE users SET %s WHERE id = $%d", strings.Join(setParts, ", "), argIndex)
 
-	result, err := r.db.DB.ExecContext(ctx, query, args...)
+	result, err := tx.ExecContext(ctx, query, args...)
 	if err != nil {
 		return fmt.Errorf("failed to update user: %w", err)
 	}
@@ -386,46 +543,19 @@ func (r *UserRepository) UpdateUser(ctx context.Context, userID string, updates
 	return nil
 }
 
-// DeleteUser performs a hard delete of a user from the database
-func (r *UserRepository) DeleteUser(ctx context.Context, userID string) error {
-	// Start a transaction to ensure data consistency
-	tx, err := r.db.DB.BeginTx(ctx, nil)
+// DeleteUser erases the personal data of a user right away and records it as an admin erasure
+// request. The user row and their orders are kept, anonymized (see EraseUser).
+func (r *UserRepository) DeleteUser(ctx context.Context, userID, actor string) error {
+	req, err := r.CreatePrivacyRequest(ctx, userID, models.PrivacyRequestErasure, models.PrivacySourceAdmin, actor, "Deleted by admin")
 	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+		return err
 	}
-	defer tx.Rollback()
 
-	// Delete related data first (orders, carts, etc.)
-	// Delete user's carts
-	_, err = tx.ExecContext(ctx, "DELETE FROM carts WHERE user_id = $1", userID)
-	if err != nil {
-		return fmt.Errorf("failed to delete user carts: %w", err)
-	}
-
-	// Delete user's orders
-	_, err = tx.ExecContext(ctx, "DELETE FROM orders WHERE user_id = $1", userID)
-	if err != nil {
-		return fmt.Errorf("failed to delete user orders: %w", err)
-	}
-
-	// Finally delete the user
-	result, err := tx.ExecContext(ctx, "DELETE FROM users WHERE id = $1", userID)
-	if err != nil {
-		return fmt.Errorf("failed to delete user: %w", err)
-	}
-
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
-	}
-
-	if rowsAffected == 0 {
-		return fmt.Errorf("user not found")
-	}
-
-	// Commit the transaction
-	if err = tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+	if err := r.EraseUser(ctx, userID, req.ID, actor); err != nil {
+		if failErr := r.FailPrivacyRequest(ctx, req.ID, err.Error()); failErr != nil {
+			log.Printf("Failed to record failure of erasure request %s: %v", req.ID, failErr)
+		}
+		return err
 	}
 
 	return nil
@@ -461,15 +591,24 @@ func (r *UserRepository) GetUserAnalytics(ctx context.Context) (*models.UserAnal
 		analytics.UsersByRole[models.UserRole(role)] = count
 	}
 
-	// Get new users today
-	todayQuery := "SELECT COUNT(*) FROM users WHERE DATE(created_at) = CURRENT_DATE"
+	// New user counts come from the daily registration rollups (UTC days)
+	todayQuery := `
+		SELECT COALESCE(SUM(registrations), 0)
+		FROM analytics_registration_rollups
+		WHERE granularity = 'day'
+		  AND bucket_start = date_trunc('day', CURRENT_TIMESTAMP AT TIME ZONE 'UTC') AT TIME ZONE 'UTC'
+	`
 	err = r.db.DB.QueryRowContext(ctx, todayQuery).Scan(&analytics.NewUsersToday)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get new users today: %w", err)
 	}
 
-	// Get new users this week
-	weekQuery := "SELECT COUNT(*) FROM users WHERE created_at >= DATE_TRUNC('week', CURRENT_DATE)"
+	weekQuery := `
+		SELECT COALESCE(SUM(registrations), 0)
+		FROM analytics_registration_rollups
+		WHERE granularity = 'day'
+		  AND bucket_start >= date_trunc('week', CURRENT_TIMESTAMP AT TIME ZONE 'UTC') AT TIME ZONE 'UTC'
+	`
 	err = r.db.DB.QueryRowContext(ctx, weekQuery).Scan(&analytics.NewUsersThisWeek)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get new users this week: %w", err)
@@ -485,13 +624,13 @@ func (r *UserRepository) GetUserAnalytics(ctx context.Context) (*models.UserAnal
 
 	// Get registration trend (last 7 days)
 	trendQuery := `
-		SELECT DATE(created_at) as date, COUNT(*) as count
-		FROM users
-		WHERE created_at >= $1
-		GROUP BY DATE(created_at)
+		SELECT bucket_start AT TIME ZONE 'UTC' as date, SUM(registrations) as count
+		FROM analytics_registration_rollups
+		WHERE granularity = 'day' AND bucket_start >= $1
+		GROUP BY bucket_start
 		ORDER BY date DESC
 	`
-	sevenDaysAgo := time.Now().AddDate(0, 0, -7)
+	sevenDaysAgo := time.Now().UTC().AddDate(0, 0, -7)
 	trendRows, err := r.db.DB.QueryContext(ctx, trendQuery, sevenDaysAgo)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get registration trend: %w", err)
@@ -508,9 +647,25 @@ func (r *UserRepository) GetUserAnalytics(ctx context.Context) (*models.UserAnal
 		analytics.RegistrationTrend = append(analytics.RegistrationTrend, item)
 	}
 
-	// Calculate status distribution based on last login
-	analytics.UsersByStatus[models.StatusActive] = analytics.ActiveUsers
-	analytics.UsersByStatus[models.StatusDeactivated] = analytics.TotalUsers - analytics.ActiveUsers
+	// Get users by account status
+	statusRows, err := r.db.DB.QueryContext(ctx, `
+		SELECT user_account_status(status, suspended_until), COUNT(*)
+		FROM users
+		GROUP BY 1
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get users by status: %w", err)
+	}
+	defer statusRows.Close()
+
+	for statusRows.Next() {
+		var status string
+		var count int
+		if err := statusRows.Scan(&status, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan status data: %w", err)
+		}
+		analytics.UsersByStatus[models.UserStatus(status)] = count
+	}
 
 	return analytics, nil
 }
@@ -542,48 +697,187 @@ func (r *UserRepository) GetUserOrderStats(ctx context.Context, userID string) (
 	return &stats, nil
 }
 
-// BulkUpdateUsers performs bulk operations on multiple users
-func (r *UserRepository) BulkUpdateUsers(ctx context.Context, userIDs []string, operation string, updates map[string]interface{}) error {
-	if len(userIDs) == 0 {
-		return fmt.Errorf("no user IDs provided")
-	}
+// BulkUpdateUsers applies a bulk operation user by user. Each user's change is committed on
+// its own, so one failing user does not undo the others; the response reports the outcome
+// per user. In a dry run nothing is written.
+func (r *UserRepository) BulkUpdateUsers(ctx context.Context, req models.BulkUserUpdateRequest, actor string) (*models.BulkUserUpdateResponse, error) {
+	response := &models.BulkUserUpdateResponse{
+		Operation: req.Operation,
+		DryRun:    req.DryRun,
+		Succeeded: []models.BulkUserUpdateItem{},
+		Failed:    []models.BulkUserUpdateItem{},
+	}
+
+	seen := make(map[string]bool, len(req.UserIDs))
+	for _, userID := range req.UserIDs {
+		if seen[userID] {
+			continue
+		}
+		seen[userID] = true
 
-	// Create placeholders for user IDs
-	placeholders := make([]string, len(userIDs))
-	args := make([]interface{}, len(userIDs))
-	for i, id := range userIDs {
-		placeholders[i] = fmt.Sprintf("$%d", i+1)
-		args[i] = id
+		item, err := r.bulkUpdateUser(ctx, req, userID, actor)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, fmt.Errorf("bulk operation interrupted: %w", ctx.Err())
+			}
+			item.Error = err.Error()
+			response.Failed = append(response.Failed, item)
+			continue
+		}
+		response.Succeeded = append(response.Succeeded, item)
 	}
 
-	var query string
-	var additionalArgs []interface{}
+	log.Printf("Bulk operation %s (dry run: %t) by %s: %d succeeded, %d failed",
+		req.Operation, req.DryRun, actor, len(response.Succeeded), len(response.Failed))
+	return response, nil
+}
+
+// bulkUpdateUser applies a bulk operation to one user
+func (r *UserRepository) bulkUpdateUser(ctx context.Context, req models.BulkUserUpdateRequest, userID, actor string) (models.BulkUserUpdateItem, error) {
+	item := models.BulkUserUpdateItem{UserID: userID}
 
-	switch operation {
-	case "role_update":
-		if role, ok := updates["role"]; ok {
-			query = fmt.Sprintf("UPDATE users SET role = $%d, updated_at = $%d WHERE user_id IN (%s)",
-				len(args)+1, len(args)+2, strings.Join(placeholders, ","))
-			additionalArgs = append(additionalArgs, role, time.Now())
-		} else {
-			return fmt.Errorf("role not provided for role_update operation")
+	user, err := r.GetUserByID(ctx, userID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "invalid input syntax") {
+			return item, fmt.Errorf("user not found")
+		}
+		return item, err
+	}
+
+	switch req.Operation {
+	case models.BulkOperationRoleUpdate:
+		item.From, item.To = string(user.Role), string(*req.Role)
+		if req.DryRun || user.Role == *req.Role {
+			return item, nil
+		}
+		return item, r.UpdateUser(ctx, userID, models.UserUpdateRequest{Role: req.Role}, nil, actor)
+	case models.BulkOperationStatusUpdate:
+		item.From, item.To = string(user.Status), string(*req.Status)
+		if req.DryRun {
+			return item, nil
+		}
+		return item, r.UpdateUserStatus(ctx, userID, models.UserStatusUpdateRequest{
+			Status:         *req.Status,
+			Reason:         req.Reason,
+			SuspendedUntil: req.SuspendedUntil,
+		}, actor)
+	case models.BulkOperationDelete:
+		item.From, item.To = user.Email, "erased"
+		if req.DryRun {
+			return item, nil
 		}
+		return item, r.DeleteUser(ctx, userID, actor)
 	default:
-		return fmt.Errorf("unsupported bulk operation: %s", operation)
+		return item, fmt.Errorf("unsupported bulk operation: %s", req.Operation)
+	}
+}
+
+// UpdateUserStatus moves a user to a new account status and records the change with its
+// reason and actor in user_status_history
+func (r *UserRepository) UpdateUserStatus(ctx context.Context, userID string, req models.UserStatusUpdateRequest, actor string) error {
+	tx, err := r.db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := updateUserStatus(ctx, tx, userID, req, actor); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
-	args = append(args, additionalArgs...)
+	return nil
+}
 
-	result, err := r.db.DB.ExecContext(ctx, query, args...)
+// updateUserStatus sets a user's account status and records the change in user_status_history
+func updateUserStatus(ctx context.Context, tx *sql.Tx, userID string, req models.UserStatusUpdateRequest, actor string) error {
+	var fromStatus string
+	err := tx.QueryRowContext(ctx,
+		"SELECT user_account_status(status, suspended_until) FROM users WHERE id = $1 FOR UPDATE",
+		userID).Scan(&fromStatus)
 	if err != nil {
-		return fmt.Errorf("failed to perform bulk update: %w", err)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("user not found")
+		}
+		return fmt.Errorf("failed to get user status: %w", err)
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	var reason *string
+	if req.Reason != "" {
+		reason = &req.Reason
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE users
+		SET status = $1, status_reason = $2, suspended_until = $3,
+		    status_changed_at = now(), status_changed_by = $4, updated_at = now()
+		WHERE id = $5
+	`, string(req.Status), reason, req.SuspendedUntil, actor, userID)
 	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
+		return fmt.Errorf("failed to update user status: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO user_status_history (user_id, from_status, to_status, reason, suspended_until, changed_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, userID, fromStatus, string(req.Status), reason, req.SuspendedUntil, actor)
+	if err != nil {
+		return fmt.Errorf("failed to record status change: %w", err)
 	}
 
-	log.Printf("Bulk operation %s affected %d rows", operation, rowsAffected)
 	return nil
 }
+
+// GetUserAccountStatus returns the current account status of a user; an expired suspension
+// reads as active. The error wraps sql.ErrNoRows when no user has the ID.
+func (r *UserRepository) GetUserAccountStatus(ctx context.Context, userID string) (models.UserStatus, error) {
+	var status models.UserStatus
+	err := r.db.DB.QueryRowContext(ctx,
+		"SELECT user_account_status(status, suspended_until) FROM users WHERE id::text = $1",
+		userID).Scan(&status)
+	if err != nil {
+		return "", fmt.Errorf("failed to get user account status: %w", err)
+	}
+	return status, nil
+}
+
+// GetUserStatusHistory retrieves the status changes of a user, newest first
+func (r *UserRepository) GetUserStatusHistory(ctx context.Context, userID string) ([]models.UserStatusChange, error) {
+	rows, err := r.db.DB.QueryContext(ctx, `
+		SELECT id, user_id, from_status, to_status, reason, suspended_until, changed_by, created_at
+		FROM user_status_history
+		WHERE user_id = $1
+		ORDER BY created_at DESC, id DESC
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query status history: %w", err)
+	}
+	defer rows.Close()
+
+	history := []models.UserStatusChange{}
+	for rows.Next() {
+		var change models.UserStatusChange
+		if err := rows.Scan(
+			&change.ID,
+			&change.UserID,
+			&change.FromStatus,
+			&change.ToStatus,
+			&change.Reason,
+			&change.SuspendedUntil,
+			&change.ChangedBy,
+			&change.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan status change: %w", err)
+		}
+		history = append(history, change)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over status history: %w", err)
+	}
+
+	return history, nil
+}
@@ -0,0 +1,76 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// MaxAddressesPerUser limits the size of a user's address book
+const MaxAddressesPerUser = 20
+
+var countryCodePattern = regexp.MustCompile(`^[A-Z]{2}$`)
+
+// Address represents an entry of a user's address book
+type Address struct {
+	ID                   string    `json:"id"`
+	UserID               string    `json:"user_id"`
+	Label                *string   `json:"label,omitempty"`
+	RecipientName        string    `json:"recipient_name"`
+	Phone                *string   `json:"phone,omitempty"`
+	Line1                string    `json:"line1"`
+	Line2                *string   `json:"line2,omitempty"`
+	City                 string    `json:"city"`
+	Region               *string   `json:"region,omitempty"`
+	PostalCode           *string   `json:"postal_code,omitempty"`
+	CountryCode          string    `json:"country_code"`
+	Latitude             *float64  `json:"latitude,omitempty"`
+	Longitude            *float64  `json:"longitude,omitempty"`
+	DeliveryInstructions *string   `json:"delivery_instructions,omitempty"`
+	IsDefault            bool      `json:"is_default"`
+	CreatedAt            time.Time `json:"created_at"`
+	UpdatedAt            time.Time `json:"updated_at"`
+}
+
+// AddressRequest represents the body of creating or replacing an address
+type AddressRequest struct {
+	Label                *string  `json:"label,omitempty" binding:"omitempty,max=50"`
+	RecipientName        string   `json:"recipient_name" binding:"required,max=200"`
+	Phone                *string  `json:"phone,omitempty" binding:"omitempty,max=20"`
+	Line1                string   `json:"line1" binding:"required,max=255"`
+	Line2                *string  `json:"line2,omitempty" binding:"omitempty,max=255"`
+	City                 string   `json:"city" binding:"required,max=100"`
+	Region               *string  `json:"region,omitempty" binding:"omitempty,max=100"`
+	PostalCode           *string  `json:"postal_code,omitempty" binding:"omitempty,max=20"`
+	CountryCode          string   `json:"country_code" binding:"required"`
+	Latitude             *float64 `json:"latitude,omitempty"`
+	Longitude            *float64 `json:"longitude,omitempty"`
+	DeliveryInstructions *string  `json:"delivery_instructions,omitempty" binding:"omitempty,max=500"`
+	IsDefault            bool     `json:"is_default"`
+}
+
+// Validate normalizes the country code and checks the coordinates
+func (r *AddressRequest) Validate() error {
+	r.RecipientName = strings.TrimSpace(r.RecipientName)
+	r.Line1 = strings.TrimSpace(r.Line1)
+	r.City = strings.TrimSpace(r.City)
+	r.CountryCode = strings.ToUpper(strings.TrimSpace(r.CountryCode))
+
+	if r.RecipientName == "" || r.Line1 == "" || r.City == "" {
+		return fmt.Errorf("recipient_name, line1 and city must not be blank")
+	}
+	if !countryCodePattern.MatchString(r.CountryCode) {
+		return fmt.Errorf("country_code must be a two-letter ISO 3166-1 code")
+	}
+	if (r.Latitude == nil) != (r.Longitude == nil) {
+		return fmt.Errorf("latitude and longitude must be given together")
+	}
+	if r.Latitude != nil && (*r.Latitude < -90 || *r.Latitude > 90) {
+		return fmt.Errorf("latitude must be between -90 and 90")
+	}
+	if r.Longitude != nil && (*r.Longitude < -180 || *r.Longitude > 180) {
+		return fmt.Errorf("longitude must be between -180 and 180")
+	}
+	return nil
+}
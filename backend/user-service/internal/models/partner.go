@@ -0,0 +1,96 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MaxPartnerStores limits the number of store IDs a single assignment may carry
+const MaxPartnerStores = 500
+
+// Partner is a Partner-role user and the stores they may see
+type Partner struct {
+	ID             int            `json:"id"`
+	UserID         string         `json:"user_id"`
+	Username       string         `json:"username"`
+	Email          string         `json:"email"`
+	RegionAssigned *string        `json:"region_assigned,omitempty"`
+	Stores         []PartnerStore `json:"stores"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+}
+
+// PartnerStore is a store in the scope of a partner. Source is "region" when the store is in
+// the partner's region and "assigned" when it was assigned individually.
+type PartnerStore struct {
+	StoreID int     `json:"store_id"`
+	Name    string  `json:"name"`
+	City    string  `json:"city"`
+	Region  *string `json:"region,omitempty"`
+	Source  string  `json:"source"`
+}
+
+// PartnerCreateRequest turns an existing user into a partner
+type PartnerCreateRequest struct {
+	UserID         string  `json:"user_id" binding:"required"`
+	RegionAssigned *string `json:"region_assigned,omitempty" binding:"omitempty,max=255"`
+	StoreIDs       []int   `json:"store_ids,omitempty"`
+}
+
+// Validate normalizes the region and checks the store IDs
+func (r *PartnerCreateRequest) Validate() error {
+	r.RegionAssigned = normalizeRegion(r.RegionAssigned)
+	return validateStoreIDs(r.StoreIDs)
+}
+
+// PartnerRegionRequest assigns a region to a partner; null or blank removes it
+type PartnerRegionRequest struct {
+	RegionAssigned *string `json:"region_assigned" binding:"omitempty,max=255"`
+}
+
+// Validate normalizes the region
+func (r *PartnerRegionRequest) Validate() error {
+	r.RegionAssigned = normalizeRegion(r.RegionAssigned)
+	return nil
+}
+
+// StoreAssignmentRequest replaces the stores assigned to a partner or region
+type StoreAssignmentRequest struct {
+	StoreIDs []int `json:"store_ids"`
+}
+
+// Validate checks the store IDs
+func (r *StoreAssignmentRequest) Validate() error {
+	return validateStoreIDs(r.StoreIDs)
+}
+
+// Region is a region named by stores or partners
+type Region struct {
+	Name         string `json:"name"`
+	StoreCount   int    `json:"store_count"`
+	PartnerCount int    `json:"partner_count"`
+}
+
+func normalizeRegion(region *string) *string {
+	if region == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*region)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
+
+func validateStoreIDs(storeIDs []int) error {
+	if len(storeIDs) > MaxPartnerStores {
+		return fmt.Errorf("at most %d stores can be assigned at once", MaxPartnerStores)
+	}
+	for _, id := range storeIDs {
+		if id <= 0 {
+			return fmt.Errorf("store IDs must be positive")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"time"
+)
+
+// PrivacyRequestType represents the kind of personal data request
+type PrivacyRequestType string
+
+const (
+	PrivacyRequestExport  PrivacyRequestType = "export"
+	PrivacyRequestErasure PrivacyRequestType = "erasure"
+)
+
+// PrivacyRequestStatus represents the processing state of a privacy request
+type PrivacyRequestStatus string
+
+const (
+	PrivacyStatusPending    PrivacyRequestStatus = "pending"
+	PrivacyStatusProcessing PrivacyRequestStatus = "processing"
+	PrivacyStatusCompleted  PrivacyRequestStatus = "completed"
+	PrivacyStatusFailed     PrivacyRequestStatus = "failed"
+)
+
+// Privacy request sources
+const (
+	PrivacySourceSelf  = "self"
+	PrivacySourceAdmin = "admin"
+)
+
+// PrivacyRequest represents a personal data export or erasure request
+type PrivacyRequest struct {
+	ID                string               `json:"id"`
+	UserID            string               `json:"user_id"`
+	Type              PrivacyRequestType   `json:"type"`
+	Status            PrivacyRequestStatus `json:"status"`
+	Source            string               `json:"source"`
+	RequestedBy       string               `json:"requested_by"`
+	Reason            *string              `json:"reason,omitempty"`
+	Error             *string              `json:"error,omitempty"`
+	DownloadAvailable bool                 `json:"download_available"`
+	ExportExpiresAt   *time.Time           `json:"export_expires_at,omitempty"`
+	CreatedAt         time.Time            `json:"created_at"`
+	StartedAt         *time.Time           `json:"started_at,omitempty"`
+	CompletedAt       *time.Time           `json:"completed_at,omitempty"`
+}
+
+// PrivacyRequestCreateRequest represents the body of an export or erasure request
+type PrivacyRequestCreateRequest struct {
+	Reason string `json:"reason,omitempty"`
+}
+
+// PrivacyRequestListParams represents filters for listing privacy requests
+type PrivacyRequestListParams struct {
+	UserID string               `form:"user_id"`
+	Type   PrivacyRequestType   `form:"type"`
+	Status PrivacyRequestStatus `form:"status"`
+	Limit  int                  `form:"limit"`
+}
+
+// UserDataExport is the personal data of a user, written to the export bundle one file per
+// section
+type UserDataExport struct {
+	ExportedAt    time.Time                `json:"exported_at"`
+	Profile       map[string]interface{}   `json:"profile"`
+	Orders        []map[string]interface{} `json:"orders"`
+	Addresses     []map[string]interface{} `json:"addresses"`
+	Carts         []map[string]interface{} `json:"carts"`
+	Sessions      []map[string]interface{} `json:"sessions"`
+	Notifications []map[string]interface{} `json:"notifications"`
+	StatusHistory []map[string]interface{} `json:"status_history"`
+	Segments      []map[string]interface{} `json:"segments"`
+	Loyalty       []map[string]interface{} `json:"loyalty"`
+	Wishlist      []map[string]interface{} `json:"wishlist"`
+}
@@ -0,0 +1,211 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MaxSegmentRules limits the number of rules of a segment
+const MaxSegmentRules = 20
+
+// SegmentFieldType is the type of value a segment field compares against
+type SegmentFieldType string
+
+const (
+	SegmentFieldNumber  SegmentFieldType = "number"
+	SegmentFieldText    SegmentFieldType = "text"
+	SegmentFieldBoolean SegmentFieldType = "boolean"
+	SegmentFieldDate    SegmentFieldType = "date"
+)
+
+// SegmentOperators lists the operators allowed for each field type
+var SegmentOperators = map[SegmentFieldType][]string{
+	SegmentFieldNumber:  {"eq", "neq", "gt", "gte", "lt", "lte"},
+	SegmentFieldText:    {"eq", "neq", "in", "not_in"},
+	SegmentFieldBoolean: {"eq"},
+	SegmentFieldDate:    {"before", "after"},
+}
+
+// SegmentField is an attribute segment rules can test
+type SegmentField struct {
+	Name        string           `json:"name"`
+	Category    string           `json:"category"` // profile, order or cart
+	Type        SegmentFieldType `json:"type"`
+	Operators   []string         `json:"operators"`
+	Description string           `json:"description"`
+}
+
+func segmentField(name, category string, fieldType SegmentFieldType, description string) SegmentField {
+	return SegmentField{
+		Name:        name,
+		Category:    category,
+		Type:        fieldType,
+		Operators:   SegmentOperators[fieldType],
+		Description: description,
+	}
+}
+
+// SegmentFields lists the attributes segment rules can test. Text comparisons ignore case.
+var SegmentFields = []SegmentField{
+	segmentField("role", "profile", SegmentFieldText, "User role"),
+	segmentField("status", "profile", SegmentFieldText, "Account status"),
+	segmentField("locale", "profile", SegmentFieldText, "Preferred locale"),
+	segmentField("marketing_consent", "profile", SegmentFieldBoolean, "Agreed to receive marketing messages"),
+	segmentField("city", "profile", SegmentFieldText, "City of any address in the address book"),
+	segmentField("country_code", "profile", SegmentFieldText, "Country of any address in the address book"),
+	segmentField("signed_up_at", "profile", SegmentFieldDate, "Registration time"),
+	segmentField("last_login_at", "profile", SegmentFieldDate, "Last sign-in; users who never signed in do not match"),
+	segmentField("days_since_last_login", "profile", SegmentFieldNumber, "Days since the last sign-in, or since registration for users who never signed in"),
+	segmentField("order_count", "order", SegmentFieldNumber, "Number of orders"),
+	segmentField("total_spent", "order", SegmentFieldNumber, "Total amount of all orders"),
+	segmentField("last_order_at", "order", SegmentFieldDate, "Time of the latest order; users without orders do not match"),
+	segmentField("days_since_last_order", "order", SegmentFieldNumber, "Days since the latest order; users without orders do not match"),
+	segmentField("ordered_mini_app", "order", SegmentFieldText, "Mini-app of any order"),
+	segmentField("ordered_store_city", "order", SegmentFieldText, "City of the store of any order"),
+	segmentField("cart_item_count", "cart", SegmentFieldNumber, "Number of items in the user's carts"),
+	segmentField("cart_mini_app", "cart", SegmentFieldText, "Mini-app of any non-empty cart"),
+	segmentField("days_since_cart_update", "cart", SegmentFieldNumber, "Days since a cart was last changed; users with empty carts do not match"),
+}
+
+// LookupSegmentField finds a segment field by name
+func LookupSegmentField(name string) (SegmentField, bool) {
+	for _, field := range SegmentFields {
+		if field.Name == name {
+			return field, true
+		}
+	}
+	return SegmentField{}, false
+}
+
+// SegmentRule tests one field of a user against a value
+type SegmentRule struct {
+	Field string          `json:"field"`
+	Op    string          `json:"op"`
+	Value json.RawMessage `json:"value"`
+}
+
+// SegmentRules is the rule set of a segment. Users match when all (or any) of the rules hold.
+type SegmentRules struct {
+	Match string        `json:"match"` // all (default) or any
+	Rules []SegmentRule `json:"rules"`
+}
+
+// Validate checks the fields, operators and values of the rule set
+func (r *SegmentRules) Validate() error {
+	if r.Match == "" {
+		r.Match = "all"
+	}
+	if r.Match != "all" && r.Match != "any" {
+		return fmt.Errorf("match must be all or any")
+	}
+	if len(r.Rules) == 0 {
+		return fmt.Errorf("at least one rule is required")
+	}
+	if len(r.Rules) > MaxSegmentRules {
+		return fmt.Errorf("at most %d rules are allowed", MaxSegmentRules)
+	}
+	for i, rule := range r.Rules {
+		if _, err := rule.ParseValue(); err != nil {
+			return fmt.Errorf("rule %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// ParseValue checks the rule and decodes its value: a float64 for numbers, a bool for
+// booleans, a time.Time for dates and a []string of lower-cased values for text
+func (r SegmentRule) ParseValue() (interface{}, error) {
+	field, ok := LookupSegmentField(r.Field)
+	if !ok {
+		return nil, fmt.Errorf("unknown field %q", r.Field)
+	}
+	validOp := false
+	for _, op := range field.Operators {
+		if op == r.Op {
+			validOp = true
+			break
+		}
+	}
+	if !validOp {
+		return nil, fmt.Errorf("operator %q is not allowed for %s (allowed: %s)", r.Op, r.Field, strings.Join(field.Operators, ", "))
+	}
+
+	switch field.Type {
+	case SegmentFieldNumber:
+		var value float64
+		if err := json.Unmarshal(r.Value, &value); err != nil {
+			return nil, fmt.Errorf("%s needs a number", r.Field)
+		}
+		return value, nil
+	case SegmentFieldBoolean:
+		var value bool
+		if err := json.Unmarshal(r.Value, &value); err != nil {
+			return nil, fmt.Errorf("%s needs true or false", r.Field)
+		}
+		return value, nil
+	case SegmentFieldDate:
+		var value string
+		if err := json.Unmarshal(r.Value, &value); err == nil {
+			if t, err := time.Parse(time.RFC3339, value); err == nil {
+				return t, nil
+			}
+			if t, err := time.Parse("2006-01-02", value); err == nil {
+				return t, nil
+			}
+		}
+		return nil, fmt.Errorf("%s needs a date (YYYY-MM-DD) or an RFC 3339 timestamp", r.Field)
+	default:
+		var values []string
+		if r.Op == "in" || r.Op == "not_in" {
+			if err := json.Unmarshal(r.Value, &values); err != nil || len(values) == 0 {
+				return nil, fmt.Errorf("%s %s needs a non-empty list of strings", r.Field, r.Op)
+			}
+		} else {
+			var value string
+			if err := json.Unmarshal(r.Value, &value); err != nil {
+				return nil, fmt.Errorf("%s needs a string", r.Field)
+			}
+			values = []string{value}
+		}
+		for i := range values {
+			values[i] = strings.ToLower(strings.TrimSpace(values[i]))
+		}
+		return values, nil
+	}
+}
+
+// Segment is a saved audience definition with its materialized member count
+type Segment struct {
+	ID              string       `json:"id"`
+	Name            string       `json:"name"`
+	Description     *string      `json:"description,omitempty"`
+	Rules           SegmentRules `json:"rules"`
+	MemberCount     int          `json:"member_count"`
+	LastEvaluatedAt *time.Time   `json:"last_evaluated_at,omitempty"`
+	CreatedBy       string       `json:"created_by"`
+	CreatedAt       time.Time    `json:"created_at"`
+	UpdatedAt       time.Time    `json:"updated_at"`
+}
+
+// SegmentRequest represents the body of creating or replacing a segment
+type SegmentRequest struct {
+	Name        string       `json:"name" binding:"required,max=100"`
+	Description *string      `json:"description,omitempty" binding:"omitempty,max=500"`
+	Rules       SegmentRules `json:"rules"`
+}
+
+// Validate trims the name and checks the rule set
+func (r *SegmentRequest) Validate() error {
+	r.Name = strings.TrimSpace(r.Name)
+	if r.Name == "" {
+		return fmt.Errorf("name must not be blank")
+	}
+	return r.Rules.Validate()
+}
+
+// SegmentPreviewResponse is the number of users a rule set currently matches
+type SegmentPreviewResponse struct {
+	MemberCount int `json:"member_count"`
+}
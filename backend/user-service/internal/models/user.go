@@ -1,6 +1,9 @@
 package models
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
 	"time"
 )
 
@@ -19,8 +22,10 @@ const (
 type UserStatus string
 
 const (
-	StatusActive      UserStatus = "active"
-	StatusDeactivated UserStatus = "deactivated"
+	StatusActive              UserStatus = "active"
+	StatusSuspended           UserStatus = "suspended"
+	StatusDeactivated         UserStatus = "deactivated"
+	StatusPendingVerification UserStatus = "pending_verification"
 )
 
 // User represents a user in the system
@@ -34,10 +39,16 @@ type User struct {
 	CreatedAt    time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
 
+	// Account status; an expired suspension reads as active
+	Status          UserStatus `json:"status"`
+	StatusReason    *string    `json:"status_reason,omitempty"`
+	SuspendedUntil  *time.Time `json:"suspended_until,omitempty"`
+	StatusChangedAt *time.Time `json:"status_changed_at,omitempty"`
+	StatusChangedBy *string    `json:"status_changed_by,omitempty"`
+
 	// Additional computed fields for admin panel
 	FullName   string     `json:"full_name"`
 	Role       UserRole   `json:"role"`
-	Status     UserStatus `json:"status"`
 	LastLogin  *time.Time `json:"last_login,omitempty"`
 	OrderCount int        `json:"order_count,omitempty"`
 	TotalSpent float64    `json:"total_spent,omitempty"`
@@ -50,6 +61,7 @@ type UserListResponse struct {
 	Page       int    `json:"page"`
 	Limit      int    `json:"limit"`
 	TotalPages int    `json:"total_pages"`
+	NextCursor string `json:"next_cursor,omitempty"` // set when paging by cursor and more users follow
 }
 
 // UserSearchParams represents search and filter parameters
@@ -61,6 +73,59 @@ type UserSearchParams struct {
 	Status *UserStatus `json:"status"`
 	Sort   string      `json:"sort"`
 	Order  string      `json:"order"`
+
+	// Cursor continues a keyset-paginated listing after the last user of the previous page.
+	// UseCursor is set when the caller asked for cursor paging, even on the first page.
+	Cursor    *UserCursor `json:"cursor,omitempty"`
+	UseCursor bool        `json:"-"`
+
+	// Date ranges are inclusive
+	CreatedFrom   *time.Time `json:"created_from,omitempty"`
+	CreatedTo     *time.Time `json:"created_to,omitempty"`
+	LastLoginFrom *time.Time `json:"last_login_from,omitempty"`
+	LastLoginTo   *time.Time `json:"last_login_to,omitempty"`
+
+	MinOrders        *int     `json:"min_orders,omitempty"`
+	MinSpent         *float64 `json:"min_spent,omitempty"`
+	MiniAppType      string   `json:"mini_app_type,omitempty"` // users with at least one order in this mini-app
+	MarketingConsent *bool    `json:"marketing_consent,omitempty"`
+	SegmentID        string   `json:"segment_id,omitempty"` // members of a user segment
+}
+
+// UserExportRow is a line of the CSV export of the user list
+type UserExportRow struct {
+	User
+	Phone            *string
+	Locale           *string
+	MarketingConsent bool
+}
+
+// UserCursor is the position of the last user of a page: its sort key (as text) and ID,
+// along with the ordering it belongs to
+type UserCursor struct {
+	Sort    string `json:"s"`
+	Order   string `json:"o"`
+	SortKey string `json:"k"`
+	ID      string `json:"id"`
+}
+
+// EncodeUserCursor encodes a cursor for the next_cursor field of a listing
+func EncodeUserCursor(cursor UserCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeUserCursor decodes a cursor given back by a client
+func DecodeUserCursor(value string) (*UserCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	var cursor UserCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == "" {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	return &cursor, nil
 }
 
 // UserCreateRequest represents user creation request
@@ -84,17 +149,61 @@ type UserUpdateRequest struct {
 
 // UserStatusUpdateRequest represents user status update request
 type UserStatusUpdateRequest struct {
-	Status UserStatus `json:"status" binding:"required"`
-	Reason string     `json:"reason,omitempty"`
+	Status         UserStatus `json:"status" binding:"required"`
+	Reason         string     `json:"reason,omitempty"`
+	SuspendedUntil *time.Time `json:"suspended_until,omitempty"` // only for suspended; omitted suspends indefinitely
+}
+
+// UserStatusChange represents an entry of a user's status history
+type UserStatusChange struct {
+	ID             int64      `json:"id"`
+	UserID         string     `json:"user_id"`
+	FromStatus     UserStatus `json:"from_status"`
+	ToStatus       UserStatus `json:"to_status"`
+	Reason         *string    `json:"reason,omitempty"`
+	SuspendedUntil *time.Time `json:"suspended_until,omitempty"`
+	ChangedBy      string     `json:"changed_by"`
+	CreatedAt      time.Time  `json:"created_at"`
 }
 
+// Bulk user operations
+const (
+	BulkOperationStatusUpdate = "status_update"
+	BulkOperationRoleUpdate   = "role_update"
+	BulkOperationDelete       = "delete"
+)
+
+// MaxBulkUserIDs limits the users a single bulk operation may touch
+const MaxBulkUserIDs = 500
+
 // BulkUserUpdateRequest represents bulk user operations
 type BulkUserUpdateRequest struct {
-	UserIDs   []string    `json:"user_ids" binding:"required"`
-	Operation string      `json:"operation" binding:"required"` // "status_update", "role_update", "delete"
-	Status    *UserStatus `json:"status,omitempty"`
-	Role      *UserRole   `json:"role,omitempty"`
-	Reason    string      `json:"reason,omitempty"`
+	UserIDs        []string    `json:"user_ids" binding:"required,min=1,dive,required"`
+	Operation      string      `json:"operation" binding:"required"` // "status_update", "role_update", "delete"
+	Status         *UserStatus `json:"status,omitempty"`
+	SuspendedUntil *time.Time  `json:"suspended_until,omitempty"`
+	Role           *UserRole   `json:"role,omitempty"`
+	Reason         string      `json:"reason,omitempty"`
+	DryRun         bool        `json:"dry_run"` // report what would change without changing anything
+}
+
+// BulkUserUpdateItem is the outcome of a bulk operation for one user. From and To describe
+// the change (the role or status before and after, "erased" for deletions); Error explains
+// why the user was skipped.
+type BulkUserUpdateItem struct {
+	UserID string `json:"user_id"`
+	From   string `json:"from,omitempty"`
+	To     string `json:"to,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// BulkUserUpdateResponse lists the users a bulk operation succeeded and failed for. In a
+// dry run, succeeded lists the users that would change.
+type BulkUserUpdateResponse struct {
+	Operation string               `json:"operation"`
+	DryRun    bool                 `json:"dry_run"`
+	Succeeded []BulkUserUpdateItem `json:"succeeded"`
+	Failed    []BulkUserUpdateItem `json:"failed"`
 }
 
 // UserAnalytics represents user analytics data
@@ -149,20 +258,25 @@ func ValidateUserRole(role string) bool {
 // ValidateUserStatus validates if the status is valid
 func ValidateUserStatus(status string) bool {
 	switch UserStatus(status) {
-	case StatusActive, StatusDeactivated:
+	case StatusActive, StatusSuspended, StatusDeactivated, StatusPendingVerification:
 		return true
 	default:
 		return false
 	}
 }
 
-// GetUserStatus determines user status based on creation date and other factors
-func (u *User) GetUserStatus() UserStatus {
-	// This is a simple implementation - can be enhanced with more complex logic
-	// For now, consider users created in the last 30 days as active
-	if time.Since(u.CreatedAt) <= 30*24*time.Hour {
-		return StatusActive
+// Validate checks the status and its suspension expiry
+func (r *UserStatusUpdateRequest) Validate() error {
+	if !ValidateUserStatus(string(r.Status)) {
+		return fmt.Errorf("status must be one of: active, suspended, deactivated, pending_verification")
 	}
-
-	return StatusDeactivated
+	if r.SuspendedUntil != nil {
+		if r.Status != StatusSuspended {
+			return fmt.Errorf("suspended_until is only allowed with status suspended")
+		}
+		if !r.SuspendedUntil.After(time.Now()) {
+			return fmt.Errorf("suspended_until must be in the future")
+		}
+	}
+	return nil
 }
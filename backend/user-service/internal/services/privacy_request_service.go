@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"user-service/internal/db"
+	"user-service/internal/models"
+)
+
+const defaultPrivacyWorkerIntervalSeconds = 30
+
+// PrivacyRequestService processes pending data export and erasure requests in the background
+type PrivacyRequestService struct {
+	userRepo *db.UserRepository
+	interval time.Duration
+	stopChan chan bool
+}
+
+// NewPrivacyRequestService creates a new privacy request service
+func NewPrivacyRequestService(database *db.Database, intervalSeconds int) *PrivacyRequestService {
+	return &PrivacyRequestService{
+		userRepo: db.NewUserRepository(database),
+		interval: time.Duration(intervalSeconds) * time.Second,
+		stopChan: make(chan bool),
+	}
+}
+
+// PrivacyWorkerIntervalFromEnv returns PRIVACY_WORKER_INTERVAL_SECONDS or the default
+func PrivacyWorkerIntervalFromEnv() int {
+	if value, err := strconv.Atoi(os.Getenv("PRIVACY_WORKER_INTERVAL_SECONDS")); err == nil && value > 0 {
+		return value
+	}
+	return defaultPrivacyWorkerIntervalSeconds
+}
+
+// Start begins polling for pending privacy requests
+func (s *PrivacyRequestService) Start() {
+	log.Printf("Starting privacy request service with %v interval", s.interval)
+
+	ticker := time.NewTicker(s.interval)
+
+	go func() {
+		s.runPending()
+		for {
+			select {
+			case <-ticker.C:
+				s.runPending()
+			case <-s.stopChan:
+				ticker.Stop()
+				log.Println("Privacy request service stopped")
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the privacy request service
+func (s *PrivacyRequestService) Stop() {
+	s.stopChan <- true
+}
+
+// runPending processes pending requests one at a time until none are left, then clears
+// expired export bundles
+func (s *PrivacyRequestService) runPending() {
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		req, err := s.userRepo.ClaimNextPrivacyRequest(ctx)
+		if err != nil {
+			log.Printf("Error claiming privacy request: %v", err)
+			cancel()
+			return
+		}
+		if req == nil {
+			cancel()
+			break
+		}
+
+		s.process(ctx, req)
+		cancel()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	cleared, err := s.userRepo.ClearExpiredExportBundles(ctx)
+	if err != nil {
+		log.Printf("Error clearing expired export bundles: %v", err)
+	} else if cleared > 0 {
+		log.Printf("Cleared %d expired export bundles", cleared)
+	}
+}
+
+// process runs one claimed request and records its outcome
+func (s *PrivacyRequestService) process(ctx context.Context, req *models.PrivacyRequest) {
+	var err error
+	switch req.Type {
+	case models.PrivacyRequestExport:
+		var bundle []byte
+		bundle, err = s.userRepo.BuildExportBundle(ctx, req.UserID)
+		if err == nil {
+			err = s.userRepo.CompleteExport(ctx, req.ID, bundle)
+		}
+	case models.PrivacyRequestErasure:
+		err = s.userRepo.EraseUser(ctx, req.UserID, req.ID, req.RequestedBy)
+	}
+
+	if err != nil {
+		log.Printf("Privacy request %s (%s of user %s) failed: %v", req.ID, req.Type, req.UserID, err)
+		if failErr := s.userRepo.FailPrivacyRequest(context.Background(), req.ID, err.Error()); failErr != nil {
+			log.Printf("Error recording failure of privacy request %s: %v", req.ID, failErr)
+		}
+		return
+	}
+
+	log.Printf("Privacy request %s (%s of user %s) completed", req.ID, req.Type, req.UserID)
+}
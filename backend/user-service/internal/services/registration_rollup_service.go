@@ -0,0 +1,201 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"user-service/internal/db"
+)
+
+const (
+	// RegistrationRollupName identifies the registration rollups in analytics_rollup_state
+	RegistrationRollupName = "registrations"
+
+	defaultRollupIntervalMinutes = 5
+
+	// rollupOverlap is re-read on every run so rows committed while the previous run was
+	// in progress are not missed
+	rollupOverlap = time.Hour
+)
+
+// RegistrationRollupService keeps the hourly and daily registration rollups up to date
+type RegistrationRollupService struct {
+	db       *db.Database
+	interval time.Duration
+	stopChan chan bool
+}
+
+// NewRegistrationRollupService creates a new registration rollup service
+func NewRegistrationRollupService(database *db.Database, intervalMinutes int) *RegistrationRollupService {
+	return &RegistrationRollupService{
+		db:       database,
+		interval: time.Duration(intervalMinutes) * time.Minute,
+		stopChan: make(chan bool),
+	}
+}
+
+// RollupIntervalFromEnv returns ANALYTICS_ROLLUP_INTERVAL_MINUTES or the default
+func RollupIntervalFromEnv() int {
+	if value, err := strconv.Atoi(os.Getenv("ANALYTICS_ROLLUP_INTERVAL_MINUTES")); err == nil && value > 0 {
+		return value
+	}
+	return defaultRollupIntervalMinutes
+}
+
+// Start begins the periodic rollup refresh
+func (s *RegistrationRollupService) Start() {
+	log.Printf("Starting registration rollup service with %v interval", s.interval)
+
+	ticker := time.NewTicker(s.interval)
+
+	go func() {
+		// Refresh right away, in the background: the first refresh backfills all history
+		// and must not hold up the server start
+		s.runRefresh()
+
+		for {
+			select {
+			case <-ticker.C:
+				s.runRefresh()
+			case <-s.stopChan:
+				ticker.Stop()
+				log.Println("Registration rollup service stopped")
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the rollup service
+func (s *RegistrationRollupService) Stop() {
+	s.stopChan <- true
+}
+
+func (s *RegistrationRollupService) runRefresh() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	if err := s.Refresh(ctx); err != nil {
+		log.Printf("Error refreshing registration rollups: %v", err)
+	}
+}
+
+// Refresh recomputes the UTC days in which a user was created or updated since the last
+// run, so role changes are reflected too. The current day is always recomputed to pick
+// up deleted users. The first run backfills everything.
+func (s *RegistrationRollupService) Refresh(ctx context.Context) error {
+	var now time.Time
+	if err := s.db.DB.QueryRowContext(ctx, "SELECT CURRENT_TIMESTAMP").Scan(&now); err != nil {
+		return fmt.Errorf("failed to read database time: %w", err)
+	}
+
+	var lastRun time.Time
+	err := s.db.DB.QueryRowContext(ctx,
+		"SELECT last_run_at FROM analytics_rollup_state WHERE rollup_name = $1",
+		RegistrationRollupName).Scan(&lastRun)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("failed to read rollup state: %w", err)
+	}
+
+	var days []time.Time
+	if err == sql.ErrNoRows {
+		var earliest sql.NullTime
+		if err := s.db.DB.QueryRowContext(ctx, "SELECT MIN(created_at) FROM users").Scan(&earliest); err != nil {
+			return fmt.Errorf("failed to find earliest registration: %w", err)
+		}
+		from := now
+		if earliest.Valid {
+			from = earliest.Time
+		}
+		log.Printf("No registration rollups yet, backfilling from %s", from.UTC().Format(time.RFC3339))
+		for day := truncateDay(from); !day.After(now); day = day.AddDate(0, 0, 1) {
+			days = append(days, day)
+		}
+	} else {
+		rows, err := s.db.DB.QueryContext(ctx, `
+			SELECT DISTINCT date_trunc('day', created_at AT TIME ZONE 'UTC') AT TIME ZONE 'UTC'
+			FROM users
+			WHERE created_at >= $1 OR updated_at >= $1
+		`, lastRun.Add(-rollupOverlap))
+		if err != nil {
+			return fmt.Errorf("failed to find changed registration days: %w", err)
+		}
+		defer rows.Close()
+
+		today := truncateDay(now)
+		days = append(days, today)
+		for rows.Next() {
+			var day time.Time
+			if err := rows.Scan(&day); err != nil {
+				return fmt.Errorf("failed to scan registration day: %w", err)
+			}
+			if !day.Equal(today) {
+				days = append(days, day)
+			}
+		}
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("failed to read changed registration days: %w", err)
+		}
+	}
+
+	for _, day := range days {
+		if err := s.refreshDay(ctx, day); err != nil {
+			return err
+		}
+	}
+
+	_, err = s.db.DB.ExecContext(ctx, `
+		INSERT INTO analytics_rollup_state (rollup_name, last_run_at, updated_at)
+		VALUES ($1, $2, CURRENT_TIMESTAMP)
+		ON CONFLICT (rollup_name) DO UPDATE SET last_run_at = EXCLUDED.last_run_at, updated_at = CURRENT_TIMESTAMP
+	`, RegistrationRollupName, now)
+	if err != nil {
+		return fmt.Errorf("failed to save rollup state: %w", err)
+	}
+	return nil
+}
+
+// refreshDay rebuilds the hourly and daily registration rollups of one UTC day
+func (s *RegistrationRollupService) refreshDay(ctx context.Context, day time.Time) error {
+	tx, err := s.db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	statements := []string{
+		`DELETE FROM analytics_registration_rollups WHERE bucket_start >= $1 AND bucket_start < $2`,
+		`INSERT INTO analytics_registration_rollups (granularity, bucket_start, role, registrations)
+		SELECT 'hour', date_trunc('hour', created_at AT TIME ZONE 'UTC') AT TIME ZONE 'UTC', role::text, COUNT(*)
+		FROM users
+		WHERE created_at >= $1 AND created_at < $2
+		GROUP BY 2, 3`,
+		`INSERT INTO analytics_registration_rollups (granularity, bucket_start, role, registrations)
+		SELECT 'day', $1, role, SUM(registrations)
+		FROM analytics_registration_rollups
+		WHERE granularity = 'hour' AND bucket_start >= $1 AND bucket_start < $2
+		GROUP BY role`,
+	}
+
+	for _, statement := range statements {
+		if _, err := tx.ExecContext(ctx, statement, day, day.AddDate(0, 0, 1)); err != nil {
+			return fmt.Errorf("failed to refresh registration rollups for %s: %w", day.Format("2006-01-02"), err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit registration rollups: %w", err)
+	}
+	return nil
+}
+
+// truncateDay returns the start of the UTC day containing t
+func truncateDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"user-service/internal/db"
+)
+
+const defaultSegmentRefreshIntervalMinutes = 60
+
+// SegmentRefreshService re-evaluates the members of every user segment on a schedule
+type SegmentRefreshService struct {
+	userRepo *db.UserRepository
+	interval time.Duration
+	stopChan chan bool
+}
+
+// NewSegmentRefreshService creates a new segment refresh service
+func NewSegmentRefreshService(database *db.Database, intervalMinutes int) *SegmentRefreshService {
+	return &SegmentRefreshService{
+		userRepo: db.NewUserRepository(database),
+		interval: time.Duration(intervalMinutes) * time.Minute,
+		stopChan: make(chan bool),
+	}
+}
+
+// SegmentRefreshIntervalFromEnv returns SEGMENT_REFRESH_INTERVAL_MINUTES or the default
+func SegmentRefreshIntervalFromEnv() int {
+	if value, err := strconv.Atoi(os.Getenv("SEGMENT_REFRESH_INTERVAL_MINUTES")); err == nil && value > 0 {
+		return value
+	}
+	return defaultSegmentRefreshIntervalMinutes
+}
+
+// Start begins refreshing segments periodically
+func (s *SegmentRefreshService) Start() {
+	log.Printf("Starting segment refresh service with %v interval", s.interval)
+
+	ticker := time.NewTicker(s.interval)
+
+	go func() {
+		s.refreshAll()
+		for {
+			select {
+			case <-ticker.C:
+				s.refreshAll()
+			case <-s.stopChan:
+				ticker.Stop()
+				log.Println("Segment refresh service stopped")
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the segment refresh service
+func (s *SegmentRefreshService) Stop() {
+	s.stopChan <- true
+}
+
+// refreshAll re-evaluates each segment in turn; a failing segment does not stop the others
+func (s *SegmentRefreshService) refreshAll() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	segments, err := s.userRepo.GetSegments(ctx)
+	cancel()
+	if err != nil {
+		log.Printf("Error listing segments: %v", err)
+		return
+	}
+
+	for _, segment := range segments {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		refreshed, err := s.userRepo.RefreshSegment(ctx, segment.ID)
+		cancel()
+		if err != nil {
+			log.Printf("Error refreshing segment %s (%s): %v", segment.ID, segment.Name, err)
+			continue
+		}
+		log.Printf("Refreshed segment %s (%s): %d members", refreshed.ID, refreshed.Name, refreshed.MemberCount)
+	}
+}
@@ -0,0 +1,8 @@
+-- Migration: Manufacturer management
+-- Date: 2026-10-19
+-- Description: Supports the manufacturer CRUD API in catalog-service. Products are
+--              filtered by manufacturer, so the foreign key column gets an index.
+
+CREATE INDEX IF NOT EXISTS idx_products_manufacturer_id ON products(manufacturer_id);
+
+COMMENT ON COLUMN products.manufacturer_id IS 'Owning manufacturer - validated by catalog-service on create/update';
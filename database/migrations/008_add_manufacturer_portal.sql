@@ -0,0 +1,42 @@
+-- Migration: Manufacturer self-service portal
+-- Date: 2026-10-19
+-- Description: Links Manufacturer-role users to a manufacturers row and adds
+--              price change proposals that require admin approval before they
+--              are applied to products.
+
+-- Link a user account (role = 'Manufacturer') to the manufacturer it acts for
+ALTER TABLE manufacturers
+ADD COLUMN IF NOT EXISTS user_id UUID REFERENCES users(id) ON DELETE SET NULL;
+
+ALTER TABLE manufacturers DROP CONSTRAINT IF EXISTS manufacturers_user_id_key;
+ALTER TABLE manufacturers
+ADD CONSTRAINT manufacturers_user_id_key UNIQUE (user_id);
+
+COMMENT ON COLUMN manufacturers.user_id IS 'User with the Manufacturer role that manages this manufacturer through the portal';
+
+-- Price change proposals submitted by manufacturers
+CREATE TABLE IF NOT EXISTS product_price_change_requests (
+    request_id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL REFERENCES products(product_id) ON DELETE CASCADE,
+    manufacturer_id INTEGER NOT NULL REFERENCES manufacturers(manufacturer_id),
+    requested_by UUID NOT NULL REFERENCES users(id),
+    current_price NUMERIC(10,2) NOT NULL,
+    proposed_price NUMERIC(10,2) NOT NULL CHECK (proposed_price > 0),
+    proposed_strikethrough_price NUMERIC(10,2),
+    reason TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'approved', 'rejected', 'withdrawn')),
+    reviewed_by VARCHAR(255),
+    review_note TEXT,
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_price_change_requests_manufacturer ON product_price_change_requests(manufacturer_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_price_change_requests_status ON product_price_change_requests(status);
+
+-- Only one proposal per product may be awaiting review at a time
+CREATE UNIQUE INDEX IF NOT EXISTS idx_price_change_requests_one_pending
+    ON product_price_change_requests(product_id) WHERE status = 'pending';
+
+COMMENT ON TABLE product_price_change_requests IS 'Manufacturer price proposals; prices change only when an admin approves';
@@ -0,0 +1,63 @@
+-- Migration: Stock request workflow
+-- Date: 2026-10-19
+-- Description: Replenishment requests per store/product using the stock_request_status
+--              enum. Requests are raised by store operators or the automatic low-stock
+--              trigger, confirmed by manufacturers and verified on delivery, which adds
+--              the received quantity to inventory. Every status change is recorded.
+
+CREATE TABLE IF NOT EXISTS stock_requests (
+    request_id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL REFERENCES products(product_id) ON DELETE CASCADE,
+    manufacturer_id INTEGER NOT NULL REFERENCES manufacturers(manufacturer_id),
+    destination_store_id INTEGER NOT NULL REFERENCES stores(store_id),
+    quantity_requested INTEGER NOT NULL CHECK (quantity_requested > 0),
+    status stock_request_status NOT NULL DEFAULT 'Pending',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Columns added on top of the original init.sql definition
+ALTER TABLE stock_requests ADD COLUMN IF NOT EXISTS quantity_confirmed INTEGER CHECK (quantity_confirmed > 0);
+ALTER TABLE stock_requests ADD COLUMN IF NOT EXISTS quantity_verified INTEGER CHECK (quantity_verified >= 0);
+ALTER TABLE stock_requests ADD COLUMN IF NOT EXISTS requested_by UUID REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE stock_requests ADD COLUMN IF NOT EXISTS verified_by UUID REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE stock_requests ADD COLUMN IF NOT EXISTS source VARCHAR(20) NOT NULL DEFAULT 'manual'
+    CHECK (source IN ('manual', 'low_stock'));
+ALTER TABLE stock_requests ADD COLUMN IF NOT EXISTS notes TEXT;
+ALTER TABLE stock_requests ADD COLUMN IF NOT EXISTS discrepancy_notes TEXT;
+ALTER TABLE stock_requests ADD COLUMN IF NOT EXISTS verified_at TIMESTAMP WITH TIME ZONE;
+
+-- Automatic requests have no requesting user; relax the legacy column where it exists
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM information_schema.columns
+               WHERE table_name = 'stock_requests' AND column_name = 'requesting_admin_id') THEN
+        ALTER TABLE stock_requests ALTER COLUMN requesting_admin_id DROP NOT NULL;
+    END IF;
+END $$;
+
+-- At most one open request per product and store; the low-stock trigger relies on this
+CREATE UNIQUE INDEX IF NOT EXISTS idx_stock_requests_one_open
+    ON stock_requests(product_id, destination_store_id)
+    WHERE status NOT IN ('Verified', 'Cancelled');
+
+CREATE INDEX IF NOT EXISTS idx_stock_requests_status ON stock_requests(status);
+CREATE INDEX IF NOT EXISTS idx_stock_requests_manufacturer ON stock_requests(manufacturer_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_stock_requests_store ON stock_requests(destination_store_id, created_at DESC);
+
+-- Status history
+CREATE TABLE IF NOT EXISTS stock_request_status_history (
+    history_id SERIAL PRIMARY KEY,
+    request_id INTEGER NOT NULL REFERENCES stock_requests(request_id) ON DELETE CASCADE,
+    old_status stock_request_status,
+    new_status stock_request_status NOT NULL,
+    changed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    note TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_stock_request_history_request ON stock_request_status_history(request_id, created_at);
+
+COMMENT ON TABLE stock_requests IS 'Replenishment requests; verification adds quantity_verified to inventory.quantity';
+COMMENT ON COLUMN stock_requests.source IS 'manual = raised by an operator or admin, low_stock = raised by the catalog-service low-stock trigger';
+COMMENT ON TABLE stock_request_status_history IS 'Append-only log of stock request status changes';
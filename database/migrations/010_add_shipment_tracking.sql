@@ -0,0 +1,45 @@
+-- Migration: Shipment tracking for 3PL partners
+-- Date: 2026-10-19
+-- Description: Shipments deliver either a customer order or a stock request and are
+--              assigned to a user with the 3PL role. Status follows the shipment_status
+--              enum (Assigned -> Picked Up -> Delivered) with a timestamp for each step
+--              and a proof-of-delivery photo on delivery.
+
+CREATE TABLE IF NOT EXISTS shipments (
+    shipment_id SERIAL PRIMARY KEY,
+    request_id INTEGER REFERENCES stock_requests(request_id),
+    assigned_3pl_id UUID REFERENCES users(id),
+    pickup_timestamp TIMESTAMP WITH TIME ZONE,
+    delivery_timestamp TIMESTAMP WITH TIME ZONE,
+    status shipment_status DEFAULT 'Assigned',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Shipments created from init.sql only carried stock requests
+ALTER TABLE shipments ALTER COLUMN request_id DROP NOT NULL;
+ALTER TABLE shipments ADD COLUMN IF NOT EXISTS order_id UUID REFERENCES orders(id) ON DELETE CASCADE;
+ALTER TABLE shipments ADD COLUMN IF NOT EXISTS assigned_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP;
+ALTER TABLE shipments ADD COLUMN IF NOT EXISTS proof_of_delivery_url VARCHAR(500);
+ALTER TABLE shipments ADD COLUMN IF NOT EXISTS delivery_notes TEXT;
+
+UPDATE shipments SET status = 'Assigned' WHERE status IS NULL;
+ALTER TABLE shipments ALTER COLUMN status SET NOT NULL;
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'shipments_target_check') THEN
+        ALTER TABLE shipments ADD CONSTRAINT shipments_target_check
+            CHECK ((order_id IS NULL) <> (request_id IS NULL));
+    END IF;
+END $$;
+
+-- One shipment per order and per stock request
+CREATE UNIQUE INDEX IF NOT EXISTS idx_shipments_order ON shipments(order_id) WHERE order_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_shipments_request ON shipments(request_id) WHERE request_id IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_shipments_status ON shipments(status);
+CREATE INDEX IF NOT EXISTS idx_shipments_assigned_3pl ON shipments(assigned_3pl_id, status);
+
+COMMENT ON TABLE shipments IS '3PL deliveries of customer orders or stock requests';
+COMMENT ON COLUMN shipments.proof_of_delivery_url IS 'Photo uploaded by the 3PL user when marking the shipment delivered';
@@ -0,0 +1,22 @@
+-- Migration: In-app notification inbox
+-- Date: 2026-10-19
+-- Description: Prepares the notifications table for the inbox served by order-service.
+--              Order IDs are UUIDs, so reference_id becomes text. Adds a notification type
+--              for the app to pick an icon, a read timestamp and an index for inbox reads.
+
+ALTER TABLE notifications ALTER COLUMN reference_id TYPE VARCHAR(64) USING reference_id::text;
+
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS notification_type VARCHAR(30) NOT NULL DEFAULT 'general'
+    CHECK (notification_type IN ('general', 'order_status', 'shipment', 'low_stock'));
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS read_at TIMESTAMP WITH TIME ZONE;
+
+UPDATE notifications SET is_read = false WHERE is_read IS NULL;
+ALTER TABLE notifications ALTER COLUMN is_read SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_notifications_recipient_created
+    ON notifications(recipient_user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_notifications_recipient_unread
+    ON notifications(recipient_user_id) WHERE is_read = false;
+
+COMMENT ON COLUMN notifications.reference_id IS 'ID of the referenced order (UUID), stock request or shipment, as text';
+COMMENT ON COLUMN notifications.notification_type IS 'order_status, shipment, low_stock or general';
@@ -0,0 +1,71 @@
+-- Migration: Pre-aggregated analytics rollups
+-- Date: 2026-10-19
+-- Description: Hourly and daily aggregate tables read by the admin dashboard endpoints.
+--              order-service maintains order, product and cart rollups; user-service
+--              maintains registration rollups. Buckets are UTC hours and UTC days; store_id 0
+--              stands for "no store" so it can be part of the primary key. Registrations are
+--              rolled up by role only: users record neither the mini-app nor the store they
+--              signed up through, so there is no mini-app or store to key them by.
+
+CREATE TABLE IF NOT EXISTS analytics_order_rollups (
+    granularity VARCHAR(4) NOT NULL CHECK (granularity IN ('hour', 'day')),
+    bucket_start TIMESTAMP WITH TIME ZONE NOT NULL,
+    mini_app_type VARCHAR(50) NOT NULL,
+    store_id INTEGER NOT NULL DEFAULT 0,
+    status VARCHAR(50) NOT NULL,
+    order_count INTEGER NOT NULL DEFAULT 0,
+    revenue NUMERIC(14,2) NOT NULL DEFAULT 0,
+    units_sold INTEGER NOT NULL DEFAULT 0,
+    refreshed_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (granularity, bucket_start, mini_app_type, store_id, status)
+);
+
+CREATE TABLE IF NOT EXISTS analytics_product_rollups (
+    granularity VARCHAR(4) NOT NULL CHECK (granularity IN ('hour', 'day')),
+    bucket_start TIMESTAMP WITH TIME ZONE NOT NULL,
+    product_id UUID NOT NULL,
+    order_count INTEGER NOT NULL DEFAULT 0,
+    units_sold INTEGER NOT NULL DEFAULT 0,
+    revenue NUMERIC(14,2) NOT NULL DEFAULT 0,
+    refreshed_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (granularity, bucket_start, product_id)
+);
+
+CREATE TABLE IF NOT EXISTS analytics_cart_rollups (
+    granularity VARCHAR(4) NOT NULL CHECK (granularity IN ('hour', 'day')),
+    bucket_start TIMESTAMP WITH TIME ZONE NOT NULL,
+    mini_app_type VARCHAR(50) NOT NULL,
+    store_id INTEGER NOT NULL DEFAULT 0,
+    carts_started INTEGER NOT NULL DEFAULT 0,
+    items_added INTEGER NOT NULL DEFAULT 0,
+    cart_value NUMERIC(14,2) NOT NULL DEFAULT 0,
+    refreshed_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (granularity, bucket_start, mini_app_type, store_id)
+);
+
+CREATE TABLE IF NOT EXISTS analytics_registration_rollups (
+    granularity VARCHAR(4) NOT NULL CHECK (granularity IN ('hour', 'day')),
+    bucket_start TIMESTAMP WITH TIME ZONE NOT NULL,
+    role VARCHAR(50) NOT NULL,
+    registrations INTEGER NOT NULL DEFAULT 0,
+    refreshed_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (granularity, bucket_start, role)
+);
+
+-- Watermarks for the incremental workers
+CREATE TABLE IF NOT EXISTS analytics_rollup_state (
+    rollup_name VARCHAR(50) PRIMARY KEY,
+    last_run_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- The incremental refresh looks up orders and users changed since the last run
+CREATE INDEX IF NOT EXISTS idx_orders_updated_at ON orders(updated_at);
+CREATE INDEX IF NOT EXISTS idx_orders_created_at ON orders(created_at);
+CREATE INDEX IF NOT EXISTS idx_users_updated_at ON users(updated_at);
+CREATE INDEX IF NOT EXISTS idx_users_created_at ON users(created_at);
+
+COMMENT ON TABLE analytics_order_rollups IS 'Orders, revenue and units per UTC hour/day, mini-app, store and status';
+COMMENT ON TABLE analytics_product_rollups IS 'Non-cancelled sales per UTC hour/day and product';
+COMMENT ON TABLE analytics_cart_rollups IS 'Carts started and items added per UTC hour/day, mini-app and store';
+COMMENT ON TABLE analytics_registration_rollups IS 'New user registrations per UTC hour/day and role';
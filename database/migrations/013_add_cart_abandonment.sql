@@ -0,0 +1,39 @@
+-- Migration: Cart abandonment tracking and recovery emails
+-- Date: 2026-10-19
+-- Description: Tracks the activity of each cart (user, mini-app and store; store_id 0 stands
+--              for "no store") so order-service can classify abandoned carts, send a single
+--              recovery email per cart session and record whether the cart later converted.
+
+CREATE TABLE IF NOT EXISTS cart_activity (
+    id SERIAL PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    mini_app_type VARCHAR(50) NOT NULL,
+    store_id INTEGER NOT NULL DEFAULT 0,
+    status VARCHAR(20) NOT NULL DEFAULT 'active'
+        CHECK (status IN ('active', 'abandoned', 'expired', 'emptied', 'converted')),
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_activity_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    abandoned_at TIMESTAMP WITH TIME ZONE,
+    recovery_token VARCHAR(64) UNIQUE,
+    recovery_email_sent_at TIMESTAMP WITH TIME ZONE,
+    converted_at TIMESTAMP WITH TIME ZONE,
+    converted_order_id UUID REFERENCES orders(id) ON DELETE SET NULL,
+    recovered BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, mini_app_type, store_id)
+);
+
+COMMENT ON TABLE cart_activity IS 'Activity and abandonment state of each cart session';
+COMMENT ON COLUMN cart_activity.recovered IS 'True when the cart converted after a recovery email was sent';
+
+CREATE INDEX IF NOT EXISTS idx_cart_activity_status_activity ON cart_activity(status, last_activity_at);
+CREATE INDEX IF NOT EXISTS idx_cart_activity_recovery_pending
+    ON cart_activity(abandoned_at) WHERE status = 'abandoned' AND recovery_email_sent_at IS NULL;
+
+-- Seed activity for carts that already exist
+INSERT INTO cart_activity (user_id, mini_app_type, store_id, started_at, last_activity_at)
+SELECT user_id, mini_app_type, COALESCE(store_id, 0), MIN(created_at), MAX(COALESCE(updated_at, created_at))
+FROM carts
+GROUP BY user_id, mini_app_type, COALESCE(store_id, 0)
+ON CONFLICT (user_id, mini_app_type, store_id) DO NOTHING;
@@ -0,0 +1,74 @@
+-- Migration: Geospatial store lookup
+-- Date: 2026-10-19
+-- Description: Adds an indexed geohash to stores so catalog-service can answer radius and
+--              map viewport queries with prefix range scans instead of computing the
+--              distance to every store. The geohash is kept in sync by a trigger;
+--              geohash_encode must match internal/geo.Encode in catalog-service.
+
+CREATE OR REPLACE FUNCTION geohash_encode(lat DOUBLE PRECISION, lng DOUBLE PRECISION, hash_precision INTEGER)
+RETURNS VARCHAR AS $$
+DECLARE
+    alphabet CONSTANT TEXT := '0123456789bcdefghjkmnpqrstuvwxyz';
+    min_lat DOUBLE PRECISION := -90;
+    max_lat DOUBLE PRECISION := 90;
+    min_lng DOUBLE PRECISION := -180;
+    max_lng DOUBLE PRECISION := 180;
+    mid DOUBLE PRECISION;
+    hash TEXT := '';
+    bit INTEGER := 0;
+    ch INTEGER := 0;
+    even BOOLEAN := true;
+BEGIN
+    WHILE length(hash) < hash_precision LOOP
+        IF even THEN
+            mid := (min_lng + max_lng) / 2;
+            IF lng >= mid THEN
+                ch := ch | (1 << (4 - bit));
+                min_lng := mid;
+            ELSE
+                max_lng := mid;
+            END IF;
+        ELSE
+            mid := (min_lat + max_lat) / 2;
+            IF lat >= mid THEN
+                ch := ch | (1 << (4 - bit));
+                min_lat := mid;
+            ELSE
+                max_lat := mid;
+            END IF;
+        END IF;
+        even := NOT even;
+
+        IF bit < 4 THEN
+            bit := bit + 1;
+        ELSE
+            hash := hash || substr(alphabet, ch + 1, 1);
+            bit := 0;
+            ch := 0;
+        END IF;
+    END LOOP;
+    RETURN hash;
+END;
+$$ LANGUAGE plpgsql IMMUTABLE STRICT;
+
+-- "C" collation keeps byte order so prefix ranges on the index work
+ALTER TABLE stores ADD COLUMN IF NOT EXISTS geohash VARCHAR(12) COLLATE "C";
+
+CREATE OR REPLACE FUNCTION set_store_geohash()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.geohash := geohash_encode(NEW.latitude, NEW.longitude, 9);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_stores_geohash ON stores;
+CREATE TRIGGER trg_stores_geohash
+    BEFORE INSERT OR UPDATE OF latitude, longitude ON stores
+    FOR EACH ROW EXECUTE FUNCTION set_store_geohash();
+
+UPDATE stores SET geohash = geohash_encode(latitude, longitude, 9) WHERE geohash IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_stores_geohash ON stores (geohash) WHERE is_active = true;
+
+COMMENT ON COLUMN stores.geohash IS 'Geohash (precision 9) of the store location, maintained by trg_stores_geohash';
@@ -0,0 +1,123 @@
+-- Migration: Store opening hours, holiday calendar and open-now checks
+-- Date: 2026-10-19
+-- Description: Adds a timezone, weekly opening hours and dated exceptions (holidays or
+--              special hours) per store. store_is_open and store_next_open_at evaluate them
+--              in the store's timezone and are shared by catalog-service (open_now, is_open,
+--              next_open_at) and order-service (rejecting orders for closed stores).
+--              A store without weekly hours is treated as always open.
+
+ALTER TABLE stores ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NOT NULL DEFAULT 'UTC';
+
+-- day_of_week follows EXTRACT(DOW): 0 = Sunday ... 6 = Saturday. A day may have several
+-- intervals (e.g. a lunch break); closes_at may be 24:00 for a store open until midnight.
+CREATE TABLE IF NOT EXISTS store_opening_hours (
+    store_id INTEGER NOT NULL REFERENCES stores(store_id) ON DELETE CASCADE,
+    day_of_week SMALLINT NOT NULL CHECK (day_of_week BETWEEN 0 AND 6),
+    opens_at TIME NOT NULL,
+    closes_at TIME NOT NULL,
+    PRIMARY KEY (store_id, day_of_week, opens_at),
+    CHECK (closes_at > opens_at)
+);
+
+CREATE TABLE IF NOT EXISTS store_hours_exceptions (
+    exception_id SERIAL PRIMARY KEY,
+    store_id INTEGER NOT NULL REFERENCES stores(store_id) ON DELETE CASCADE,
+    exception_date DATE NOT NULL,
+    is_closed BOOLEAN NOT NULL DEFAULT true,
+    opens_at TIME,
+    closes_at TIME,
+    reason VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (store_id, exception_date),
+    CHECK (is_closed OR (opens_at IS NOT NULL AND closes_at IS NOT NULL AND closes_at > opens_at))
+);
+
+COMMENT ON TABLE store_opening_hours IS 'Weekly opening hours in the store''s local time';
+COMMENT ON TABLE store_hours_exceptions IS 'Holidays (is_closed) or special hours that replace the weekly hours on a date';
+
+CREATE OR REPLACE FUNCTION store_is_open(p_store_id INTEGER, p_at TIMESTAMP WITH TIME ZONE)
+RETURNS BOOLEAN AS $$
+DECLARE
+    tz TEXT;
+    local_ts TIMESTAMP;
+    ex RECORD;
+BEGIN
+    SELECT timezone INTO tz FROM stores WHERE store_id = p_store_id;
+    IF tz IS NULL THEN
+        RETURN false;
+    END IF;
+    local_ts := p_at AT TIME ZONE tz;
+
+    SELECT is_closed, opens_at, closes_at INTO ex
+    FROM store_hours_exceptions
+    WHERE store_id = p_store_id AND exception_date = local_ts::date;
+    IF FOUND THEN
+        RETURN NOT ex.is_closed AND local_ts::time >= ex.opens_at AND local_ts::time < ex.closes_at;
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM store_opening_hours WHERE store_id = p_store_id) THEN
+        RETURN true;
+    END IF;
+
+    RETURN EXISTS (
+        SELECT 1 FROM store_opening_hours
+        WHERE store_id = p_store_id
+          AND day_of_week = EXTRACT(DOW FROM local_ts)
+          AND local_ts::time >= opens_at AND local_ts::time < closes_at
+    );
+END;
+$$ LANGUAGE plpgsql STABLE;
+
+-- Returns p_at when the store is open, otherwise the next opening within two weeks (NULL if none)
+CREATE OR REPLACE FUNCTION store_next_open_at(p_store_id INTEGER, p_at TIMESTAMP WITH TIME ZONE)
+RETURNS TIMESTAMP WITH TIME ZONE AS $$
+DECLARE
+    tz TEXT;
+    local_ts TIMESTAMP;
+    has_hours BOOLEAN;
+    day DATE;
+    ex RECORD;
+    candidate TIME;
+BEGIN
+    IF store_is_open(p_store_id, p_at) THEN
+        RETURN p_at;
+    END IF;
+
+    SELECT timezone INTO tz FROM stores WHERE store_id = p_store_id;
+    IF tz IS NULL THEN
+        RETURN NULL;
+    END IF;
+    local_ts := p_at AT TIME ZONE tz;
+    has_hours := EXISTS (SELECT 1 FROM store_opening_hours WHERE store_id = p_store_id);
+
+    FOR i IN 0..14 LOOP
+        day := local_ts::date + i;
+        candidate := NULL;
+
+        SELECT is_closed, opens_at INTO ex
+        FROM store_hours_exceptions
+        WHERE store_id = p_store_id AND exception_date = day;
+        IF FOUND THEN
+            IF NOT ex.is_closed AND day + ex.opens_at > local_ts THEN
+                candidate := ex.opens_at;
+            END IF;
+        ELSIF NOT has_hours THEN
+            IF i > 0 THEN
+                candidate := TIME '00:00';
+            END IF;
+        ELSE
+            SELECT MIN(opens_at) INTO candidate
+            FROM store_opening_hours
+            WHERE store_id = p_store_id
+              AND day_of_week = EXTRACT(DOW FROM day)
+              AND day + opens_at > local_ts;
+        END IF;
+
+        IF candidate IS NOT NULL THEN
+            RETURN (day + candidate) AT TIME ZONE tz;
+        END IF;
+    END LOOP;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql STABLE;
@@ -0,0 +1,25 @@
+-- Migration: Per-store pricing and availability overrides
+-- Date: 2026-10-19
+-- Description: Lets a store sell a product at its own price or take it off its shelves
+--              without touching the product itself. The effective price at a store is the
+--              override price when set, otherwise products.main_price; the effective stock
+--              is the store's inventory (quantity - reserved_quantity) when the store has an
+--              inventory row, otherwise products.stock_left. Both catalog-service and
+--              order-service read the same rules.
+
+CREATE TABLE IF NOT EXISTS store_product_overrides (
+    store_id INTEGER NOT NULL REFERENCES stores(store_id) ON DELETE CASCADE,
+    product_id INTEGER NOT NULL REFERENCES products(product_id) ON DELETE CASCADE,
+    price NUMERIC(10, 2) CHECK (price IS NULL OR price > 0),
+    strikethrough_price NUMERIC(10, 2) CHECK (strikethrough_price IS NULL OR strikethrough_price > 0),
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (store_id, product_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_store_product_overrides_product ON store_product_overrides(product_id);
+
+COMMENT ON TABLE store_product_overrides IS 'Per-store price and availability overrides for products';
+COMMENT ON COLUMN store_product_overrides.price IS 'Price at this store; NULL keeps products.main_price';
+COMMENT ON COLUMN store_product_overrides.is_active IS 'false hides the product at this store and blocks adding it to carts';
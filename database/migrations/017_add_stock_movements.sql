@@ -0,0 +1,62 @@
+-- Migration: Inventory stock movement ledger
+-- Date: 2026-10-19
+-- Description: Every change to store inventory writes an immutable stock_movements row
+--              with the quantity before and after, the reason, the actor and the order,
+--              stock request or transfer behind it. inventory.quantity equals the sum of a
+--              product's movements at a store, which the reconciliation endpoint checks.
+--              products.stock_left mirrors the inventory of the product's own store.
+
+CREATE TABLE IF NOT EXISTS stock_movements (
+    movement_id BIGSERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL REFERENCES products(product_id) ON DELETE CASCADE,
+    store_id INTEGER NOT NULL REFERENCES stores(store_id) ON DELETE CASCADE,
+    movement_type VARCHAR(20) NOT NULL
+        CHECK (movement_type IN ('initial', 'adjustment', 'count', 'transfer_in', 'transfer_out', 'sale', 'receipt')),
+    quantity_change INTEGER NOT NULL,
+    quantity_before INTEGER NOT NULL,
+    quantity_after INTEGER NOT NULL,
+    reason TEXT,
+    actor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    order_id UUID,
+    stock_request_id INTEGER,
+    transfer_id UUID,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (quantity_after = quantity_before + quantity_change)
+);
+
+CREATE INDEX IF NOT EXISTS idx_stock_movements_product_store ON stock_movements(product_id, store_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_stock_movements_store_created ON stock_movements(store_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_stock_movements_order ON stock_movements(order_id) WHERE order_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_stock_movements_transfer ON stock_movements(transfer_id) WHERE transfer_id IS NOT NULL;
+
+-- Movements are append-only; corrections are new adjustment movements
+CREATE OR REPLACE FUNCTION prevent_stock_movement_update() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'stock_movements is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_stock_movements_immutable ON stock_movements;
+CREATE TRIGGER trg_stock_movements_immutable
+    BEFORE UPDATE ON stock_movements
+    FOR EACH ROW EXECUTE FUNCTION prevent_stock_movement_update();
+
+-- Products sold from their own store get an inventory row holding their current stock
+INSERT INTO inventory (product_id, store_id, quantity, last_updated)
+SELECT p.product_id, p.store_id, GREATEST(p.stock_left, 0), CURRENT_TIMESTAMP
+FROM products p
+WHERE p.store_id IS NOT NULL
+ON CONFLICT (product_id, store_id) DO NOTHING;
+
+-- Open the ledger with the current quantity of every inventory row
+INSERT INTO stock_movements (product_id, store_id, movement_type, quantity_change, quantity_before, quantity_after, reason)
+SELECT i.product_id, i.store_id, 'initial', i.quantity, 0, i.quantity, 'Opening balance'
+FROM inventory i
+WHERE i.product_id IS NOT NULL AND i.store_id IS NOT NULL
+  AND NOT EXISTS (
+      SELECT 1 FROM stock_movements sm
+      WHERE sm.product_id = i.product_id AND sm.store_id = i.store_id
+  );
+
+COMMENT ON TABLE stock_movements IS 'Append-only ledger of store inventory changes';
+COMMENT ON COLUMN stock_movements.transfer_id IS 'Pairs the transfer_out and transfer_in movements of one transfer';
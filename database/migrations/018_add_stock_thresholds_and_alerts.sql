@@ -0,0 +1,61 @@
+-- Migration: Low-stock alerts and reorder thresholds
+-- Date: 2026-10-19
+-- Description: Configurable safety stock, reorder point and reorder quantity per product,
+--              optionally overridden per store. The safety stock replaces the fixed 5-unit
+--              display buffer in catalog-service and order-service (stock_safety_stock).
+--              The low-stock checker in catalog-service opens a low_stock_alerts row when a
+--              store's available stock falls to its reorder point, notifies admins and the
+--              linked manufacturer, and resolves the alert once stock is back above it.
+
+CREATE TABLE IF NOT EXISTS stock_thresholds (
+    threshold_id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL REFERENCES products(product_id) ON DELETE CASCADE,
+    store_id INTEGER REFERENCES stores(store_id) ON DELETE CASCADE, -- NULL applies to every store
+    safety_stock INTEGER NOT NULL DEFAULT 5 CHECK (safety_stock >= 0),
+    reorder_point INTEGER NOT NULL CHECK (reorder_point >= 0),
+    reorder_quantity INTEGER NOT NULL CHECK (reorder_quantity > 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_stock_thresholds_product
+    ON stock_thresholds(product_id) WHERE store_id IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_stock_thresholds_product_store
+    ON stock_thresholds(product_id, store_id) WHERE store_id IS NOT NULL;
+
+-- Safety stock of a product at a store: the store threshold, else the product threshold,
+-- else the historical 5-unit buffer
+CREATE OR REPLACE FUNCTION stock_safety_stock(p_product_id INTEGER, p_store_id INTEGER) RETURNS INTEGER AS $$
+    SELECT COALESCE((
+        SELECT t.safety_stock
+        FROM stock_thresholds t
+        WHERE t.product_id = p_product_id
+          AND (t.store_id = p_store_id OR t.store_id IS NULL)
+        ORDER BY t.store_id NULLS LAST
+        LIMIT 1
+    ), 5);
+$$ LANGUAGE sql STABLE;
+
+CREATE TABLE IF NOT EXISTS low_stock_alerts (
+    alert_id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL REFERENCES products(product_id) ON DELETE CASCADE,
+    store_id INTEGER NOT NULL REFERENCES stores(store_id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'acknowledged', 'resolved')),
+    available_quantity INTEGER NOT NULL,
+    reorder_point INTEGER NOT NULL,
+    safety_stock INTEGER NOT NULL,
+    stock_request_id INTEGER REFERENCES stock_requests(request_id) ON DELETE SET NULL,
+    acknowledged_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    acknowledged_at TIMESTAMP WITH TIME ZONE,
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- At most one unresolved alert per product and store
+CREATE UNIQUE INDEX IF NOT EXISTS idx_low_stock_alerts_one_open
+    ON low_stock_alerts(product_id, store_id) WHERE status <> 'resolved';
+CREATE INDEX IF NOT EXISTS idx_low_stock_alerts_status_created ON low_stock_alerts(status, created_at DESC);
+
+COMMENT ON TABLE stock_thresholds IS 'Safety stock and reorder settings per product, optionally per store';
+COMMENT ON COLUMN stock_thresholds.safety_stock IS 'Units held back from customers; available minus safety stock is displayed';
+COMMENT ON TABLE low_stock_alerts IS 'Raised by the catalog-service low-stock checker when available stock reaches the reorder point';
@@ -0,0 +1,178 @@
+-- Migration: Canonical store type registry
+-- Date: 2026-10-19
+-- Description: Replaces the store_type enum, which mixed English values ('Retail', 'Unmanned',
+--              'Warehouse') with Chinese display strings ('无人门店', '无人商店', ...), by a
+--              store_types registry keyed by stable codes (UnmannedStore, ExhibitionMall, ...).
+--              Display names are kept per locale in store_type_translations. stores.type and
+--              products.store_type are converted to codes and reference the registry, which
+--              replaces the cmd/fix-store-types repair tool.
+
+CREATE TABLE IF NOT EXISTS store_types (
+    code VARCHAR(30) PRIMARY KEY,
+    mini_app_type mini_app_type NOT NULL,
+    category_association store_type_association, -- matched against product_categories; NULL matches only 'All'
+    sort_order INTEGER NOT NULL DEFAULT 0,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE
+);
+
+CREATE TABLE IF NOT EXISTS store_type_translations (
+    code VARCHAR(30) NOT NULL REFERENCES store_types(code) ON DELETE CASCADE,
+    locale VARCHAR(10) NOT NULL,
+    display_name VARCHAR(100) NOT NULL,
+    PRIMARY KEY (code, locale)
+);
+
+INSERT INTO store_types (code, mini_app_type, category_association, sort_order) VALUES
+    ('RetailStore', 'RetailStore', NULL, 1),
+    ('UnmannedStore', 'UnmannedStore', 'Unmanned', 2),
+    ('UnmannedWarehouse', 'UnmannedStore', 'Unmanned', 3),
+    ('ExhibitionStore', 'ExhibitionSales', 'Retail', 4),
+    ('ExhibitionMall', 'ExhibitionSales', 'Retail', 5),
+    ('GroupBuying', 'GroupBuying', NULL, 6)
+ON CONFLICT (code) DO NOTHING;
+
+INSERT INTO store_type_translations (code, locale, display_name) VALUES
+    ('RetailStore', 'zh-CN', '零售商店'),
+    ('UnmannedStore', 'zh-CN', '无人门店'),
+    ('UnmannedWarehouse', 'zh-CN', '无人仓店'),
+    ('ExhibitionStore', 'zh-CN', '展销商店'),
+    ('ExhibitionMall', 'zh-CN', '展销商城'),
+    ('GroupBuying', 'zh-CN', '团购团批'),
+    ('RetailStore', 'en', 'Retail Store'),
+    ('UnmannedStore', 'en', 'Unmanned Store'),
+    ('UnmannedWarehouse', 'en', 'Unmanned Warehouse'),
+    ('ExhibitionStore', 'en', 'Exhibition Store'),
+    ('ExhibitionMall', 'en', 'Exhibition Mall'),
+    ('GroupBuying', 'en', 'Group Buying')
+ON CONFLICT (code, locale) DO NOTHING;
+
+-- Maps every value the old enum (and fix-store-types) ever produced to its code
+CREATE OR REPLACE FUNCTION legacy_store_type_code(value TEXT) RETURNS VARCHAR(30) AS $$
+    SELECT CASE value
+        WHEN '零售商店' THEN 'RetailStore'
+        WHEN '无人门店' THEN 'UnmannedStore'
+        WHEN '无人商店' THEN 'UnmannedStore'
+        WHEN 'Unmanned' THEN 'UnmannedStore'
+        WHEN '无人仓店' THEN 'UnmannedWarehouse'
+        WHEN 'Warehouse' THEN 'UnmannedWarehouse'
+        WHEN '展销商店' THEN 'ExhibitionStore'
+        WHEN 'Retail' THEN 'ExhibitionStore'
+        WHEN '展销商城' THEN 'ExhibitionMall'
+        WHEN 'Exhibition' THEN 'ExhibitionMall'
+        WHEN '团购团批' THEN 'GroupBuying'
+        ELSE value
+    END;
+$$ LANGUAGE sql IMMUTABLE;
+
+-- The views from migrations 002-004 depend on the converted columns and are unused by the
+-- services; they are recreated unchanged after the conversion
+DROP VIEW IF EXISTS location_based_categories;
+DROP VIEW IF EXISTS location_based_products;
+DROP VIEW IF EXISTS admin_products;
+DROP VIEW IF EXISTS public_products;
+
+ALTER TABLE stores
+    ALTER COLUMN type TYPE VARCHAR(30) USING legacy_store_type_code(type::text);
+ALTER TABLE products
+    ALTER COLUMN store_type TYPE VARCHAR(30) USING legacy_store_type_code(store_type::text);
+
+-- Products of location-based mini-apps take the type of their store; the others carry the
+-- store type of their mini-app instead of the placeholder the admin panel used to send
+UPDATE products p
+SET store_type = s.type
+FROM stores s
+WHERE p.store_id = s.store_id
+  AND p.mini_app_type IN ('UnmannedStore', 'ExhibitionSales')
+  AND p.store_type <> s.type;
+
+UPDATE products
+SET store_type = mini_app_type::text
+WHERE mini_app_type IN ('RetailStore', 'GroupBuying')
+  AND store_type <> mini_app_type::text;
+
+ALTER TABLE stores DROP CONSTRAINT IF EXISTS stores_type_fkey;
+ALTER TABLE stores
+    ADD CONSTRAINT stores_type_fkey FOREIGN KEY (type) REFERENCES store_types(code);
+ALTER TABLE products DROP CONSTRAINT IF EXISTS products_store_type_fkey;
+ALTER TABLE products
+    ADD CONSTRAINT products_store_type_fkey FOREIGN KEY (store_type) REFERENCES store_types(code);
+
+DROP FUNCTION legacy_store_type_code(TEXT);
+DROP TYPE IF EXISTS store_type;
+
+CREATE VIEW location_based_categories AS
+SELECT
+    c.*,
+    s.name as store_name,
+    s.city as store_city,
+    s.latitude,
+    s.longitude,
+    s.type as store_type
+FROM product_categories c
+JOIN stores s ON c.store_id = s.store_id
+WHERE c.store_id IS NOT NULL AND c.is_active = TRUE AND s.is_active = TRUE;
+
+CREATE VIEW location_based_products AS
+SELECT
+    p.*,
+    s.name as store_name,
+    s.city as store_city,
+    s.address as store_address,
+    s.latitude as store_latitude,
+    s.longitude as store_longitude,
+    s.type as store_type_detail
+FROM products p
+JOIN stores s ON p.store_id = s.store_id
+WHERE p.store_id IS NOT NULL AND p.is_active = TRUE AND s.is_active = TRUE;
+
+CREATE VIEW admin_products AS
+SELECT
+    p.*,
+    ARRAY_AGG(
+        JSON_BUILD_OBJECT(
+            'id', pi.image_id,
+            'url', pi.image_url,
+            'display_order', pi.display_order,
+            'is_primary', pi.is_primary
+        ) ORDER BY pi.display_order, pi.image_id
+    ) FILTER (WHERE pi.image_id IS NOT NULL) as images
+FROM products p
+LEFT JOIN product_images pi ON p.product_id = pi.product_id
+WHERE p.is_active = TRUE
+GROUP BY p.product_id;
+
+CREATE VIEW public_products AS
+SELECT
+    p.product_id,
+    p.sku,
+    p.title,
+    p.description_short,
+    p.description_long,
+    p.manufacturer_id,
+    p.store_type,
+    p.mini_app_type,
+    p.store_id,
+    p.main_price,
+    p.strikethrough_price,
+    p.stock_left,
+    p.minimum_order_quantity,
+    p.is_active,
+    p.is_featured,
+    p.is_mini_app_recommendation,
+    p.created_at,
+    p.updated_at,
+    ARRAY_AGG(
+        JSON_BUILD_OBJECT(
+            'id', pi.image_id,
+            'url', pi.image_url,
+            'display_order', pi.display_order,
+            'is_primary', pi.is_primary
+        ) ORDER BY pi.display_order, pi.image_id
+    ) FILTER (WHERE pi.image_id IS NOT NULL) as images
+FROM products p
+LEFT JOIN product_images pi ON p.product_id = pi.product_id
+WHERE p.is_active = TRUE
+GROUP BY p.product_id;
+
+COMMENT ON TABLE store_types IS 'Canonical store types; the code is the only store type value stored or exchanged over the API';
+COMMENT ON TABLE store_type_translations IS 'Localized display names of store types (zh-CN, en)';
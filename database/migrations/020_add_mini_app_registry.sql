@@ -0,0 +1,76 @@
+-- Migration: Mini-app registry
+-- Date: 2026-10-19
+-- Description: Moves the mini-app rules hard-coded in catalog-service and order-service into a
+--              mini_apps table: whether a mini-app requires a store, enforces stock and enforces
+--              minimum order quantities, and (mini_app_store_types) which store types it serves.
+--              The mini_app_type enum is replaced by registry codes so a mini-app can be added
+--              from the admin API without a schema change.
+
+CREATE TABLE IF NOT EXISTS mini_apps (
+    code VARCHAR(50) PRIMARY KEY,
+    display_name VARCHAR(100) NOT NULL,
+    requires_store BOOLEAN NOT NULL DEFAULT FALSE,   -- carts and orders are kept per store
+    enforces_stock BOOLEAN NOT NULL DEFAULT FALSE,   -- carts and orders are limited to available stock
+    enforces_moq BOOLEAN NOT NULL DEFAULT TRUE,      -- products' minimum_order_quantity applies
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    sort_order INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO mini_apps (code, display_name, requires_store, enforces_stock, enforces_moq, sort_order) VALUES
+    ('RetailStore', '零售商店', FALSE, FALSE, TRUE, 1),
+    ('UnmannedStore', '无人商店', TRUE, TRUE, TRUE, 2),
+    ('ExhibitionSales', '展销展消', TRUE, FALSE, TRUE, 3),
+    ('GroupBuying', '团购团批', FALSE, FALSE, TRUE, 4)
+ON CONFLICT (code) DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS mini_app_store_types (
+    mini_app_code VARCHAR(50) NOT NULL REFERENCES mini_apps(code) ON DELETE CASCADE,
+    store_type_code VARCHAR(30) NOT NULL REFERENCES store_types(code) ON DELETE CASCADE,
+    PRIMARY KEY (mini_app_code, store_type_code)
+);
+
+CREATE INDEX IF NOT EXISTS idx_mini_app_store_types_store_type ON mini_app_store_types(store_type_code);
+
+-- store_types.mini_app_type (migration 019) becomes the allowed store types of each mini-app
+INSERT INTO mini_app_store_types (mini_app_code, store_type_code)
+SELECT mini_app_type::text, code FROM store_types
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE store_types DROP COLUMN IF EXISTS mini_app_type;
+
+-- Category associations hold registry codes instead of enum values
+DROP VIEW IF EXISTS location_based_categories;
+DROP VIEW IF EXISTS global_categories;
+
+ALTER TABLE product_categories ALTER COLUMN mini_app_association DROP DEFAULT;
+ALTER TABLE product_categories
+    ALTER COLUMN mini_app_association TYPE VARCHAR(50)[] USING mini_app_association::text[];
+ALTER TABLE product_categories
+    ALTER COLUMN mini_app_association SET DEFAULT ARRAY['RetailStore']::VARCHAR(50)[];
+
+DROP TYPE IF EXISTS mini_app_type;
+
+CREATE VIEW location_based_categories AS
+SELECT
+    c.*,
+    s.name as store_name,
+    s.city as store_city,
+    s.latitude,
+    s.longitude,
+    s.type as store_type
+FROM product_categories c
+JOIN stores s ON c.store_id = s.store_id
+WHERE c.store_id IS NOT NULL AND c.is_active = TRUE AND s.is_active = TRUE;
+
+CREATE VIEW global_categories AS
+SELECT *
+FROM product_categories
+WHERE store_id IS NULL AND is_active = TRUE;
+
+CREATE TRIGGER update_mini_apps_updated_at BEFORE UPDATE ON mini_apps
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE mini_apps IS 'Mini-app registry consulted by catalog-service and order-service; edited through the catalog admin API';
+COMMENT ON TABLE mini_app_store_types IS 'Store types a mini-app serves; location-based mini-apps only list stores of these types';
@@ -0,0 +1,49 @@
+-- Migration: User account status lifecycle
+-- Date: 2026-10-19
+-- Description: Makes users.status a real account lifecycle (active, suspended, deactivated,
+--              pending_verification) instead of a value the admin API echoed back. Suspensions
+--              can carry an expiry, every change records its reason and actor, and the history
+--              of changes is kept in user_status_history. auth-service refuses to sign in
+--              accounts that are not active and the other services reject their tokens.
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_status_check;
+ALTER TABLE users
+ADD CONSTRAINT users_status_check
+CHECK (status IN ('active', 'suspended', 'deactivated', 'pending_verification'));
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS status_reason TEXT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS suspended_until TIMESTAMP WITH TIME ZONE; -- NULL suspends indefinitely
+ALTER TABLE users ADD COLUMN IF NOT EXISTS status_changed_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS status_changed_by VARCHAR(255);
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_suspended_until_check;
+ALTER TABLE users
+ADD CONSTRAINT users_suspended_until_check
+CHECK (suspended_until IS NULL OR status = 'suspended');
+
+CREATE TABLE IF NOT EXISTS user_status_history (
+    id BIGSERIAL PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    from_status VARCHAR(20) NOT NULL,
+    to_status VARCHAR(20) NOT NULL,
+    reason TEXT,
+    suspended_until TIMESTAMP WITH TIME ZONE,
+    changed_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_status_history_user ON user_status_history(user_id, created_at DESC);
+
+-- The status an account has right now: a suspension whose expiry has passed counts as active.
+-- Every service checks accounts through this function so they agree on expired suspensions.
+CREATE OR REPLACE FUNCTION user_account_status(status VARCHAR, suspended_until TIMESTAMP WITH TIME ZONE)
+RETURNS VARCHAR AS $$
+    SELECT CASE
+        WHEN status = 'suspended' AND suspended_until IS NOT NULL AND suspended_until <= now() THEN 'active'
+        ELSE status
+    END;
+$$ LANGUAGE sql STABLE;
+
+COMMENT ON COLUMN users.status IS 'User account status: active, suspended, deactivated, pending_verification';
+COMMENT ON COLUMN users.suspended_until IS 'End of a suspension; the account is active again afterwards (see user_account_status)';
+COMMENT ON TABLE user_status_history IS 'Account status changes with their reason and the admin (or service) that made them';
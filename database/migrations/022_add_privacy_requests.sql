@@ -0,0 +1,38 @@
+-- Migration: Privacy requests (data export and erasure)
+-- Date: 2026-10-19
+-- Description: Logs every personal data export and erasure request, whether the user made it
+--              or an admin did on their behalf. user-service processes pending requests in the
+--              background: exports are stored as a ZIP bundle that can be downloaded until it
+--              expires, and erasure anonymizes the user's personal fields while their orders
+--              are kept for accounting. Requests are never deleted so they serve as the audit log.
+
+CREATE TABLE IF NOT EXISTS privacy_requests (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id),
+    request_type VARCHAR(20) NOT NULL CHECK (request_type IN ('export', 'erasure')),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'processing', 'completed', 'failed')),
+    source VARCHAR(20) NOT NULL CHECK (source IN ('self', 'admin')),
+    requested_by VARCHAR(255) NOT NULL,                -- the user's or admin's email (or user ID)
+    reason TEXT,
+    error TEXT,
+    export_bundle BYTEA,                               -- ZIP of the exported data, cleared on expiry
+    export_expires_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    started_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_privacy_requests_user ON privacy_requests(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_privacy_requests_pending ON privacy_requests(created_at) WHERE status = 'pending';
+
+-- One open request of each type per user
+CREATE UNIQUE INDEX IF NOT EXISTS idx_privacy_requests_open
+    ON privacy_requests(user_id, request_type) WHERE status IN ('pending', 'processing');
+
+-- Erased users keep their row (orders, shipments and audit trails reference it) with the
+-- personal fields anonymized
+ALTER TABLE users ADD COLUMN IF NOT EXISTS erased_at TIMESTAMP WITH TIME ZONE;
+
+COMMENT ON TABLE privacy_requests IS 'Audit log of personal data export and erasure requests, processed by user-service';
+COMMENT ON COLUMN users.erased_at IS 'When the personal data of the user was erased; the row is kept anonymized';
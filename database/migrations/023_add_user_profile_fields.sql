@@ -0,0 +1,19 @@
+-- Migration: Customer self-service profile
+-- Date: 2026-10-19
+-- Description: Adds the profile fields users can edit themselves through auth-service
+--              /api/me (locale, marketing consent, avatar) and the pending email of an email
+--              change, which only replaces users.email once the user enters the code sent to
+--              the new address.
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS phone VARCHAR(20);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS locale VARCHAR(10);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS avatar_url VARCHAR(500);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS marketing_consent BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS marketing_consent_updated_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS pending_email VARCHAR(255);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS pending_email_requested_at TIMESTAMP WITH TIME ZONE;
+
+COMMENT ON COLUMN users.locale IS 'Preferred locale of the user (e.g. en, zh-CN); NULL uses the app default';
+COMMENT ON COLUMN users.marketing_consent IS 'Whether the user agreed to receive marketing messages';
+COMMENT ON COLUMN users.marketing_consent_updated_at IS 'When the user last gave or withdrew marketing consent';
+COMMENT ON COLUMN users.pending_email IS 'New email waiting for the user to enter the code sent to it';
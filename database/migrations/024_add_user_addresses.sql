@@ -0,0 +1,42 @@
+-- Migration: User address book and order delivery details
+-- Date: 2026-10-19
+-- Description: Users keep an address book in user-service (several addresses, one default,
+--              optional coordinates). Orders record how they are fulfilled: delivered to one of
+--              the user's addresses, whose contents are copied onto the order so later edits
+--              or deletions of the address do not change it, or picked up at the order's store.
+
+CREATE TABLE IF NOT EXISTS user_addresses (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    label VARCHAR(50),                                 -- e.g. Home, Office
+    recipient_name VARCHAR(200) NOT NULL,
+    phone VARCHAR(20),
+    line1 VARCHAR(255) NOT NULL,
+    line2 VARCHAR(255),
+    city VARCHAR(100) NOT NULL,
+    region VARCHAR(100),
+    postal_code VARCHAR(20),
+    country_code CHAR(2) NOT NULL,                     -- ISO 3166-1 alpha-2
+    latitude DECIMAL(9, 6) CHECK (latitude BETWEEN -90 AND 90),
+    longitude DECIMAL(9, 6) CHECK (longitude BETWEEN -180 AND 180),
+    delivery_instructions TEXT,
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CHECK ((latitude IS NULL) = (longitude IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_addresses_user ON user_addresses(user_id, created_at);
+
+-- At most one default address per user
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_addresses_default ON user_addresses(user_id) WHERE is_default;
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS fulfillment_method VARCHAR(20)
+    CHECK (fulfillment_method IN ('delivery', 'pickup'));  -- NULL for orders placed before this migration
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS delivery_address JSONB;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS delivery_address_id UUID REFERENCES user_addresses(id) ON DELETE SET NULL;
+
+COMMENT ON TABLE user_addresses IS 'Address book of each user, managed through user-service /api/addresses';
+COMMENT ON COLUMN orders.fulfillment_method IS 'delivery (to delivery_address) or pickup (at store_id)';
+COMMENT ON COLUMN orders.delivery_address IS 'Copy of the user address the order is delivered to, taken when the order was placed';
+COMMENT ON COLUMN orders.delivery_address_id IS 'Address book entry delivery_address was copied from; NULL once it is deleted';
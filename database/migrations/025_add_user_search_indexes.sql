@@ -0,0 +1,27 @@
+-- Migration: Indexed admin user search
+-- Date: 2026-10-19
+-- Description: The admin user list in user-service searches a single lower-cased text made of
+--              the username, email, names and phone with LIKE '%term%', served by a trigram
+--              index, and pages with keyset cursors over (sort key, id) instead of OFFSET.
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Text matched by the admin user search; must stay in sync with the query in user-service
+CREATE OR REPLACE FUNCTION user_search_text(
+    p_username TEXT, p_email TEXT, p_first_name TEXT, p_last_name TEXT, p_phone TEXT
+) RETURNS TEXT AS $$
+    SELECT LOWER(CONCAT_WS(' ', p_username, p_email, p_first_name, p_last_name, p_phone))
+$$ LANGUAGE SQL IMMUTABLE PARALLEL SAFE;
+
+CREATE INDEX IF NOT EXISTS idx_users_search_trgm ON users
+    USING GIN (user_search_text(username, email, first_name, last_name, phone) gin_trgm_ops);
+
+-- Keyset pagination for the default and last login orderings; users who never signed in
+-- sort as if they had at -infinity
+CREATE INDEX IF NOT EXISTS idx_users_created_at_id ON users(created_at, id);
+CREATE INDEX IF NOT EXISTS idx_users_last_login_id ON users((COALESCE(last_login, '-infinity'::timestamptz)), id);
+
+-- Per-user order statistics and the mini-app usage filter are served by
+-- idx_orders_user_mini_app (user_id, mini_app_type) from migration 001.
+
+COMMENT ON FUNCTION user_search_text(TEXT, TEXT, TEXT, TEXT, TEXT) IS 'Lower-cased text searched by the admin user list, indexed by idx_users_search_trgm';
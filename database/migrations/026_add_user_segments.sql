@@ -0,0 +1,33 @@
+-- Migration: User segments
+-- Date: 2026-10-19
+-- Description: Admins define audiences in user-service as stored rule sets over profile,
+--              order and cart attributes (e.g. UnmannedStore customers in Milan with no order
+--              in 30 days). Members are materialized when a segment is saved and re-evaluated
+--              on a schedule, so listing and exporting them does not re-run the rules.
+
+CREATE TABLE IF NOT EXISTS user_segments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    rules JSONB NOT NULL,                              -- {"match": "all"|"any", "rules": [...]}
+    member_count INTEGER NOT NULL DEFAULT 0,
+    last_evaluated_at TIMESTAMP WITH TIME ZONE,
+    created_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_segments_name ON user_segments(LOWER(name));
+
+CREATE TABLE IF NOT EXISTS user_segment_members (
+    segment_id UUID NOT NULL REFERENCES user_segments(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    added_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (segment_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_segment_members_user ON user_segment_members(user_id);
+
+COMMENT ON TABLE user_segments IS 'Audience definitions managed through user-service /api/admin/segments';
+COMMENT ON COLUMN user_segments.rules IS 'Rule set evaluated by user-service; see its README for fields and operators';
+COMMENT ON TABLE user_segment_members IS 'Users matching each segment as of its last_evaluated_at';
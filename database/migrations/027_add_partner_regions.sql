@@ -0,0 +1,50 @@
+-- Migration: Partner regions and store assignments
+-- Date: 2026-10-19
+-- Description: Partner-role users run the stores of a region. Stores get a region, partners
+--              (partners.region_assigned) see every store of their region plus the stores
+--              assigned to them individually. partner_store_scope resolves both; order-service
+--              limits the admin order, cart and statistics views of Partner users to it.
+
+ALTER TABLE stores ADD COLUMN IF NOT EXISTS region VARCHAR(255);
+
+CREATE INDEX IF NOT EXISTS idx_stores_region ON stores(LOWER(region));
+
+COMMENT ON COLUMN stores.region IS 'Region the store belongs to, matched case-insensitively against partners.region_assigned';
+
+-- One partners row per user, removed with the user
+CREATE UNIQUE INDEX IF NOT EXISTS idx_partners_user ON partners(user_id);
+
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint
+        WHERE conname = 'partners_user_id_fkey'
+    ) THEN
+        ALTER TABLE partners ADD CONSTRAINT partners_user_id_fkey
+            FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+    END IF;
+END $$;
+
+-- Stores assigned to a partner in addition to those of its region
+CREATE TABLE IF NOT EXISTS partner_stores (
+    partner_id INTEGER NOT NULL REFERENCES partners(partner_id) ON DELETE CASCADE,
+    store_id INTEGER NOT NULL REFERENCES stores(store_id) ON DELETE CASCADE,
+    assigned_by VARCHAR(255),
+    assigned_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (partner_id, store_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_partner_stores_store ON partner_stores(store_id);
+
+-- Every store a partner may see, with how it got there
+CREATE OR REPLACE VIEW partner_store_scope AS
+SELECT p.partner_id, p.user_id, s.store_id, 'region'::text AS source
+FROM partners p
+JOIN stores s ON LOWER(s.region) = LOWER(p.region_assigned)
+UNION
+SELECT p.partner_id, p.user_id, ps.store_id, 'assigned'::text AS source
+FROM partners p
+JOIN partner_stores ps ON ps.partner_id = p.partner_id;
+
+COMMENT ON TABLE partner_stores IS 'Stores assigned to a partner individually, managed through user-service /api/admin/partners';
+COMMENT ON VIEW partner_store_scope IS 'Stores each partner may see: those of its region and those assigned to it';
@@ -0,0 +1,78 @@
+-- Migration: Loyalty points
+-- Date: 2026-10-19
+-- Description: Customers earn points on delivered orders according to rules per mini-app and
+--              store, and redeem them as a discount when placing an order. Every change to a
+--              balance is a loyalty_ledger entry. Credits are lots that expire; debits consume
+--              lots soonest-expiry first and record what they took in loyalty_allocations so
+--              redemptions of cancelled orders can be handed back to the same lots.
+
+-- Earning rules. A rule without mini_app_type or store_id applies to all of them; the most
+-- specific active rule for an order wins (store before mini-app).
+CREATE TABLE IF NOT EXISTS loyalty_rules (
+    rule_id SERIAL PRIMARY KEY,
+    mini_app_type VARCHAR(50) REFERENCES mini_apps(code) ON DELETE CASCADE,
+    store_id INTEGER REFERENCES stores(store_id) ON DELETE CASCADE,
+    points_per_unit NUMERIC(10, 4) NOT NULL CHECK (points_per_unit >= 0),
+    min_order_amount NUMERIC(10, 2) NOT NULL DEFAULT 0 CHECK (min_order_amount >= 0),
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_loyalty_rules_scope
+    ON loyalty_rules(COALESCE(mini_app_type, ''), COALESCE(store_id, 0));
+
+COMMENT ON COLUMN loyalty_rules.points_per_unit IS 'Points earned per currency unit paid, rounded down per order';
+
+-- One point per currency unit everywhere until rules are configured
+INSERT INTO loyalty_rules (points_per_unit, created_by)
+VALUES (1, 'system')
+ON CONFLICT DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS loyalty_ledger (
+    entry_id BIGSERIAL PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    entry_type VARCHAR(20) NOT NULL
+        CHECK (entry_type IN ('earn', 'redeem', 'earn_reversal', 'redeem_refund', 'expire', 'adjust')),
+    points INTEGER NOT NULL,
+    remaining INTEGER NOT NULL DEFAULT 0 CHECK (remaining >= 0 AND remaining <= GREATEST(points, 0)),
+    expires_at TIMESTAMP WITH TIME ZONE,
+    order_id UUID REFERENCES orders(id) ON DELETE SET NULL,
+    rule_id INTEGER REFERENCES loyalty_rules(rule_id) ON DELETE SET NULL,
+    reason TEXT,
+    created_by VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMENT ON TABLE loyalty_ledger IS 'Loyalty point changes per user; positive entries are lots that can be spent until expires_at';
+COMMENT ON COLUMN loyalty_ledger.remaining IS 'Points of a positive entry not yet spent, reversed or expired';
+
+CREATE INDEX IF NOT EXISTS idx_loyalty_ledger_user_created ON loyalty_ledger(user_id, created_at DESC, entry_id DESC);
+CREATE INDEX IF NOT EXISTS idx_loyalty_ledger_open_lots ON loyalty_ledger(user_id, expires_at) WHERE remaining > 0;
+CREATE INDEX IF NOT EXISTS idx_loyalty_ledger_expiry ON loyalty_ledger(expires_at) WHERE remaining > 0;
+
+-- An order earns, redeems, and has each reversed at most once
+CREATE UNIQUE INDEX IF NOT EXISTS idx_loyalty_ledger_order_entry
+    ON loyalty_ledger(order_id, entry_type) WHERE order_id IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS loyalty_allocations (
+    debit_entry_id BIGINT NOT NULL REFERENCES loyalty_ledger(entry_id) ON DELETE CASCADE,
+    lot_entry_id BIGINT NOT NULL REFERENCES loyalty_ledger(entry_id) ON DELETE CASCADE,
+    points INTEGER NOT NULL CHECK (points > 0),
+    PRIMARY KEY (debit_entry_id, lot_entry_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_loyalty_allocations_lot ON loyalty_allocations(lot_entry_id);
+
+COMMENT ON TABLE loyalty_allocations IS 'Points each debit took from each lot';
+
+-- The redemption applied to an order; total_amount is what remains to be paid
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS loyalty_points_redeemed INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS loyalty_discount NUMERIC(10, 2) NOT NULL DEFAULT 0;
+
+-- Earned points are announced in the notification inbox
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_notification_type_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_notification_type_check
+    CHECK (notification_type IN ('general', 'order_status', 'shipment', 'low_stock', 'loyalty'));
+COMMENT ON COLUMN notifications.notification_type IS 'order_status, shipment, low_stock, loyalty or general';
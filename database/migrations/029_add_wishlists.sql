@@ -0,0 +1,44 @@
+-- Migration: Wishlists and saved-for-later
+-- Date: 2026-10-19
+-- Description: Users keep a wishlist of products in order-service, either general or for a
+--              mini-app (and, for mini-apps that require a store, a store whose price and
+--              stock apply). Cart items can be saved for later into the wishlist with their
+--              quantity and moved back. A background worker compares each item's price and
+--              stock with what it last saw and notifies the user of price drops and of
+--              products coming back in stock.
+
+CREATE TABLE IF NOT EXISTS wishlist_items (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    product_id UUID NOT NULL REFERENCES products(product_uuid) ON DELETE CASCADE,
+    mini_app_type VARCHAR(50) REFERENCES mini_apps(code) ON DELETE CASCADE,  -- NULL for the general wishlist
+    store_id INTEGER REFERENCES stores(store_id) ON DELETE CASCADE,
+    quantity INTEGER NOT NULL DEFAULT 1 CHECK (quantity > 0),
+    saved_from_cart BOOLEAN NOT NULL DEFAULT FALSE,
+    notify_back_in_stock BOOLEAN NOT NULL DEFAULT TRUE,
+    notify_price_drop BOOLEAN NOT NULL DEFAULT TRUE,
+    price_when_added NUMERIC(10, 2),
+    last_seen_price NUMERIC(10, 2),
+    last_seen_in_stock BOOLEAN,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CHECK (store_id IS NULL OR mini_app_type IS NOT NULL)
+);
+
+-- A product is on a user's wishlist once per mini-app and store
+CREATE UNIQUE INDEX IF NOT EXISTS idx_wishlist_items_unique
+    ON wishlist_items(user_id, product_id, COALESCE(mini_app_type, ''), COALESCE(store_id, 0));
+CREATE INDEX IF NOT EXISTS idx_wishlist_items_user ON wishlist_items(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_wishlist_items_product ON wishlist_items(product_id);
+
+COMMENT ON TABLE wishlist_items IS 'Wishlisted and saved-for-later products, managed through order-service /api/wishlist';
+COMMENT ON COLUMN wishlist_items.last_seen_price IS 'Price at the last wishlist alert check; a lower price raises a price-drop notification';
+COMMENT ON COLUMN wishlist_items.last_seen_in_stock IS 'Availability at the last wishlist alert check; a change to true raises a back-in-stock notification';
+
+-- Wishlist alerts are announced in the notification inbox and reference the product
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_notification_type_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_notification_type_check
+    CHECK (notification_type IN ('general', 'order_status', 'shipment', 'low_stock', 'loyalty', 'wishlist'));
+COMMENT ON COLUMN notifications.notification_type IS 'order_status, shipment, low_stock, loyalty, wishlist or general';
+
+ALTER TYPE notification_reference_type ADD VALUE IF NOT EXISTS 'Product';
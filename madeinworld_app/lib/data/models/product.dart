@@ -66,13 +66,13 @@ class Product {
 
     final storeTypeStr = storeTypeValue.toString();
 
-    // Try to parse Chinese values from backend
+    // Parse the store type code from the backend
     try {
-      return StoreTypeExtension.fromChineseValue(storeTypeStr);
+      return StoreTypeExtension.fromApiValue(storeTypeStr);
     } catch (e) {
-      // Fallback: try English enum values
+      // Fallback: Chinese display values from older cached data
       try {
-        return StoreTypeExtension.fromApiValue(storeTypeStr);
+        return StoreTypeExtension.fromChineseValue(storeTypeStr);
       } catch (e) {
         // Final fallback: try enum name matching
         try {
@@ -165,7 +165,7 @@ class Product {
       'description_short': descriptionShort,
       'description_long': descriptionLong,
       'manufacturer_id': manufacturerId,
-      'store_type': storeType.toString().split('.').last,
+      'store_type': storeType.apiValue,
       'mini_app_type': miniAppType.apiValue,
       'store_id': storeId,
       'main_price': mainPrice,
@@ -36,7 +36,7 @@ class Store {
   }
 
   /// Parse store type from API response
-  /// Handles both Chinese values from API and enum names for backward compatibility
+  /// Handles store type codes from the API and Chinese values from older cached data
   static StoreType _parseStoreType(dynamic typeValue) {
     if (typeValue == null) {
       throw ArgumentError('Store type cannot be null');
@@ -44,19 +44,23 @@ class Store {
 
     final typeString = typeValue.toString();
 
-    // First try to parse as Chinese value (from API)
+    // First try to parse as store type code (from API)
     try {
-      return StoreTypeExtension.fromChineseValue(typeString);
+      return StoreTypeExtension.fromApiValue(typeString);
     } catch (e) {
-      // If that fails, try to parse as enum name (backward compatibility)
+      // If that fails, try Chinese values and enum names (older cached data)
       try {
-        return StoreType.values.firstWhere(
-          (e) => e.toString().split('.').last.toLowerCase() == typeString.toLowerCase(),
-        );
+        return StoreTypeExtension.fromChineseValue(typeString);
       } catch (e2) {
-        // If both fail, log the error and throw with helpful message
-        debugPrint('ERROR: Unknown store type: "$typeString". Expected Chinese values: 无人门店, 无人仓店, 展销商店, 展销商城');
-        throw ArgumentError('Unknown store type: "$typeString"');
+        try {
+          return StoreType.values.firstWhere(
+            (e) => e.toString().split('.').last.toLowerCase() == typeString.toLowerCase(),
+          );
+        } catch (e3) {
+          // If all fail, log the error and throw with helpful message
+          debugPrint('ERROR: Unknown store type: "$typeString". Expected codes: UnmannedStore, UnmannedWarehouse, ExhibitionStore, ExhibitionMall');
+          throw ArgumentError('Unknown store type: "$typeString"');
+        }
       }
     }
   }
@@ -69,7 +73,7 @@ class Store {
       'address': address,
       'latitude': latitude,
       'longitude': longitude,
-      'type': type.toString().split('.').last,
+      'type': type.apiValue,
       'is_active': isActive,
     };
   }